@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rylenko/limbo/pkg/chess/book"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+)
+
+const (
+	// Output formats of the book.
+	formatPolyglot = "polyglot"
+	formatNative   = "native"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "run(): %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		outPath   = flag.String("out", "book.bin", "path to the output book")
+		format    = flag.String("format", formatPolyglot, "output format: \"polyglot\" or \"native\"")
+		maxPly    = flag.Uint("maxply", 20, "plies of each game added to the book, 0 means no limit")
+		minGames  = flag.Uint("mingames", 3, "minimum count of games in which the move was played")
+		minRating = flag.Uint("minrating", 0, "minimum rating of both players, 0 means no filter")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <games.pgn>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		return errors.New("no PGN files")
+	}
+
+	if *format != formatPolyglot && *format != formatNative {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	builder := book.NewBuilder(game.Engine{}, book.BuilderOptions{
+		MaxPly:    *maxPly,
+		MinGames:  *minGames,
+		MinRating: *minRating,
+	})
+
+	for _, path := range flag.Args() {
+		if err := addGames(builder, path); err != nil {
+			return fmt.Errorf("addGames(%q): %w", path, err)
+		}
+	}
+
+	file, err := os.Create(*outPath)
+	if err != nil {
+		return fmt.Errorf("Create(%q): %w", *outPath, err)
+	}
+
+	if *format == formatNative {
+		err = builder.WriteNative(file)
+	} else {
+		err = builder.WritePolyglot(file)
+	}
+
+	if err != nil {
+		return errors.Join(fmt.Errorf("write %s book: %w", *format, err), file.Close())
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("Close(): %w", err)
+	}
+
+	return nil
+}
+
+// addGames adds all games from passed PGN file to the builder and reports the count of added and skipped games.
+//
+// The games with impossible moves are reported and partially added.
+func addGames(builder *book.Builder, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Open(): %w", err)
+	}
+	defer file.Close() //nolint:errcheck // Nothing to lose on read-only file close error.
+
+	reader := pgn.NewReader(file)
+
+	var addedCount, skippedCount int
+
+	for index := 1; ; index++ {
+		pgnGame, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("game #%d, Read(): %w", index, err)
+		}
+
+		added, err := builder.Add(pgnGame)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: game #%d, Add(): %v\n", path, index, err)
+		}
+
+		if added {
+			addedCount++
+		} else {
+			skippedCount++
+		}
+	}
+
+	fmt.Printf("%s: %d games added, %d skipped\n", path, addedCount, skippedCount)
+
+	return nil
+}
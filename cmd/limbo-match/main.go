@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/match"
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+const (
+	// Prefix of the player specification, which means the in-process searcher with passed maximum depth.
+	searcherSpecPrefix = "limbo:"
+
+	startFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "run(): %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		firstSpec    = flag.String("first", "limbo:3", "first player: path to UCI engine or \"limbo:<max depth>\"")
+		secondSpec   = flag.String("second", "limbo:2", "second player: path to UCI engine or \"limbo:<max depth>\"")
+		gamesCount   = flag.Uint("games", 100, "maximum count of games")
+		openingsPath = flag.String("openings", "", "path to the file with one opening FEN per line")
+		depth        = flag.Uint("depth", 0, "search depth per move, 0 means no limit")
+		nodes        = flag.Uint64("nodes", 0, "search nodes per move, 0 means no limit")
+		moveTime     = flag.Duration("movetime", 100*time.Millisecond, "search time per move, 0 means no limit")
+		maxPlies     = flag.Int("maxplies", 400, "plies after which the game is adjudicated as a draw, 0 means no limit")
+		elo0         = flag.Float64("elo0", 0, "SPRT null hypothesis Elo difference")
+		elo1         = flag.Float64("elo1", 5, "SPRT alternative hypothesis Elo difference")
+		alpha        = flag.Float64("alpha", 0.05, "SPRT false positive probability")
+		beta         = flag.Float64("beta", 0.05, "SPRT false negative probability")
+	)
+
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	openings, err := readOpenings(*openingsPath)
+	if err != nil {
+		return fmt.Errorf("readOpenings(%q): %w", *openingsPath, err)
+	}
+
+	first, err := newPlayer(ctx, *firstSpec)
+	if err != nil {
+		return fmt.Errorf("newPlayer(%q): %w", *firstSpec, err)
+	}
+	defer closePlayer(first)
+
+	second, err := newPlayer(ctx, *secondSpec)
+	if err != nil {
+		return fmt.Errorf("newPlayer(%q): %w", *secondSpec, err)
+	}
+	defer closePlayer(second)
+
+	limits := search.Limits{Depth: uint8(min(*depth, uint(search.SearcherMaxDepth))), Nodes: *nodes, MoveTime: *moveTime}
+
+	stats, err := match.NewMatch(first, second, openings, limits, *maxPlies).Run(
+		ctx,
+		*gamesCount,
+		func(report match.GameReport, stats match.Stats) bool {
+			result, err := report.Result.PGN()
+			if err != nil {
+				result = report.Result.String()
+			}
+
+			fmt.Printf(
+				"game #%d: %s vs %s: %s (%s), %d plies; +%d =%d -%d\n",
+				report.Index+1, report.White, report.Black, result, report.Termination, len(report.Moves),
+				stats.Wins, stats.Draws, stats.Losses,
+			)
+
+			_, verdict, err := stats.CalcSPRT(*elo0, *elo1, *alpha, *beta)
+
+			return err == nil && verdict == match.VerdictNil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("Run(): %w", err)
+	}
+
+	fmt.Printf("%s vs %s: +%d =%d -%d\n", first.Name(), second.Name(), stats.Wins, stats.Draws, stats.Losses)
+
+	if elo, margin, err := stats.CalcElo(); err != nil {
+		fmt.Printf("Elo: %v\n", err)
+	} else {
+		fmt.Printf("Elo: %.1f +/- %.1f\n", elo, margin)
+	}
+
+	llr, verdict, err := stats.CalcSPRT(*elo0, *elo1, *alpha, *beta)
+	if err != nil {
+		return fmt.Errorf("CalcSPRT(): %w", err)
+	}
+
+	lower, upper := match.CalcSPRTBounds(*alpha, *beta)
+
+	fmt.Printf(
+		"SPRT [%.1f, %.1f]: LLR %.2f [%.2f, %.2f], %s\n", *elo0, *elo1, llr, lower, upper, formatVerdict(verdict))
+
+	return nil
+}
+
+// closePlayer closes passed player and reports the error if any.
+func closePlayer(player match.Player) {
+	if err := player.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s.Close(): %v\n", player.Name(), err)
+	}
+}
+
+// newPlayer creates the player from passed specification: either the path to UCI engine binary or "limbo:<depth>".
+func newPlayer(ctx context.Context, spec string) (match.Player, error) {
+	depthString, ok := strings.CutPrefix(spec, searcherSpecPrefix)
+	if !ok {
+		player, err := match.NewUCIPlayer(ctx, spec)
+		if err != nil {
+			return nil, fmt.Errorf("NewUCIPlayer(%q): %w", spec, err)
+		}
+
+		return player, nil
+	}
+
+	depth, err := strconv.ParseUint(depthString, 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("ParseUint(%s, 10, 8): %w", depthString, err)
+	}
+
+	return match.NewSearcherPlayer(spec, search.NewSearcher(game.Engine{}, uint8(depth))), nil
+}
+
+// readOpenings reads opening FENs from passed file, one per line. Empty lines and lines starting with "#" are
+// skipped. If the path is empty, the start position is the only opening.
+func readOpenings(path string) ([]string, error) {
+	if path == "" {
+		return []string{startFEN}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Open(): %w", err)
+	}
+	defer file.Close() //nolint:errcheck // Nothing to lose on read-only file close error.
+
+	var openings []string
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		openings = append(openings, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Scan(): %w", err)
+	}
+
+	if len(openings) == 0 {
+		return nil, errors.New("no openings")
+	}
+
+	return openings, nil
+}
+
+// formatVerdict formats passed verdict for humans.
+func formatVerdict(verdict match.Verdict) string {
+	switch verdict {
+	case match.VerdictPass:
+		return "passed"
+	case match.VerdictFail:
+		return "failed"
+	case match.VerdictNil:
+		return "inconclusive"
+	default:
+		return verdict.String()
+	}
+}
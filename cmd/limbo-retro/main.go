@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/retro"
+)
+
+// Extension of the distance to mate table files.
+const tableExtension = ".dtm"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "run(): %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	dir := flag.String("dir", ".", "directory with the tables, existing tables are not solved again")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <material key>...\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Material key examples: KQvK, KRvK, KPvK.\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		return errors.New("no material keys")
+	}
+
+	solver := retro.NewSolver(game.Engine{})
+
+	if err := addTables(solver, *dir); err != nil {
+		return fmt.Errorf("addTables(%q): %w", *dir, err)
+	}
+
+	for _, key := range flag.Args() {
+		if _, err := solver.Solve(key); err != nil {
+			return fmt.Errorf("Solve(%q): %w", key, err)
+		}
+	}
+
+	for _, table := range solver.Tables() {
+		path := filepath.Join(*dir, table.Key()+tableExtension)
+
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		if err := writeTable(table, path); err != nil {
+			return fmt.Errorf("writeTable(%q): %w", path, err)
+		}
+
+		fmt.Printf("%s: written\n", path)
+	}
+
+	return nil
+}
+
+// addTables reads all tables from passed directory and adds them to the solver.
+func addTables(solver *retro.Solver, dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+tableExtension))
+	if err != nil {
+		return fmt.Errorf("Glob(): %w", err)
+	}
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("Open(%q): %w", path, err)
+		}
+
+		table, err := retro.ReadTable(file)
+		file.Close() //nolint:errcheck,gosec // Nothing to lose on read-only file close error.
+
+		if err != nil {
+			return fmt.Errorf("ReadTable(%q): %w", path, err)
+		}
+
+		solver.AddTable(table)
+	}
+
+	return nil
+}
+
+// writeTable writes passed table to the file with passed path.
+func writeTable(table *retro.Table, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Create(): %w", err)
+	}
+
+	if _, err := table.WriteTo(file); err != nil {
+		return errors.Join(fmt.Errorf("WriteTo(): %w", err), file.Close())
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("Close(): %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+
+	"limbo/internal/server"
+)
+
+// Maximum time to read the request headers. Bodies are not limited, because WebSocket connections live long.
+const readHeaderTimeout = 10 * time.Second
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+
+	flag.Parse()
+
+	gameServer, err := server.NewServer(game.Engine{}, server.Options{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "NewServer(): %v\n", err)
+		os.Exit(1)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           gameServer.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	if err := httpServer.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "ListenAndServe(%q): %v\n", *addr, err)
+		os.Exit(1)
+	}
+}
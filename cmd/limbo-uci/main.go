@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/search"
+	"github.com/rylenko/limbo/pkg/chess/uci"
+)
+
+// Default maximum search depth. It may be changed by the GUI using "MaxDepth" option.
+const defaultMaxDepth = 4
+
+func main() {
+	engine := game.Engine{}
+	handler := uci.NewHandler(engine, search.NewSearcher(engine, defaultMaxDepth), os.Stdout)
+
+	if err := handler.Run(os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "Run(): %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -1,3 +1,5 @@
 module limbo
 
 go 1.23.3
+
+require github.com/coder/websocket v1.8.14
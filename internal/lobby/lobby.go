@@ -0,0 +1,265 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// Name of the variant, which is used if the seek has no variant.
+const lobbyDefaultVariant = "Standard"
+
+// Pairing is the game created for two matched seeks.
+type Pairing struct {
+	White Seek
+	Black Seek
+	Game  *game.Game
+	// Clock of the game, which is not started, so the press of white after the first move starts the clock of black.
+	Clock *clock.Clock
+}
+
+// Options configures the lobby.
+type Options struct {
+	// Engine of the created games.
+	Engine game.Engine
+	// Strategy of the matching. Nil means FirstComeStrategy.
+	Strategy Strategy
+	// TimeSource of the created clocks. Nil means clock.SystemTimeSource.
+	TimeSource clock.TimeSource
+	// OnPairing is called in the matching goroutine for each created game.
+	OnPairing func(pairing *Pairing)
+	// OnFlag is called if the player of passed color of the created game has run out of time.
+	OnFlag func(pairing *Pairing, color piece.Color)
+	// OnError is called in the matching goroutine if the strategy returns invalid pairs or the game of the matched
+	// seeks can not be created. The matching goes on after the error.
+	OnError func(err error)
+}
+
+// Lobby keeps the seeks of the players and matches them in the background. It is safe for concurrent use.
+type Lobby struct {
+	options Options
+	// Wake is signaled after each post to run the matching.
+	wake chan struct{}
+
+	mu     sync.Mutex
+	seeks  []Seek
+	nextID uint64
+}
+
+// NewLobby creates a new Lobby without seeks. The matching starts after Run.
+func NewLobby(options Options) *Lobby {
+	if options.Strategy == nil {
+		options.Strategy = FirstComeStrategy{}
+	}
+
+	return &Lobby{
+		options: options,
+		wake:    make(chan struct{}, 1),
+		nextID:  1,
+	}
+}
+
+// Post validates passed seek, adds it to the lobby and returns its ID.
+func (lobby *Lobby) Post(seek Seek) (string, error) {
+	if seek.Player == "" {
+		return "", errors.New("no player")
+	}
+
+	if seek.Variant == "" {
+		seek.Variant = lobbyDefaultVariant
+	}
+
+	if _, err := game.NewVariantFromName(lobby.options.Engine, seek.Variant); err != nil {
+		return "", fmt.Errorf("NewVariantFromName(%q): %w", seek.Variant, err)
+	}
+
+	if err := seek.TimeControl.Validate(); err != nil {
+		return "", fmt.Errorf("Validate(): %w", err)
+	}
+
+	if seek.Color != piece.ColorNil && seek.Color != piece.ColorWhite && seek.Color != piece.ColorBlack {
+		return "", fmt.Errorf("unknown color %s", seek.Color)
+	}
+
+	if seek.MaxRating != 0 && seek.MinRating > seek.MaxRating {
+		return "", fmt.Errorf("empty rating range %d-%d", seek.MinRating, seek.MaxRating)
+	}
+
+	lobby.mu.Lock()
+	seek.ID = strconv.FormatUint(lobby.nextID, 10)
+	lobby.seeks = append(lobby.seeks, seek)
+	lobby.nextID++
+	lobby.mu.Unlock()
+
+	select {
+	case lobby.wake <- struct{}{}:
+	default:
+	}
+
+	return seek.ID, nil
+}
+
+// Cancel removes the seek with passed ID. It returns false if there is no such seek, for example, it was matched.
+func (lobby *Lobby) Cancel(id string) bool {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	for index, seek := range lobby.seeks {
+		if seek.ID == id {
+			lobby.seeks = append(lobby.seeks[:index], lobby.seeks[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Seeks returns the waiting seeks in the post order.
+func (lobby *Lobby) Seeks() []Seek {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	seeks := make([]Seek, len(lobby.seeks))
+	copy(seeks, lobby.seeks)
+
+	return seeks
+}
+
+// Run matches the seeks after each post until passed context is done.
+func (lobby *Lobby) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-lobby.wake:
+		}
+
+		pairs, err := lobby.takePairs()
+		if err != nil {
+			lobby.reportError(fmt.Errorf("takePairs(): %w", err))
+		}
+
+		for _, pair := range pairs {
+			pairing, err := lobby.newPairing(pair[0], pair[1])
+			if err != nil {
+				lobby.reportError(fmt.Errorf("newPairing(%s, %s): %w", pair[0].ID, pair[1].ID, err))
+				continue
+			}
+
+			if lobby.options.OnPairing != nil {
+				lobby.options.OnPairing(pairing)
+			}
+		}
+	}
+}
+
+// takePairs removes the seeks paired by the strategy from the lobby and returns them.
+//
+// The invalid pairs are skipped, so their seeks stay in the lobby, and returned as the error.
+func (lobby *Lobby) takePairs() ([][2]Seek, error) {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	pairs := lobby.options.Strategy.Pair(lobby.seeks)
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	seekPairs := make([][2]Seek, 0, len(pairs))
+	paired := make([]bool, len(lobby.seeks))
+
+	var errs []error
+
+	for _, pair := range pairs {
+		if err := checkPair(pair, paired); err != nil {
+			errs = append(errs, fmt.Errorf("checkPair(%v): %w", pair, err))
+			continue
+		}
+
+		seekPairs = append(seekPairs, [2]Seek{lobby.seeks[pair[0]], lobby.seeks[pair[1]]})
+		paired[pair[0]], paired[pair[1]] = true, true
+	}
+
+	seeks := lobby.seeks[:0]
+
+	for index, seek := range lobby.seeks {
+		if !paired[index] {
+			seeks = append(seeks, seek)
+		}
+	}
+
+	lobby.seeks = seeks
+
+	return seekPairs, errors.Join(errs...)
+}
+
+// reportError passes passed error of the matching to the error callback if it is set.
+func (lobby *Lobby) reportError(err error) {
+	if lobby.options.OnError != nil {
+		lobby.options.OnError(err)
+	}
+}
+
+// newPairing assigns the colors to passed seeks according to their preferences and creates the game with the clock.
+func (lobby *Lobby) newPairing(first, second Seek) (*Pairing, error) {
+	white, black := first, second
+
+	switch {
+	case first.Color == piece.ColorBlack || second.Color == piece.ColorWhite:
+		white, black = second, first
+	case first.Color == piece.ColorNil && second.Color == piece.ColorNil && rand.IntN(2) == 0: //nolint:mnd // Two colors.
+		white, black = second, first
+	}
+
+	variant, err := game.NewVariantFromName(lobby.options.Engine, first.Variant)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantFromName(%q): %w", first.Variant, err)
+	}
+
+	g, err := game.NewGameVariantStart(variant)
+	if err != nil {
+		return nil, fmt.Errorf("NewGameVariantStart(): %w", err)
+	}
+
+	pairing := &Pairing{White: white, Black: black, Game: g}
+
+	pairing.Clock, err = clock.NewClock(first.TimeControl, clock.Options{
+		TimeSource: lobby.options.TimeSource,
+		OnFlag: func(color piece.Color) {
+			if lobby.options.OnFlag != nil {
+				lobby.options.OnFlag(pairing, color)
+			}
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("NewClock(): %w", err)
+	}
+
+	return pairing, nil
+}
+
+// checkPair checks that passed pair has the distinct indexes of the seeks, which are not paired yet.
+func checkPair(pair Pair, paired []bool) error {
+	for _, index := range pair {
+		if index < 0 || index >= len(paired) {
+			return fmt.Errorf("index %d is out of %d seeks", index, len(paired))
+		}
+
+		if paired[index] {
+			return fmt.Errorf("seek %d is already paired", index)
+		}
+	}
+
+	if pair[0] == pair[1] {
+		return fmt.Errorf("seek %d is paired with itself", pair[0])
+	}
+
+	return nil
+}
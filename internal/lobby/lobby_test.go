@@ -0,0 +1,196 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// Timeout of the matching in the tests.
+const testTimeout = 5 * time.Second
+
+func TestLobbyPost(t *testing.T) {
+	t.Parallel()
+
+	control := clock.NewTimeControlSuddenDeath(time.Minute)
+
+	tests := []struct {
+		name      string
+		seek      Seek
+		errString string
+	}{
+		{"valid", Seek{Player: "alice", TimeControl: control}, ""},
+		{"no player", Seek{TimeControl: control}, "no player"},
+		{
+			"unknown variant",
+			Seek{Player: "alice", TimeControl: control, Variant: "Chess"},
+			"NewVariantFromName(\"Chess\"): unknown variant",
+		},
+		{"no time control", Seek{Player: "alice"}, "Validate(): no periods"},
+		{
+			"unknown color",
+			Seek{Player: "alice", TimeControl: control, Color: 3},
+			"unknown color <unknown Color=3>",
+		},
+		{
+			"empty rating range",
+			Seek{Player: "alice", TimeControl: control, MinRating: 1600, MaxRating: 1500},
+			"empty rating range 1600-1500",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			lobby := NewLobby(Options{})
+
+			id, err := lobby.Post(test.seek)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("Post(%+v) expected error %q but got %q", test.seek, test.errString, err)
+			}
+
+			if seeks := lobby.Seeks(); err == nil && (len(seeks) != 1 || seeks[0].ID != id || seeks[0].Variant != "Standard") {
+				t.Fatalf("Seeks() expected standard seek %s but got %+v", id, seeks)
+			}
+		})
+	}
+}
+
+func TestLobbyRun(t *testing.T) {
+	t.Parallel()
+
+	pairings := make(chan *Pairing)
+
+	lobby := NewLobby(Options{
+		Engine:   game.Engine{},
+		Strategy: ClosestRatingStrategy{},
+		OnPairing: func(pairing *Pairing) {
+			pairings <- pairing
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+
+	go func() {
+		errs <- lobby.Run(ctx)
+	}()
+
+	control := clock.NewTimeControlFischer(3*time.Minute, 2*time.Second)
+
+	cancelledID, err := lobby.Post(Seek{Player: "alice", Rating: 1500, TimeControl: control})
+	if err != nil {
+		t.Fatalf("Post(alice): %v", err)
+	}
+
+	if !lobby.Cancel(cancelledID) || lobby.Cancel(cancelledID) {
+		t.Fatalf("Cancel(%s) expected to remove the seek once", cancelledID)
+	}
+
+	seeks := []Seek{
+		{Player: "bob", Rating: 1500, TimeControl: control, Variant: "Atomic", Color: piece.ColorBlack},
+		{Player: "carol", Rating: 1600, TimeControl: control, Variant: "Atomic"},
+	}
+
+	for _, seek := range seeks {
+		if _, err := lobby.Post(seek); err != nil {
+			t.Fatalf("Post(%s): %v", seek.Player, err)
+		}
+	}
+
+	select {
+	case pairing := <-pairings:
+		if pairing.White.Player != "carol" || pairing.Black.Player != "bob" {
+			t.Fatalf("OnPairing() expected carol with white and bob with black but got %+v", pairing)
+		}
+
+		if pairing.Game.Variant().Name() != "Atomic" || pairing.Clock.Remaining(piece.ColorWhite) != 3*time.Minute {
+			t.Fatalf("OnPairing() expected Atomic game with 3 minutes clock but got %+v", pairing)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("OnPairing() was not called")
+	}
+
+	if seeks := lobby.Seeks(); len(seeks) != 0 {
+		t.Fatalf("Seeks() expected no seeks after the pairing but got %+v", seeks)
+	}
+
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() expected context cancellation but got %v", err)
+	}
+}
+
+// testStrategy returns the same pairs for any seeks.
+type testStrategy []Pair
+
+// Pair returns the pairs of the test strategy.
+func (strategy testStrategy) Pair(_ []Seek) []Pair {
+	return strategy
+}
+
+func TestLobbyRunInvalidPairs(t *testing.T) {
+	t.Parallel()
+
+	pairings := make(chan *Pairing, 1)
+	errs := make(chan error, 1)
+
+	lobby := NewLobby(Options{
+		Engine:   game.Engine{},
+		Strategy: testStrategy{{0, 2}, {-1, 1}, {1, 1}, {2, 1}, {1, 3}},
+		OnPairing: func(pairing *Pairing) {
+			pairings <- pairing
+		},
+		OnError: func(err error) {
+			errs <- err
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	control := clock.NewTimeControlSuddenDeath(time.Minute)
+
+	// The matching runs after the posts, so the strategy sees all seeks.
+	for _, player := range [...]string{"alice", "bob", "carol", "dave"} {
+		if _, err := lobby.Post(Seek{Player: player, TimeControl: control}); err != nil {
+			t.Fatalf("Post(%s): %v", player, err)
+		}
+	}
+
+	go func() {
+		_ = lobby.Run(ctx)
+	}()
+
+	expectedErr := "takePairs(): checkPair([-1 1]): index -1 is out of 4 seeks\n" +
+		"checkPair([1 1]): seek 1 is paired with itself\n" +
+		"checkPair([2 1]): seek 2 is already paired"
+
+	select {
+	case err := <-errs:
+		if err.Error() != expectedErr {
+			t.Fatalf("OnError() expected error %q but got %q", expectedErr, err)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("OnError() was not called")
+	}
+
+	for range 2 {
+		select {
+		case <-pairings:
+		case <-time.After(testTimeout):
+			t.Fatal("OnPairing() was not called for the valid pair")
+		}
+	}
+
+	if seeks := lobby.Seeks(); len(seeks) != 0 {
+		t.Fatalf("Seeks() expected no seeks after the pairings but got %+v", seeks)
+	}
+}
@@ -0,0 +1,58 @@
+package lobby
+
+import (
+	"slices"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// Seek is the request of the player to play the game.
+type Seek struct {
+	// ID is assigned by the lobby on the post.
+	ID     string
+	Player string
+	// Rating of the player, which is compared with the rating range of the opponent.
+	Rating int
+	// TimeControl of the game. Both seeks must have the same time control.
+	TimeControl clock.TimeControl
+	// Color preference. piece.ColorNil means any color.
+	Color piece.Color
+	// Name of the variant as in the PGN Variant tag. Empty name means the standard chess.
+	Variant string
+	Rated   bool
+	// Accepted range of the opponent rating. Zero maximum means no upper limit.
+	MinRating int
+	MaxRating int
+}
+
+// CheckSeeksCompatible checks that passed seeks of different players may be paired: the variant, the time control
+// and the rated flag are the same, the color preferences do not conflict and each rating is in the range of the
+// opponent.
+func CheckSeeksCompatible(first, second Seek) bool {
+	if first.Player == second.Player || first.Variant != second.Variant || first.Rated != second.Rated {
+		return false
+	}
+
+	if !checkTimeControlsEqual(first.TimeControl, second.TimeControl) {
+		return false
+	}
+
+	if first.Color != piece.ColorNil && first.Color == second.Color {
+		return false
+	}
+
+	return first.checkRatingAccepted(second.Rating) && second.checkRatingAccepted(first.Rating)
+}
+
+// checkRatingAccepted checks that passed rating of the opponent is in the rating range of the seek.
+func (seek Seek) checkRatingAccepted(rating int) bool {
+	return rating >= seek.MinRating && (seek.MaxRating == 0 || rating <= seek.MaxRating)
+}
+
+// checkTimeControlsEqual checks that passed time controls have the same periods and delay.
+func checkTimeControlsEqual(first, second clock.TimeControl) bool {
+	return slices.Equal(first.Periods, second.Periods) &&
+		first.Delay == second.Delay &&
+		first.DelayMode == second.DelayMode
+}
@@ -0,0 +1,114 @@
+package lobby
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+func TestCheckSeeksCompatible(t *testing.T) {
+	t.Parallel()
+
+	blitz := clock.NewTimeControlFischer(3*time.Minute, 2*time.Second)
+	base := Seek{Player: "alice", Rating: 1500, TimeControl: blitz, Variant: "Standard", Rated: true}
+
+	tests := []struct {
+		name       string
+		second     Seek
+		compatible bool
+	}{
+		{"same", Seek{Player: "bob", Rating: 1600, TimeControl: blitz, Variant: "Standard", Rated: true}, true},
+		{"same player", base, false},
+		{
+			"other variant",
+			Seek{Player: "bob", TimeControl: blitz, Variant: "Atomic", Rated: true},
+			false,
+		},
+		{
+			"other time control",
+			Seek{Player: "bob", TimeControl: clock.NewTimeControlFischer(3*time.Minute, 0), Variant: "Standard", Rated: true},
+			false,
+		},
+		{
+			"other delay",
+			Seek{
+				Player:      "bob",
+				TimeControl: clock.NewTimeControlBronstein(3*time.Minute, 2*time.Second),
+				Variant:     "Standard",
+				Rated:       true,
+			},
+			false,
+		},
+		{"casual", Seek{Player: "bob", TimeControl: blitz, Variant: "Standard"}, false},
+		{
+			"rating below range",
+			Seek{Player: "bob", Rating: 1500, TimeControl: blitz, Variant: "Standard", Rated: true, MinRating: 1600},
+			false,
+		},
+		{
+			"rating above range",
+			Seek{Player: "bob", Rating: 1500, TimeControl: blitz, Variant: "Standard", Rated: true, MaxRating: 1400},
+			false,
+		},
+		{
+			"rating in range",
+			Seek{
+				Player:      "bob",
+				Rating:      1500,
+				TimeControl: blitz,
+				Variant:     "Standard",
+				Rated:       true,
+				MinRating:   1400,
+				MaxRating:   1500,
+			},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if compatible := CheckSeeksCompatible(base, test.second); compatible != test.compatible {
+				t.Fatalf("CheckSeeksCompatible(%+v, %+v) expected %t", base, test.second, test.compatible)
+			}
+
+			if compatible := CheckSeeksCompatible(test.second, base); compatible != test.compatible {
+				t.Fatalf("CheckSeeksCompatible(%+v, %+v) expected %t", test.second, base, test.compatible)
+			}
+		})
+	}
+}
+
+func TestCheckSeeksCompatibleColors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		first      piece.Color
+		second     piece.Color
+		compatible bool
+	}{
+		{piece.ColorNil, piece.ColorNil, true},
+		{piece.ColorWhite, piece.ColorNil, true},
+		{piece.ColorNil, piece.ColorBlack, true},
+		{piece.ColorWhite, piece.ColorBlack, true},
+		{piece.ColorWhite, piece.ColorWhite, false},
+		{piece.ColorBlack, piece.ColorBlack, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.first.String()+"/"+test.second.String(), func(t *testing.T) {
+			t.Parallel()
+
+			control := clock.NewTimeControlSuddenDeath(time.Minute)
+			first := Seek{Player: "alice", TimeControl: control, Color: test.first}
+			second := Seek{Player: "bob", TimeControl: control, Color: test.second}
+
+			if compatible := CheckSeeksCompatible(first, second); compatible != test.compatible {
+				t.Fatalf("CheckSeeksCompatible(%s, %s) expected %t", test.first, test.second, test.compatible)
+			}
+		})
+	}
+}
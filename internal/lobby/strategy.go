@@ -0,0 +1,65 @@
+package lobby
+
+// Pair is the pair of indexes of the compatible seeks.
+type Pair [2]int
+
+// Strategy chooses which compatible seeks are paired.
+type Strategy interface {
+	// Pair returns the pairs of indexes of passed seeks, which are ordered by the post time. Each seek must be in one
+	// pair at most and the seeks of each pair must be compatible.
+	Pair(seeks []Seek) []Pair
+}
+
+// FirstComeStrategy pairs each seek, starting from the oldest one, with the oldest compatible seek.
+type FirstComeStrategy struct{}
+
+// Pair returns the pairs of the oldest compatible seeks.
+func (FirstComeStrategy) Pair(seeks []Seek) []Pair {
+	return pairSeeks(seeks, func(_, _ Seek) int {
+		return 0
+	})
+}
+
+// ClosestRatingStrategy pairs each seek, starting from the oldest one, with the compatible seek of the closest rating.
+// The older seek is chosen among the seeks with the same rating difference.
+type ClosestRatingStrategy struct{}
+
+// Pair returns the pairs of the compatible seeks with the closest ratings.
+func (ClosestRatingStrategy) Pair(seeks []Seek) []Pair {
+	return pairSeeks(seeks, func(first, second Seek) int {
+		return max(first.Rating-second.Rating, second.Rating-first.Rating)
+	})
+}
+
+// pairSeeks pairs each seek, starting from the oldest one, with the compatible seek with the lowest cost. The older
+// seek is chosen among the seeks with the same cost.
+func pairSeeks(seeks []Seek, calcCost func(first, second Seek) int) []Pair {
+	var pairs []Pair
+
+	paired := make([]bool, len(seeks))
+
+	for first := range seeks {
+		if paired[first] {
+			continue
+		}
+
+		best, bestCost := -1, 0
+
+		for second := first + 1; second < len(seeks); second++ {
+			if paired[second] || !CheckSeeksCompatible(seeks[first], seeks[second]) {
+				continue
+			}
+
+			if cost := calcCost(seeks[first], seeks[second]); best == -1 || cost < bestCost {
+				best, bestCost = second, cost
+			}
+		}
+
+		if best != -1 {
+			paired[first], paired[best] = true, true
+			pairs = append(pairs, Pair{first, best})
+		}
+	}
+
+	return pairs
+}
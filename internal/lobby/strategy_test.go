@@ -0,0 +1,48 @@
+package lobby
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+)
+
+func TestStrategyPair(t *testing.T) {
+	t.Parallel()
+
+	blitz := clock.NewTimeControlSuddenDeath(5 * time.Minute)
+	bullet := clock.NewTimeControlSuddenDeath(time.Minute)
+
+	seeks := []Seek{
+		{Player: "alice", Rating: 1500, TimeControl: blitz},
+		{Player: "bob", Rating: 2000, TimeControl: blitz},
+		{Player: "carol", Rating: 1000, TimeControl: bullet},
+		{Player: "dave", Rating: 1550, TimeControl: blitz},
+		{Player: "erin", Rating: 1100, TimeControl: bullet},
+		{Player: "alice", Rating: 1500, TimeControl: bullet},
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		seeks    []Seek
+		pairs    []Pair
+	}{
+		{"first come", FirstComeStrategy{}, seeks, []Pair{{0, 1}, {2, 4}}},
+		{"closest rating", ClosestRatingStrategy{}, seeks, []Pair{{0, 3}, {2, 4}}},
+		{"no seeks", ClosestRatingStrategy{}, nil, nil},
+		{"unpaired seek", FirstComeStrategy{}, seeks[:3], []Pair{{0, 1}}},
+		{"same player", FirstComeStrategy{}, []Seek{seeks[5], seeks[5]}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if pairs := test.strategy.Pair(test.seeks); !reflect.DeepEqual(pairs, test.pairs) {
+				t.Fatalf("Pair() expected %v but got %v", test.pairs, pairs)
+			}
+		})
+	}
+}
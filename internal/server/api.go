@@ -0,0 +1,114 @@
+package server
+
+// Types of the events sent to the WebSocket clients.
+const (
+	// EventTypeState is sent first after the connection and contains the whole game.
+	EventTypeState = "state"
+	// EventTypeMove is sent after each move.
+	EventTypeMove = "move"
+	// EventTypeClock is sent after each move and the flag fall of the game with the time control.
+	EventTypeClock = "clock"
+	// EventTypeResult is sent once after the game end, including the flag fall.
+	EventTypeResult = "result"
+	// EventTypeError is sent only to the client, whose message was rejected.
+	EventTypeError = "error"
+)
+
+// Types of the messages received from the WebSocket clients.
+const (
+	// MessageTypeMove makes the move in UCI or SAN. Only the player with the token of the active color may move.
+	MessageTypeMove = "move"
+	// MessageTypePremove queues the move in UCI, which is played on the next turn of the player if it is possible.
+	MessageTypePremove = "premove"
+	// MessageTypeConditional adds the line of the conditional moves in SAN: the pairs of the expected move of the
+	// opponent and the reply, which is played if the opponent played the expected move.
+	MessageTypeConditional = "conditional"
+)
+
+// GameInfo is the public information about the game.
+type GameInfo struct {
+	ID      string `json:"id"`
+	Variant string `json:"variant"`
+	// Names of the players. Empty name means that the seat is free.
+	White string `json:"white"`
+	Black string `json:"black"`
+	// FEN of the current position.
+	FEN string `json:"fen"`
+	// UCI of the made moves.
+	Moves []string `json:"moves"`
+	// PGN result, which is "*" if the game is in progress.
+	Result      string `json:"result"`
+	Termination string `json:"termination,omitempty"`
+	// Clock of the game. It is nil if the game has no time control.
+	Clock *ClockInfo `json:"clock,omitempty"`
+}
+
+// ClockInfo is the state of the game clock.
+type ClockInfo struct {
+	// Time control in the PGN TimeControl tag format.
+	TimeControl string `json:"timeControl"`
+	// Remaining time of the players in milliseconds.
+	White int64 `json:"white"`
+	Black int64 `json:"black"`
+	// Color of the running clock. Empty color means that the clock is not running.
+	Active string `json:"active,omitempty"`
+}
+
+// CreateGameRequest is the body of the game creation request.
+type CreateGameRequest struct {
+	Player string `json:"player"`
+	// Color of the creator: "white" or "black". Empty color means white.
+	Color string `json:"color"`
+	// Name of the variant as in the PGN Variant tag. Empty name means the standard chess.
+	Variant string `json:"variant"`
+	// FEN of the start position. Empty FEN means the start position of the variant.
+	FEN string `json:"fen"`
+	// Time control in the PGN TimeControl tag format, for example, "180+2". Empty time control means no clock.
+	TimeControl string `json:"timeControl"`
+}
+
+// JoinGameRequest is the body of the game join request.
+type JoinGameRequest struct {
+	Player string `json:"player"`
+}
+
+// SeatResponse is returned to the player, who created or joined the game.
+//
+// The token must be passed in the "token" query parameter of the WebSocket URL to make moves.
+type SeatResponse struct {
+	Game  GameInfo `json:"game"`
+	Color string   `json:"color"`
+	Token string   `json:"token"`
+}
+
+// ErrorResponse is returned if the request failed.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Event is the message sent to the WebSocket clients.
+type Event struct {
+	Type string `json:"type"`
+	// Game is set in the state event.
+	Game *GameInfo `json:"game,omitempty"`
+	// Move and the position after it are set in the move event.
+	UCI string `json:"uci,omitempty"`
+	SAN string `json:"san,omitempty"`
+	FEN string `json:"fen,omitempty"`
+	// Clock is set in the clock event.
+	Clock *ClockInfo `json:"clock,omitempty"`
+	// Result and the reason of the game end are set in the result event.
+	Result      string `json:"result,omitempty"`
+	Termination string `json:"termination,omitempty"`
+	// Error is set in the error event.
+	Error string `json:"error,omitempty"`
+}
+
+// Message is the message received from the WebSocket clients.
+type Message struct {
+	Type string `json:"type"`
+	// Move in UCI or SAN for the move message and in UCI for the premove message.
+	Move string `json:"move"`
+	// Moves in SAN for the conditional message.
+	Moves []string `json:"moves,omitempty"`
+}
@@ -0,0 +1,458 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/broadcast"
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Count of random bytes in the player token.
+const roomTokenSize = 16
+
+var (
+	// Mapping of colors to their names in the API.
+	roomColorNames = map[piece.Color]string{
+		piece.ColorWhite: "white",
+		piece.ColorBlack: "black",
+	}
+
+	// Mapping of terminations to their names in the API.
+	roomTerminationNames = map[game.Termination]string{
+		game.TerminationCheckmate:                     "checkmate",
+		game.TerminationStalemate:                     "stalemate",
+		game.TerminationInsufficientMaterial:          "insufficient material",
+		game.TerminationFiftyMoves:                    "fifty moves",
+		game.TerminationRepetition:                    "repetition",
+		game.TerminationAdjudication:                  "adjudication",
+		game.TerminationVariantEnd:                    "variant end",
+		game.TerminationTimeout:                       "timeout",
+		game.TerminationTimeoutVsInsufficientMaterial: "timeout vs insufficient material",
+		game.TerminationResignation:                   "resignation",
+		game.TerminationAgreement:                     "agreement",
+	}
+)
+
+// seat is the player of one color.
+type seat struct {
+	name  string
+	token string
+}
+
+// room is the game with its players. The events of the game are sent to the WebSocket subscribers by the hub.
+type room struct {
+	id    string
+	hub   *broadcast.Hub
+	mu    sync.Mutex
+	game  *game.Game
+	seats map[piece.Color]seat
+	// Clock of the game, which is nil if the game has no time control.
+	clock       *clock.Clock
+	timeControl clock.TimeControl
+}
+
+// newRoom creates a new room with passed game and without players, which sends the clock events through passed hub.
+func newRoom(id string, g *game.Game, hub *broadcast.Hub) *room {
+	return &room{
+		id:    id,
+		hub:   hub,
+		game:  g,
+		seats: make(map[piece.Color]seat),
+	}
+}
+
+// setTimeControl creates the clock of the room with passed time control and time source. The clock of the opponent
+// starts after the first move and the game is ended on the flag fall.
+func (room *room) setTimeControl(control clock.TimeControl, timeSource clock.TimeSource) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	roomClock, err := clock.NewClock(control, clock.Options{TimeSource: timeSource, OnFlag: room.timeout})
+	if err != nil {
+		return fmt.Errorf("NewClock(): %w", err)
+	}
+
+	room.clock, room.timeControl = roomClock, control
+
+	return nil
+}
+
+// info returns the public information about the room.
+func (room *room) info() (GameInfo, error) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	return room.infoLocked()
+}
+
+// newStateEvent returns the state event of the WebSocket clients with the game in passed snapshot of the hub.
+func (room *room) newStateEvent(snapshot broadcast.Snapshot) (Event, error) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	info, err := room.newGameInfoLocked(snapshot)
+	if err != nil {
+		return Event{}, fmt.Errorf("newGameInfoLocked(): %w", err)
+	}
+
+	return Event{Type: EventTypeState, Game: &info}, nil
+}
+
+// join seats the player with passed name on passed color or on the free color if passed color is piece.ColorNil.
+//
+// Returns the seated color and the token of the player.
+func (room *room) join(name string, color piece.Color) (piece.Color, string, error) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if color == piece.ColorNil {
+		color = piece.ColorWhite
+		if _, ok := room.seats[color]; ok {
+			color = piece.ColorBlack
+		}
+	}
+
+	if _, ok := roomColorNames[color]; !ok {
+		return piece.ColorNil, "", fmt.Errorf("unknown color %s", color)
+	}
+
+	if _, ok := room.seats[color]; ok {
+		return piece.ColorNil, "", errors.New("seat is taken")
+	}
+
+	token, err := newRoomToken()
+	if err != nil {
+		return piece.ColorNil, "", fmt.Errorf("newRoomToken(): %w", err)
+	}
+
+	room.seats[color] = seat{name: name, token: token}
+
+	return color, token, nil
+}
+
+// move makes the move in UCI or SAN from the player with passed token and sends the clock event to the subscribers.
+// Then the queued moves of the players are played.
+func (room *room) move(token, text string) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if len(room.seats) != len(roomColorNames) {
+		return errors.New("waiting for the opponent")
+	}
+
+	pos := room.game.Position()
+
+	if token == "" || room.seats[pos.ActiveColor()].token != token {
+		return errors.New("not your turn")
+	}
+
+	m, err := calcMoveFromText(room.game.Variant(), pos, text)
+	if err != nil {
+		return fmt.Errorf("calcMoveFromText(%q): %w", text, err)
+	}
+
+	result, _, err := room.game.Result()
+	if err != nil {
+		return fmt.Errorf("Result(): %w", err)
+	}
+
+	if result != game.ResultNil {
+		return errors.New("game is over")
+	}
+
+	if err := room.game.Move(m); err != nil {
+		return fmt.Errorf("Move(%+v): %w", m, err)
+	}
+
+	// The clock is pressed only after the made move, so the failed move does not credit the increment. The clock is
+	// sent even if the flag has fallen, because the move is already made.
+	var pressErr error
+	if room.clock != nil {
+		pressErr = room.clock.Press(pos.ActiveColor())
+	}
+
+	if err := room.publishClockLocked(); err != nil {
+		return fmt.Errorf("publishClockLocked(): %w", err)
+	}
+
+	if pressErr != nil {
+		return fmt.Errorf("Press(%s): %w", pos.ActiveColor(), pressErr)
+	}
+
+	if err := room.playQueuedMovesLocked(); err != nil {
+		return fmt.Errorf("playQueuedMovesLocked(): %w", err)
+	}
+
+	return nil
+}
+
+// premove queues the premove in UCI of the player with passed token, which waits for the move of the opponent.
+func (room *room) premove(token, uci string) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	color, err := room.findColorLocked(token)
+	if err != nil {
+		return fmt.Errorf("findColorLocked(): %w", err)
+	}
+
+	if err := room.game.Premove(color, uci); err != nil {
+		return fmt.Errorf("Premove(%s, %q): %w", color, uci, err)
+	}
+
+	return nil
+}
+
+// addConditionalMoves adds the line of the conditional moves in SAN of the player with passed token, which waits for
+// the move of the opponent.
+func (room *room) addConditionalMoves(token string, sans []string) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	color, err := room.findColorLocked(token)
+	if err != nil {
+		return fmt.Errorf("findColorLocked(): %w", err)
+	}
+
+	if err := room.game.AddConditionalMoves(color, sans); err != nil {
+		return fmt.Errorf("AddConditionalMoves(%s, %v): %w", color, sans, err)
+	}
+
+	return nil
+}
+
+// playQueuedMovesLocked plays the queued moves of the players while they are possible. The time of the queued moves is
+// not charged.
+//
+// The room must be locked.
+func (room *room) playQueuedMovesLocked() error {
+	for {
+		pos := room.game.Position()
+
+		m, ok, err := room.game.PlayQueuedMove()
+		if err != nil {
+			return fmt.Errorf("PlayQueuedMove(): %w", err)
+		}
+
+		if !ok {
+			return nil
+		}
+
+		if room.clock != nil {
+			if err := room.clock.PressPremove(pos.ActiveColor()); err != nil {
+				return fmt.Errorf("%+v, PressPremove(%s): %w", m, pos.ActiveColor(), err)
+			}
+		}
+
+		if err := room.publishClockLocked(); err != nil {
+			return fmt.Errorf("%+v, publishClockLocked(): %w", m, err)
+		}
+	}
+}
+
+// publishClockLocked stops the clock if the game is over and sends the clock event to the subscribers if the game has
+// the time control.
+//
+// The room must be locked.
+func (room *room) publishClockLocked() error {
+	if room.clock == nil {
+		return nil
+	}
+
+	result, _, err := room.game.Result()
+	if err != nil {
+		return fmt.Errorf("Result(): %w", err)
+	}
+
+	if result != game.ResultNil {
+		room.clock.Stop()
+	}
+
+	white, black := room.clock.Remaining(piece.ColorWhite), room.clock.Remaining(piece.ColorBlack)
+
+	if err := room.hub.PublishClock(room.id, white, black, room.clock.Active()); err != nil {
+		return fmt.Errorf("PublishClock(%s): %w", room.id, err)
+	}
+
+	return nil
+}
+
+// timeout ends the game, because passed color has run out of time, and sends the clock event to the subscribers. The
+// result event is sent by the hub.
+func (room *room) timeout(color piece.Color) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if err := room.game.Timeout(color); err != nil {
+		// The game is already over.
+		return
+	}
+
+	_ = room.publishClockLocked() //nolint:errcheck // The game is over anyway.
+}
+
+// newEvent converts passed hub event to the event of the WebSocket clients.
+//
+// Returns false if the event is not sent to the clients.
+func (room *room) newEvent(event broadcast.Event) (Event, bool, error) {
+	switch event.Type {
+	case broadcast.EventTypeMove:
+		return Event{Type: EventTypeMove, UCI: event.UCI, SAN: event.SAN, FEN: event.FEN}, true, nil
+	case broadcast.EventTypeClock:
+		room.mu.Lock()
+		timeControl := room.timeControl.PGN()
+		room.mu.Unlock()
+
+		return Event{Type: EventTypeClock, Clock: &ClockInfo{
+			TimeControl: timeControl,
+			White:       event.White.Milliseconds(),
+			Black:       event.Black.Milliseconds(),
+			Active:      roomColorNames[event.Active],
+		}}, true, nil
+	case broadcast.EventTypeResult:
+		resultPGN, err := event.Result.PGN()
+		if err != nil {
+			return Event{}, false, fmt.Errorf("%s.PGN(): %w", event.Result, err)
+		}
+
+		return Event{
+			Type:        EventTypeResult,
+			Result:      resultPGN,
+			Termination: roomTerminationNames[event.Termination],
+		}, true, nil
+	case broadcast.EventTypeNil, broadcast.EventTypeTakeback, broadcast.EventTypeChat:
+		// The room does not take back the moves and has no chat.
+		return Event{}, false, nil
+	default:
+		return Event{}, false, fmt.Errorf("unknown event type %s", event.Type)
+	}
+}
+
+// clockInfoLocked returns the state of the clock or nil if the room has no clock.
+//
+// The room must be locked.
+func (room *room) clockInfoLocked() *ClockInfo {
+	if room.clock == nil {
+		return nil
+	}
+
+	return &ClockInfo{
+		TimeControl: room.timeControl.PGN(),
+		White:       room.clock.Remaining(piece.ColorWhite).Milliseconds(),
+		Black:       room.clock.Remaining(piece.ColorBlack).Milliseconds(),
+		Active:      roomColorNames[room.clock.Active()],
+	}
+}
+
+// infoLocked returns the public information about the room.
+//
+// The room must be locked.
+func (room *room) infoLocked() (GameInfo, error) {
+	fen, err := room.game.Position().FEN()
+	if err != nil {
+		return GameInfo{}, fmt.Errorf("FEN(): %w", err)
+	}
+
+	moves := room.game.Moves()
+	snapshot := broadcast.Snapshot{FEN: fen, Moves: make([]string, 0, len(moves))}
+
+	for _, m := range moves {
+		uci, err := m.UCI()
+		if err != nil {
+			return GameInfo{}, fmt.Errorf("UCI(%+v): %w", m, err)
+		}
+
+		snapshot.Moves = append(snapshot.Moves, uci)
+	}
+
+	snapshot.Result, snapshot.Termination, err = room.game.Result()
+	if err != nil {
+		return GameInfo{}, fmt.Errorf("Result(): %w", err)
+	}
+
+	return room.newGameInfoLocked(snapshot)
+}
+
+// newGameInfoLocked returns the public information about the room with the game in passed snapshot.
+//
+// The room must be locked.
+func (room *room) newGameInfoLocked(snapshot broadcast.Snapshot) (GameInfo, error) {
+	resultPGN, err := snapshot.Result.PGN()
+	if err != nil {
+		return GameInfo{}, fmt.Errorf("%s.PGN(): %w", snapshot.Result, err)
+	}
+
+	return GameInfo{
+		ID:          room.id,
+		Variant:     room.game.Variant().Name(),
+		White:       room.seats[piece.ColorWhite].name,
+		Black:       room.seats[piece.ColorBlack].name,
+		FEN:         snapshot.FEN,
+		Moves:       snapshot.Moves,
+		Result:      resultPGN,
+		Termination: roomTerminationNames[snapshot.Termination],
+		Clock:       room.clockInfoLocked(),
+	}, nil
+}
+
+// findColorLocked returns the color of the player with passed token.
+//
+// The room must be locked.
+func (room *room) findColorLocked(token string) (piece.Color, error) {
+	if len(room.seats) != len(roomColorNames) {
+		return piece.ColorNil, errors.New("waiting for the opponent")
+	}
+
+	for color, seat := range room.seats {
+		if token != "" && seat.token == token {
+			return color, nil
+		}
+	}
+
+	return piece.ColorNil, errors.New("not a player")
+}
+
+// calcMoveFromText finds the possible move in passed position by its UCI or SAN.
+func calcMoveFromText(variant game.Variant, pos *position.Position, text string) (move.Move, error) {
+	moves, err := variant.CalcMoves(pos)
+	if err != nil {
+		return move.Move{}, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	for _, m := range moves {
+		uci, err := m.UCI()
+		if err != nil {
+			return move.Move{}, fmt.Errorf("UCI(%+v): %w", m, err)
+		}
+
+		if uci == text {
+			return m, nil
+		}
+	}
+
+	m, err := variant.CalcMoveFromSAN(pos, text)
+	if err != nil {
+		return move.Move{}, fmt.Errorf("CalcMoveFromSAN(%q): %w", text, err)
+	}
+
+	return m, nil
+}
+
+// newRoomToken generates a new random token of the player.
+func newRoomToken() (string, error) {
+	token := make([]byte, roomTokenSize)
+
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("Read(): %w", err)
+	}
+
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,301 @@
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/broadcast"
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+const (
+	// Name of the variant, which is used if the game creation request has no variant.
+	serverDefaultVariant = "Standard"
+
+	// Size of the event buffer of each subscriber. Subscribers, which fill the buffer, are dropped.
+	serverSubscriberBufferSize = 64
+)
+
+// Server serves the REST API to create, join and list games and the WebSocket channel of each game.
+//
+// Endpoints:
+//
+//	GET  /games           lists all games.
+//	POST /games           creates a new game with CreateGameRequest body.
+//	GET  /games/{id}      returns the game.
+//	POST /games/{id}/join joins the game with JoinGameRequest body.
+//	GET  /games/{id}/ws   opens the WebSocket channel of the game.
+type Server struct {
+	engine  game.Engine
+	options Options
+	hub     *broadcast.Hub
+
+	mu     sync.Mutex
+	rooms  map[string]*room
+	nextID uint64
+}
+
+// Options configures the server.
+type Options struct {
+	// Source of the current time and timers of the game clocks. Nil means clock.SystemTimeSource.
+	TimeSource clock.TimeSource
+}
+
+// NewServer creates a new Server without games, which uses passed engine to validate moves.
+func NewServer(engine game.Engine, options Options) (*Server, error) {
+	hub, err := broadcast.NewHub(serverSubscriberBufferSize)
+	if err != nil {
+		return nil, fmt.Errorf("NewHub(%d): %w", serverSubscriberBufferSize, err)
+	}
+
+	return &Server{
+		engine:  engine,
+		options: options,
+		hub:     hub,
+		rooms:   make(map[string]*room),
+		nextID:  1,
+	}, nil
+}
+
+// Handler returns the HTTP handler of the server.
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /games", server.handleListGames)
+	mux.HandleFunc("POST /games", server.handleCreateGame)
+	mux.HandleFunc("GET /games/{id}", server.handleGetGame)
+	mux.HandleFunc("POST /games/{id}/join", server.handleJoinGame)
+	mux.HandleFunc("GET /games/{id}/ws", server.handleGameWebSocket)
+
+	return mux
+}
+
+// handleListGames writes the information about all games in the creation order.
+func (server *Server) handleListGames(writer http.ResponseWriter, _ *http.Request) {
+	server.mu.Lock()
+	rooms := make([]*room, 0, len(server.rooms))
+
+	for _, room := range server.rooms {
+		rooms = append(rooms, room)
+	}
+	server.mu.Unlock()
+
+	slices.SortFunc(rooms, func(first, second *room) int {
+		return compareRoomIDs(first.id, second.id)
+	})
+
+	infos := make([]GameInfo, 0, len(rooms))
+
+	for _, room := range rooms {
+		info, err := room.info()
+		if err != nil {
+			writeError(writer, http.StatusInternalServerError, fmt.Errorf("room %s, info(): %w", room.id, err))
+			return
+		}
+
+		infos = append(infos, info)
+	}
+
+	writeJSON(writer, http.StatusOK, infos)
+}
+
+// handleCreateGame creates a new game and seats its creator.
+func (server *Server) handleCreateGame(writer http.ResponseWriter, request *http.Request) {
+	var body CreateGameRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		writeError(writer, http.StatusBadRequest, fmt.Errorf("Decode(): %w", err))
+		return
+	}
+
+	if body.Player == "" {
+		writeError(writer, http.StatusBadRequest, errors.New("no player"))
+		return
+	}
+
+	color := piece.ColorWhite
+
+	if body.Color != "" {
+		var err error
+
+		color, err = newColorFromName(body.Color)
+		if err != nil {
+			writeError(writer, http.StatusBadRequest, fmt.Errorf("newColorFromName(%q): %w", body.Color, err))
+			return
+		}
+	}
+
+	g, err := server.newGame(body.Variant, body.FEN)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, fmt.Errorf("newGame(%q, %q): %w", body.Variant, body.FEN, err))
+		return
+	}
+
+	room := newRoom("", g, server.hub)
+
+	if body.TimeControl != "" {
+		control, err := clock.NewTimeControlFromPGN(body.TimeControl)
+		if err != nil {
+			writeError(writer, http.StatusBadRequest, fmt.Errorf("NewTimeControlFromPGN(%q): %w", body.TimeControl, err))
+			return
+		}
+
+		if err := room.setTimeControl(control, server.options.TimeSource); err != nil {
+			writeError(writer, http.StatusBadRequest, fmt.Errorf("setTimeControl(%q): %w", body.TimeControl, err))
+			return
+		}
+	}
+
+	server.mu.Lock()
+	room.id = strconv.FormatUint(server.nextID, 10)
+
+	// The game is added to the hub before the room, so the subscribers of the found room always find the game.
+	if err := server.hub.AddGame(room.id, g); err != nil {
+		server.mu.Unlock()
+		writeError(writer, http.StatusInternalServerError, fmt.Errorf("AddGame(%s): %w", room.id, err))
+
+		return
+	}
+
+	server.rooms[room.id] = room
+	server.nextID++
+	server.mu.Unlock()
+
+	writeSeat(writer, http.StatusCreated, room, body.Player, color)
+}
+
+// handleGetGame writes the information about the game.
+func (server *Server) handleGetGame(writer http.ResponseWriter, request *http.Request) {
+	room, ok := server.findRoom(request.PathValue("id"))
+	if !ok {
+		writeError(writer, http.StatusNotFound, errors.New("game not found"))
+		return
+	}
+
+	info, err := room.info()
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, fmt.Errorf("info(): %w", err))
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, info)
+}
+
+// handleJoinGame seats the player on the free color of the game.
+func (server *Server) handleJoinGame(writer http.ResponseWriter, request *http.Request) {
+	room, ok := server.findRoom(request.PathValue("id"))
+	if !ok {
+		writeError(writer, http.StatusNotFound, errors.New("game not found"))
+		return
+	}
+
+	var body JoinGameRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		writeError(writer, http.StatusBadRequest, fmt.Errorf("Decode(): %w", err))
+		return
+	}
+
+	if body.Player == "" {
+		writeError(writer, http.StatusBadRequest, errors.New("no player"))
+		return
+	}
+
+	writeSeat(writer, http.StatusOK, room, body.Player, piece.ColorNil)
+}
+
+// newGame creates a new game of the variant with passed name from passed FEN. Empty name means the standard chess and
+// empty FEN means the start position of the variant.
+func (server *Server) newGame(variantName, fen string) (*game.Game, error) {
+	if variantName == "" {
+		variantName = serverDefaultVariant
+	}
+
+	variant, err := game.NewVariantFromName(server.engine, variantName)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantFromName(%q): %w", variantName, err)
+	}
+
+	if fen == "" {
+		g, err := game.NewGameVariantStart(variant)
+		if err != nil {
+			return nil, fmt.Errorf("NewGameVariantStart(): %w", err)
+		}
+
+		return g, nil
+	}
+
+	pos, err := position.NewPositionFromFEN(fen)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+	}
+
+	return game.NewGame(variant, []*position.Position{pos}, nil), nil
+}
+
+// findRoom returns the room with passed ID.
+func (server *Server) findRoom(id string) (*room, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	room, ok := server.rooms[id]
+
+	return room, ok
+}
+
+// newColorFromName parses the color name in the API.
+//
+// Name argument examples: "white", "black".
+func newColorFromName(name string) (piece.Color, error) {
+	for color, colorName := range roomColorNames {
+		if colorName == name {
+			return color, nil
+		}
+	}
+
+	return piece.ColorNil, errors.New("unknown color")
+}
+
+// compareRoomIDs compares the numeric room IDs, so the rooms are sorted in the creation order.
+func compareRoomIDs(first, second string) int {
+	return cmp.Or(cmp.Compare(len(first), len(second)), cmp.Compare(first, second))
+}
+
+// writeSeat seats the player in the room and writes the seat with passed status.
+func writeSeat(writer http.ResponseWriter, status int, room *room, player string, color piece.Color) {
+	color, token, err := room.join(player, color)
+	if err != nil {
+		writeError(writer, http.StatusConflict, fmt.Errorf("join(%q): %w", player, err))
+		return
+	}
+
+	info, err := room.info()
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, fmt.Errorf("info(): %w", err))
+		return
+	}
+
+	writeJSON(writer, status, SeatResponse{Game: info, Color: roomColorNames[color], Token: token})
+}
+
+// writeJSON writes passed value as JSON body with passed status.
+func writeJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	_ = json.NewEncoder(writer).Encode(value) //nolint:errchkjson // The client may be gone, nothing to do.
+}
+
+// writeError writes passed error as ErrorResponse with passed status.
+func writeError(writer http.ResponseWriter, status int, err error) {
+	writeJSON(writer, status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,393 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/rylenko/limbo/pkg/chess/broadcast"
+	"github.com/rylenko/limbo/pkg/chess/clock"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// Timeout of the WebSocket operations in the tests.
+const testTimeout = 5 * time.Second
+
+func TestServerREST(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestHTTPServer(t, Options{})
+
+	var created SeatResponse
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{Player: "alice", Color: "black"},
+		http.StatusCreated, &created)
+
+	if created.Color != "black" || created.Token == "" || created.Game.Black != "alice" || created.Game.White != "" {
+		t.Fatalf("POST /games expected black seat of alice but got %+v", created)
+	}
+
+	var joined SeatResponse
+	testRequest(t, httpServer, http.MethodPost, "/games/"+created.Game.ID+"/join", JoinGameRequest{Player: "bob"},
+		http.StatusOK, &joined)
+
+	if joined.Color != "white" || joined.Token == created.Token || joined.Game.White != "bob" {
+		t.Fatalf("POST /games/{id}/join expected white seat of bob but got %+v", joined)
+	}
+
+	var errorResponse ErrorResponse
+	testRequest(t, httpServer, http.MethodPost, "/games/"+created.Game.ID+"/join", JoinGameRequest{Player: "carol"},
+		http.StatusConflict, &errorResponse)
+	testRequest(t, httpServer, http.MethodPost, "/games/404/join", JoinGameRequest{Player: "carol"},
+		http.StatusNotFound, &errorResponse)
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{Player: "carol", Variant: "Chess"},
+		http.StatusBadRequest, &errorResponse)
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{}, http.StatusBadRequest, &errorResponse)
+
+	var hill SeatResponse
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{
+		Player:  "carol",
+		Variant: "King of the Hill",
+		FEN:     "4k3/8/8/8/8/8/8/4K3 w - - 0 1",
+	}, http.StatusCreated, &hill)
+
+	var games []GameInfo
+	testRequest(t, httpServer, http.MethodGet, "/games", nil, http.StatusOK, &games)
+
+	if len(games) != 2 || games[0].ID != created.Game.ID || games[1].ID != hill.Game.ID {
+		t.Fatalf("GET /games expected games %s and %s but got %+v", created.Game.ID, hill.Game.ID, games)
+	}
+
+	var info GameInfo
+	testRequest(t, httpServer, http.MethodGet, "/games/"+hill.Game.ID, nil, http.StatusOK, &info)
+
+	if info.Variant != "King of the Hill" || info.FEN != "4k3/8/8/8/8/8/8/4K3 w - - 0 1" || info.Result != "*" {
+		t.Fatalf("GET /games/{id} expected King of the Hill game in progress but got %+v", info)
+	}
+}
+
+func TestServerWebSocket(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestHTTPServer(t, Options{})
+
+	var white, black SeatResponse
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{Player: "alice"}, http.StatusCreated, &white)
+	testRequest(t, httpServer, http.MethodPost, "/games/"+white.Game.ID+"/join", JoinGameRequest{Player: "bob"},
+		http.StatusOK, &black)
+
+	whiteConn := testDial(t, httpServer, white.Game.ID, white.Token)
+	blackConn := testDial(t, httpServer, white.Game.ID, black.Token)
+	spectatorConn := testDial(t, httpServer, white.Game.ID, "")
+
+	for _, conn := range []*websocket.Conn{whiteConn, blackConn, spectatorConn} {
+		if event := testReadEvent(t, conn); event.Type != EventTypeState || event.Game == nil || event.Game.Black != "bob" {
+			t.Fatalf("Read() expected state event with bob but got %+v", event)
+		}
+	}
+
+	testWriteMessage(t, blackConn, Message{Type: MessageTypeMove, Move: "e7e5"})
+
+	if event := testReadEvent(t, blackConn); event.Type != EventTypeError {
+		t.Fatalf("Read() expected error event but got %+v", event)
+	}
+
+	testWriteMessage(t, spectatorConn, Message{Type: MessageTypeMove, Move: "e2e4"})
+
+	if event := testReadEvent(t, spectatorConn); event.Type != EventTypeError {
+		t.Fatalf("Read() expected error event but got %+v", event)
+	}
+
+	moves := []struct {
+		conn *websocket.Conn
+		move string
+		uci  string
+		san  string
+	}{
+		{whiteConn, "f3", "f2f3", "f3"},
+		{blackConn, "e7e5", "e7e5", "e5"},
+		{whiteConn, "g2g4", "g2g4", "g4"},
+		{blackConn, "Qh4#", "d8h4", "Qh4#"},
+	}
+
+	for _, move := range moves {
+		testWriteMessage(t, move.conn, Message{Type: MessageTypeMove, Move: move.move})
+
+		for _, conn := range []*websocket.Conn{whiteConn, blackConn, spectatorConn} {
+			event := testReadEvent(t, conn)
+			if event.Type != EventTypeMove || event.UCI != move.uci || event.SAN != move.san || event.FEN == "" {
+				t.Fatalf("Read() expected move event %s, %s but got %+v", move.uci, move.san, event)
+			}
+		}
+	}
+
+	for _, conn := range []*websocket.Conn{whiteConn, blackConn, spectatorConn} {
+		event := testReadEvent(t, conn)
+		if event.Type != EventTypeResult || event.Result != "0-1" || event.Termination != "checkmate" {
+			t.Fatalf("Read() expected result event 0-1 by checkmate but got %+v", event)
+		}
+	}
+
+	testWriteMessage(t, whiteConn, Message{Type: MessageTypeMove, Move: "a2a3"})
+
+	if event := testReadEvent(t, whiteConn); event.Type != EventTypeError {
+		t.Fatalf("Read() expected error event after the game end but got %+v", event)
+	}
+}
+
+func TestServerQueuedMoves(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestHTTPServer(t, Options{})
+
+	var white, black SeatResponse
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{Player: "alice"}, http.StatusCreated, &white)
+	testRequest(t, httpServer, http.MethodPost, "/games/"+white.Game.ID+"/join", JoinGameRequest{Player: "bob"},
+		http.StatusOK, &black)
+
+	whiteConn := testDial(t, httpServer, white.Game.ID, white.Token)
+	blackConn := testDial(t, httpServer, white.Game.ID, black.Token)
+
+	for _, conn := range []*websocket.Conn{whiteConn, blackConn} {
+		if event := testReadEvent(t, conn); event.Type != EventTypeState {
+			t.Fatalf("Read() expected state event but got %+v", event)
+		}
+	}
+
+	testWriteMessage(t, whiteConn, Message{Type: MessageTypePremove, Move: "g1f3"})
+
+	if event := testReadEvent(t, whiteConn); event.Type != EventTypeError {
+		t.Fatalf("Read() expected error event for the premove on the own turn but got %+v", event)
+	}
+
+	testWriteMessage(t, blackConn, Message{Type: MessageTypeConditional, Moves: []string{"e4", "c5", "Nf3", "d6"}})
+	testWriteMessage(t, blackConn, Message{Type: MessageTypePremove, Move: "b8c6"})
+
+	// The messages of the connection are handled in order, so the error of the move out of turn means that the queued
+	// moves are added.
+	testWriteMessage(t, blackConn, Message{Type: MessageTypeMove, Move: "e7e5"})
+
+	event := testReadEvent(t, blackConn)
+	if event.Type != EventTypeError || !strings.Contains(event.Error, "not your turn") {
+		t.Fatalf("Read() expected error event for the move out of turn but got %+v", event)
+	}
+
+	testWriteMessage(t, whiteConn, Message{Type: MessageTypeMove, Move: "e2e4"})
+
+	for _, san := range [...]string{"e4", "c5"} {
+		if event := testReadEvent(t, blackConn); event.Type != EventTypeMove || event.SAN != san {
+			t.Fatalf("Read() expected move event %s but got %+v", san, event)
+		}
+	}
+
+	testWriteMessage(t, whiteConn, Message{Type: MessageTypeMove, Move: "d2d4"})
+
+	for _, san := range [...]string{"d4", "Nc6"} {
+		if event := testReadEvent(t, blackConn); event.Type != EventTypeMove || event.SAN != san {
+			t.Fatalf("Read() expected move event %s but got %+v", san, event)
+		}
+	}
+}
+
+func TestServerTimeout(t *testing.T) {
+	t.Parallel()
+
+	timeSource := clock.NewManualTimeSource(time.Time{})
+
+	httpServer := newTestHTTPServer(t, Options{TimeSource: timeSource})
+
+	var errorResponse ErrorResponse
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{Player: "alice", TimeControl: "5m"},
+		http.StatusBadRequest, &errorResponse)
+
+	var white, black SeatResponse
+	testRequest(t, httpServer, http.MethodPost, "/games", CreateGameRequest{Player: "alice", TimeControl: "60"},
+		http.StatusCreated, &white)
+	testRequest(t, httpServer, http.MethodPost, "/games/"+white.Game.ID+"/join", JoinGameRequest{Player: "bob"},
+		http.StatusOK, &black)
+
+	if info := white.Game.Clock; info == nil || info.TimeControl != "60" || info.White != 60000 || info.Active != "" {
+		t.Fatalf("POST /games expected stopped clock with one minute but got %+v", info)
+	}
+
+	whiteConn := testDial(t, httpServer, white.Game.ID, white.Token)
+	testReadEvent(t, whiteConn)
+
+	testWriteMessage(t, whiteConn, Message{Type: MessageTypeMove, Move: "e2e4"})
+
+	if event := testReadEvent(t, whiteConn); event.Type != EventTypeMove || event.SAN != "e4" {
+		t.Fatalf("Read() expected move event e4 but got %+v", event)
+	}
+
+	if event := testReadEvent(t, whiteConn); event.Type != EventTypeClock || event.Clock == nil ||
+		event.Clock.Active != "black" || event.Clock.TimeControl != "60" {
+		t.Fatalf("Read() expected clock event with running clock of black but got %+v", event)
+	}
+
+	// The clock of black is started before the clock event is sent, so the timer of its flag fall is already set.
+	timeSource.Advance(time.Minute)
+
+	if event := testReadEvent(t, whiteConn); event.Type != EventTypeResult || event.Result != "1-0" ||
+		event.Termination != "timeout" {
+		t.Fatalf("Read() expected result event 1-0 by timeout but got %+v", event)
+	}
+
+	event := testReadEvent(t, whiteConn)
+	if event.Type != EventTypeClock || event.Clock == nil || event.Clock.White != 60000 || event.Clock.Black != 0 ||
+		event.Clock.Active != "" {
+		t.Fatalf("Read() expected stopped clock event after the flag fall but got %+v", event)
+	}
+}
+
+func TestRoomMoveAfterGameEnd(t *testing.T) {
+	t.Parallel()
+
+	g, err := game.NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	hub, err := broadcast.NewHub(1)
+	if err != nil {
+		t.Fatalf("NewHub(): %v", err)
+	}
+
+	room := newRoom("1", g, hub)
+
+	if err := room.setTimeControl(clock.NewTimeControlSuddenDeath(time.Minute), clock.NewManualTimeSource(time.Time{})); err != nil {
+		t.Fatalf("setTimeControl(): %v", err)
+	}
+
+	_, token, err := room.join("alice", piece.ColorWhite)
+	if err != nil {
+		t.Fatalf("join(alice): %v", err)
+	}
+
+	if _, _, err := room.join("bob", piece.ColorBlack); err != nil {
+		t.Fatalf("join(bob): %v", err)
+	}
+
+	// The game ends without the clock, for example, by the timeout recorded before the move.
+	if err := g.Timeout(piece.ColorBlack); err != nil {
+		t.Fatalf("Timeout(): %v", err)
+	}
+
+	if err := room.move(token, "e2e4"); err == nil || err.Error() != "game is over" {
+		t.Fatalf("move() expected error %q but got %q", "game is over", err)
+	}
+
+	if active := room.clock.Active(); active != piece.ColorNil {
+		t.Fatalf("move() expected the clock not to be pressed but got active %s", active)
+	}
+}
+
+// newTestHTTPServer starts the HTTP server of the new Server with passed options, which is closed after the test.
+func newTestHTTPServer(t *testing.T, options Options) *httptest.Server {
+	t.Helper()
+
+	gameServer, err := NewServer(game.Engine{}, options)
+	if err != nil {
+		t.Fatalf("NewServer(): %v", err)
+	}
+
+	httpServer := httptest.NewServer(gameServer.Handler())
+	t.Cleanup(httpServer.Close)
+
+	return httpServer
+}
+
+// testRequest sends passed body as JSON to the server and decodes the response to passed value.
+func testRequest(
+	t *testing.T, httpServer *httptest.Server, method, path string, body any, status int, response any,
+) {
+	t.Helper()
+
+	var reader *bytes.Reader
+
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Marshal(%+v): %v", body, err)
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), method, httpServer.URL+path, reader)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext(%s, %s): %v", method, path, err)
+	}
+
+	resp, err := httpServer.Client().Do(request)
+	if err != nil {
+		t.Fatalf("Do(%s %s): %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s expected status %d but got %d", method, path, status, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Fatalf("%s %s, Decode(): %v", method, path, err)
+	}
+}
+
+// testDial opens the WebSocket connection to the game with passed token.
+func testDial(t *testing.T, httpServer *httptest.Server, id, token string) *websocket.Conn {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	url := strings.Replace(httpServer.URL, "http", "ws", 1) + "/games/" + id + "/ws?token=" + token
+
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("Dial(%s): %v", url, err)
+	}
+
+	t.Cleanup(func() {
+		conn.CloseNow() //nolint:errcheck // The test is over.
+	})
+
+	return conn
+}
+
+// testReadEvent reads the next event from the connection.
+func testReadEvent(t *testing.T, conn *websocket.Conn) Event {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	var event Event
+
+	if err := wsjson.Read(ctx, conn, &event); err != nil {
+		t.Fatalf("Read(): %v", err)
+	}
+
+	return event
+}
+
+// testWriteMessage writes passed message to the connection.
+func testWriteMessage(t *testing.T, conn *websocket.Conn, message Message) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	if err := wsjson.Write(ctx, conn, message); err != nil {
+		t.Fatalf("Write(%+v): %v", message, err)
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// handleGameWebSocket streams the game events to the client and makes the moves from the client messages.
+//
+// The client without the "token" query parameter is a spectator.
+func (server *Server) handleGameWebSocket(writer http.ResponseWriter, request *http.Request) {
+	room, ok := server.findRoom(request.PathValue("id"))
+	if !ok {
+		writeError(writer, http.StatusNotFound, errors.New("game not found"))
+		return
+	}
+
+	conn, err := websocket.Accept(writer, request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow() //nolint:errcheck // The connection is closed anyway.
+
+	subscription, err := server.hub.Subscribe(room.id)
+	if err != nil {
+		conn.Close(websocket.StatusInternalError, "subscribe failed") //nolint:errcheck // Nothing to do.
+		return
+	}
+	defer subscription.Close()
+
+	ctx, cancel := context.WithCancel(request.Context())
+	defer cancel()
+
+	state, err := room.newStateEvent(subscription.Snapshot)
+	if err != nil {
+		conn.Close(websocket.StatusInternalError, "state failed") //nolint:errcheck // Nothing to do.
+		return
+	}
+
+	if err := wsjson.Write(ctx, conn, state); err != nil {
+		return
+	}
+
+	go readGameMessages(ctx, cancel, conn, room, request.URL.Query().Get("token"))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case hubEvent, ok := <-subscription.Events:
+			if !ok {
+				conn.Close(websocket.StatusPolicyViolation, "too slow") //nolint:errcheck // Nothing to do.
+				return
+			}
+
+			event, ok, err := room.newEvent(hubEvent)
+			if err != nil {
+				conn.Close(websocket.StatusInternalError, "event failed") //nolint:errcheck // Nothing to do.
+				return
+			}
+
+			if !ok {
+				continue
+			}
+
+			if err := wsjson.Write(ctx, conn, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readGameMessages makes the moves from the client messages and writes the errors back to the client until the
+// connection is closed. Passed cancel function is called after the last message.
+func readGameMessages(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, room *room, token string) {
+	defer cancel()
+
+	for {
+		var message Message
+
+		if err := wsjson.Read(ctx, conn, &message); err != nil {
+			return
+		}
+
+		var err error
+
+		switch message.Type {
+		case MessageTypeMove:
+			if err = room.move(token, message.Move); err != nil {
+				err = fmt.Errorf("move(%q): %w", message.Move, err)
+			}
+		case MessageTypePremove:
+			if err = room.premove(token, message.Move); err != nil {
+				err = fmt.Errorf("premove(%q): %w", message.Move, err)
+			}
+		case MessageTypeConditional:
+			if err = room.addConditionalMoves(token, message.Moves); err != nil {
+				err = fmt.Errorf("addConditionalMoves(%v): %w", message.Moves, err)
+			}
+		default:
+			err = fmt.Errorf("unknown message type %q", message.Type)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if err := wsjson.Write(ctx, conn, Event{Type: EventTypeError, Error: err.Error()}); err != nil {
+			return
+		}
+	}
+}
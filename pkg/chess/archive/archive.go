@@ -0,0 +1,343 @@
+// Package archive implements the compact binary format of the game archive.
+//
+// The archive starts with the magic and the version of the format followed by the records of the games. The record is
+// the length of the payload as 4-byte big-endian integer, the payload and its CRC-32 checksum as 4-byte big-endian
+// integer.
+//
+// The payload is the header of the game and its moves. Strings are prefixed by their length as uvarint. Each move is
+// the index into the list of possible moves of the variant in the position before it, which has deterministic order.
+// The index is 1 byte if there are at most 256 possible moves and 2 big-endian bytes otherwise.
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+const (
+	// Magic and the version of the format at the start of the archive.
+	archiveMagic   = "LIMBOARC"
+	archiveVersion = 1
+
+	// Maximum length of the payload of the record.
+	archiveMaxPayloadLength = 1 << 24
+
+	// Maximum count of the possible moves, whose index is encoded in 1 byte.
+	archiveMaxShortMoves = 256
+)
+
+// Header is the information about the game, which is not in its moves.
+type Header struct {
+	White string
+	Black string
+	// Ratings of the players or zero if they are unknown.
+	WhiteRating uint16
+	BlackRating uint16
+	// Time control in PGN or empty string if it is unknown, for example, "300+3".
+	TimeControl string
+	// Date of the game or zero time if it is unknown. It is stored with second precision in UTC.
+	Date time.Time
+}
+
+// encodePayload encodes passed header and passed game to the payload of the record.
+func encodePayload(header Header, g *game.Game) ([]byte, error) {
+	positions, moves := g.Positions(), g.Moves()
+	if len(positions) == 0 {
+		return nil, errors.New("no positions")
+	}
+
+	variant := g.Variant()
+
+	fen, err := calcStartFEN(variant, positions[0])
+	if err != nil {
+		return nil, fmt.Errorf("calcStartFEN(): %w", err)
+	}
+
+	result, termination, err := g.Result()
+	if err != nil {
+		return nil, fmt.Errorf("Result(): %w", err)
+	}
+
+	payload := appendString(nil, header.White)
+	payload = appendString(payload, header.Black)
+	payload = binary.AppendUvarint(payload, uint64(header.WhiteRating))
+	payload = binary.AppendUvarint(payload, uint64(header.BlackRating))
+	payload = appendString(payload, header.TimeControl)
+
+	if header.Date.IsZero() {
+		payload = append(payload, 0)
+	} else {
+		payload = binary.AppendVarint(append(payload, 1), header.Date.Unix())
+	}
+
+	payload = appendString(payload, variant.Name())
+	payload = appendString(payload, fen)
+	payload = append(payload, byte(result), byte(termination))
+	payload = binary.AppendUvarint(payload, uint64(len(moves)))
+
+	for index, m := range moves {
+		possibleMoves, err := variant.CalcMoves(positions[index])
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, CalcMoves(): %w", index, err)
+		}
+
+		moveIndex := slices.Index(possibleMoves, m)
+		if moveIndex < 0 {
+			return nil, fmt.Errorf("move #%d is not possible", index)
+		}
+
+		if len(possibleMoves) <= archiveMaxShortMoves {
+			payload = append(payload, byte(moveIndex))
+		} else {
+			payload = binary.BigEndian.AppendUint16(payload, uint16(moveIndex))
+		}
+	}
+
+	return payload, nil
+}
+
+// decodePayload decodes the header and the game from passed payload of the record. The game is rebuilt by the replay
+// of the moves using passed engine. The result, which is not calculated from the positions, is restored from the
+// payload.
+func decodePayload(engine game.Engine, payload []byte) (Header, *game.Game, error) {
+	reader := bytes.NewReader(payload)
+
+	var (
+		header Header
+		err    error
+	)
+
+	if header.White, err = readString(reader); err != nil {
+		return Header{}, nil, fmt.Errorf("readString(white): %w", err)
+	}
+
+	if header.Black, err = readString(reader); err != nil {
+		return Header{}, nil, fmt.Errorf("readString(black): %w", err)
+	}
+
+	if header.WhiteRating, err = readRating(reader); err != nil {
+		return Header{}, nil, fmt.Errorf("readRating(white): %w", err)
+	}
+
+	if header.BlackRating, err = readRating(reader); err != nil {
+		return Header{}, nil, fmt.Errorf("readRating(black): %w", err)
+	}
+
+	if header.TimeControl, err = readString(reader); err != nil {
+		return Header{}, nil, fmt.Errorf("readString(time control): %w", err)
+	}
+
+	if header.Date, err = readDate(reader); err != nil {
+		return Header{}, nil, fmt.Errorf("readDate(): %w", err)
+	}
+
+	g, err := readGame(engine, reader)
+	if err != nil {
+		return Header{}, nil, fmt.Errorf("readGame(): %w", err)
+	}
+
+	if reader.Len() != 0 {
+		return Header{}, nil, fmt.Errorf("%d extra bytes", reader.Len())
+	}
+
+	return header, g, nil
+}
+
+// readGame reads the variant, the start position, the result and the moves and rebuilds the game.
+func readGame(engine game.Engine, reader *bytes.Reader) (*game.Game, error) {
+	name, err := readString(reader)
+	if err != nil {
+		return nil, fmt.Errorf("readString(variant): %w", err)
+	}
+
+	variant, err := game.NewVariantFromName(engine, name)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantFromName(%q): %w", name, err)
+	}
+
+	fen, err := readString(reader)
+	if err != nil {
+		return nil, fmt.Errorf("readString(FEN): %w", err)
+	}
+
+	g, err := game.NewGameVariantStart(variant)
+	if err != nil {
+		return nil, fmt.Errorf("NewGameVariantStart(%s): %w", name, err)
+	}
+
+	if fen != "" {
+		pos, err := position.NewPositionFromFEN(fen)
+		if err != nil {
+			return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+		}
+
+		g = game.NewGame(variant, []*position.Position{pos}, nil)
+	}
+
+	var resultBytes [2]byte
+
+	if _, err := io.ReadFull(reader, resultBytes[:]); err != nil {
+		return nil, fmt.Errorf("ReadFull(result): %w", err)
+	}
+
+	movesLength, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("ReadUvarint(moves): %w", err)
+	}
+
+	for index := range movesLength {
+		possibleMoves, err := variant.CalcMoves(g.Position())
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, CalcMoves(): %w", index, err)
+		}
+
+		moveIndex, err := readMoveIndex(reader, len(possibleMoves))
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, readMoveIndex(): %w", index, err)
+		}
+
+		if err := g.Move(possibleMoves[moveIndex]); err != nil {
+			return nil, fmt.Errorf("move #%d, Move(%+v): %w", index, possibleMoves[moveIndex], err)
+		}
+	}
+
+	result, _, err := g.Result()
+	if err != nil {
+		return nil, fmt.Errorf("Result(): %w", err)
+	}
+
+	storedResult, storedTermination := game.Result(resultBytes[0]), game.Termination(resultBytes[1])
+
+	if result == game.ResultNil && storedResult != game.ResultNil {
+		if err := g.End(storedResult, storedTermination); err != nil {
+			return nil, fmt.Errorf("End(%s, %s): %w", storedResult, storedTermination, err)
+		}
+	}
+
+	return g, nil
+}
+
+// readMoveIndex reads the index of the move in the list of passed count of possible moves.
+func readMoveIndex(reader *bytes.Reader, movesLength int) (int, error) {
+	var index int
+
+	if movesLength <= archiveMaxShortMoves {
+		indexByte, err := reader.ReadByte()
+		if err != nil {
+			return 0, fmt.Errorf("ReadByte(): %w", err)
+		}
+
+		index = int(indexByte)
+	} else {
+		var indexBytes [2]byte
+
+		if _, err := io.ReadFull(reader, indexBytes[:]); err != nil {
+			return 0, fmt.Errorf("ReadFull(): %w", err)
+		}
+
+		index = int(binary.BigEndian.Uint16(indexBytes[:]))
+	}
+
+	if index >= movesLength {
+		return 0, fmt.Errorf("index %d of %d possible moves", index, movesLength)
+	}
+
+	return index, nil
+}
+
+// readDate reads the optional date as the flag and Unix time in seconds.
+func readDate(reader *bytes.Reader) (time.Time, error) {
+	flag, err := reader.ReadByte()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ReadByte(): %w", err)
+	}
+
+	if flag == 0 {
+		return time.Time{}, nil
+	}
+
+	seconds, err := binary.ReadVarint(reader)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ReadVarint(): %w", err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// readRating reads the rating as uvarint.
+func readRating(reader *bytes.Reader) (uint16, error) {
+	rating, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, fmt.Errorf("ReadUvarint(): %w", err)
+	}
+
+	if rating > math.MaxUint16 {
+		return 0, fmt.Errorf("rating %d is too large", rating)
+	}
+
+	return uint16(rating), nil
+}
+
+// readString reads the string prefixed by its length as uvarint.
+func readString(reader *bytes.Reader) (string, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return "", fmt.Errorf("ReadUvarint(): %w", err)
+	}
+
+	if length > uint64(reader.Len()) {
+		return "", fmt.Errorf("length %d is out of payload", length)
+	}
+
+	data := make([]byte, length)
+
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return "", fmt.Errorf("ReadFull(): %w", err)
+	}
+
+	return string(data), nil
+}
+
+// appendString appends passed string prefixed by its length as uvarint.
+func appendString(data []byte, s string) []byte {
+	return append(binary.AppendUvarint(data, uint64(len(s))), s...)
+}
+
+// calcChecksum calculates the checksum of passed payload.
+func calcChecksum(payload []byte) uint32 {
+	return crc32.ChecksumIEEE(payload)
+}
+
+// calcStartFEN returns FEN of passed start position or the empty string if it is the start position of the variant.
+func calcStartFEN(variant game.Variant, pos *position.Position) (string, error) {
+	fen, err := pos.FEN()
+	if err != nil {
+		return "", fmt.Errorf("FEN(): %w", err)
+	}
+
+	variantPos, err := variant.NewPositionStart()
+	if err != nil {
+		return "", fmt.Errorf("NewPositionStart(): %w", err)
+	}
+
+	variantFEN, err := variantPos.FEN()
+	if err != nil {
+		return "", fmt.Errorf("variant FEN(): %w", err)
+	}
+
+	if fen == variantFEN {
+		return "", nil
+	}
+
+	return fen, nil
+}
@@ -0,0 +1,179 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+func TestWriterReader(t *testing.T) {
+	t.Parallel()
+
+	engine := game.Engine{}
+
+	timeoutGame, err := game.NewGameFromUCIs("", "e2e4", "e7e5", "g1f3")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(timeout): %v", err)
+	}
+
+	if err := timeoutGame.Timeout(piece.ColorBlack); err != nil {
+		t.Fatalf("Timeout(): %v", err)
+	}
+
+	mateGame, err := game.NewGameFromUCIs("", "f2f3", "e7e5", "g2g4", "d8h4")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(mate): %v", err)
+	}
+
+	endgameGame, err := game.NewGameFromUCIs("4k3/8/8/8/8/8/4P3/4K3 w - - 0 30", "e2e4", "e8d7")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(endgame): %v", err)
+	}
+
+	tests := []struct {
+		header Header
+		g      *game.Game
+	}{
+		{
+			Header{
+				White:       "alice",
+				Black:       "bob",
+				WhiteRating: 1850,
+				BlackRating: 2100,
+				TimeControl: "300+3",
+				Date:        time.Date(2024, time.March, 5, 18, 30, 0, 0, time.UTC),
+			},
+			timeoutGame,
+		},
+		{Header{White: "carol"}, mateGame},
+		{Header{}, endgameGame},
+	}
+
+	var buffer bytes.Buffer
+
+	writer := NewWriter(&buffer)
+
+	for index, test := range tests {
+		if err := writer.Write(test.header, test.g); err != nil {
+			t.Fatalf("game #%d, Write(): %v", index, err)
+		}
+	}
+
+	reader := NewReader(&buffer, engine)
+
+	for index, test := range tests {
+		header, g, err := reader.Read()
+		if err != nil {
+			t.Fatalf("game #%d, Read(): %v", index, err)
+		}
+
+		if !reflect.DeepEqual(header, test.header) {
+			t.Fatalf("game #%d, Read() expected header %+v but got %+v", index, test.header, header)
+		}
+
+		if !reflect.DeepEqual(g.Positions(), test.g.Positions()) || !reflect.DeepEqual(g.Moves(), test.g.Moves()) {
+			t.Fatalf("game #%d, Read() expected the same positions and moves as the written game", index)
+		}
+
+		checkTestGameResult(t, g, test.g)
+	}
+
+	if _, _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Read() expected io.EOF after the last game but got %v", err)
+	}
+}
+
+func TestReaderReadErrors(t *testing.T) {
+	t.Parallel()
+
+	g, err := game.NewGameFromUCIs("", "e2e4")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(): %v", err)
+	}
+
+	var buffer bytes.Buffer
+
+	if err := NewWriter(&buffer).Write(Header{White: "alice"}, g); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+
+	data := buffer.Bytes()
+	magicLength := len(archiveMagic) + 1
+
+	corrupted := bytes.Clone(data)
+	corrupted[magicLength+4] ^= 0xFF
+
+	version := bytes.Clone(data)
+	version[magicLength-1] = archiveVersion + 1
+
+	tests := []struct {
+		name      string
+		data      []byte
+		errString string
+	}{
+		{"empty", nil, "EOF"},
+		{"magic", []byte("LIMBO"), "readMagic(): ReadFull(): unexpected EOF"},
+		{"not archive", []byte("NOTANARCHIVE"), "readMagic(): not an archive"},
+		{"version", version, "readMagic(): unknown version 2"},
+		{"checksum", corrupted, "checksum mismatch"},
+		{"truncated", data[:len(data)-1], "ReadFull(payload): unexpected EOF"},
+		{"length", append(bytes.Clone(data[:magicLength]), 0xFF, 0, 0, 0), "payload of 4278190080 bytes is too large"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := NewReader(bytes.NewReader(test.data), game.Engine{}).Read()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("Read() expected error %q but got %q", test.errString, err)
+			}
+		})
+	}
+}
+
+func TestEncodePayloadMoveIndexes(t *testing.T) {
+	t.Parallel()
+
+	g, err := game.NewGameFromUCIs("", "e2e4", "e7e5")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(): %v", err)
+	}
+
+	payload, err := encodePayload(Header{}, g)
+	if err != nil {
+		t.Fatalf("encodePayload(): %v", err)
+	}
+
+	// Empty names, zero ratings, empty time control, no date, variant, empty FEN, result and termination, count of
+	// moves and two moves of 1 byte.
+	expectedLength := 2 + 2 + 1 + 1 + 1 + len("Standard") + 1 + 2 + 1 + 2
+	if len(payload) != expectedLength {
+		t.Fatalf("encodePayload() expected %d bytes but got %d", expectedLength, len(payload))
+	}
+}
+
+// checkTestGameResult checks that passed games have the same result and termination.
+func checkTestGameResult(t *testing.T, g, expected *game.Game) {
+	t.Helper()
+
+	result, termination, err := g.Result()
+	if err != nil {
+		t.Fatalf("Result(): %v", err)
+	}
+
+	expectedResult, expectedTermination, err := expected.Result()
+	if err != nil {
+		t.Fatalf("expected Result(): %v", err)
+	}
+
+	if result != expectedResult || termination != expectedTermination {
+		t.Fatalf("Result() expected %s, %s but got %s, %s", expectedResult, expectedTermination, result, termination)
+	}
+}
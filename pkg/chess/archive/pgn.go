@@ -0,0 +1,182 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+)
+
+const (
+	// Layout of PGN "Date" tag.
+	pgnDateLayout = "2006.01.02"
+
+	// Values of PGN "Termination" tag.
+	pgnTerminationTimeForfeit  = "time forfeit"
+	pgnTerminationAdjudication = "adjudication"
+)
+
+// NewHeaderFromPGN creates the header from the tags of passed PGN game.
+//
+// Unknown and malformed ratings, dates and time controls, for example, "?" or "2024.??.??", are left zero.
+func NewHeaderFromPGN(pgnGame *pgn.Game) Header {
+	var header Header
+
+	header.White, _ = pgnGame.GetTag("White")
+	header.Black, _ = pgnGame.GetTag("Black")
+	header.TimeControl, _ = pgnGame.GetTag("TimeControl")
+
+	if header.TimeControl == "-" || header.TimeControl == "?" {
+		header.TimeControl = ""
+	}
+
+	for _, rating := range [...]struct {
+		tag   string
+		value *uint16
+	}{
+		{"WhiteElo", &header.WhiteRating},
+		{"BlackElo", &header.BlackRating},
+	} {
+		value, _ := pgnGame.GetTag(rating.tag)
+
+		if parsed, err := strconv.ParseUint(value, 10, 16); err == nil {
+			*rating.value = uint16(parsed)
+		}
+	}
+
+	if value, ok := pgnGame.GetTag("Date"); ok {
+		if date, err := time.Parse(pgnDateLayout, value); err == nil {
+			header.Date = date
+		}
+	}
+
+	return header
+}
+
+// NewGameFromPGN replays passed PGN game using passed engine. The result of the PGN game, which is not calculated from
+// the positions, is restored with the termination from "Termination" tag: the timeout if it is "time forfeit" and
+// the adjudication otherwise.
+func NewGameFromPGN(engine game.Engine, pgnGame *pgn.Game) (*game.Game, error) {
+	g, err := pgnGame.NewVariantGame(engine)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantGame(): %w", err)
+	}
+
+	result, _, err := g.Result()
+	if err != nil {
+		return nil, fmt.Errorf("Result(): %w", err)
+	}
+
+	if result != game.ResultNil || pgnGame.Result == game.ResultNil {
+		return g, nil
+	}
+
+	termination := game.TerminationAdjudication
+
+	if value, _ := pgnGame.GetTag("Termination"); value == pgnTerminationTimeForfeit {
+		termination = game.TerminationTimeout
+		if pgnGame.Result == game.ResultDraw {
+			termination = game.TerminationTimeoutVsInsufficientMaterial
+		}
+	}
+
+	if err := g.End(pgnGame.Result, termination); err != nil {
+		return nil, fmt.Errorf("End(%s, %s): %w", pgnGame.Result, termination, err)
+	}
+
+	return g, nil
+}
+
+// NewPGNGame creates the PGN game from passed header and passed game.
+func NewPGNGame(header Header, g *game.Game) (*pgn.Game, error) {
+	tags := []pgn.Tag{{Name: "White", Value: header.White}, {Name: "Black", Value: header.Black}}
+
+	if !header.Date.IsZero() {
+		tags = append(tags, pgn.Tag{Name: "Date", Value: header.Date.Format(pgnDateLayout)})
+	}
+
+	if header.WhiteRating != 0 {
+		tags = append(tags, pgn.Tag{Name: "WhiteElo", Value: strconv.Itoa(int(header.WhiteRating))})
+	}
+
+	if header.BlackRating != 0 {
+		tags = append(tags, pgn.Tag{Name: "BlackElo", Value: strconv.Itoa(int(header.BlackRating))})
+	}
+
+	if header.TimeControl != "" {
+		tags = append(tags, pgn.Tag{Name: "TimeControl", Value: header.TimeControl})
+	}
+
+	_, termination, err := g.Result()
+	if err != nil {
+		return nil, fmt.Errorf("Result(): %w", err)
+	}
+
+	switch termination {
+	case game.TerminationTimeout, game.TerminationTimeoutVsInsufficientMaterial:
+		tags = append(tags, pgn.Tag{Name: "Termination", Value: pgnTerminationTimeForfeit})
+	case game.TerminationAdjudication:
+		tags = append(tags, pgn.Tag{Name: "Termination", Value: pgnTerminationAdjudication})
+	}
+
+	pgnGame, err := pgn.NewGameFromGame(g, tags)
+	if err != nil {
+		return nil, fmt.Errorf("NewGameFromGame(): %w", err)
+	}
+
+	return pgnGame, nil
+}
+
+// ConvertFromPGN reads all games from passed PGN reader and writes them to passed archive writer.
+//
+// Returns the count of the converted games.
+func ConvertFromPGN(engine game.Engine, reader *pgn.Reader, writer *Writer) (int, error) {
+	for count := 0; ; count++ {
+		pgnGame, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("game #%d, Read(): %w", count, err)
+		}
+
+		g, err := NewGameFromPGN(engine, pgnGame)
+		if err != nil {
+			return count, fmt.Errorf("game #%d, NewGameFromPGN(): %w", count, err)
+		}
+
+		if err := writer.Write(NewHeaderFromPGN(pgnGame), g); err != nil {
+			return count, fmt.Errorf("game #%d, Write(): %w", count, err)
+		}
+	}
+}
+
+// ConvertToPGN reads all games from passed archive reader and writes them to passed PGN writer.
+//
+// Returns the count of the converted games.
+func ConvertToPGN(reader *Reader, writer *pgn.Writer) (int, error) {
+	for count := 0; ; count++ {
+		header, g, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("game #%d, Read(): %w", count, err)
+		}
+
+		pgnGame, err := NewPGNGame(header, g)
+		if err != nil {
+			return count, fmt.Errorf("game #%d, NewPGNGame(): %w", count, err)
+		}
+
+		if err := writer.Write(pgnGame); err != nil {
+			return count, fmt.Errorf("game #%d, Write(): %w", count, err)
+		}
+	}
+}
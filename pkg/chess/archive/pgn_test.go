@@ -0,0 +1,105 @@
+package archive
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+)
+
+func TestConvertPGN(t *testing.T) {
+	t.Parallel()
+
+	const text = `[Event "Casual"]
+[White "alice"]
+[Black "bob"]
+[Date "2024.03.05"]
+[WhiteElo "1850"]
+[BlackElo "?"]
+[TimeControl "300+3"]
+[Termination "time forfeit"]
+
+1. e4 e5 2. Nf3 1-0
+
+[White "carol"]
+[Black "dave"]
+[Date "2024.??.??"]
+
+1. f3 e5 2. g4 Qh4# 0-1
+
+[White "erin"]
+[Black "frank"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 30"]
+
+30. e4 Kd7 1/2-1/2
+`
+
+	const expectedText = `[White "alice"]
+[Black "bob"]
+[Date "2024.03.05"]
+[WhiteElo "1850"]
+[TimeControl "300+3"]
+[Termination "time forfeit"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 1-0
+
+[White "carol"]
+[Black "dave"]
+[Result "0-1"]
+
+1. f3 e5 2. g4 Qh4# 0-1
+
+[White "erin"]
+[Black "frank"]
+[Termination "adjudication"]
+[Result "1/2-1/2"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 30"]
+
+30. e4 Kd7 1/2-1/2
+
+`
+
+	engine := game.Engine{}
+
+	var archive bytes.Buffer
+
+	count, err := ConvertFromPGN(engine, pgn.NewReader(strings.NewReader(text)), NewWriter(&archive))
+	if err != nil || count != 3 {
+		t.Fatalf("ConvertFromPGN() expected 3 games but got %d, %v", count, err)
+	}
+
+	header, _, err := NewReader(bytes.NewReader(archive.Bytes()), engine).Read()
+	if err != nil {
+		t.Fatalf("Read(): %v", err)
+	}
+
+	expectedHeader := Header{
+		White:       "alice",
+		Black:       "bob",
+		WhiteRating: 1850,
+		TimeControl: "300+3",
+		Date:        time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	if !reflect.DeepEqual(header, expectedHeader) {
+		t.Fatalf("Read() expected header %+v but got %+v", expectedHeader, header)
+	}
+
+	var builder strings.Builder
+
+	count, err = ConvertToPGN(NewReader(&archive, engine), pgn.NewWriter(&builder))
+	if err != nil || count != 3 {
+		t.Fatalf("ConvertToPGN() expected 3 games but got %d, %v", count, err)
+	}
+
+	if builder.String() != expectedText {
+		t.Fatalf("ConvertToPGN() expected %q but got %q", expectedText, builder.String())
+	}
+}
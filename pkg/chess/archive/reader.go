@@ -0,0 +1,101 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+// Reader reads games from the archive one by one.
+type Reader struct {
+	reader  *bufio.Reader
+	engine  game.Engine
+	started bool
+}
+
+// NewReader creates a new Reader, which reads the archive from passed reader and replays the games using passed
+// engine.
+func NewReader(reader io.Reader, engine game.Engine) *Reader {
+	return &Reader{reader: bufio.NewReader(reader), engine: engine}
+}
+
+// Read reads the next game with its header.
+//
+// Returns io.EOF if there are no more games. The empty archive has no games.
+func (reader *Reader) Read() (Header, *game.Game, error) {
+	if !reader.started {
+		if err := reader.readMagic(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return Header{}, nil, io.EOF
+			}
+
+			return Header{}, nil, fmt.Errorf("readMagic(): %w", err)
+		}
+
+		reader.started = true
+	}
+
+	var lengthBytes [4]byte
+
+	if _, err := io.ReadFull(reader.reader, lengthBytes[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Header{}, nil, io.EOF
+		}
+
+		return Header{}, nil, fmt.Errorf("ReadFull(length): %w", err)
+	}
+
+	length := binary.BigEndian.Uint32(lengthBytes[:])
+	if length > archiveMaxPayloadLength {
+		return Header{}, nil, fmt.Errorf("payload of %d bytes is too large", length)
+	}
+
+	data := make([]byte, length+4) //nolint:mnd // Payload and checksum.
+
+	if _, err := io.ReadFull(reader.reader, data); err != nil {
+		// The record is incomplete, so the end of the archive is unexpected.
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return Header{}, nil, fmt.Errorf("ReadFull(payload): %w", err)
+	}
+
+	payload := data[:length]
+
+	if checksum := binary.BigEndian.Uint32(data[length:]); checksum != calcChecksum(payload) {
+		return Header{}, nil, errors.New("checksum mismatch")
+	}
+
+	header, g, err := decodePayload(reader.engine, payload)
+	if err != nil {
+		return Header{}, nil, fmt.Errorf("decodePayload(): %w", err)
+	}
+
+	return header, g, nil
+}
+
+// readMagic reads and checks the magic and the version of the format.
+//
+// Returns io.EOF if the archive is empty.
+func (reader *Reader) readMagic() error {
+	data := make([]byte, len(archiveMagic)+1)
+
+	if _, err := io.ReadFull(reader.reader, data); err != nil {
+		return fmt.Errorf("ReadFull(): %w", err)
+	}
+
+	if string(data[:len(archiveMagic)]) != archiveMagic {
+		return errors.New("not an archive")
+	}
+
+	if version := data[len(archiveMagic)]; version != archiveVersion {
+		return fmt.Errorf("unknown version %d", version)
+	}
+
+	return nil
+}
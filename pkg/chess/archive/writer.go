@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+// Writer writes games to the archive one by one. The magic and the version of the format are written before the first
+// game.
+type Writer struct {
+	writer  io.Writer
+	started bool
+}
+
+// NewWriter creates a new Writer, which writes the archive to passed writer.
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{writer: writer}
+}
+
+// Write writes the record of passed game with passed header.
+func (writer *Writer) Write(header Header, g *game.Game) error {
+	payload, err := encodePayload(header, g)
+	if err != nil {
+		return fmt.Errorf("encodePayload(): %w", err)
+	}
+
+	if len(payload) > archiveMaxPayloadLength {
+		return fmt.Errorf("payload of %d bytes is too large", len(payload))
+	}
+
+	var data []byte
+
+	if !writer.started {
+		data = append([]byte(archiveMagic), archiveVersion)
+	}
+
+	data = binary.BigEndian.AppendUint32(data, uint32(len(payload)))
+	data = append(data, payload...)
+	data = binary.BigEndian.AppendUint32(data, calcChecksum(payload))
+
+	if _, err := writer.writer.Write(data); err != nil {
+		return fmt.Errorf("Write(): %w", err)
+	}
+
+	writer.started = true
+
+	return nil
+}
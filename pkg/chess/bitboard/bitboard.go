@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"errors"
 	"math/bits"
+	"strings"
 	"unsafe"
 
 	"github.com/rylenko/limbo/pkg/chess/square"
@@ -20,6 +21,17 @@ const BitboardNil Bitboard = iota
 
 var bitboardBitsCount = unsafe.Sizeof(BitboardNil)
 
+// Format formats the bitboard as the dot-grid of String for %s and %v verbs and as the unsigned integer for other
+// verbs, so "0x%X" still prints the mask in hexadecimal.
+func (bitboard Bitboard) Format(state fmt.State, verb rune) {
+	if verb == 's' || verb == 'v' {
+		fmt.Fprint(state, bitboard.String())
+		return
+	}
+
+	fmt.Fprintf(state, fmt.FormatString(state, verb), uint64(bitboard))
+}
+
 // GetSquares gets all set squares in the bitboard.
 func (bitboard Bitboard) GetSquares() []square.Square {
 	squares := make([]square.Square, 0, bits.OnesCount(bitboard))
@@ -64,6 +76,41 @@ func (bitboard Bitboard) SetSquares(squares ...Square) (Bitboard, error) {
 	return bitboard, nil
 }
 
+// String returns 8x8 grid of the bitboard, where the rank 8 is on the top, the file A is on the left, set squares are
+// "1" and unset squares are ".".
+//
+// Return example for 0x8040201008040201:
+//
+//	. . . . . . . 1
+//	. . . . . . 1 .
+//	. . . . . 1 . .
+//	. . . . 1 . . .
+//	. . . 1 . . . .
+//	. . 1 . . . . .
+//	. 1 . . . . . .
+//	1 . . . . . . .
+func (bitboard Bitboard) String() string {
+	rows := make([]string, 0, len(Ranks))
+
+	for rankIndex := len(Ranks) - 1; rankIndex >= 0; rankIndex-- {
+		cells := make([]string, 0, len(Files))
+
+		for fileIndex := range Files {
+			index := rankIndex*len(Files) + fileIndex
+
+			if bitboard&(1<<(len(Ranks)*len(Files)-index-1)) != BitboardNil {
+				cells = append(cells, "1")
+			} else {
+				cells = append(cells, ".")
+			}
+		}
+
+		rows = append(rows, strings.Join(cells, " "))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
 // UnsetSquares unsets bitboard bits corresponding to the passed squares.
 //
 // TODO: test.
@@ -1,6 +1,7 @@
 package bitboard
 
 import (
+	"fmt"
 	"slices"
 	"testing"
 )
@@ -71,3 +72,39 @@ func TestBitboardSetSquaresAndGetSquares(t *testing.T) {
 		})
 	}
 }
+
+func TestBitboardString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		bitboard Bitboard
+		str      string
+	}{
+		{"empty", BitboardNil, ". . . . . . . .\n. . . . . . . .\n. . . . . . . .\n. . . . . . . .\n" +
+			". . . . . . . .\n. . . . . . . .\n. . . . . . . .\n. . . . . . . ."},
+		{"diagonal", 0x8040201008040201, ". . . . . . . 1\n. . . . . . 1 .\n. . . . . 1 . .\n. . . . 1 . . .\n" +
+			". . . 1 . . . .\n. . 1 . . . . .\n. 1 . . . . . .\n1 . . . . . . ."},
+		{"pawns", 0x00FF00000000FF00, ". . . . . . . .\n1 1 1 1 1 1 1 1\n. . . . . . . .\n. . . . . . . .\n" +
+			". . . . . . . .\n. . . . . . . .\n1 1 1 1 1 1 1 1\n. . . . . . . ."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if str := test.bitboard.String(); str != test.str {
+				t.Fatalf("String(0x%X) expected\n%s\nbut got\n%s", uint64(test.bitboard), test.str, str)
+			}
+
+			if str := fmt.Sprintf("%v", test.bitboard); str != test.str {
+				t.Fatalf("Sprintf(%%v, 0x%X) expected\n%s\nbut got\n%s", uint64(test.bitboard), test.str, str)
+			}
+
+			hex := fmt.Sprintf("%X", uint64(test.bitboard))
+			if str := fmt.Sprintf("%X", test.bitboard); str != hex {
+				t.Fatalf("Sprintf(%%X, 0x%s) expected %s but got %s", hex, hex, str)
+			}
+		})
+	}
+}
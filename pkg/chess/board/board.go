@@ -1,6 +1,7 @@
 package board
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,22 @@ import (
 	"github.com/rylenko/limbo/pkg/chess/square"
 )
 
+// Mapping of pieces to FEN letters.
+var boardFENPieces = map[Piece]string{
+	PieceWhiteKing:   "K",
+	PieceWhiteQueen:  "Q",
+	PieceWhiteRook:   "R",
+	PieceWhiteBishop: "B",
+	PieceWhiteKnight: "N",
+	PieceWhitePawn:   "P",
+	PieceBlackKing:   "k",
+	PieceBlackQueen:  "q",
+	PieceBlackRook:   "r",
+	PieceBlackBishop: "b",
+	PieceBlackKnight: "n",
+	PieceBlackPawn:   "p",
+}
+
 // Board is the collection of Bitboards, representing chess board.
 type Board struct {
 	bitboards map[Piece]Bitboard
@@ -70,6 +87,96 @@ func NewBoardFromFEN(fen string) (*Board, error) {
 	return NewBoard(bitboards), nil
 }
 
+// Equals checks that the current board has the same pieces on the same squares as passed board.
+//
+// Note that the missing piece bitboard and the empty piece bitboard are equal.
+func (board *Board) Equals(other *Board) bool {
+	for piece, bitboard := range board.bitboards {
+		if other.bitboards[piece] != bitboard {
+			return false
+		}
+	}
+
+	for piece, bitboard := range other.bitboards {
+		if board.bitboards[piece] != bitboard {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FEN returns FEN representation of the current board.
+//
+// Return example: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR".
+func (board *Board) FEN() (string, error) {
+	parts := make([]string, 0, len(Ranks))
+
+	for rankIndex := len(Ranks) - 1; rankIndex >= 0; rankIndex-- {
+		var (
+			part         strings.Builder
+			emptySquares byte
+		)
+
+		for _, file := range Files {
+			square, err := NewSquare(Ranks[rankIndex], file)
+			if err != nil {
+				return "", fmt.Errorf("NewSquare(%s, %s): %w", Ranks[rankIndex], file, err)
+			}
+
+			piece, err := board.GetPieceFromSquare(square)
+			if err != nil {
+				return "", fmt.Errorf("GetPieceFromSquare(%s): %w", square, err)
+			}
+
+			if piece == PieceNil {
+				emptySquares++
+				continue
+			}
+
+			if emptySquares > 0 {
+				part.WriteByte('0' + emptySquares)
+				emptySquares = 0
+			}
+
+			part.WriteString(boardFENPieces[piece])
+		}
+
+		if emptySquares > 0 {
+			part.WriteByte('0' + emptySquares)
+		}
+
+		parts = append(parts, part.String())
+	}
+
+	return strings.Join(parts, "/"), nil
+}
+
+// ExplodeRaw explodes passed square as in Atomic chess: the piece on the square and all pieces around it, except
+// pawns, are removed from the board.
+//
+// Note that the explosion is raw, so it can, for example, remove both kings.
+func (board *Board) ExplodeRaw(center Square) error {
+	ringBitboard, ok := moveKingRawDestBitboards[center]
+	if !ok {
+		return fmt.Errorf("king bitboard of %s not found", center)
+	}
+
+	if _, err := board.removePieceFromSquare(center); err != nil {
+		return fmt.Errorf("removePieceFromSquare(%s): %w", center, err)
+	}
+
+	for piece, bitboard := range board.bitboards {
+		if piece == PieceWhitePawn || piece == PieceBlackPawn {
+			continue
+		}
+
+		board.bitboards[piece] = bitboard &^ ringBitboard
+	}
+
+	return nil
+}
+
 // GetColorBitboard returns bitboard of occupied squares by pieces of passed color.
 func (board *Board) GetColorBitboard(color Color) (Bitboard, error) {
 	var bitboard Bitboard
@@ -101,6 +208,11 @@ func (board *Board) GetOccupiedBitboard() (Bitboard, error) {
 	return whites | blacks, nil
 }
 
+// GetPieceBitboard returns bitboard of squares occupied by passed piece.
+func (board *Board) GetPieceBitboard(piece Piece) Bitboard {
+	return board.bitboards[piece]
+}
+
 // GetPieceFromSquare returns a piece that is on the passed square or PieceNil if the square is not occupied.
 //
 // Please note that even if no error has occurred, a piece may be PieceNil if there is no piece on the passed square.
@@ -127,6 +239,30 @@ func (board *Board) GetPieceFromSquare(square Square) (Piece, error) {
 //
 // TODO: test.
 func (board *Board) MoveRaw(move Move) error {
+	if move.dropPiece != PieceNil {
+		return board.moveDropRaw(move)
+	}
+
+	// Rooks castle from the standard squares if the rook origin is not specified.
+	if move.tags.Contains(MoveTagKingSideCastle) || move.tags.Contains(MoveTagQueenSideCastle) {
+		rookOriginFile := FileH
+		if move.tags.Contains(MoveTagQueenSideCastle) {
+			rookOriginFile = FileA
+		}
+
+		rank, err := move.origin.Rank()
+		if err != nil {
+			return fmt.Errorf("%s.Rank(): %w", move.origin, err)
+		}
+
+		rookOrigin, err := NewSquare(rank, rookOriginFile)
+		if err != nil {
+			return fmt.Errorf("NewSquare(%s, %s): %w", rank, rookOriginFile, err)
+		}
+
+		return board.MoveCastleRaw(move, rookOrigin)
+	}
+
 	originPiece, err := board.removePieceFromSquare(move.origin)
 	if err != nil {
 		return fmt.Errorf("removePieceFromSquare(%s): %w", move.origin, err)
@@ -170,43 +306,61 @@ func (board *Board) MoveRaw(move Move) error {
 		}
 	}
 
-	var (
-		castleRookPiece  Piece
-		castleRookOrigin Square
-		castleRookDest   Square
-	)
+	return nil
+}
+
+// MoveCastleRaw makes a raw castling move of the king by passed move and of the rook from passed origin.
+//
+// The rook is moved next to the king destination: to the F file for the king side castling and to the D file for the
+// queen side castling. The king and the rook may stand on the destination squares of each other, as in Chess960.
+//
+// Note that the move is raw, so it was not validated.
+func (board *Board) MoveCastleRaw(move Move, rookOrigin Square) error {
+	rookDestFile := FileF
 
 	switch {
-	case originPieceColor == ColorWhite && move.tags.Contains(MoveTagKingSideCastle):
-		castleRookPiece = PieceWhiteRook
-		castleRookOrigin = SquareH1
-		castleRookDest = SquareF1
-	case originPieceColor == ColorWhite && move.tags.Contains(MoveTagQueenSideCastle):
-		castleRookPiece = PieceWhiteRook
-		castleRookOrigin = SquareA1
-		castleRookDest = SquareD1
-	case originPieceColor == ColorBlack && move.tags.Contains(MoveTagKingSideCastle):
-		castleRookPiece = PieceBlackRook
-		castleRookOrigin = SquareH8
-		castleRookDest = SquareF8
-	case originPieceColor == ColorBlack && move.tags.Contains(MoveTagQueenSideCastle):
-		castleRookPiece = PieceBlackRook
-		castleRookOrigin = SquareA8
-		castleRookDest = SquareD8
-	}
-
-	if castleRookPiece == PieceNil || castleRookOrigin == SquareNil || castleRookDest == SquareNil {
-		return nil
-	}
-
-	castleRookBitboardWithoutOrigin, err := board.bitboards[castleRookPiece].UnsetSquares(castleRookOrigin)
+	case move.tags.Contains(MoveTagKingSideCastle):
+	case move.tags.Contains(MoveTagQueenSideCastle):
+		rookDestFile = FileD
+	default:
+		return errors.New("move is not a castling")
+	}
+
+	rank, err := move.dest.Rank()
+	if err != nil {
+		return fmt.Errorf("%s.Rank(): %w", move.dest, err)
+	}
+
+	rookDest, err := NewSquare(rank, rookDestFile)
+	if err != nil {
+		return fmt.Errorf("NewSquare(%s, %s): %w", rank, rookDestFile, err)
+	}
+
+	// Both pieces are removed before the placement, because their squares may intersect.
+	king, err := board.removePieceFromSquare(move.origin)
 	if err != nil {
-		return fmt.Errorf("0x%X.UnsetSquare(%s): %w", board.bitboards[castleRookPiece], castleRookOrigin, err)
+		return fmt.Errorf("removePieceFromSquare(%s): %w", move.origin, err)
 	}
 
-	board.bitboards[castleRookPiece], err = castleRookBitboardWithoutOrigin.SetSquares(castleRookDest)
+	rook, err := board.removePieceFromSquare(rookOrigin)
 	if err != nil {
-		return fmt.Errorf("0x%X.UnsetSquare(%s): %w", castleRookBitboardWithoutOrigin, castleRookDest, err)
+		return fmt.Errorf("removePieceFromSquare(%s): %w", rookOrigin, err)
+	}
+
+	if king != PieceWhiteKing && king != PieceBlackKing {
+		return fmt.Errorf("no king on the origin %s", move.origin)
+	}
+
+	if rook != PieceWhiteRook && rook != PieceBlackRook {
+		return fmt.Errorf("no rook on the rook origin %s", rookOrigin)
+	}
+
+	if err := board.setPieceToSquare(king, move.dest); err != nil {
+		return fmt.Errorf("setPieceToSquare(%s, %s): %w", king, move.dest, err)
+	}
+
+	if err := board.setPieceToSquare(rook, rookDest); err != nil {
+		return fmt.Errorf("setPieceToSquare(%s, %s): %w", rook, rookDest, err)
 	}
 
 	return nil
@@ -229,6 +383,24 @@ func (board *Board) OccupiedByColor(square Square, color Color) (bool, error) {
 	return occupied, nil
 }
 
+// moveDropRaw places the dropped piece of passed move to the empty destination.
+func (board *Board) moveDropRaw(move Move) error {
+	destPiece, err := board.GetPieceFromSquare(move.dest)
+	if err != nil {
+		return fmt.Errorf("GetPieceFromSquare(%s): %w", move.dest, err)
+	}
+
+	if destPiece != PieceNil {
+		return fmt.Errorf("drop destination %s is occupied by %s", move.dest, destPiece)
+	}
+
+	if err := board.setPieceToSquare(move.dropPiece, move.dest); err != nil {
+		return fmt.Errorf("setPieceToSquare(%s, %s): %w", move.dropPiece, move.dest, err)
+	}
+
+	return nil
+}
+
 // removePieceFromSquare removes piece from the passed square if exists.
 //
 // Please note that each piece has its own set of squares. If the squares of some pieces intersect, firstly, this is an
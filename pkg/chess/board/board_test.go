@@ -180,3 +180,154 @@ func TestBoardGetOccupiedBitboard(t *testing.T) {
 		})
 	}
 }
+
+func TestBoardEquals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		board  *Board
+		other  *Board
+		equals bool
+	}{
+		{"same", testBoardStart, testBoardStart, true},
+		{"different", testBoardStart, testBoardHarder, false},
+		{
+			"empty bitboard",
+			NewBoard(map[Piece]Bitboard{PieceWhiteKing: 0x0800000000000000, PieceWhiteQueen: BitboardNil}),
+			NewBoard(map[Piece]Bitboard{PieceWhiteKing: 0x0800000000000000}),
+			true,
+		},
+		{
+			"missing bitboard",
+			NewBoard(map[Piece]Bitboard{PieceWhiteKing: 0x0800000000000000}),
+			NewBoard(map[Piece]Bitboard{PieceWhiteKing: 0x0800000000000000, PieceWhiteQueen: 0x1000000000000000}),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if equals := test.board.Equals(test.other); equals != test.equals {
+				t.Fatalf("Equals(%+v, %+v) expected %t but got %t", test.board, test.other, test.equals, equals)
+			}
+		})
+	}
+}
+
+func TestBoardFEN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		board *Board
+		fen   string
+	}{
+		{"start", testBoardStart, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR"},
+		{"empty", NewBoard(nil), "8/8/8/8/8/8/8/8"},
+		{"king", NewBoard(map[Piece]Bitboard{PieceWhiteKing: 0x0800000000000000}), "8/8/8/8/8/8/8/4K3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fen, err := test.board.FEN()
+			if err != nil {
+				t.Fatalf("FEN(%+v) expected no error but got %v", test.board, err)
+			}
+
+			if fen != test.fen {
+				t.Fatalf("FEN(%+v) expected %q but got %q", test.board, test.fen, fen)
+			}
+		})
+	}
+}
+
+func TestBoardMoveRawDrop(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		move      Move
+		fen       string
+		errString string
+	}{
+		{"knight", NewMoveDrop(PieceWhiteKnight, SquareF3, MoveTagsNil), "4k3/8/8/8/8/5N2/8/4K3", ""},
+		{"pawn", NewMoveDrop(PieceBlackPawn, SquareD5, MoveTagsNil), "4k3/8/8/3p4/8/8/8/4K3", ""},
+		{
+			"occupied",
+			NewMoveDrop(PieceWhiteQueen, SquareE8, MoveTagsNil),
+			"4k3/8/8/8/8/8/8/4K3",
+			"drop destination SquareE8 is occupied by PieceBlackKing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			board, err := NewBoardFromFEN("4k3/8/8/8/8/8/8/4K3")
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(): %v", err)
+			}
+
+			err = board.MoveRaw(test.move)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("MoveRaw(%+v) expected error %q but got %q", test.move, test.errString, err)
+			}
+
+			expectedBoard, err := NewBoardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.fen, err)
+			}
+
+			if !board.Equals(expectedBoard) {
+				t.Fatalf("MoveRaw(%+v) expected board %q", test.move, test.fen)
+			}
+		})
+	}
+}
+
+func TestBoardExplodeRaw(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		fen       string
+		center    Square
+		expected  string
+		errString string
+	}{
+		{"ring", "4k3/8/8/2nbp3/2QN4/2rPB3/8/4K3", SquareD4, "4k3/8/8/4p3/8/3P4/8/4K3", ""},
+		{"corner", "4k3/8/8/8/8/8/PN6/RB2K3", SquareA1, "4k3/8/8/8/8/8/P7/4K3", ""},
+		{"unknown square", "4k3/8/8/8/8/8/8/4K3", SquareNil, "4k3/8/8/8/8/8/8/4K3", "king bitboard of SquareNil not found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			board, err := NewBoardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.fen, err)
+			}
+
+			err = board.ExplodeRaw(test.center)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("ExplodeRaw(%s) expected error %q but got %q", test.center, test.errString, err)
+			}
+
+			expectedBoard, err := NewBoardFromFEN(test.expected)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.expected, err)
+			}
+
+			if !board.Equals(expectedBoard) {
+				t.Fatalf("ExplodeRaw(%s) expected board %q", test.center, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mapping of pieces to Unicode chess glyphs.
+var renderUnicodePieces = map[Piece]string{
+	PieceWhiteKing:   "♔",
+	PieceWhiteQueen:  "♕",
+	PieceWhiteRook:   "♖",
+	PieceWhiteBishop: "♗",
+	PieceWhiteKnight: "♘",
+	PieceWhitePawn:   "♙",
+	PieceBlackKing:   "♚",
+	PieceBlackQueen:  "♛",
+	PieceBlackRook:   "♜",
+	PieceBlackBishop: "♝",
+	PieceBlackKnight: "♞",
+	PieceBlackPawn:   "♟",
+}
+
+// Symbol of the empty square in the rendered board.
+const renderEmptySquare = "."
+
+// RenderOptions configures the text rendering of the board.
+//
+// Zero value renders ASCII grid from the white perspective without coordinates and highlights.
+type RenderOptions struct {
+	// Render Unicode chess glyphs instead of FEN letters.
+	Unicode bool
+	// Color at the bottom of the grid. ColorNil means ColorWhite.
+	Perspective Color
+	// Render rank digits on the left and file letters below the grid.
+	Coordinates bool
+	// Squares to surround with square brackets.
+	Highlight Bitboard
+}
+
+// Render renders the board as 8x8 text grid for terminals and logs. Each square takes three characters, so highlighted
+// squares are surrounded with square brackets without shifting the grid.
+//
+// Return example for the start position with coordinates and highlighted E2:
+//
+//	8  r  n  b  q  k  b  n  r
+//	7  p  p  p  p  p  p  p  p
+//	6  .  .  .  .  .  .  .  .
+//	5  .  .  .  .  .  .  .  .
+//	4  .  .  .  .  .  .  .  .
+//	3  .  .  .  .  .  .  .  .
+//	2  P  P  P  P [P] P  P  P
+//	1  R  N  B  Q  K  B  N  R
+//	   a  b  c  d  e  f  g  h
+func (board *Board) Render(options RenderOptions) (string, error) {
+	ranks, files := Ranks, Files
+
+	switch options.Perspective {
+	case ColorNil, ColorWhite:
+		ranks = [len(Ranks)]Rank{Rank8, Rank7, Rank6, Rank5, Rank4, Rank3, Rank2, Rank1}
+	case ColorBlack:
+		files = [len(Files)]File{FileH, FileG, FileF, FileE, FileD, FileC, FileB, FileA}
+	default:
+		return "", fmt.Errorf("unknown perspective %s", options.Perspective)
+	}
+
+	pieceSymbols := boardFENPieces
+	if options.Unicode {
+		pieceSymbols = renderUnicodePieces
+	}
+
+	rows := make([]string, 0, len(ranks)+1)
+
+	for _, rank := range ranks {
+		var row strings.Builder
+
+		if options.Coordinates {
+			row.WriteString(string(rune('1'+rank-Rank1)) + " ")
+		}
+
+		for _, file := range files {
+			square, err := NewSquare(rank, file)
+			if err != nil {
+				return "", fmt.Errorf("NewSquare(%s, %s): %w", rank, file, err)
+			}
+
+			piece, err := board.GetPieceFromSquare(square)
+			if err != nil {
+				return "", fmt.Errorf("GetPieceFromSquare(%s): %w", square, err)
+			}
+
+			symbol := renderEmptySquare
+			if piece != PieceNil {
+				symbol = pieceSymbols[piece]
+			}
+
+			highlighted, err := options.Highlight.Occupied(square)
+			if err != nil {
+				return "", fmt.Errorf("0x%X.Occupied(%s): %w", options.Highlight, square, err)
+			}
+
+			if highlighted {
+				row.WriteString("[" + symbol + "]")
+			} else {
+				row.WriteString(" " + symbol + " ")
+			}
+		}
+
+		rows = append(rows, strings.TrimRight(row.String(), " "))
+	}
+
+	if options.Coordinates {
+		var row strings.Builder
+
+		row.WriteString("  ")
+
+		for _, file := range files {
+			row.WriteString(" " + string(rune('a'+file-FileA)) + " ")
+		}
+
+		rows = append(rows, strings.TrimRight(row.String(), " "))
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
@@ -0,0 +1,71 @@
+package board
+
+import (
+	"testing"
+)
+
+func TestBoardRender(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		fen       string
+		options   RenderOptions
+		str       string
+		errString string
+	}{
+		{
+			"ascii",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR",
+			RenderOptions{},
+			" r  n  b  q  k  b  n  r\n p  p  p  p  p  p  p  p\n .  .  .  .  .  .  .  .\n .  .  .  .  .  .  .  .\n" +
+				" .  .  .  .  .  .  .  .\n .  .  .  .  .  .  .  .\n P  P  P  P  P  P  P  P\n R  N  B  Q  K  B  N  R",
+			"",
+		},
+		{
+			"coordinates and highlight",
+			"4k3/8/8/8/4P3/8/8/4K3",
+			RenderOptions{Coordinates: true, Highlight: 0x0008000800000000},
+			"8  .  .  .  .  k  .  .  .\n7  .  .  .  .  .  .  .  .\n6  .  .  .  .  .  .  .  .\n" +
+				"5  .  .  .  .  .  .  .  .\n4  .  .  .  . [P] .  .  .\n3  .  .  .  .  .  .  .  .\n" +
+				"2  .  .  .  . [.] .  .  .\n1  .  .  .  .  K  .  .  .\n   a  b  c  d  e  f  g  h",
+			"",
+		},
+		{
+			"unicode black perspective",
+			"4k3/8/8/8/4P3/8/8/4K2R",
+			RenderOptions{Unicode: true, Perspective: ColorBlack, Coordinates: true},
+			"1  ♖  .  .  ♔  .  .  .  .\n2  .  .  .  .  .  .  .  .\n3  .  .  .  .  .  .  .  .\n" +
+				"4  .  .  .  ♙  .  .  .  .\n5  .  .  .  .  .  .  .  .\n6  .  .  .  .  .  .  .  .\n" +
+				"7  .  .  .  .  .  .  .  .\n8  .  .  .  ♚  .  .  .  .\n   h  g  f  e  d  c  b  a",
+			"",
+		},
+		{
+			"invalid perspective",
+			"4k3/8/8/8/8/8/8/4K3",
+			RenderOptions{Perspective: Color(123)},
+			"",
+			"unknown perspective <unknown Color=123>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			board, err := NewBoardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			str, err := board.Render(test.options)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("Render(%+v) expected error %q but got %q", test.options, test.errString, err)
+			}
+
+			if str != test.str {
+				t.Fatalf("Render(%+v) expected\n%s\nbut got\n%s", test.options, test.str, str)
+			}
+		})
+	}
+}
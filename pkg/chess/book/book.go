@@ -0,0 +1,286 @@
+package book
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+// PolyglotEntrySize is the size of the single Polyglot book entry in bytes.
+const PolyglotEntrySize = 16
+
+// Polyglot move bit fields: destination file, destination row, origin file, origin row and promotion.
+const (
+	polyglotMoveFieldMask       = 0b111
+	polyglotMoveDestRowShift    = 3
+	polyglotMoveOriginFileShift = 6
+	polyglotMoveOriginRowShift  = 9
+	polyglotMovePromoShift      = 12
+)
+
+// Roles of the Polyglot move promotion field values.
+var polyglotPromoRoles = [...]piece.Role{
+	piece.RoleNil, piece.RoleKnight, piece.RoleBishop, piece.RoleRook, piece.RoleQueen,
+}
+
+// PolyglotEntry represents the single entry of the Polyglot book.
+//
+// See http://hgm.nubati.net/book_format.html for the format description.
+type PolyglotEntry struct {
+	Key    uint64
+	Move   uint16
+	Weight uint16
+	Learn  uint32
+}
+
+// BookMove represents the move found in the book with its weight.
+type BookMove struct {
+	Move   move.Move
+	Weight uint16
+}
+
+// PolyglotBook reads Polyglot .bin opening books.
+//
+// The entries are sorted by key in the book, so the lookup is the binary search over the reader without loading the
+// whole book into the memory.
+type PolyglotBook struct {
+	reader       io.ReaderAt
+	entriesCount int
+	engine       game.Engine
+}
+
+// NewPolyglotBook creates a new PolyglotBook, which reads the book of passed size from passed reader.
+func NewPolyglotBook(reader io.ReaderAt, size int64, engine game.Engine) (*PolyglotBook, error) {
+	if size%PolyglotEntrySize != 0 {
+		return nil, fmt.Errorf("size %d is not a multiple of the entry size %d", size, PolyglotEntrySize)
+	}
+
+	return &PolyglotBook{
+		reader:       reader,
+		entriesCount: int(size / PolyglotEntrySize),
+		engine:       engine,
+	}, nil
+}
+
+// CalcMoves finds all book moves in passed position.
+//
+// Entries with the moves, which are not possible in the position, for example, because of the key collision, are
+// skipped.
+func (book *PolyglotBook) CalcMoves(pos *position.Position) ([]BookMove, error) {
+	key, err := CalcPolyglotKey(pos)
+	if err != nil {
+		return nil, fmt.Errorf("CalcPolyglotKey(): %w", err)
+	}
+
+	entries, err := book.readEntries(key)
+	if err != nil {
+		return nil, fmt.Errorf("readEntries(0x%X): %w", key, err)
+	}
+
+	moves := make([]BookMove, 0, len(entries))
+
+	for _, entry := range entries {
+		uci, err := decodePolyglotMove(entry.Move, pos)
+		if err != nil {
+			return nil, fmt.Errorf("decodePolyglotMove(0x%X): %w", entry.Move, err)
+		}
+
+		move, err := book.engine.CalcMoveFromUCI(pos, uci)
+		if err != nil {
+			continue
+		}
+
+		moves = append(moves, BookMove{Move: move, Weight: entry.Weight})
+	}
+
+	return moves, nil
+}
+
+// PickMove picks the random book move in passed position. The probability of the move is proportional to its weight.
+//
+// Returns false if there are no book moves with positive weights.
+func (book *PolyglotBook) PickMove(pos *position.Position, random *rand.Rand) (move.Move, bool, error) {
+	moves, err := book.CalcMoves(pos)
+	if err != nil {
+		return move.Move{}, false, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	var weightsSum uint64
+
+	for _, bookMove := range moves {
+		weightsSum += uint64(bookMove.Weight)
+	}
+
+	if weightsSum == 0 {
+		return move.Move{}, false, nil
+	}
+
+	target := random.Uint64N(weightsSum)
+
+	for _, bookMove := range moves {
+		if target < uint64(bookMove.Weight) {
+			return bookMove.Move, true, nil
+		}
+
+		target -= uint64(bookMove.Weight)
+	}
+
+	return move.Move{}, false, errors.New("weighted selection overflow")
+}
+
+// readEntries reads all entries with passed key.
+func (book *PolyglotBook) readEntries(key uint64) ([]PolyglotEntry, error) {
+	var readErr error
+
+	// Index of the first entry with the key not less than passed key.
+	index := sort.Search(book.entriesCount, func(index int) bool {
+		if readErr != nil {
+			return true
+		}
+
+		entry, err := book.readEntry(index)
+		if err != nil {
+			readErr = fmt.Errorf("readEntry(%d): %w", index, err)
+			return true
+		}
+
+		return entry.Key >= key
+	})
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var entries []PolyglotEntry
+
+	for ; index < book.entriesCount; index++ {
+		entry, err := book.readEntry(index)
+		if err != nil {
+			return nil, fmt.Errorf("readEntry(%d): %w", index, err)
+		}
+
+		if entry.Key != key {
+			break
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// readEntry reads the entry with passed index.
+func (book *PolyglotBook) readEntry(index int) (PolyglotEntry, error) {
+	var bytes [PolyglotEntrySize]byte
+
+	if _, err := book.reader.ReadAt(bytes[:], int64(index)*PolyglotEntrySize); err != nil {
+		return PolyglotEntry{}, fmt.Errorf("ReadAt(): %w", err)
+	}
+
+	return PolyglotEntry{
+		Key:    binary.BigEndian.Uint64(bytes[0:8]),
+		Move:   binary.BigEndian.Uint16(bytes[8:10]),
+		Weight: binary.BigEndian.Uint16(bytes[10:12]),
+		Learn:  binary.BigEndian.Uint32(bytes[12:16]),
+	}, nil
+}
+
+// decodePolyglotMove decodes the Polyglot move in passed position to UCI long algebraic notation.
+//
+// The Polyglot castling, which is encoded as the king takes his own rook, is converted to the usual king move.
+func decodePolyglotMove(raw uint16, pos *position.Position) (string, error) {
+	destFile := raw & polyglotMoveFieldMask
+	destRow := raw >> polyglotMoveDestRowShift & polyglotMoveFieldMask
+	originFile := raw >> polyglotMoveOriginFileShift & polyglotMoveFieldMask
+	originRow := raw >> polyglotMoveOriginRowShift & polyglotMoveFieldMask
+	promo := raw >> polyglotMovePromoShift & polyglotMoveFieldMask
+
+	origin, err := square.NewSquare(square.Rank(originRow+1), square.File(originFile+1))
+	if err != nil {
+		return "", fmt.Errorf("NewSquare(origin): %w", err)
+	}
+
+	dest, err := square.NewSquare(square.Rank(destRow+1), square.File(destFile+1))
+	if err != nil {
+		return "", fmt.Errorf("NewSquare(dest): %w", err)
+	}
+
+	if int(promo) >= len(polyglotPromoRoles) {
+		return "", fmt.Errorf("unknown promotion %d", promo)
+	}
+
+	originPiece, err := pos.Board().GetPieceFromSquare(origin)
+	if err != nil {
+		return "", fmt.Errorf("GetPieceFromSquare(%s): %w", origin, err)
+	}
+
+	castlingDest, err := calcPolyglotCastlingDest(pos, originPiece, dest)
+	if err != nil {
+		return "", fmt.Errorf("calcPolyglotCastlingDest(%s, %s): %w", originPiece, dest, err)
+	}
+
+	if castlingDest != square.SquareNil {
+		dest = castlingDest
+	}
+
+	m := move.NewMove(origin, dest, move.MoveTagsNil, polyglotPromoRoles[promo])
+
+	uci, err := m.UCI()
+	if err != nil {
+		return "", fmt.Errorf("UCI(%+v): %w", m, err)
+	}
+
+	return uci, nil
+}
+
+// calcPolyglotCastlingDest returns the destination of the king, if passed piece moving to passed destination is the
+// Polyglot castling, which is encoded as the king takes his own rook. Otherwise returns square.SquareNil.
+//
+// The rooks are taken from the castling rights of passed position, so the rooks on any files of Chess960 castle too.
+func calcPolyglotCastlingDest(
+	pos *position.Position, originPiece piece.Piece, dest square.Square,
+) (square.Square, error) {
+	for _, colorSide := range pos.CastlingRights() {
+		color, err := colorSide.Color()
+		if err != nil {
+			return square.SquareNil, fmt.Errorf("%s.Color(): %w", colorSide, err)
+		}
+
+		king, err := piece.NewPiece(color, piece.RoleKing)
+		if err != nil {
+			return square.SquareNil, fmt.Errorf("NewPiece(%s, %s): %w", color, piece.RoleKing, err)
+		}
+
+		rookSquare, err := pos.CastlingRookSquare(colorSide)
+		if err != nil {
+			return square.SquareNil, fmt.Errorf("CastlingRookSquare(%s): %w", colorSide, err)
+		}
+
+		if originPiece != king || rookSquare != dest {
+			continue
+		}
+
+		rank, err := colorSide.Rank()
+		if err != nil {
+			return square.SquareNil, fmt.Errorf("%s.Rank(): %w", colorSide, err)
+		}
+
+		kingDest, err := square.NewSquare(rank, colorSide.KingDestFile())
+		if err != nil {
+			return square.SquareNil, fmt.Errorf("NewSquare(%s, %s): %w", rank, colorSide.KingDestFile(), err)
+		}
+
+		return kingDest, nil
+	}
+
+	return square.SquareNil, nil
+}
@@ -0,0 +1,149 @@
+package book
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+const (
+	testMoveE2E4 = 0x031C
+	testMoveD2D4 = 0x02DB
+	testKeyStart = 0x463B96181691FC9C
+)
+
+// newTestPolyglotBook creates a new book with passed entries, which must be sorted by key.
+func newTestPolyglotBook(t *testing.T, entries []PolyglotEntry) *PolyglotBook {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	for _, entry := range entries {
+		if err := binary.Write(&buffer, binary.BigEndian, entry); err != nil {
+			t.Fatalf("Write(%+v): %v", entry, err)
+		}
+	}
+
+	book, err := NewPolyglotBook(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), game.Engine{})
+	if err != nil {
+		t.Fatalf("NewPolyglotBook(): %v", err)
+	}
+
+	return book
+}
+
+func TestPolyglotBookCalcMoves(t *testing.T) {
+	t.Parallel()
+
+	book := newTestPolyglotBook(t, []PolyglotEntry{
+		{Key: 0x0000000000000001, Move: testMoveE2E4, Weight: 1},
+		{Key: testKeyStart, Move: testMoveE2E4, Weight: 3},
+		{Key: testKeyStart, Move: testMoveD2D4, Weight: 2},
+		{Key: 0xFFFFFFFFFFFFFFFF, Move: testMoveD2D4, Weight: 1},
+	})
+
+	pos, err := position.NewPositionStart()
+	if err != nil {
+		t.Fatalf("NewPositionStart(): %v", err)
+	}
+
+	moves, err := book.CalcMoves(pos)
+	if err != nil {
+		t.Fatalf("CalcMoves(): %v", err)
+	}
+
+	var got []string
+
+	for _, bookMove := range moves {
+		uci, err := bookMove.Move.UCI()
+		if err != nil {
+			t.Fatalf("UCI(%+v): %v", bookMove.Move, err)
+		}
+
+		got = append(got, uci)
+	}
+
+	if expected := []string{"e2e4", "d2d4"}; !slices.Equal(got, expected) {
+		t.Fatalf("CalcMoves() expected %v but got %v", expected, got)
+	}
+}
+
+func TestPolyglotBookPickMove(t *testing.T) {
+	t.Parallel()
+
+	pos, err := position.NewPositionStart()
+	if err != nil {
+		t.Fatalf("NewPositionStart(): %v", err)
+	}
+
+	random := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // Weak random numbers are enough for the test.
+
+	book := newTestPolyglotBook(t, []PolyglotEntry{
+		{Key: testKeyStart, Move: testMoveE2E4, Weight: 1},
+		{Key: testKeyStart, Move: testMoveD2D4, Weight: 0},
+	})
+
+	for range 10 {
+		move, ok, err := book.PickMove(pos, random)
+		if err != nil || !ok {
+			t.Fatalf("PickMove() expected move but got %t, %v", ok, err)
+		}
+
+		if uci, _ := move.UCI(); uci != "e2e4" {
+			t.Fatalf("PickMove() expected e2e4 but got %q", uci)
+		}
+	}
+
+	emptyBook := newTestPolyglotBook(t, nil)
+
+	if _, ok, err := emptyBook.PickMove(pos, random); ok || err != nil {
+		t.Fatalf("PickMove() expected no move but got %t, %v", ok, err)
+	}
+}
+
+func TestDecodePolyglotMove(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fen  string
+		raw  uint16
+		uci  string
+	}{
+		{"pawn", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", testMoveE2E4, "e2e4"},
+		{"white king side castling", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", 0x0107, "e1g1"},
+		{"white queen side castling", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", 0x0100, "e1c1"},
+		{"black king side castling", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", 0x0F3F, "e8g8"},
+		{"Chess960 king side castling", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 w GBgb - 0 1", 0x0106, "e1g1"},
+		{"Chess960 queen side castling", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 w GBgb - 0 1", 0x0101, "e1c1"},
+		{"Chess960 black queen side castling", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 b GBgb - 0 1", 0x0F39, "e8c8"},
+		{"rook is not a king", "8/8/8/8/8/8/8/R3RK1k w - - 0 1", 0x0107, "e1h1"},
+		{"no castling right", "r3k2r/8/8/8/8/8/8/R3K2R w kq - 0 1", 0x0107, "e1h1"},
+		{"promotion", "8/4P3/8/8/8/8/8/4K2k w - - 0 1", 0x4D3C, "e7e8q"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			uci, err := decodePolyglotMove(test.raw, pos)
+			if err != nil {
+				t.Fatalf("decodePolyglotMove(0x%X): %v", test.raw, err)
+			}
+
+			if uci != test.uci {
+				t.Fatalf("decodePolyglotMove(0x%X) expected %q but got %q", test.raw, test.uci, uci)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package book
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+// Magic bytes at the start of the native book file, which also contain the format version.
+const nativeBookMagic = "LIMBOBK1"
+
+// BuilderOptions contains the filters of the games and positions added to the book.
+type BuilderOptions struct {
+	// Maximum count of plies of each game added to the book. Zero means no limit.
+	MaxPly uint
+	// Minimum count of games, in which the move was played, to include the move into the book.
+	MinGames uint
+	// Minimum "WhiteElo" and "BlackElo" tag values of the game. Zero means that the games without ratings are added.
+	MinRating uint
+}
+
+// NativeEntry represents the single entry of the native book: the move in the position with its statistics from the
+// point of view of the moving side.
+//
+// Keys and moves are the same as in PolyglotEntry, so the native book may be converted to the Polyglot one.
+type NativeEntry struct {
+	Key    uint64
+	Move   uint16
+	Games  uint32
+	Wins   uint32
+	Draws  uint32
+	Losses uint32
+}
+
+// Weight calculates the Polyglot weight of the entry: two points for a win and one point for a draw.
+func (entry NativeEntry) Weight() uint64 {
+	return 2*uint64(entry.Wins) + uint64(entry.Draws) //nolint:mnd // Two points for a win.
+}
+
+// Builder aggregates the statistics of the moves played in the games to build the book.
+type Builder struct {
+	engine  game.Engine
+	options BuilderOptions
+	// Statistics of the moves by Polyglot keys of the positions and Polyglot moves.
+	entries map[uint64]map[uint16]*NativeEntry
+}
+
+// NewBuilder creates a new empty Builder with passed options.
+func NewBuilder(engine game.Engine, options BuilderOptions) *Builder {
+	return &Builder{
+		engine:  engine,
+		options: options,
+		entries: make(map[uint64]map[uint16]*NativeEntry),
+	}
+}
+
+// Add replays passed game and adds its moves to the book.
+//
+// Returns false if the game is skipped, because it is not finished or the players ratings are too low. If the game
+// contains an impossible move, the moves before it are added anyway and the error is returned.
+func (builder *Builder) Add(pgnGame *pgn.Game) (bool, error) {
+	if pgnGame.Result == game.ResultNil {
+		return false, nil
+	}
+
+	ok, err := builder.checkRatings(pgnGame)
+	if err != nil {
+		return false, fmt.Errorf("checkRatings(): %w", err)
+	}
+
+	if !ok {
+		return false, nil
+	}
+
+	g, err := pgnGame.NewGameStart()
+	if err != nil {
+		return false, fmt.Errorf("NewGameStart(): %w", err)
+	}
+
+	for ply, san := range pgnGame.Moves {
+		if builder.options.MaxPly > 0 && uint(ply) >= builder.options.MaxPly {
+			break
+		}
+
+		pos := g.Position()
+
+		m, err := builder.engine.CalcMoveFromSAN(pos, san)
+		if err != nil {
+			return true, fmt.Errorf("ply #%d, CalcMoveFromSAN(%q): %w", ply, san, err)
+		}
+
+		if err := builder.addMove(pos, m, pgnGame.Result); err != nil {
+			return true, fmt.Errorf("ply #%d, addMove(%+v): %w", ply, m, err)
+		}
+
+		if err := g.Move(m); err != nil {
+			return true, fmt.Errorf("ply #%d, Move(%+v): %w", ply, m, err)
+		}
+	}
+
+	return true, nil
+}
+
+// NativeEntries returns the entries of the moves, which were played in the enough count of games, sorted by keys and
+// then by weights in descending order.
+func (builder *Builder) NativeEntries() []NativeEntry {
+	var entries []NativeEntry
+
+	for _, moves := range builder.entries {
+		for _, entry := range moves {
+			if uint(entry.Games) >= builder.options.MinGames {
+				entries = append(entries, *entry)
+			}
+		}
+	}
+
+	slices.SortFunc(entries, func(left, right NativeEntry) int {
+		return cmp.Or(
+			cmp.Compare(left.Key, right.Key),
+			cmp.Compare(right.Weight(), left.Weight()),
+			cmp.Compare(left.Move, right.Move),
+		)
+	})
+
+	return entries
+}
+
+// PolyglotEntries returns the entries of the Polyglot book in the order of NativeEntries.
+//
+// Weights are scaled down proportionally if the maximum weight does not fit into the entry.
+func (builder *Builder) PolyglotEntries() []PolyglotEntry {
+	nativeEntries := builder.NativeEntries()
+
+	var maxWeight uint64
+
+	for _, entry := range nativeEntries {
+		maxWeight = max(maxWeight, entry.Weight())
+	}
+
+	scale := 1.0
+	if maxWeight > math.MaxUint16 {
+		scale = float64(math.MaxUint16) / float64(maxWeight)
+	}
+
+	entries := make([]PolyglotEntry, 0, len(nativeEntries))
+
+	for _, entry := range nativeEntries {
+		weight := uint16(float64(entry.Weight()) * scale)
+
+		// Keep the moves with points playable after the scaling.
+		if weight == 0 && entry.Weight() > 0 {
+			weight = 1
+		}
+
+		entries = append(entries, PolyglotEntry{Key: entry.Key, Move: entry.Move, Weight: weight})
+	}
+
+	return entries
+}
+
+// WriteNative writes the native book to passed writer: the magic bytes, the count of entries and the entries in
+// big-endian byte order.
+func (builder *Builder) WriteNative(writer io.Writer) error {
+	entries := builder.NativeEntries()
+
+	if _, err := io.WriteString(writer, nativeBookMagic); err != nil {
+		return fmt.Errorf("WriteString(magic): %w", err)
+	}
+
+	if err := binary.Write(writer, binary.BigEndian, uint32(len(entries))); err != nil { //nolint:gosec // Book size.
+		return fmt.Errorf("Write(count): %w", err)
+	}
+
+	if err := binary.Write(writer, binary.BigEndian, entries); err != nil {
+		return fmt.Errorf("Write(entries): %w", err)
+	}
+
+	return nil
+}
+
+// WritePolyglot writes the Polyglot .bin book to passed writer.
+func (builder *Builder) WritePolyglot(writer io.Writer) error {
+	if err := binary.Write(writer, binary.BigEndian, builder.PolyglotEntries()); err != nil {
+		return fmt.Errorf("Write(entries): %w", err)
+	}
+
+	return nil
+}
+
+// addMove adds passed move in passed position of the game with passed result to the statistics.
+func (builder *Builder) addMove(pos *position.Position, m move.Move, result game.Result) error {
+	key, err := CalcPolyglotKey(pos)
+	if err != nil {
+		return fmt.Errorf("CalcPolyglotKey(): %w", err)
+	}
+
+	raw, err := encodePolyglotMove(m)
+	if err != nil {
+		return fmt.Errorf("encodePolyglotMove(%+v): %w", m, err)
+	}
+
+	moves, ok := builder.entries[key]
+	if !ok {
+		moves = make(map[uint16]*NativeEntry)
+		builder.entries[key] = moves
+	}
+
+	entry, ok := moves[raw]
+	if !ok {
+		entry = &NativeEntry{Key: key, Move: raw}
+		moves[raw] = entry
+	}
+
+	entry.Games++
+
+	switch {
+	case result == game.ResultDraw:
+		entry.Draws++
+	case (result == game.ResultWhiteWon) == (pos.ActiveColor() == piece.ColorWhite):
+		entry.Wins++
+	default:
+		entry.Losses++
+	}
+
+	return nil
+}
+
+// checkRatings checks that both players ratings from the game tags are not less than the minimum rating.
+func (builder *Builder) checkRatings(pgnGame *pgn.Game) (bool, error) {
+	if builder.options.MinRating == 0 {
+		return true, nil
+	}
+
+	for _, tagName := range [...]string{"WhiteElo", "BlackElo"} {
+		value, ok := pgnGame.GetTag(tagName)
+		if !ok || value == "" || value == "-" || value == "?" {
+			return false, nil
+		}
+
+		rating, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			return false, fmt.Errorf("ParseUint(%s, 10, 0): %w", value, err)
+		}
+
+		if uint(rating) < builder.options.MinRating {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ReadNativeEntries reads all entries of the native book written by WriteNative.
+func ReadNativeEntries(reader io.Reader) ([]NativeEntry, error) {
+	magic := make([]byte, len(nativeBookMagic))
+
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return nil, fmt.Errorf("ReadFull(magic): %w", err)
+	}
+
+	if string(magic) != nativeBookMagic {
+		return nil, errors.New("not a native book")
+	}
+
+	var count uint32
+
+	if err := binary.Read(reader, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("Read(count): %w", err)
+	}
+
+	entries := make([]NativeEntry, count)
+
+	if err := binary.Read(reader, binary.BigEndian, entries); err != nil {
+		return nil, fmt.Errorf("Read(entries): %w", err)
+	}
+
+	return entries, nil
+}
+
+// encodePolyglotMove encodes passed move to the Polyglot move.
+//
+// The castling is encoded as the king takes his own rook.
+func encodePolyglotMove(m move.Move) (uint16, error) {
+	dest := m.Dest()
+
+	originRank, err := m.Origin().Rank()
+	if err != nil {
+		return 0, fmt.Errorf("%s.Rank(): %w", m.Origin(), err)
+	}
+
+	switch {
+	case m.Tags().Contains(move.MoveTagKingSideCastle):
+		dest, err = square.NewSquare(originRank, square.FileH)
+	case m.Tags().Contains(move.MoveTagQueenSideCastle):
+		dest, err = square.NewSquare(originRank, square.FileA)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("NewSquare(%s, castling rook file): %w", originRank, err)
+	}
+
+	originIndex, err := calcPolyglotSquareIndex(m.Origin())
+	if err != nil {
+		return 0, fmt.Errorf("calcPolyglotSquareIndex(%s): %w", m.Origin(), err)
+	}
+
+	destIndex, err := calcPolyglotSquareIndex(dest)
+	if err != nil {
+		return 0, fmt.Errorf("calcPolyglotSquareIndex(%s): %w", dest, err)
+	}
+
+	promo := slices.Index(polyglotPromoRoles[:], m.PromoRole())
+	if promo < 0 {
+		return 0, fmt.Errorf("unknown promotion %s", m.PromoRole())
+	}
+
+	raw := uint16(destIndex) | uint16(originIndex)<<polyglotMoveOriginFileShift | uint16(promo)<<polyglotMovePromoShift
+
+	return raw, nil
+}
@@ -0,0 +1,144 @@
+package book
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+const testPGN = `[WhiteElo "2500"]
+[BlackElo "2400"]
+
+1. e4 e5 2. Nf3 1-0
+
+[WhiteElo "2500"]
+[BlackElo "2400"]
+
+1. e4 c5 0-1
+
+[WhiteElo "1500"]
+[BlackElo "2400"]
+
+1. d4 d5 1/2-1/2
+
+1. c4 *`
+
+// newTestBuilder creates a new builder with passed options and adds all games from the test PGN.
+func newTestBuilder(t *testing.T, options BuilderOptions) *Builder {
+	t.Helper()
+
+	builder := NewBuilder(game.Engine{}, options)
+	reader := pgn.NewReader(strings.NewReader(testPGN))
+
+	for range 4 {
+		pgnGame, err := reader.Read()
+		if err != nil {
+			t.Fatalf("Read(): %v", err)
+		}
+
+		if _, err := builder.Add(pgnGame); err != nil {
+			t.Fatalf("Add(): %v", err)
+		}
+	}
+
+	return builder
+}
+
+func TestBuilderNativeEntries(t *testing.T) {
+	t.Parallel()
+
+	builder := newTestBuilder(t, BuilderOptions{MaxPly: 1, MinGames: 1, MinRating: 2000})
+
+	expected := []NativeEntry{{Key: testKeyStart, Move: testMoveE2E4, Games: 2, Wins: 1, Losses: 1}}
+
+	if entries := builder.NativeEntries(); !slices.Equal(entries, expected) {
+		t.Fatalf("NativeEntries() expected %+v but got %+v", expected, entries)
+	}
+
+	builder = newTestBuilder(t, BuilderOptions{MaxPly: 1})
+
+	expected = []NativeEntry{
+		{Key: testKeyStart, Move: testMoveE2E4, Games: 2, Wins: 1, Losses: 1},
+		{Key: testKeyStart, Move: testMoveD2D4, Games: 1, Draws: 1},
+	}
+
+	if entries := builder.NativeEntries(); !slices.Equal(entries, expected) {
+		t.Fatalf("NativeEntries() expected %+v but got %+v", expected, entries)
+	}
+}
+
+func TestBuilderWrite(t *testing.T) {
+	t.Parallel()
+
+	builder := newTestBuilder(t, BuilderOptions{MinGames: 2})
+
+	var native bytes.Buffer
+
+	if err := builder.WriteNative(&native); err != nil {
+		t.Fatalf("WriteNative(): %v", err)
+	}
+
+	entries, err := ReadNativeEntries(&native)
+	if err != nil {
+		t.Fatalf("ReadNativeEntries(): %v", err)
+	}
+
+	if expected := builder.NativeEntries(); !slices.Equal(entries, expected) {
+		t.Fatalf("ReadNativeEntries() expected %+v but got %+v", expected, entries)
+	}
+
+	var polyglot bytes.Buffer
+
+	if err := builder.WritePolyglot(&polyglot); err != nil {
+		t.Fatalf("WritePolyglot(): %v", err)
+	}
+
+	if polyglot.Len() != len(entries)*PolyglotEntrySize {
+		t.Fatalf("WritePolyglot() expected %d bytes but got %d", len(entries)*PolyglotEntrySize, polyglot.Len())
+	}
+}
+
+func TestEncodePolyglotMove(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		move move.Move
+		raw  uint16
+	}{
+		{"pawn", move.NewMove(square.SquareE2, square.SquareE4, move.MoveTagsNil, piece.RoleNil), testMoveE2E4},
+		{
+			"white king side castling",
+			move.NewMove(square.SquareE1, square.SquareG1, move.MoveTags(move.MoveTagKingSideCastle), piece.RoleNil),
+			0x0107,
+		},
+		{
+			"black king side castling",
+			move.NewMove(square.SquareE8, square.SquareG8, move.MoveTags(move.MoveTagKingSideCastle), piece.RoleNil),
+			0x0F3F,
+		},
+		{"promotion", move.NewMove(square.SquareE7, square.SquareE8, move.MoveTagsNil, piece.RoleQueen), 0x4D3C},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			raw, err := encodePolyglotMove(test.move)
+			if err != nil {
+				t.Fatalf("encodePolyglotMove(%+v): %v", test.move, err)
+			}
+
+			if raw != test.raw {
+				t.Fatalf("encodePolyglotMove(%+v) expected 0x%X but got 0x%X", test.move, test.raw, raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package book
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+const (
+	// Offsets of the random numbers groups in polyglotRandoms.
+	polyglotCastlingOffset  = 768
+	polyglotEnPassantOffset = 772
+	polyglotTurnOffset      = 780
+
+	// Count of the squares on the board, which is the size of the piece random numbers group.
+	polyglotSquaresCount = 64
+)
+
+// Polyglot piece kinds. Note that the order differs from the Piece enumeration.
+var polyglotPieceKinds = map[piece.Piece]uint64{
+	piece.PieceBlackPawn:   0,
+	piece.PieceWhitePawn:   1,
+	piece.PieceBlackKnight: 2,
+	piece.PieceWhiteKnight: 3,
+	piece.PieceBlackBishop: 4,
+	piece.PieceWhiteBishop: 5,
+	piece.PieceBlackRook:   6,
+	piece.PieceWhiteRook:   7,
+	piece.PieceBlackQueen:  8,
+	piece.PieceWhiteQueen:  9,
+	piece.PieceBlackKing:   10,
+	piece.PieceWhiteKing:   11,
+}
+
+// Polyglot castling random numbers indexes relative to the castling offset.
+var polyglotCastlingIndexes = map[position.ColorSide]uint64{
+	position.ColorSideWhiteKing:  0,
+	position.ColorSideWhiteQueen: 1,
+	position.ColorSideBlackKing:  2,
+	position.ColorSideBlackQueen: 3,
+}
+
+// CalcPolyglotKey calculates the Zobrist key of passed position using the Polyglot book format random numbers.
+//
+// Note that the en passant file is hashed only if the pawn of the active color can capture en passant, as the format
+// requires.
+func CalcPolyglotKey(pos *position.Position) (uint64, error) {
+	if pos == nil {
+		return 0, errors.New("position is nil")
+	}
+
+	var key uint64
+
+	for p, kind := range polyglotPieceKinds {
+		for _, sq := range pos.Board().GetPieceBitboard(p).GetSquares() {
+			index, err := calcPolyglotSquareIndex(sq)
+			if err != nil {
+				return 0, fmt.Errorf("calcPolyglotSquareIndex(%s): %w", sq, err)
+			}
+
+			key ^= polyglotRandoms[polyglotSquaresCount*kind+index]
+		}
+	}
+
+	for _, colorSide := range pos.CastlingRights() {
+		index, ok := polyglotCastlingIndexes[colorSide]
+		if !ok {
+			return 0, fmt.Errorf("unknown color side %s", colorSide)
+		}
+
+		key ^= polyglotRandoms[polyglotCastlingOffset+index]
+	}
+
+	enPassantFile, err := calcPolyglotEnPassantFile(pos)
+	if err != nil {
+		return 0, fmt.Errorf("calcPolyglotEnPassantFile(): %w", err)
+	}
+
+	if enPassantFile != square.FileNil {
+		key ^= polyglotRandoms[polyglotEnPassantOffset+uint64(enPassantFile)-1]
+	}
+
+	if pos.ActiveColor() == piece.ColorWhite {
+		key ^= polyglotRandoms[polyglotTurnOffset]
+	}
+
+	return key, nil
+}
+
+// calcPolyglotEnPassantFile returns the file of the en passant square if the pawn of the active color stands next to
+// the pawn, which made the long move. Otherwise FileNil is returned.
+func calcPolyglotEnPassantFile(pos *position.Position) (square.File, error) {
+	enPassantSquare := pos.EnPassantSquare()
+	if enPassantSquare == square.SquareNil {
+		return square.FileNil, nil
+	}
+
+	file, err := enPassantSquare.File()
+	if err != nil {
+		return square.FileNil, fmt.Errorf("%s.File(): %w", enPassantSquare, err)
+	}
+
+	// The capturing pawn stands on the rank of the pawn, which made the long move.
+	pawn, pawnRank := piece.PieceWhitePawn, square.Rank5
+	if pos.ActiveColor() == piece.ColorBlack {
+		pawn, pawnRank = piece.PieceBlackPawn, square.Rank4
+	}
+
+	pawnBitboard := pos.Board().GetPieceBitboard(pawn)
+
+	for _, pawnFile := range [...]square.File{file - 1, file + 1} {
+		if pawnFile == square.FileNil || pawnFile > square.FileH {
+			continue
+		}
+
+		pawnSquare, err := square.NewSquare(pawnRank, pawnFile)
+		if err != nil {
+			return square.FileNil, fmt.Errorf("NewSquare(%s, %s): %w", pawnRank, pawnFile, err)
+		}
+
+		occupied, err := pawnBitboard.Occupied(pawnSquare)
+		if err != nil {
+			return square.FileNil, fmt.Errorf("0x%X.Occupied(%s): %w", pawnBitboard, pawnSquare, err)
+		}
+
+		if occupied {
+			return file, nil
+		}
+	}
+
+	return square.FileNil, nil
+}
+
+// calcPolyglotSquareIndex calculates the Polyglot index of passed square: 8*row+file, where A1 is zero.
+func calcPolyglotSquareIndex(sq square.Square) (uint64, error) {
+	rank, err := sq.Rank()
+	if err != nil {
+		return 0, fmt.Errorf("Rank(): %w", err)
+	}
+
+	file, err := sq.File()
+	if err != nil {
+		return 0, fmt.Errorf("File(): %w", err)
+	}
+
+	return uint64(len(square.Files))*(uint64(rank)-1) + uint64(file) - 1, nil
+}
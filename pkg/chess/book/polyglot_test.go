@@ -0,0 +1,68 @@
+package book
+
+import (
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+func TestCalcPolyglotKey(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from the Polyglot book format description.
+	tests := []struct {
+		name string
+		fen  string
+		key  uint64
+	}{
+		{"start", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 0x463B96181691FC9C},
+		{"e2e4", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", 0x823C9B50FD114196},
+		{"e2e4 d7d5", "rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", 0x0756B94461C50FB0},
+		{"e2e4 d7d5 e4e5", "rnbqkbnr/ppp1pppp/8/3pP3/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 2", 0x662FAFB965DB29D4},
+		{
+			"e2e4 d7d5 e4e5 f7f5",
+			"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3",
+			0x22A48B5A8E47FF78,
+		},
+		{
+			"e2e4 d7d5 e4e5 f7f5 e1e2",
+			"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPPKPPP/RNBQ1BNR b kq - 0 3",
+			0x652A607CA3F242C1,
+		},
+		{
+			"e2e4 d7d5 e4e5 f7f5 e1e2 e8f7",
+			"rnbq1bnr/ppp1pkpp/8/3pPp2/8/8/PPPPKPPP/RNBQ1BNR w - - 0 4",
+			0x00FDD303C946BDD9,
+		},
+		{
+			"a2a4 b7b5 h2h4 b5b4 c2c4",
+			"rnbqkbnr/p1pppppp/8/8/PpP4P/8/1P1PPPP1/RNBQKBNR b KQkq c3 0 3",
+			0x3C8123EA7B067637,
+		},
+		{
+			"a2a4 b7b5 h2h4 b5b4 c2c4 b4c3 a1a3",
+			"rnbqkbnr/p1pppppp/8/8/P6P/R1p5/1P1PPPP1/1NBQKBNR b Kkq - 0 4",
+			0x5C3F9B829B279560,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			key, err := CalcPolyglotKey(pos)
+			if err != nil {
+				t.Fatalf("CalcPolyglotKey(%q): %v", test.fen, err)
+			}
+
+			if key != test.key {
+				t.Fatalf("CalcPolyglotKey(%q) expected 0x%016X but got 0x%016X", test.fen, test.key, key)
+			}
+		})
+	}
+}
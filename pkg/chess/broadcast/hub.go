@@ -0,0 +1,400 @@
+// Package broadcast implements the hub, which fans out the events of the games to the spectators.
+package broadcast
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// EventType represents the kind of the event of the game.
+type EventType uint8
+
+const (
+	// EventTypeNil means that there is no event.
+	EventTypeNil EventType = iota
+	// EventTypeMove means that the move was made.
+	EventTypeMove
+	// EventTypeTakeback means that the moves were taken back.
+	EventTypeTakeback
+	// EventTypeClock means that the remaining time of the players was updated.
+	EventTypeClock
+	// EventTypeResult means that the game is over.
+	EventTypeResult
+	// EventTypeChat means that the chat message was sent.
+	EventTypeChat
+)
+
+// String returns string representation of current event type.
+func (eventType EventType) String() string {
+	switch eventType {
+	case EventTypeNil:
+		return "EventTypeNil"
+	case EventTypeMove:
+		return "EventTypeMove"
+	case EventTypeTakeback:
+		return "EventTypeTakeback"
+	case EventTypeClock:
+		return "EventTypeClock"
+	case EventTypeResult:
+		return "EventTypeResult"
+	case EventTypeChat:
+		return "EventTypeChat"
+	default:
+		return fmt.Sprintf("<unknown EventType=%d>", eventType)
+	}
+}
+
+// Event is the event of the game sent to the subscribers.
+type Event struct {
+	Type EventType
+	// Move in UCI and standard algebraic notation for the move event.
+	UCI string
+	SAN string
+	// FEN of the position after the move or the takeback.
+	FEN string
+	// Count of the taken back moves for the takeback event.
+	Plies int
+	// Remaining time of the players and the color of the running clock, which is piece.ColorNil if the clock is not
+	// running, for the clock event.
+	White  time.Duration
+	Black  time.Duration
+	Active piece.Color
+	// Result and the reason of the game end for the result event.
+	Result      game.Result
+	Termination game.Termination
+	// Author and text of the message for the chat event.
+	Author string
+	Text   string
+}
+
+// Snapshot is the state of the game sent to the subscriber on join.
+type Snapshot struct {
+	// FEN of the current position.
+	FEN string
+	// Made moves in UCI.
+	Moves []string
+	// Result and the reason of the game end, which are game.ResultNil and game.TerminationNil if the game is in
+	// progress.
+	Result      game.Result
+	Termination game.Termination
+}
+
+// Subscription is the subscription to the events of the game.
+type Subscription struct {
+	// State of the game at the moment of the subscription, after which the events follow.
+	Snapshot Snapshot
+	// Events of the game. The channel is closed if the subscriber is too slow to read the events, the game is removed
+	// from the hub or the subscription is closed.
+	Events <-chan Event
+
+	channel *channel
+	events  chan Event
+}
+
+// Close cancels the subscription and closes its events channel if it is not closed yet.
+func (subscription *Subscription) Close() {
+	subscription.channel.mu.Lock()
+	defer subscription.channel.mu.Unlock()
+
+	subscription.channel.unsubscribeLocked(subscription.events)
+}
+
+// Hub fans out the events of the games to the subscribers. It is safe for concurrent use.
+//
+// The moves, the takebacks and the results are taken from the game events, so the snapshot on join and the following
+// events are consistent. The clock and the chat events are published by the caller.
+type Hub struct {
+	bufferSize int
+
+	mu       sync.Mutex
+	channels map[string]*channel
+}
+
+// channel is the game with its subscribers.
+type channel struct {
+	removeListener func()
+
+	mu sync.Mutex
+	// State of the game built from its events.
+	snapshot    Snapshot
+	subscribers map[chan Event]struct{}
+	// Error of the conversion of the game event, after which the state is not consistent with the game.
+	err error
+}
+
+// NewHub creates a new Hub without games, which subscribers have passed count of the buffered events. The subscriber
+// is dropped if its buffer is full.
+func NewHub(bufferSize int) (*Hub, error) {
+	if bufferSize <= 0 {
+		return nil, fmt.Errorf("buffer size %d is not positive", bufferSize)
+	}
+
+	return &Hub{bufferSize: bufferSize, channels: make(map[string]*channel)}, nil
+}
+
+// AddGame adds passed game with passed ID to the hub. The events of the game are sent to the subscribers until the
+// game is removed.
+func (hub *Hub) AddGame(id string, g *game.Game) error {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if _, ok := hub.channels[id]; ok {
+		return fmt.Errorf("game %q is already added", id)
+	}
+
+	c := &channel{subscribers: make(map[chan Event]struct{})}
+
+	// The channel is locked before the listening, so the events wait for the state of the channel.
+	c.mu.Lock()
+
+	snapshot, removeListener := g.Listen(c.handleGameEvent)
+	c.removeListener = removeListener
+
+	err := c.resetLocked(snapshot)
+	c.err = err
+
+	c.mu.Unlock()
+
+	if err != nil {
+		// The listener is removed without the channel lock, because the game may wait for it with the game locked.
+		removeListener()
+		return fmt.Errorf("resetLocked(): %w", err)
+	}
+
+	hub.channels[id] = c
+
+	return nil
+}
+
+// RemoveGame removes the game with passed ID from the hub and closes the events channels of its subscribers.
+func (hub *Hub) RemoveGame(id string) error {
+	hub.mu.Lock()
+	c, ok := hub.channels[id]
+	delete(hub.channels, id)
+	hub.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("game %q not found", id)
+	}
+
+	c.removeListener()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for events := range c.subscribers {
+		c.unsubscribeLocked(events)
+	}
+
+	return nil
+}
+
+// Subscribe subscribes to the events of the game with passed ID. The subscription contains the snapshot of the game,
+// after which the events follow.
+func (hub *Hub) Subscribe(id string) (*Subscription, error) {
+	c, err := hub.findChannel(id)
+	if err != nil {
+		return nil, fmt.Errorf("findChannel(%q): %w", id, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, fmt.Errorf("game %q is broken: %w", id, c.err)
+	}
+
+	events := make(chan Event, hub.bufferSize)
+	c.subscribers[events] = struct{}{}
+
+	snapshot := c.snapshot
+	snapshot.Moves = slices.Clone(c.snapshot.Moves)
+
+	return &Subscription{Snapshot: snapshot, Events: events, channel: c, events: events}, nil
+}
+
+// Subscribers returns the count of the subscribers of the game with passed ID.
+func (hub *Hub) Subscribers(id string) (int, error) {
+	c, err := hub.findChannel(id)
+	if err != nil {
+		return 0, fmt.Errorf("findChannel(%q): %w", id, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.subscribers), nil
+}
+
+// PublishClock sends the clock event with passed remaining time of the players and passed color of the running clock
+// to the subscribers of the game with passed ID.
+func (hub *Hub) PublishClock(id string, white, black time.Duration, active piece.Color) error {
+	if err := hub.publish(id, Event{Type: EventTypeClock, White: white, Black: black, Active: active}); err != nil {
+		return fmt.Errorf("publish(%q): %w", id, err)
+	}
+
+	return nil
+}
+
+// PublishChat sends the chat event with passed author and text to the subscribers of the game with passed ID.
+func (hub *Hub) PublishChat(id, author, text string) error {
+	if err := hub.publish(id, Event{Type: EventTypeChat, Author: author, Text: text}); err != nil {
+		return fmt.Errorf("publish(%q): %w", id, err)
+	}
+
+	return nil
+}
+
+// publish sends passed event to the subscribers of the game with passed ID.
+func (hub *Hub) publish(id string, event Event) error {
+	c, err := hub.findChannel(id)
+	if err != nil {
+		return fmt.Errorf("findChannel(): %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.broadcastLocked(event)
+
+	return nil
+}
+
+// findChannel returns the channel of the game with passed ID.
+func (hub *Hub) findChannel(id string) (*channel, error) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	c, ok := hub.channels[id]
+	if !ok {
+		return nil, errors.New("game not found")
+	}
+
+	return c, nil
+}
+
+// handleGameEvent updates the state of the channel by passed game event and sends the event to the subscribers.
+//
+// It is called with the game locked.
+func (c *channel) handleGameEvent(gameEvent game.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	event, err := c.applyGameEventLocked(gameEvent)
+	if err != nil {
+		// The subscribers can not follow the game anymore, so they are dropped.
+		c.err = fmt.Errorf("applyGameEventLocked(%s): %w", gameEvent.Type, err)
+
+		for events := range c.subscribers {
+			c.unsubscribeLocked(events)
+		}
+
+		return
+	}
+
+	c.broadcastLocked(event)
+}
+
+// applyGameEventLocked updates the state of the channel by passed game event and returns the event for the
+// subscribers.
+//
+// The channel must be locked.
+func (c *channel) applyGameEventLocked(gameEvent game.Event) (Event, error) {
+	fen, err := gameEvent.Position.FEN()
+	if err != nil {
+		return Event{}, fmt.Errorf("FEN(): %w", err)
+	}
+
+	event := Event{Type: EventTypeNil, FEN: fen}
+
+	switch gameEvent.Type {
+	case game.EventTypeMove:
+		uci, err := gameEvent.Move.UCI()
+		if err != nil {
+			return Event{}, fmt.Errorf("UCI(%+v): %w", gameEvent.Move, err)
+		}
+
+		event.Type, event.UCI, event.SAN = EventTypeMove, uci, gameEvent.SAN
+		c.snapshot.Moves = append(c.snapshot.Moves, uci)
+	case game.EventTypeTakeback:
+		if gameEvent.Plies > len(c.snapshot.Moves) {
+			return Event{}, fmt.Errorf("%d plies of %d moves are taken back", gameEvent.Plies, len(c.snapshot.Moves))
+		}
+
+		event.Type, event.Plies = EventTypeTakeback, gameEvent.Plies
+		c.snapshot.Moves = c.snapshot.Moves[:len(c.snapshot.Moves)-gameEvent.Plies]
+	case game.EventTypeResult:
+		event.Type, event.Result, event.Termination = EventTypeResult, gameEvent.Result, gameEvent.Termination
+		c.snapshot.Result, c.snapshot.Termination = gameEvent.Result, gameEvent.Termination
+	case game.EventTypeNil:
+		return Event{}, errors.New("no event")
+	default:
+		return Event{}, errors.New("unknown event")
+	}
+
+	c.snapshot.FEN = fen
+
+	return event, nil
+}
+
+// resetLocked sets the state of the channel to passed snapshot of the game.
+//
+// The channel must be locked.
+func (c *channel) resetLocked(snapshot *game.Game) error {
+	fen, err := snapshot.Position().FEN()
+	if err != nil {
+		return fmt.Errorf("FEN(): %w", err)
+	}
+
+	moves := snapshot.Moves()
+	c.snapshot = Snapshot{FEN: fen, Moves: make([]string, 0, len(moves))}
+
+	for _, m := range moves {
+		uci, err := m.UCI()
+		if err != nil {
+			return fmt.Errorf("UCI(%+v): %w", m, err)
+		}
+
+		c.snapshot.Moves = append(c.snapshot.Moves, uci)
+	}
+
+	c.snapshot.Result, c.snapshot.Termination, err = snapshot.Result()
+	if err != nil {
+		return fmt.Errorf("Result(): %w", err)
+	}
+
+	return nil
+}
+
+// broadcastLocked sends passed event to all subscribers and drops the subscribers with the full buffer.
+//
+// The channel must be locked.
+func (c *channel) broadcastLocked(event Event) {
+	for events := range c.subscribers {
+		select {
+		case events <- event:
+		default:
+			c.unsubscribeLocked(events)
+		}
+	}
+}
+
+// unsubscribeLocked removes passed subscriber and closes its channel if it was not removed yet.
+//
+// The channel must be locked.
+func (c *channel) unsubscribeLocked(events chan Event) {
+	if _, ok := c.subscribers[events]; ok {
+		delete(c.subscribers, events)
+		close(events)
+	}
+}
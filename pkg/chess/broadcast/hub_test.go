@@ -0,0 +1,322 @@
+package broadcast
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// Moves of the test game in UCI.
+var testMoves = []string{
+	"e2e4", "e7e5", "g1f3", "b8c6", "f1c4", "g8f6", "d2d3", "f8c5", "c2c3", "d7d6", "b1d2", "a7a6",
+}
+
+func TestHub(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewHub(0); err == nil || err.Error() != "buffer size 0 is not positive" {
+		t.Fatalf("NewHub(0) expected error but got %q", err)
+	}
+
+	hub, err := NewHub(8)
+	if err != nil {
+		t.Fatalf("NewHub(): %v", err)
+	}
+
+	g, err := game.NewGameFromUCIs("", "e2e4")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(): %v", err)
+	}
+
+	if err := hub.AddGame("game", g); err != nil {
+		t.Fatalf("AddGame(): %v", err)
+	}
+
+	if err := hub.AddGame("game", g); err == nil || err.Error() != `game "game" is already added` {
+		t.Fatalf("AddGame() expected error but got %q", err)
+	}
+
+	if _, err := hub.Subscribe("unknown"); err == nil || err.Error() != `findChannel("unknown"): game not found` {
+		t.Fatalf("Subscribe(unknown) expected error but got %q", err)
+	}
+
+	subscription, err := hub.Subscribe("game")
+	if err != nil {
+		t.Fatalf("Subscribe(): %v", err)
+	}
+
+	if !slices.Equal(subscription.Snapshot.Moves, []string{"e2e4"}) || subscription.Snapshot.FEN == "" {
+		t.Fatalf("Subscribe() expected snapshot after e4 but got %+v", subscription.Snapshot)
+	}
+
+	slow, err := hub.Subscribe("game")
+	if err != nil {
+		t.Fatalf("Subscribe(slow): %v", err)
+	}
+
+	expected := []Event{
+		{Type: EventTypeMove, UCI: "e7e5", SAN: "e5"},
+		{Type: EventTypeClock, White: time.Minute, Black: 50 * time.Second, Active: piece.ColorWhite},
+		{Type: EventTypeChat, Author: "alice", Text: "hi"},
+		{Type: EventTypeResult, Result: game.ResultBlackWon, Termination: game.TerminationResignation},
+	}
+
+	m, err := game.Engine{}.CalcMoveFromUCI(g.Position(), "e7e5")
+	if err != nil {
+		t.Fatalf("CalcMoveFromUCI(): %v", err)
+	}
+
+	if err := g.Move(m); err != nil {
+		t.Fatalf("Move(): %v", err)
+	}
+
+	if err := hub.PublishClock("game", time.Minute, 50*time.Second, piece.ColorWhite); err != nil {
+		t.Fatalf("PublishClock(): %v", err)
+	}
+
+	if err := hub.PublishChat("game", "alice", "hi"); err != nil {
+		t.Fatalf("PublishChat(): %v", err)
+	}
+
+	if err := g.Resign(piece.ColorWhite); err != nil {
+		t.Fatalf("Resign(): %v", err)
+	}
+
+	for index, expectedEvent := range expected {
+		event := <-subscription.Events
+		event.FEN = ""
+
+		if event != expectedEvent {
+			t.Fatalf("event #%d expected %+v but got %+v", index, expectedEvent, event)
+		}
+	}
+
+	for range 8 {
+		if err := hub.PublishChat("game", "bob", "spam"); err != nil {
+			t.Fatalf("PublishChat(): %v", err)
+		}
+	}
+
+	// The slow subscriber has 4 events in the buffer, so it is dropped by the chat messages.
+	if count, err := hub.Subscribers("game"); err != nil || count != 1 {
+		t.Fatalf("Subscribers() expected 1 but got %d, %v", count, err)
+	}
+
+	if events := drainTestEvents(slow.Events); events != 8 {
+		t.Fatalf("slow subscriber expected 8 events before the drop but got %d", events)
+	}
+
+	if err := hub.RemoveGame("game"); err != nil {
+		t.Fatalf("RemoveGame(): %v", err)
+	}
+
+	if events := drainTestEvents(subscription.Events); events != 8 {
+		t.Fatalf("subscriber expected 8 chat events before the removal but got %d", events)
+	}
+
+	subscription.Close()
+
+	if err := hub.RemoveGame("game"); err == nil || err.Error() != `game "game" not found` {
+		t.Fatalf("RemoveGame() expected error but got %q", err)
+	}
+}
+
+func TestHubTakeback(t *testing.T) {
+	t.Parallel()
+
+	hub, err := NewHub(8)
+	if err != nil {
+		t.Fatalf("NewHub(): %v", err)
+	}
+
+	g, err := game.NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	if err := hub.AddGame("game", g); err != nil {
+		t.Fatalf("AddGame(): %v", err)
+	}
+
+	for _, uci := range [...]string{"e2e4", "e7e5"} {
+		m, err := game.Engine{}.CalcMoveFromUCI(g.Position(), uci)
+		if err != nil {
+			t.Fatalf("CalcMoveFromUCI(%q): %v", uci, err)
+		}
+
+		if err := g.Move(m); err != nil {
+			t.Fatalf("Move(%q): %v", uci, err)
+		}
+	}
+
+	if err := g.OfferTakeback(piece.ColorWhite); err != nil {
+		t.Fatalf("OfferTakeback(): %v", err)
+	}
+
+	if err := g.AcceptTakeback(piece.ColorBlack); err != nil {
+		t.Fatalf("AcceptTakeback(): %v", err)
+	}
+
+	subscription, err := hub.Subscribe("game")
+	if err != nil {
+		t.Fatalf("Subscribe(): %v", err)
+	}
+
+	start, err := g.Position().FEN()
+	if err != nil {
+		t.Fatalf("FEN(): %v", err)
+	}
+
+	if len(subscription.Snapshot.Moves) != 0 || subscription.Snapshot.FEN != start {
+		t.Fatalf("Subscribe() expected the start snapshot but got %+v", subscription.Snapshot)
+	}
+}
+
+func TestHubConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		earlySubscribers = 1000
+		lateSubscribers  = 1000
+	)
+
+	hub, err := NewHub(16)
+	if err != nil {
+		t.Fatalf("NewHub(): %v", err)
+	}
+
+	g, err := game.NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	if err := hub.AddGame("game", g); err != nil {
+		t.Fatalf("AddGame(): %v", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		slow []*Subscription
+	)
+
+	// The half of the early subscribers does not read the events, so they are dropped.
+	for subscriber := range earlySubscribers {
+		subscription, err := hub.Subscribe("game")
+		if err != nil {
+			t.Fatalf("Subscribe(): %v", err)
+		}
+
+		if subscriber%2 == 0 {
+			slow = append(slow, subscription)
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			followTestSubscription(t, subscription)
+		}()
+	}
+
+	for range lateSubscribers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			subscription, err := hub.Subscribe("game")
+			if err != nil {
+				t.Errorf("Subscribe(): %v", err)
+				return
+			}
+
+			followTestSubscription(t, subscription)
+		}()
+	}
+
+	for index, uci := range testMoves {
+		m, err := game.Engine{}.CalcMoveFromUCI(g.Position(), uci)
+		if err != nil {
+			t.Fatalf("move #%d, CalcMoveFromUCI(%q): %v", index, uci, err)
+		}
+
+		if err := g.Move(m); err != nil {
+			t.Fatalf("move #%d, Move(%q): %v", index, uci, err)
+		}
+
+		if err := hub.PublishClock("game", time.Minute, time.Minute, piece.ColorWhite); err != nil {
+			t.Fatalf("PublishClock(): %v", err)
+		}
+
+		if err := hub.PublishChat("game", "alice", uci); err != nil {
+			t.Fatalf("move #%d, PublishChat(): %v", index, err)
+		}
+	}
+
+	if err := g.Resign(piece.ColorBlack); err != nil {
+		t.Fatalf("Resign(): %v", err)
+	}
+
+	wg.Wait()
+
+	if err := hub.RemoveGame("game"); err != nil {
+		t.Fatalf("RemoveGame(): %v", err)
+	}
+
+	for _, subscription := range slow {
+		if events := drainTestEvents(subscription.Events); events > 16 {
+			t.Fatalf("slow subscriber expected at most 16 events but got %d", events)
+		}
+	}
+}
+
+// followTestSubscription reads the events of passed subscription to the test game until the result and checks that
+// the snapshot and the moves make the whole game. The subscription may be dropped before the result, then the moves
+// must be the start of the game.
+func followTestSubscription(t *testing.T, subscription *Subscription) {
+	t.Helper()
+
+	moves := subscription.Snapshot.Moves
+	result := subscription.Snapshot.Result
+
+	for result == game.ResultNil {
+		event, ok := <-subscription.Events
+		if !ok {
+			break
+		}
+
+		switch event.Type {
+		case EventTypeMove:
+			moves = append(moves, event.UCI)
+		case EventTypeResult:
+			result = event.Result
+		case EventTypeNil, EventTypeTakeback, EventTypeClock, EventTypeChat:
+		}
+	}
+
+	subscription.Close()
+
+	if result != game.ResultNil && (result != game.ResultWhiteWon || !slices.Equal(moves, testMoves)) {
+		t.Errorf("subscriber expected the whole game but got %v, %s", moves, result)
+	}
+
+	if result == game.ResultNil && !slices.Equal(moves, testMoves[:len(moves)]) {
+		t.Errorf("dropped subscriber expected the start of the game but got %v", moves)
+	}
+}
+
+// drainTestEvents reads passed events until the channel is closed and returns their count.
+func drainTestEvents(events <-chan Event) int {
+	var count int
+
+	for range events {
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,399 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+// Options configures the clock.
+type Options struct {
+	// Source of the current time and timers. Nil means SystemTimeSource.
+	TimeSource TimeSource
+	// OnFlag is called once if the player of passed color has run out of time. It is called without the clock lock.
+	// If the flag fall is noticed by Press, it is called in a new goroutine, so the caller of Press may hold its locks.
+	OnFlag func(color piece.Color)
+	// LagCompensation returns the part of the move time of passed color, which is not charged, for example, the
+	// network lag. The result is clamped between zero and the move time. Nil means no compensation.
+	//
+	// The compensation is applied on Press, so it does not postpone the flag fall noticed by the timer.
+	LagCompensation func(color piece.Color, elapsed time.Duration) time.Duration
+}
+
+// Clock is the chess clock of two players, which is safe for concurrent use.
+type Clock struct {
+	control TimeControl
+	options Options
+
+	mu      sync.Mutex
+	players map[piece.Color]*player
+	// Color of the running clock. It is piece.ColorNil before the start and after the stop.
+	active piece.Color
+	// Start of the current turn or the time of the last resume.
+	turnStart time.Time
+	// Time spent on the current turn before the last pause.
+	turnElapsed time.Duration
+	paused      bool
+	stopped     bool
+	flagged     piece.Color
+	timer       Timer
+	// Generation of the timer to ignore the calls of stopped timers.
+	generation uint64
+}
+
+// player is the clock state of one color.
+type player struct {
+	remaining time.Duration
+	// Index of the current period and count of moves made in it.
+	period      int
+	periodMoves uint
+}
+
+// NewClock creates a new stopped Clock with passed time control.
+func NewClock(control TimeControl, options Options) (*Clock, error) {
+	if err := control.Validate(); err != nil {
+		return nil, fmt.Errorf("Validate(): %w", err)
+	}
+
+	if options.TimeSource == nil {
+		options.TimeSource = SystemTimeSource{}
+	}
+
+	return &Clock{
+		control: control,
+		options: options,
+		players: map[piece.Color]*player{
+			piece.ColorWhite: {remaining: control.Periods[0].Time},
+			piece.ColorBlack: {remaining: control.Periods[0].Time},
+		},
+	}, nil
+}
+
+// Start starts the clock of passed color.
+func (clock *Clock) Start(color piece.Color) error {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	if clock.stopped {
+		return errors.New("clock is stopped")
+	}
+
+	if clock.active != piece.ColorNil {
+		return errors.New("clock is already started")
+	}
+
+	if _, ok := clock.players[color]; !ok {
+		return fmt.Errorf("unknown color %s", color)
+	}
+
+	clock.startLocked(color, clock.options.TimeSource.Now())
+
+	return nil
+}
+
+// Press ends the move of passed color, charges its time and starts the clock of the opponent. If the clock is not
+// started yet, it only starts the clock of the opponent.
+//
+// The delay, the increment and the next period are applied according to the time control.
+func (clock *Clock) Press(color piece.Color) error {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.pressLocked(color, true)
+}
+
+// PressPremove ends the move of passed color like Press, but the time spent on the move is not charged, because the
+// move was queued before the turn, for example, the premove. The increment is applied.
+func (clock *Clock) PressPremove(color piece.Color) error {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.pressLocked(color, false)
+}
+
+// pressLocked ends the move of passed color and charges the time spent on the move if passed charge flag is set.
+//
+// The clock must be locked.
+func (clock *Clock) pressLocked(color piece.Color, charge bool) error {
+	if clock.stopped {
+		return errors.New("clock is stopped")
+	}
+
+	if clock.paused {
+		return errors.New("clock is paused")
+	}
+
+	p, ok := clock.players[color]
+	if !ok {
+		return fmt.Errorf("unknown color %s", color)
+	}
+
+	opponentColor, err := color.Opposite()
+	if err != nil {
+		return fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	now := clock.options.TimeSource.Now()
+
+	if clock.active == piece.ColorNil {
+		clock.startLocked(opponentColor, now)
+		return nil
+	}
+
+	if clock.active != color {
+		return fmt.Errorf("clock of %s is not running", color)
+	}
+
+	var elapsed time.Duration
+	if charge {
+		elapsed = clock.calcElapsedLocked(now)
+	}
+
+	if clock.options.LagCompensation != nil {
+		compensation := clock.options.LagCompensation(color, elapsed)
+		elapsed -= max(0, min(compensation, elapsed))
+	}
+
+	charged := clock.calcChargedTime(elapsed)
+	if charged >= p.remaining {
+		clock.flagLocked(now)
+
+		if clock.options.OnFlag != nil {
+			go clock.options.OnFlag(color)
+		}
+
+		return errors.New("flag has fallen")
+	}
+
+	p.remaining -= charged
+
+	if clock.control.DelayMode == DelayModeBronstein {
+		p.remaining += min(elapsed, clock.control.Delay)
+	}
+
+	period := clock.control.Periods[p.period]
+	p.remaining += period.Increment
+	p.periodMoves++
+
+	if period.Moves > 0 && p.periodMoves == period.Moves {
+		// The last period with the count of moves is repeated.
+		if p.period+1 < len(clock.control.Periods) {
+			p.period++
+		}
+
+		p.periodMoves = 0
+		p.remaining += clock.control.Periods[p.period].Time
+	}
+
+	clock.startLocked(opponentColor, now)
+
+	return nil
+}
+
+// Pause pauses the running clock. The time spent on the current move is kept.
+func (clock *Clock) Pause() error {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	if clock.active == piece.ColorNil {
+		return errors.New("clock is not running")
+	}
+
+	if clock.paused {
+		return errors.New("clock is already paused")
+	}
+
+	clock.turnElapsed = clock.calcElapsedLocked(clock.options.TimeSource.Now())
+	clock.paused = true
+	clock.stopTimerLocked()
+
+	return nil
+}
+
+// Resume resumes the paused clock.
+func (clock *Clock) Resume() error {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	if !clock.paused {
+		return errors.New("clock is not paused")
+	}
+
+	now := clock.options.TimeSource.Now()
+
+	clock.paused = false
+	clock.turnStart = now
+	clock.scheduleLocked(now)
+
+	return nil
+}
+
+// Stop stops the clock forever, for example, after the game end. The time spent on the current move is charged.
+func (clock *Clock) Stop() {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	clock.stopLocked(clock.options.TimeSource.Now())
+}
+
+// Remaining returns the time left on the clock of passed color.
+func (clock *Clock) Remaining(color piece.Color) time.Duration {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.calcRemainingLocked(color, clock.options.TimeSource.Now())
+}
+
+// Active returns the color of the running clock or piece.ColorNil if the clock is not running.
+func (clock *Clock) Active() piece.Color {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.active
+}
+
+// Flagged returns the color, which has run out of time, or piece.ColorNil if there is no flag fall.
+func (clock *Clock) Flagged() piece.Color {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.flagged
+}
+
+// onTimer notices the flag fall of the running clock if the timer of passed generation is still actual.
+func (clock *Clock) onTimer(generation uint64) {
+	clock.mu.Lock()
+
+	if generation != clock.generation || clock.active == piece.ColorNil || clock.paused {
+		clock.mu.Unlock()
+		return
+	}
+
+	now := clock.options.TimeSource.Now()
+
+	if clock.calcLeftLocked(now) > 0 {
+		clock.scheduleLocked(now)
+		clock.mu.Unlock()
+
+		return
+	}
+
+	color := clock.active
+	clock.flagLocked(now)
+	clock.mu.Unlock()
+
+	if clock.options.OnFlag != nil {
+		clock.options.OnFlag(color)
+	}
+}
+
+// startLocked starts the turn of passed color.
+//
+// The clock must be locked.
+func (clock *Clock) startLocked(color piece.Color, now time.Time) {
+	clock.active = color
+	clock.turnStart = now
+	clock.turnElapsed = 0
+	clock.scheduleLocked(now)
+}
+
+// flagLocked marks the flag fall of the running clock and stops the clock.
+//
+// The clock must be locked.
+func (clock *Clock) flagLocked(now time.Time) {
+	clock.flagged = clock.active
+	clock.stopLocked(now)
+	clock.players[clock.flagged].remaining = 0
+}
+
+// stopLocked charges the time of the current move and stops the clock forever.
+//
+// The clock must be locked.
+func (clock *Clock) stopLocked(now time.Time) {
+	if clock.active != piece.ColorNil {
+		clock.players[clock.active].remaining = clock.calcRemainingLocked(clock.active, now)
+	}
+
+	clock.active = piece.ColorNil
+	clock.paused = false
+	clock.stopped = true
+	clock.stopTimerLocked()
+}
+
+// scheduleLocked replaces the timer with a new one, which fires at the flag fall of the running clock.
+//
+// The clock must be locked.
+func (clock *Clock) scheduleLocked(now time.Time) {
+	clock.stopTimerLocked()
+
+	generation := clock.generation
+	clock.timer = clock.options.TimeSource.AfterFunc(clock.calcLeftLocked(now), func() {
+		clock.onTimer(generation)
+	})
+}
+
+// stopTimerLocked stops the timer and invalidates its calls, which may be already in progress.
+//
+// The clock must be locked.
+func (clock *Clock) stopTimerLocked() {
+	if clock.timer != nil {
+		clock.timer.Stop()
+		clock.timer = nil
+	}
+
+	clock.generation++
+}
+
+// calcRemainingLocked calculates the time left on the clock of passed color.
+//
+// The clock must be locked.
+func (clock *Clock) calcRemainingLocked(color piece.Color, now time.Time) time.Duration {
+	p, ok := clock.players[color]
+	if !ok {
+		return 0
+	}
+
+	remaining := p.remaining
+	if color == clock.active {
+		remaining -= clock.calcChargedTime(clock.calcElapsedLocked(now))
+	}
+
+	return max(0, remaining)
+}
+
+// calcLeftLocked calculates the time left until the flag fall of the running clock.
+//
+// The clock must be locked.
+func (clock *Clock) calcLeftLocked(now time.Time) time.Duration {
+	left := clock.players[clock.active].remaining - clock.calcElapsedLocked(now)
+
+	if clock.control.DelayMode == DelayModeSimple {
+		left += clock.control.Delay
+	}
+
+	return left
+}
+
+// calcElapsedLocked calculates the time spent on the current move.
+//
+// The clock must be locked.
+func (clock *Clock) calcElapsedLocked(now time.Time) time.Duration {
+	if clock.paused {
+		return clock.turnElapsed
+	}
+
+	return clock.turnElapsed + now.Sub(clock.turnStart)
+}
+
+// calcChargedTime calculates the time, which is charged from the clock for the move of passed duration. The simple
+// delay is not charged.
+func (clock *Clock) calcChargedTime(elapsed time.Duration) time.Duration {
+	if clock.control.DelayMode == DelayModeSimple {
+		return max(0, elapsed-clock.control.Delay)
+	}
+
+	return elapsed
+}
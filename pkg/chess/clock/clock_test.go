@@ -0,0 +1,247 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+func TestClockPress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		control TimeControl
+		// Durations of the moves starting from white.
+		moves []time.Duration
+		white time.Duration
+		black time.Duration
+	}{
+		{
+			"sudden death",
+			NewTimeControlSuddenDeath(time.Minute),
+			[]time.Duration{10 * time.Second, 5 * time.Second, 20 * time.Second},
+			30 * time.Second,
+			55 * time.Second,
+		},
+		{
+			"fischer",
+			NewTimeControlFischer(time.Minute, 2*time.Second),
+			[]time.Duration{10 * time.Second, 5 * time.Second},
+			52 * time.Second,
+			57 * time.Second,
+		},
+		{
+			"bronstein",
+			NewTimeControlBronstein(time.Minute, 3*time.Second),
+			[]time.Duration{10 * time.Second, 2 * time.Second},
+			53 * time.Second,
+			time.Minute,
+		},
+		{
+			"simple delay",
+			NewTimeControlSimpleDelay(time.Minute, 3*time.Second),
+			[]time.Duration{10 * time.Second, 2 * time.Second},
+			53 * time.Second,
+			time.Minute,
+		},
+		{
+			"periods",
+			TimeControl{Periods: []Period{
+				{Moves: 2, Time: time.Minute, Increment: time.Second},
+				{Time: 30 * time.Second},
+			}},
+			[]time.Duration{10 * time.Second, 10 * time.Second, 10 * time.Second, 0, 10 * time.Second},
+			62 * time.Second,
+			82 * time.Second,
+		},
+		{
+			"repeated period",
+			TimeControl{Periods: []Period{{Moves: 1, Time: time.Minute}}},
+			[]time.Duration{10 * time.Second, 10 * time.Second, 10 * time.Second},
+			160 * time.Second,
+			110 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewManualTimeSource(time.Time{})
+
+			clock, err := NewClock(test.control, Options{TimeSource: source})
+			if err != nil {
+				t.Fatalf("NewClock(%+v): %v", test.control, err)
+			}
+
+			if err := clock.Start(piece.ColorWhite); err != nil {
+				t.Fatalf("Start(): %v", err)
+			}
+
+			color := piece.ColorWhite
+
+			for index, duration := range test.moves {
+				source.Advance(duration)
+
+				if err := clock.Press(color); err != nil {
+					t.Fatalf("move #%d, Press(%s): %v", index, color, err)
+				}
+
+				color, _ = color.Opposite()
+			}
+
+			if clock.Active() != color {
+				t.Fatalf("Active() expected %s but got %s", color, clock.Active())
+			}
+
+			clock.Stop()
+
+			if white := clock.Remaining(piece.ColorWhite); white != test.white {
+				t.Fatalf("Remaining(white) expected %s but got %s", test.white, white)
+			}
+
+			if black := clock.Remaining(piece.ColorBlack); black != test.black {
+				t.Fatalf("Remaining(black) expected %s but got %s", test.black, black)
+			}
+		})
+	}
+}
+
+func TestClockFlag(t *testing.T) {
+	t.Parallel()
+
+	source := NewManualTimeSource(time.Time{})
+
+	var flagged []piece.Color
+
+	clock, err := NewClock(NewTimeControlSimpleDelay(10*time.Second, 2*time.Second), Options{
+		TimeSource: source,
+		OnFlag: func(color piece.Color) {
+			flagged = append(flagged, color)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClock(): %v", err)
+	}
+
+	if err := clock.Press(piece.ColorWhite); err != nil {
+		t.Fatalf("Press(white): %v", err)
+	}
+
+	source.Advance(11 * time.Second)
+
+	if remaining := clock.Remaining(piece.ColorBlack); remaining != time.Second {
+		t.Fatalf("Remaining(black) expected 1s but got %s", remaining)
+	}
+
+	if err := clock.Pause(); err != nil {
+		t.Fatalf("Pause(): %v", err)
+	}
+
+	source.Advance(time.Minute)
+
+	if err := clock.Resume(); err != nil {
+		t.Fatalf("Resume(): %v", err)
+	}
+
+	source.Advance(999 * time.Millisecond)
+
+	if len(flagged) != 0 {
+		t.Fatalf("OnFlag() expected no calls but got %v", flagged)
+	}
+
+	source.Advance(time.Millisecond)
+
+	if len(flagged) != 1 || flagged[0] != piece.ColorBlack || clock.Flagged() != piece.ColorBlack {
+		t.Fatalf("OnFlag() expected call with black but got %v", flagged)
+	}
+
+	if clock.Remaining(piece.ColorBlack) != 0 || clock.Active() != piece.ColorNil {
+		t.Fatalf("expected stopped clock without time of black but got %s", clock.Remaining(piece.ColorBlack))
+	}
+
+	if err := clock.Press(piece.ColorBlack); err == nil || err.Error() != "clock is stopped" {
+		t.Fatalf("Press(black) expected error %q but got %q", "clock is stopped", err)
+	}
+}
+
+func TestClockPressPremove(t *testing.T) {
+	t.Parallel()
+
+	source := NewManualTimeSource(time.Time{})
+
+	clock, err := NewClock(NewTimeControlFischer(time.Minute, 2*time.Second), Options{TimeSource: source})
+	if err != nil {
+		t.Fatalf("NewClock(): %v", err)
+	}
+
+	if err := clock.Start(piece.ColorWhite); err != nil {
+		t.Fatalf("Start(): %v", err)
+	}
+
+	source.Advance(10 * time.Second)
+
+	if err := clock.PressPremove(piece.ColorWhite); err != nil {
+		t.Fatalf("PressPremove(white): %v", err)
+	}
+
+	if err := clock.PressPremove(piece.ColorWhite); err == nil || err.Error() != "clock of ColorWhite is not running" {
+		t.Fatalf("PressPremove(white) expected error but got %q", err)
+	}
+
+	clock.Stop()
+
+	if white := clock.Remaining(piece.ColorWhite); white != 62*time.Second {
+		t.Fatalf("Remaining(white) expected 1m2s but got %s", white)
+	}
+}
+
+func TestClockLagCompensation(t *testing.T) {
+	t.Parallel()
+
+	source := NewManualTimeSource(time.Time{})
+
+	clock, err := NewClock(NewTimeControlSuddenDeath(time.Minute), Options{
+		TimeSource: source,
+		LagCompensation: func(color piece.Color, _ time.Duration) time.Duration {
+			if color == piece.ColorWhite {
+				return 500 * time.Millisecond
+			}
+
+			return time.Hour
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClock(): %v", err)
+	}
+
+	if err := clock.Start(piece.ColorWhite); err != nil {
+		t.Fatalf("Start(): %v", err)
+	}
+
+	source.Advance(2 * time.Second)
+
+	if err := clock.Press(piece.ColorBlack); err == nil || err.Error() != "clock of ColorBlack is not running" {
+		t.Fatalf("Press(black) expected error but got %q", err)
+	}
+
+	for _, color := range [...]piece.Color{piece.ColorWhite, piece.ColorBlack} {
+		if err := clock.Press(color); err != nil {
+			t.Fatalf("Press(%s): %v", color, err)
+		}
+
+		source.Advance(2 * time.Second)
+	}
+
+	clock.Stop()
+
+	if white := clock.Remaining(piece.ColorWhite); white != 56500*time.Millisecond {
+		t.Fatalf("Remaining(white) expected 56.5s but got %s", white)
+	}
+
+	if black := clock.Remaining(piece.ColorBlack); black != time.Minute {
+		t.Fatalf("Remaining(black) expected 1m but got %s", black)
+	}
+}
@@ -0,0 +1,220 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DelayMode is the way the delay of the time control is applied to the move.
+type DelayMode uint8
+
+const (
+	// DelayModeNil means that the time control has no delay.
+	DelayModeNil DelayMode = iota
+	// DelayModeBronstein means that the time spent on the move, but not more than the delay, is added back after the
+	// move.
+	DelayModeBronstein
+	// DelayModeSimple means that the clock starts to count down only after the delay, as in the US simple delay.
+	DelayModeSimple
+)
+
+// String returns string representation of current delay mode.
+func (mode DelayMode) String() string {
+	switch mode {
+	case DelayModeNil:
+		return "DelayModeNil"
+	case DelayModeBronstein:
+		return "DelayModeBronstein"
+	case DelayModeSimple:
+		return "DelayModeSimple"
+	default:
+		return fmt.Sprintf("<unknown DelayMode=%d>", mode)
+	}
+}
+
+// Period is the part of the time control.
+type Period struct {
+	// Count of moves, which must be made in the period. Zero means the rest of the game.
+	Moves uint
+	// Time, which is added to the clock at the start of the period.
+	Time time.Duration
+	// Increment, which is added to the clock after each move of the period.
+	Increment time.Duration
+}
+
+// TimeControl is the sequence of periods with the optional delay of each move.
+//
+// The last period is repeated if it has the count of moves.
+type TimeControl struct {
+	Periods []Period
+	// Delay of each move, which is applied according to the delay mode.
+	Delay     time.Duration
+	DelayMode DelayMode
+}
+
+// NewTimeControlSuddenDeath creates a new time control, where the whole game must be played in passed time.
+func NewTimeControlSuddenDeath(duration time.Duration) TimeControl {
+	return TimeControl{Periods: []Period{{Time: duration}}}
+}
+
+// NewTimeControlFischer creates a new time control with passed Fischer increment after each move.
+func NewTimeControlFischer(duration, increment time.Duration) TimeControl {
+	return TimeControl{Periods: []Period{{Time: duration, Increment: increment}}}
+}
+
+// NewTimeControlBronstein creates a new time control with passed Bronstein delay of each move.
+func NewTimeControlBronstein(duration, delay time.Duration) TimeControl {
+	return TimeControl{Periods: []Period{{Time: duration}}, Delay: delay, DelayMode: DelayModeBronstein}
+}
+
+// NewTimeControlSimpleDelay creates a new time control with passed US simple delay of each move.
+func NewTimeControlSimpleDelay(duration, delay time.Duration) TimeControl {
+	return TimeControl{Periods: []Period{{Time: duration}}, Delay: delay, DelayMode: DelayModeSimple}
+}
+
+// NewTimeControlFromPGN parses the value of the PGN TimeControl tag. Periods are separated by colons and each period
+// is "seconds", "seconds+increment", "moves/seconds" or "moves/seconds+increment".
+//
+// PGN argument examples: "300", "180+2", "40/5400+30:1800+30".
+func NewTimeControlFromPGN(pgn string) (TimeControl, error) {
+	if pgn == "" {
+		return TimeControl{}, errors.New("empty time control")
+	}
+
+	texts := strings.Split(pgn, ":")
+	periods := make([]Period, 0, len(texts))
+
+	for index, text := range texts {
+		period, err := newPeriodFromPGN(text)
+		if err != nil {
+			return TimeControl{}, fmt.Errorf("period #%d, newPeriodFromPGN(%q): %w", index, text, err)
+		}
+
+		periods = append(periods, period)
+	}
+
+	control := TimeControl{Periods: periods}
+
+	if err := control.Validate(); err != nil {
+		return TimeControl{}, fmt.Errorf("Validate(): %w", err)
+	}
+
+	return control, nil
+}
+
+// PGN returns the value of the PGN TimeControl tag. Fractions of seconds and the delay are not represented.
+//
+// Return examples: "300", "180+2", "40/5400+30:1800+30".
+func (control TimeControl) PGN() string {
+	var builder strings.Builder
+
+	for index, period := range control.Periods {
+		if index > 0 {
+			builder.WriteByte(':')
+		}
+
+		if period.Moves > 0 {
+			builder.WriteString(strconv.FormatUint(uint64(period.Moves), 10))
+			builder.WriteByte('/')
+		}
+
+		builder.WriteString(strconv.FormatInt(int64(period.Time/time.Second), 10))
+
+		if period.Increment > 0 {
+			builder.WriteByte('+')
+			builder.WriteString(strconv.FormatInt(int64(period.Increment/time.Second), 10))
+		}
+	}
+
+	return builder.String()
+}
+
+// Validate checks that the time control has periods with non-negative durations, that only the last period is played
+// until the end of the game and that the delay matches its mode.
+func (control TimeControl) Validate() error {
+	if len(control.Periods) == 0 {
+		return errors.New("no periods")
+	}
+
+	if control.Periods[0].Time <= 0 {
+		return errors.New("no time in the first period")
+	}
+
+	for index, period := range control.Periods {
+		if period.Time < 0 || period.Increment < 0 {
+			return fmt.Errorf("period #%d has negative duration", index)
+		}
+
+		if period.Moves == 0 && index != len(control.Periods)-1 {
+			return fmt.Errorf("period #%d without moves is not the last", index)
+		}
+	}
+
+	if control.Delay < 0 {
+		return errors.New("negative delay")
+	}
+
+	switch control.DelayMode {
+	case DelayModeNil:
+		if control.Delay != 0 {
+			return errors.New("delay without mode")
+		}
+	case DelayModeBronstein, DelayModeSimple:
+	default:
+		return fmt.Errorf("unknown delay mode %s", control.DelayMode)
+	}
+
+	return nil
+}
+
+// newPeriodFromPGN parses the period of the PGN TimeControl tag.
+//
+// Text argument examples: "300", "180+2", "40/5400+30".
+func newPeriodFromPGN(text string) (Period, error) {
+	var period Period
+
+	if movesText, rest, ok := strings.Cut(text, "/"); ok {
+		moves, err := strconv.ParseUint(movesText, 10, 32)
+		if err != nil {
+			return Period{}, fmt.Errorf("ParseUint(%q): %w", movesText, err)
+		}
+
+		if moves == 0 {
+			return Period{}, errors.New("zero moves")
+		}
+
+		period.Moves = uint(moves)
+		text = rest
+	}
+
+	timeText, incrementText, hasIncrement := strings.Cut(text, "+")
+
+	var err error
+
+	period.Time, err = parseSeconds(timeText)
+	if err != nil {
+		return Period{}, fmt.Errorf("parseSeconds(%q): %w", timeText, err)
+	}
+
+	if hasIncrement {
+		period.Increment, err = parseSeconds(incrementText)
+		if err != nil {
+			return Period{}, fmt.Errorf("parseSeconds(%q): %w", incrementText, err)
+		}
+	}
+
+	return period, nil
+}
+
+// parseSeconds parses the non-negative integer count of seconds.
+func parseSeconds(text string) (time.Duration, error) {
+	seconds, err := strconv.ParseUint(text, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("ParseUint(): %w", err)
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
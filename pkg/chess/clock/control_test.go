@@ -0,0 +1,103 @@
+package clock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewTimeControlFromPGN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pgn       string
+		control   TimeControl
+		errString string
+	}{
+		{"300", NewTimeControlSuddenDeath(5 * time.Minute), ""},
+		{"180+2", NewTimeControlFischer(3*time.Minute, 2*time.Second), ""},
+		{
+			"40/5400+30:1800+30",
+			TimeControl{Periods: []Period{
+				{Moves: 40, Time: 90 * time.Minute, Increment: 30 * time.Second},
+				{Time: 30 * time.Minute, Increment: 30 * time.Second},
+			}},
+			"",
+		},
+		{"40/7200", TimeControl{Periods: []Period{{Moves: 40, Time: 2 * time.Hour}}}, ""},
+		{"", TimeControl{}, "empty time control"},
+		{"0+5", TimeControl{}, "Validate(): no time in the first period"},
+		{"300:40/60", TimeControl{}, "Validate(): period #0 without moves is not the last"},
+		{
+			"0/300",
+			TimeControl{},
+			"period #0, newPeriodFromPGN(\"0/300\"): zero moves",
+		},
+		{
+			"5m",
+			TimeControl{},
+			"period #0, newPeriodFromPGN(\"5m\"): parseSeconds(\"5m\"): ParseUint(): strconv.ParseUint: parsing \"5m\": " +
+				"invalid syntax",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pgn, func(t *testing.T) {
+			t.Parallel()
+
+			control, err := NewTimeControlFromPGN(test.pgn)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewTimeControlFromPGN(%q) expected error %q but got %q", test.pgn, test.errString, err)
+			}
+
+			if !reflect.DeepEqual(control, test.control) {
+				t.Fatalf("NewTimeControlFromPGN(%q) expected %+v but got %+v", test.pgn, test.control, control)
+			}
+
+			if err == nil && control.PGN() != test.pgn {
+				t.Fatalf("PGN() expected %q but got %q", test.pgn, control.PGN())
+			}
+		})
+	}
+}
+
+func TestTimeControlValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		control   TimeControl
+		errString string
+	}{
+		{"bronstein", NewTimeControlBronstein(time.Minute, 5*time.Second), ""},
+		{"simple delay", NewTimeControlSimpleDelay(time.Minute, 5*time.Second), ""},
+		{"no periods", TimeControl{}, "no periods"},
+		{
+			"negative increment",
+			NewTimeControlFischer(time.Minute, -time.Second),
+			"period #0 has negative duration",
+		},
+		{
+			"delay without mode",
+			TimeControl{Periods: []Period{{Time: time.Minute}}, Delay: time.Second},
+			"delay without mode",
+		},
+		{"negative delay", NewTimeControlBronstein(time.Minute, -time.Second), "negative delay"},
+		{
+			"unknown mode",
+			TimeControl{Periods: []Period{{Time: time.Minute}}, DelayMode: 3},
+			"unknown delay mode <unknown DelayMode=3>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.control.Validate()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("Validate() expected error %q but got %q", test.errString, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package clock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Timer is the scheduled call, which can be cancelled.
+type Timer interface {
+	// Stop cancels the call and returns false if the call was already made or cancelled.
+	Stop() bool
+}
+
+// TimeSource is the source of the current time and timers. Tests may replace it to control the time manually.
+type TimeSource interface {
+	Now() time.Time
+	// AfterFunc calls passed function in its own goroutine after passed duration.
+	AfterFunc(duration time.Duration, f func()) Timer
+}
+
+// SystemTimeSource is the time source, which uses the system clock.
+type SystemTimeSource struct{}
+
+// Now returns the current system time.
+func (SystemTimeSource) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc calls passed function after passed duration using time.AfterFunc.
+func (SystemTimeSource) AfterFunc(duration time.Duration, f func()) Timer {
+	return time.AfterFunc(duration, f)
+}
+
+// ManualTimeSource is the time source, which time is advanced manually. It is useful in the tests.
+type ManualTimeSource struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// manualTimer is the timer of ManualTimeSource.
+type manualTimer struct {
+	source *ManualTimeSource
+	at     time.Time
+	f      func()
+	done   bool
+}
+
+// NewManualTimeSource creates a new manual time source, which starts from passed time.
+func NewManualTimeSource(now time.Time) *ManualTimeSource {
+	return &ManualTimeSource{now: now}
+}
+
+// Now returns the current manual time.
+func (source *ManualTimeSource) Now() time.Time {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	return source.now
+}
+
+// AfterFunc schedules the call of passed function, when the time is advanced by passed duration.
+func (source *ManualTimeSource) AfterFunc(duration time.Duration, f func()) Timer {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	timer := &manualTimer{source: source, at: source.now.Add(duration), f: f}
+	source.timers = append(source.timers, timer)
+
+	return timer
+}
+
+// Advance advances the time by passed duration and calls the functions of the expired timers in the current
+// goroutine.
+func (source *ManualTimeSource) Advance(duration time.Duration) {
+	source.mu.Lock()
+	source.now = source.now.Add(duration)
+
+	var expired []*manualTimer
+
+	for _, timer := range source.timers {
+		if !timer.done && !timer.at.After(source.now) {
+			timer.done = true
+			expired = append(expired, timer)
+		}
+	}
+
+	source.timers = slices.DeleteFunc(source.timers, func(timer *manualTimer) bool { return timer.done })
+	source.mu.Unlock()
+
+	for _, timer := range expired {
+		timer.f()
+	}
+}
+
+// Stop cancels the call and returns false if the call was already made or cancelled.
+func (timer *manualTimer) Stop() bool {
+	timer.source.mu.Lock()
+	defer timer.source.mu.Unlock()
+
+	stopped := !timer.done
+	timer.done = true
+
+	return stopped
+}
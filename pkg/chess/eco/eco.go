@@ -0,0 +1,150 @@
+package eco
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/book"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Count of the columns in the dataset: ECO code, name and EPD of the position after the opening moves.
+const classifierColumnsCount = 3
+
+var (
+	// Openings dataset from https://github.com/lichess-org/chess-openings.
+	//
+	//go:embed openings.tsv
+	classifierOpeningsTSV string
+
+	// Classifier with the embedded dataset, which is created on the first use.
+	classifierDefault     *Classifier
+	classifierDefaultErr  error
+	classifierDefaultOnce sync.Once
+)
+
+// Opening represents the named opening from the Encyclopaedia of Chess Openings.
+type Opening struct {
+	ECO  string
+	Name string
+}
+
+// String returns human-readable representation of current opening.
+//
+// Return example: "Sicilian Defense: Najdorf Variation (B90)".
+func (opening Opening) String() string {
+	return fmt.Sprintf("%s (%s)", opening.Name, opening.ECO)
+}
+
+// Classifier maps positions to the openings.
+//
+// Positions are matched by Polyglot keys, so transpositions are recognized.
+type Classifier struct {
+	openings map[uint64]Opening
+}
+
+// NewClassifier returns the classifier with the embedded dataset.
+//
+// The dataset is parsed once, so the returned classifier is shared.
+func NewClassifier() (*Classifier, error) {
+	classifierDefaultOnce.Do(func() {
+		classifierDefault, classifierDefaultErr = NewClassifierFromTSV(strings.NewReader(classifierOpeningsTSV))
+	})
+
+	if classifierDefaultErr != nil {
+		return nil, fmt.Errorf("NewClassifierFromTSV(): %w", classifierDefaultErr)
+	}
+
+	return classifierDefault, nil
+}
+
+// NewClassifierFromTSV creates a new classifier with the dataset read from passed reader.
+//
+// The dataset is tab-separated values with the header: ECO code, name and EPD of the position after the opening
+// moves. If the same position has several openings, the first one is used.
+//
+// Line example: "B90	Sicilian Defense: Najdorf Variation	rnbqkb1r/1p2pppp/p2p1n2/8/3NP3/2N5/PPP2PPP/R1BQKB1R w KQkq -".
+func NewClassifierFromTSV(reader io.Reader) (*Classifier, error) {
+	classifier := &Classifier{openings: make(map[uint64]Opening)}
+
+	scanner := bufio.NewScanner(reader)
+
+	// Skip the header.
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+
+		return nil, errors.New("no header")
+	}
+
+	for lineNumber := 2; scanner.Scan(); lineNumber++ {
+		columns := strings.Split(scanner.Text(), "\t")
+		if len(columns) != classifierColumnsCount {
+			return nil, fmt.Errorf("line %d: expected %d columns but got %d", lineNumber, classifierColumnsCount, len(columns))
+		}
+
+		// EPD has no move counters.
+		fen := columns[2] + " 0 1"
+
+		pos, err := position.NewPositionFromFEN(fen)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: NewPositionFromFEN(%q): %w", lineNumber, fen, err)
+		}
+
+		key, err := book.CalcPolyglotKey(pos)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: CalcPolyglotKey(): %w", lineNumber, err)
+		}
+
+		if _, ok := classifier.openings[key]; !ok {
+			classifier.openings[key] = Opening{ECO: columns[0], Name: columns[1]}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Scan(): %w", err)
+	}
+
+	return classifier, nil
+}
+
+// ClassifyGame returns the deepest opening of passed game, that is, the opening of the latest position, which has one.
+//
+// Returns false if no position of the game is in the dataset.
+func (classifier *Classifier) ClassifyGame(g *game.Game) (Opening, bool, error) {
+	positions := g.Positions()
+
+	for index := len(positions) - 1; index >= 0; index-- {
+		opening, ok, err := classifier.ClassifyPosition(positions[index])
+		if err != nil {
+			return Opening{}, false, fmt.Errorf("position #%d, ClassifyPosition(): %w", index, err)
+		}
+
+		if ok {
+			return opening, true, nil
+		}
+	}
+
+	return Opening{}, false, nil
+}
+
+// ClassifyPosition returns the opening of passed position.
+//
+// Returns false if the position is not in the dataset.
+func (classifier *Classifier) ClassifyPosition(pos *position.Position) (Opening, bool, error) {
+	key, err := book.CalcPolyglotKey(pos)
+	if err != nil {
+		return Opening{}, false, fmt.Errorf("CalcPolyglotKey(): %w", err)
+	}
+
+	opening, ok := classifier.openings[key]
+
+	return opening, ok, nil
+}
@@ -0,0 +1,55 @@
+package eco
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+)
+
+func TestClassifierClassifyGame(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pgn     string
+		opening string
+		ok      bool
+	}{
+		{"1. e4 c5 2. Nf3 d6 3. d4 cxd4 4. Nxd4 Nf6 5. Nc3 a6 *", "Sicilian Defense: Najdorf Variation (B90)", true},
+		// Transposition to the Najdorf with the move order of the Open Sicilian.
+		{"1. e4 c5 2. Nc3 d6 3. Nf3 Nf6 4. d4 cxd4 5. Nxd4 a6 *", "Sicilian Defense: Najdorf Variation (B90)", true},
+		// The last moves are out of the dataset, so the deepest opening is returned.
+		{"1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 4. h4 h5 *", "Ruy Lopez: Morphy Defense (C70)", true},
+		{"*", "", false},
+	}
+
+	classifier, err := NewClassifier()
+	if err != nil {
+		t.Fatalf("NewClassifier(): %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.pgn, func(t *testing.T) {
+			t.Parallel()
+
+			pgnGame, err := pgn.NewReader(strings.NewReader(test.pgn)).Read()
+			if err != nil {
+				t.Fatalf("Read(): %v", err)
+			}
+
+			g, err := pgnGame.NewGame()
+			if err != nil {
+				t.Fatalf("NewGame(): %v", err)
+			}
+
+			opening, ok, err := classifier.ClassifyGame(g)
+			if err != nil {
+				t.Fatalf("ClassifyGame(): %v", err)
+			}
+
+			if ok != test.ok || (ok && opening.String() != test.opening) {
+				t.Fatalf("ClassifyGame() expected %q, %t but got %q, %t", test.opening, test.ok, opening, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package explorer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+// Magic bytes at the start of the index file, which also contain the format version.
+const indexMagic = "LIMBOEX1"
+
+// Write writes the index to passed writer: the magic bytes, the positions sorted by keys and the materials sorted by
+// signatures in big-endian byte order.
+func (index *Index) Write(writer io.Writer) error {
+	buffered := bufio.NewWriter(writer)
+
+	if _, err := buffered.WriteString(indexMagic); err != nil {
+		return fmt.Errorf("WriteString(magic): %w", err)
+	}
+
+	if err := writeUint32(buffered, len(index.positions)); err != nil {
+		return fmt.Errorf("writeUint32(positions): %w", err)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(index.positions)) {
+		if err := index.writePosition(buffered, key); err != nil {
+			return fmt.Errorf("writePosition(%x): %w", key, err)
+		}
+	}
+
+	if err := writeUint32(buffered, len(index.materials)); err != nil {
+		return fmt.Errorf("writeUint32(materials): %w", err)
+	}
+
+	for _, signature := range slices.Sorted(maps.Keys(index.materials)) {
+		entry := index.materials[signature]
+
+		if err := writeString(buffered, signature); err != nil {
+			return fmt.Errorf("writeString(%s): %w", signature, err)
+		}
+
+		if err := writeStatsAndIDs(buffered, entry.stats, entry.ids); err != nil {
+			return fmt.Errorf("%s, writeStatsAndIDs(): %w", signature, err)
+		}
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("Flush(): %w", err)
+	}
+
+	return nil
+}
+
+// ReadIndex reads the index written by Write. The index uses passed engine to replay the moves of the added games.
+func ReadIndex(reader io.Reader, engine game.Engine) (*Index, error) {
+	buffered := bufio.NewReader(reader)
+	magic := make([]byte, len(indexMagic))
+
+	if _, err := io.ReadFull(buffered, magic); err != nil {
+		return nil, fmt.Errorf("ReadFull(magic): %w", err)
+	}
+
+	if string(magic) != indexMagic {
+		return nil, errors.New("not an index")
+	}
+
+	index := NewIndex(engine)
+
+	var positionsCount uint32
+
+	if err := binary.Read(buffered, binary.BigEndian, &positionsCount); err != nil {
+		return nil, fmt.Errorf("Read(positions): %w", err)
+	}
+
+	for positionIndex := range positionsCount {
+		if err := index.readPosition(buffered); err != nil {
+			return nil, fmt.Errorf("position #%d, readPosition(): %w", positionIndex, err)
+		}
+	}
+
+	var materialsCount uint32
+
+	if err := binary.Read(buffered, binary.BigEndian, &materialsCount); err != nil {
+		return nil, fmt.Errorf("Read(materials): %w", err)
+	}
+
+	for materialIndex := range materialsCount {
+		signature, err := readString(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("material #%d, readString(): %w", materialIndex, err)
+		}
+
+		stats, ids, err := readStatsAndIDs(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("material #%d, readStatsAndIDs(): %w", materialIndex, err)
+		}
+
+		index.materials[signature] = &materialEntry{ids: ids, stats: stats}
+	}
+
+	return index, nil
+}
+
+// writePosition writes the key, the statistics, the game IDs and the next moves of the position with passed key.
+func (index *Index) writePosition(writer io.Writer, key uint64) error {
+	entry := index.positions[key]
+
+	if err := binary.Write(writer, binary.BigEndian, key); err != nil {
+		return fmt.Errorf("Write(key): %w", err)
+	}
+
+	if err := writeStatsAndIDs(writer, entry.stats, entry.ids); err != nil {
+		return fmt.Errorf("writeStatsAndIDs(): %w", err)
+	}
+
+	if err := writeUint32(writer, len(entry.moves)); err != nil {
+		return fmt.Errorf("writeUint32(moves): %w", err)
+	}
+
+	for _, uci := range slices.Sorted(maps.Keys(entry.moves)) {
+		if err := writeString(writer, uci); err != nil {
+			return fmt.Errorf("writeString(%s): %w", uci, err)
+		}
+
+		if err := binary.Write(writer, binary.BigEndian, *entry.moves[uci]); err != nil {
+			return fmt.Errorf("Write(%s stats): %w", uci, err)
+		}
+	}
+
+	return nil
+}
+
+// readPosition reads the position written by writePosition and adds it to the index.
+func (index *Index) readPosition(reader io.Reader) error {
+	var key uint64
+
+	if err := binary.Read(reader, binary.BigEndian, &key); err != nil {
+		return fmt.Errorf("Read(key): %w", err)
+	}
+
+	stats, ids, err := readStatsAndIDs(reader)
+	if err != nil {
+		return fmt.Errorf("readStatsAndIDs(): %w", err)
+	}
+
+	var movesCount uint32
+
+	if err := binary.Read(reader, binary.BigEndian, &movesCount); err != nil {
+		return fmt.Errorf("Read(moves): %w", err)
+	}
+
+	entry := &positionEntry{ids: ids, stats: stats, moves: make(map[string]*Stats, movesCount)}
+
+	for moveIndex := range movesCount {
+		uci, err := readString(reader)
+		if err != nil {
+			return fmt.Errorf("move #%d, readString(): %w", moveIndex, err)
+		}
+
+		var moveStats Stats
+
+		if err := binary.Read(reader, binary.BigEndian, &moveStats); err != nil {
+			return fmt.Errorf("move #%d, Read(stats): %w", moveIndex, err)
+		}
+
+		entry.moves[uci] = &moveStats
+	}
+
+	index.positions[key] = entry
+
+	return nil
+}
+
+// writeStatsAndIDs writes passed statistics, the count of passed game IDs and the IDs.
+func writeStatsAndIDs(writer io.Writer, stats Stats, ids []uint32) error {
+	if err := binary.Write(writer, binary.BigEndian, stats); err != nil {
+		return fmt.Errorf("Write(stats): %w", err)
+	}
+
+	if err := writeUint32(writer, len(ids)); err != nil {
+		return fmt.Errorf("writeUint32(IDs): %w", err)
+	}
+
+	if err := binary.Write(writer, binary.BigEndian, ids); err != nil {
+		return fmt.Errorf("Write(IDs): %w", err)
+	}
+
+	return nil
+}
+
+// readStatsAndIDs reads the statistics and the game IDs written by writeStatsAndIDs.
+func readStatsAndIDs(reader io.Reader) (Stats, []uint32, error) {
+	var stats Stats
+
+	if err := binary.Read(reader, binary.BigEndian, &stats); err != nil {
+		return Stats{}, nil, fmt.Errorf("Read(stats): %w", err)
+	}
+
+	var count uint32
+
+	if err := binary.Read(reader, binary.BigEndian, &count); err != nil {
+		return Stats{}, nil, fmt.Errorf("Read(count): %w", err)
+	}
+
+	if count > stats.Games() {
+		return Stats{}, nil, fmt.Errorf("%d IDs of %d games", count, stats.Games())
+	}
+
+	ids := make([]uint32, count)
+
+	if err := binary.Read(reader, binary.BigEndian, ids); err != nil {
+		return Stats{}, nil, fmt.Errorf("Read(IDs): %w", err)
+	}
+
+	return stats, ids, nil
+}
+
+// writeUint32 writes passed count as 4-byte integer.
+func writeUint32(writer io.Writer, count int) error {
+	if uint64(count) > math.MaxUint32 {
+		return fmt.Errorf("count %d is too large", count)
+	}
+
+	if err := binary.Write(writer, binary.BigEndian, uint32(count)); err != nil {
+		return fmt.Errorf("Write(): %w", err)
+	}
+
+	return nil
+}
+
+// writeString writes passed string prefixed by its length as 1 byte.
+func writeString(writer io.Writer, s string) error {
+	if len(s) > math.MaxUint8 {
+		return fmt.Errorf("string of %d bytes is too long", len(s))
+	}
+
+	if err := binary.Write(writer, binary.BigEndian, append([]byte{byte(len(s))}, s...)); err != nil {
+		return fmt.Errorf("Write(): %w", err)
+	}
+
+	return nil
+}
+
+// readString reads the string written by writeString.
+func readString(reader io.Reader) (string, error) {
+	var length uint8
+
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return "", fmt.Errorf("Read(length): %w", err)
+	}
+
+	data := make([]byte, length)
+
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return "", fmt.Errorf("ReadFull(): %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,291 @@
+// Package explorer implements the index of the positions and the material of the games to search the games, which
+// reached the position or the material, with the statistics of their results.
+package explorer
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/rylenko/limbo/pkg/chess/archive"
+	"github.com/rylenko/limbo/pkg/chess/book"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Stats is the count of the games by their results.
+type Stats struct {
+	WhiteWins uint32
+	Draws     uint32
+	BlackWins uint32
+}
+
+// Games returns the total count of the games.
+func (stats Stats) Games() uint32 {
+	return stats.WhiteWins + stats.Draws + stats.BlackWins
+}
+
+// add adds the game with passed result to the statistics.
+func (stats *Stats) add(result game.Result) {
+	switch result {
+	case game.ResultWhiteWon:
+		stats.WhiteWins++
+	case game.ResultBlackWon:
+		stats.BlackWins++
+	case game.ResultDraw:
+		stats.Draws++
+	}
+}
+
+// MoveStats is the statistics of the games, in which the move was played in the position.
+type MoveStats struct {
+	// Move in UCI and standard algebraic notation.
+	UCI   string
+	SAN   string
+	Stats Stats
+}
+
+// PositionResult is the result of the search by the position.
+type PositionResult struct {
+	// IDs of the games, which reached the position, in ascending order.
+	GameIDs []uint32
+	Stats   Stats
+	// Next moves sorted by the count of the games in descending order and then by UCI.
+	Moves []MoveStats
+}
+
+// MaterialResult is the result of the search by the material signature.
+type MaterialResult struct {
+	// IDs of the games, which reached the material, in ascending order.
+	GameIDs []uint32
+	Stats   Stats
+}
+
+// positionEntry is the indexed position.
+type positionEntry struct {
+	ids   []uint32
+	stats Stats
+	// Statistics of the next moves by UCI.
+	moves map[string]*Stats
+}
+
+// materialEntry is the indexed material signature.
+type materialEntry struct {
+	ids   []uint32
+	stats Stats
+}
+
+// Index keeps the positions by their Polyglot keys and the material signatures of the finished standard games.
+//
+// A game is counted once per position, next move and material, even if it reached them several times.
+type Index struct {
+	engine    game.Engine
+	positions map[uint64]*positionEntry
+	materials map[string]*materialEntry
+}
+
+// NewIndex creates a new empty Index, which uses passed engine to replay the moves.
+func NewIndex(engine game.Engine) *Index {
+	return &Index{
+		engine:    engine,
+		positions: make(map[uint64]*positionEntry),
+		materials: make(map[string]*materialEntry),
+	}
+}
+
+// Add adds the positions and the material of passed game with passed ID to the index. The IDs must be added in
+// ascending order.
+//
+// Returns false if the game is skipped, because it is not finished or it is not the standard one.
+func (index *Index) Add(id uint32, g *game.Game) (bool, error) {
+	result, _, err := g.Result()
+	if err != nil {
+		return false, fmt.Errorf("Result(): %w", err)
+	}
+
+	if result == game.ResultNil || g.Variant().Name() != game.NewVariantStandard(index.engine).Name() {
+		return false, nil
+	}
+
+	positions, moves := g.Positions(), g.Moves()
+	seenMoves := make(map[uint64]map[string]struct{})
+	seenMaterials := make(map[string]struct{})
+
+	for ply, pos := range positions {
+		key, err := book.CalcPolyglotKey(pos)
+		if err != nil {
+			return false, fmt.Errorf("ply #%d, CalcPolyglotKey(): %w", ply, err)
+		}
+
+		var uci string
+
+		if ply < len(moves) {
+			if uci, err = moves[ply].UCI(); err != nil {
+				return false, fmt.Errorf("ply #%d, UCI(%+v): %w", ply, moves[ply], err)
+			}
+		}
+
+		index.addPosition(id, key, uci, result, seenMoves)
+
+		signature, err := CalcMaterialSignature(pos)
+		if err != nil {
+			return false, fmt.Errorf("ply #%d, CalcMaterialSignature(): %w", ply, err)
+		}
+
+		if _, ok := seenMaterials[signature]; !ok {
+			seenMaterials[signature] = struct{}{}
+			index.addMaterial(id, signature, result)
+		}
+	}
+
+	return true, nil
+}
+
+// AddArchive adds all games of passed archive reader to the index. The ID of the game is its number in the archive
+// starting from zero.
+//
+// Returns the count of the added games.
+func (index *Index) AddArchive(reader *archive.Reader) (int, error) {
+	var added int
+
+	for id := uint32(0); ; id++ {
+		_, g, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return added, nil
+		}
+
+		if err != nil {
+			return added, fmt.Errorf("game #%d, Read(): %w", id, err)
+		}
+
+		ok, err := index.Add(id, g)
+		if err != nil {
+			return added, fmt.Errorf("game #%d, Add(): %w", id, err)
+		}
+
+		if ok {
+			added++
+		}
+	}
+}
+
+// SearchFEN searches the games, which reached the position with passed FEN.
+func (index *Index) SearchFEN(fen string) (PositionResult, error) {
+	pos, err := position.NewPositionFromFEN(fen)
+	if err != nil {
+		return PositionResult{}, fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+	}
+
+	result, err := index.SearchPosition(pos)
+	if err != nil {
+		return PositionResult{}, fmt.Errorf("SearchPosition(): %w", err)
+	}
+
+	return result, nil
+}
+
+// SearchPosition searches the games, which reached passed position. The result is empty if there are no such games.
+func (index *Index) SearchPosition(pos *position.Position) (PositionResult, error) {
+	key, err := book.CalcPolyglotKey(pos)
+	if err != nil {
+		return PositionResult{}, fmt.Errorf("CalcPolyglotKey(): %w", err)
+	}
+
+	entry, ok := index.positions[key]
+	if !ok {
+		return PositionResult{}, nil
+	}
+
+	result := PositionResult{
+		GameIDs: slices.Clone(entry.ids),
+		Stats:   entry.stats,
+		Moves:   make([]MoveStats, 0, len(entry.moves)),
+	}
+
+	for uci, stats := range entry.moves {
+		m, err := index.engine.CalcMoveFromUCI(pos, uci)
+		if err != nil {
+			return PositionResult{}, fmt.Errorf("CalcMoveFromUCI(%q): %w", uci, err)
+		}
+
+		san, err := index.engine.CalcMoveSAN(pos, m)
+		if err != nil {
+			return PositionResult{}, fmt.Errorf("CalcMoveSAN(%q): %w", uci, err)
+		}
+
+		result.Moves = append(result.Moves, MoveStats{UCI: uci, SAN: san, Stats: *stats})
+	}
+
+	slices.SortFunc(result.Moves, func(left, right MoveStats) int {
+		return cmp.Or(cmp.Compare(right.Stats.Games(), left.Stats.Games()), cmp.Compare(left.UCI, right.UCI))
+	})
+
+	return result, nil
+}
+
+// SearchMaterial searches the games, which reached the material with passed signature. The signature is normalized by
+// NormalizeMaterialSignature. The result is empty if there are no such games.
+//
+// Signature argument examples: "KRPvKR", "R+P vs R".
+func (index *Index) SearchMaterial(signature string) (MaterialResult, error) {
+	normalized, err := NormalizeMaterialSignature(signature)
+	if err != nil {
+		return MaterialResult{}, fmt.Errorf("NormalizeMaterialSignature(%q): %w", signature, err)
+	}
+
+	entry, ok := index.materials[normalized]
+	if !ok {
+		return MaterialResult{}, nil
+	}
+
+	return MaterialResult{GameIDs: slices.Clone(entry.ids), Stats: entry.stats}, nil
+}
+
+// addPosition adds the game with passed ID and result to the position with passed key and passed next move, which is
+// empty after the last position. The seen moves contain the positions and the moves, which were already added.
+func (index *Index) addPosition(
+	id uint32, key uint64, uci string, result game.Result, seenMoves map[uint64]map[string]struct{},
+) {
+	entry, ok := index.positions[key]
+	if !ok {
+		entry = &positionEntry{moves: make(map[string]*Stats)}
+		index.positions[key] = entry
+	}
+
+	seen, ok := seenMoves[key]
+	if !ok {
+		seen = make(map[string]struct{})
+		seenMoves[key] = seen
+
+		entry.ids = append(entry.ids, id)
+		entry.stats.add(result)
+	}
+
+	if _, ok := seen[uci]; ok || uci == "" {
+		return
+	}
+
+	seen[uci] = struct{}{}
+
+	stats, ok := entry.moves[uci]
+	if !ok {
+		stats = &Stats{}
+		entry.moves[uci] = stats
+	}
+
+	stats.add(result)
+}
+
+// addMaterial adds the game with passed ID and result to the material with passed signature.
+func (index *Index) addMaterial(id uint32, signature string, result game.Result) {
+	entry, ok := index.materials[signature]
+	if !ok {
+		entry = &materialEntry{}
+		index.materials[signature] = entry
+	}
+
+	entry.ids = append(entry.ids, id)
+	entry.stats.add(result)
+}
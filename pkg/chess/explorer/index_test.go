@@ -0,0 +1,138 @@
+package explorer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/archive"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	engine := game.Engine{}
+
+	var buffer bytes.Buffer
+
+	writer := archive.NewWriter(&buffer)
+
+	games := []struct {
+		ucis []string
+		// Color, which has run out of time, or piece.ColorNil if the game is not timed out.
+		flagged piece.Color
+	}{
+		{[]string{"e2e4", "e7e5", "g1f3"}, piece.ColorBlack},
+		{[]string{"e2e4", "e7e5", "d1h5"}, piece.ColorNil},
+		{[]string{"f2f3", "e7e5", "g2g4", "d8h4"}, piece.ColorNil},
+		{[]string{"e2e4", "c7c5"}, piece.ColorWhite},
+	}
+
+	for index, test := range games {
+		g, err := game.NewGameFromUCIs("", test.ucis...)
+		if err != nil {
+			t.Fatalf("game #%d, NewGameFromUCIs(): %v", index, err)
+		}
+
+		if test.flagged != piece.ColorNil {
+			if err := g.Timeout(test.flagged); err != nil {
+				t.Fatalf("game #%d, Timeout(): %v", index, err)
+			}
+		}
+
+		if err := writer.Write(archive.Header{}, g); err != nil {
+			t.Fatalf("game #%d, Write(): %v", index, err)
+		}
+	}
+
+	index := NewIndex(engine)
+
+	added, err := index.AddArchive(archive.NewReader(&buffer, engine))
+	if err != nil || added != 3 {
+		t.Fatalf("AddArchive() expected 3 added games but got %d, %v", added, err)
+	}
+
+	var data bytes.Buffer
+
+	if err := index.Write(&data); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+
+	readIndex, err := ReadIndex(&data, engine)
+	if err != nil {
+		t.Fatalf("ReadIndex(): %v", err)
+	}
+
+	if !reflect.DeepEqual(readIndex, index) {
+		t.Fatal("ReadIndex() expected the written index")
+	}
+
+	result, err := readIndex.SearchFEN("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1")
+	if err != nil {
+		t.Fatalf("SearchFEN(): %v", err)
+	}
+
+	expected := PositionResult{
+		GameIDs: []uint32{0, 3},
+		Stats:   Stats{WhiteWins: 1, BlackWins: 1},
+		Moves: []MoveStats{
+			{UCI: "c7c5", SAN: "c5", Stats: Stats{BlackWins: 1}},
+			{UCI: "e7e5", SAN: "e5", Stats: Stats{WhiteWins: 1}},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("SearchFEN() expected %+v but got %+v", expected, result)
+	}
+
+	material, err := readIndex.SearchMaterial("KQRRBBNNPPPPPPPP vs KQRRBBNNPPPPPPPP")
+	if err != nil {
+		t.Fatalf("SearchMaterial(): %v", err)
+	}
+
+	expectedMaterial := MaterialResult{GameIDs: []uint32{0, 2, 3}, Stats: Stats{WhiteWins: 1, BlackWins: 2}}
+
+	if !reflect.DeepEqual(material, expectedMaterial) {
+		t.Fatalf("SearchMaterial() expected %+v but got %+v", expectedMaterial, material)
+	}
+
+	if material, err := readIndex.SearchMaterial("R+P vs R"); err != nil || material.GameIDs != nil {
+		t.Fatalf("SearchMaterial() expected no games but got %+v, %v", material, err)
+	}
+}
+
+func TestIndexAddRepetition(t *testing.T) {
+	t.Parallel()
+
+	g, err := game.NewGameFromUCIs("", "g1f3", "g8f6", "f3g1", "f6g8")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(): %v", err)
+	}
+
+	if err := g.Timeout(piece.ColorWhite); err != nil {
+		t.Fatalf("Timeout(): %v", err)
+	}
+
+	index := NewIndex(game.Engine{})
+
+	if ok, err := index.Add(7, g); !ok || err != nil {
+		t.Fatalf("Add() expected added game but got %t, %v", ok, err)
+	}
+
+	result, err := index.SearchFEN("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	if err != nil {
+		t.Fatalf("SearchFEN(): %v", err)
+	}
+
+	expected := PositionResult{
+		GameIDs: []uint32{7},
+		Stats:   Stats{BlackWins: 1},
+		Moves:   []MoveStats{{UCI: "g1f3", SAN: "Nf3", Stats: Stats{BlackWins: 1}}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("SearchFEN() expected %+v but got %+v", expected, result)
+	}
+}
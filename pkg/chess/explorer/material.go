@@ -0,0 +1,111 @@
+package explorer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+const (
+	// Separator of the white and black material in the signature.
+	materialSeparator = "v"
+
+	// Letters of the roles in the order of the signature.
+	materialRoleLetters = "KQRBNP"
+)
+
+// CalcMaterialSignature calculates the material signature of passed position: the pieces of white, the separator and
+// the pieces of black. The pieces are ordered from the king to the pawns.
+//
+// Return examples: "KQRRBBNNPPPPPPPPvKQRRBBNNPPPPPPPP", "KRPvKR", "KvK".
+func CalcMaterialSignature(pos *position.Position) (string, error) {
+	var builder strings.Builder
+
+	for index, color := range [...]piece.Color{piece.ColorWhite, piece.ColorBlack} {
+		if index > 0 {
+			builder.WriteString(materialSeparator)
+		}
+
+		pieces, err := piece.NewPiecesOfColor(color)
+		if err != nil {
+			return "", fmt.Errorf("NewPiecesOfColor(%s): %w", color, err)
+		}
+
+		for _, p := range pieces {
+			role, err := p.Role()
+			if err != nil {
+				return "", fmt.Errorf("%s.Role(): %w", p, err)
+			}
+
+			letter, err := role.FEN()
+			if err != nil {
+				return "", fmt.Errorf("%s.FEN(): %w", role, err)
+			}
+
+			count := len(pos.Board().GetPieceBitboard(p).GetSquares())
+			builder.WriteString(strings.Repeat(strings.ToUpper(letter), count))
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// NormalizeMaterialSignature converts passed human-readable material to the signature. The pieces may be in any order
+// and case and separated by "+", and the sides are separated by "v" or "vs". The kings are added if they are missing.
+//
+// Signature argument examples: "R+P vs R", "krp v kr", "KRPvKR".
+func NormalizeMaterialSignature(signature string) (string, error) {
+	signature = strings.NewReplacer(" ", "", "+", "").Replace(strings.ToUpper(signature))
+
+	white, black, ok := strings.Cut(signature, "VS")
+	if !ok {
+		white, black, ok = strings.Cut(signature, "V")
+	}
+
+	if !ok {
+		return "", fmt.Errorf("no separator in %q", signature)
+	}
+
+	sides := [...]string{white, black}
+
+	for index, side := range sides {
+		normalized, err := normalizeMaterialSide(side)
+		if err != nil {
+			return "", fmt.Errorf("normalizeMaterialSide(%q): %w", side, err)
+		}
+
+		sides[index] = normalized
+	}
+
+	return sides[0] + materialSeparator + sides[1], nil
+}
+
+// normalizeMaterialSide orders the pieces of the side from the king to the pawns and adds the missing king.
+func normalizeMaterialSide(side string) (string, error) {
+	var counts [len(materialRoleLetters)]int
+
+	for _, letter := range side {
+		index := strings.IndexRune(materialRoleLetters, letter)
+		if index < 0 {
+			return "", fmt.Errorf("unknown piece %q", letter)
+		}
+
+		counts[index]++
+	}
+
+	if counts[0] > 1 {
+		return "", fmt.Errorf("%d kings", counts[0])
+	}
+
+	counts[0] = 1
+
+	var builder strings.Builder
+
+	for index, count := range counts {
+		builder.WriteString(strings.Repeat(string(materialRoleLetters[index]), count))
+	}
+
+	return builder.String(), nil
+}
@@ -0,0 +1,75 @@
+package explorer
+
+import (
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+func TestCalcMaterialSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fen       string
+		signature string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "KQRRBBNNPPPPPPPPvKQRRBBNNPPPPPPPP"},
+		{"8/8/4k3/8/3KP3/8/8/r6R w - - 0 60", "KRPvKR"},
+		{"8/8/4k3/8/3K4/8/8/8 b - - 0 80", "KvK"},
+		{"8/8/2nk4/8/3K4/8/8/8 w - - 0 70", "KvKN"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fen, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(): %v", err)
+			}
+
+			signature, err := CalcMaterialSignature(pos)
+			if err != nil {
+				t.Fatalf("CalcMaterialSignature(): %v", err)
+			}
+
+			if signature != test.signature {
+				t.Fatalf("CalcMaterialSignature() expected %q but got %q", test.signature, signature)
+			}
+		})
+	}
+}
+
+func TestNormalizeMaterialSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		signature  string
+		normalized string
+		errString  string
+	}{
+		{"KRPvKR", "KRPvKR", ""},
+		{"R+P vs R", "KRPvKR", ""},
+		{"krp v kr", "KRPvKR", ""},
+		{"PNQ vs", "KQNPvK", ""},
+		{"v", "KvK", ""},
+		{"KRP", "", `no separator in "KRP"`},
+		{"KXvK", "", `normalizeMaterialSide("KX"): unknown piece 'X'`},
+		{"KKvK", "", `normalizeMaterialSide("KK"): 2 kings`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.signature, func(t *testing.T) {
+			t.Parallel()
+
+			normalized, err := NormalizeMaterialSignature(test.signature)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NormalizeMaterialSignature() expected error %q but got %q", test.errString, err)
+			}
+
+			if normalized != test.normalized {
+				t.Fatalf("NormalizeMaterialSignature() expected %q but got %q", test.normalized, normalized)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Start position of the Antichess, which differs from the standard one by the absence of castling rights.
+const antichessStartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1"
+
+// VariantAntichess represents the rules of the Antichess, where the player wins by losing all pieces or by being
+// stalemated. Captures are compulsory, the king is an ordinary piece without check and pawns can promote to the king.
+type VariantAntichess struct {
+	engine Engine
+}
+
+// NewVariantAntichess creates a new Antichess variant, which uses passed engine.
+func NewVariantAntichess(engine Engine) VariantAntichess {
+	return VariantAntichess{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+//
+// SAN argument examples: "e4", "Kxe2", "exd6", "a1=K".
+func (variant VariantAntichess) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.calcMoveFromSAN(position, san, variant.CalcMoves)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position. The moves never have
+// check suffixes, because there are no checks.
+//
+// Return examples: "e4", "Kxe2", "exd6", "a1=K".
+func (variant VariantAntichess) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.calcMoveSAN(position, move, variant.CalcMoves, newVariantRawMover(variant))
+}
+
+// CalcMoves calculates all possible moves in passed position for active color pieces. Only captures are possible if
+// there is at least one.
+func (variant VariantAntichess) CalcMoves(position *Position) ([]Move, error) {
+	rawMoves, err := variant.engine.CalcRawMoves(position)
+	if err != nil {
+		return nil, fmt.Errorf("CalcRawMoves(): %w", err)
+	}
+
+	moves := make([]Move, 0, len(rawMoves))
+
+	for _, rawMove := range rawMoves {
+		moves = append(moves, rawMove)
+
+		if rawMove.promoRole == RoleQueen {
+			moves = append(moves, NewMove(rawMove.origin, rawMove.dest, rawMove.tags, RoleKing))
+		}
+	}
+
+	captureExists := slices.ContainsFunc(moves, func(move Move) bool {
+		return move.tags.Contains(MoveTagCapture) || move.tags.Contains(MoveTagEnPassantCapture)
+	})
+
+	if captureExists {
+		moves = slices.DeleteFunc(moves, func(move Move) bool {
+			return !move.tags.Contains(MoveTagCapture) && !move.tags.Contains(MoveTagEnPassantCapture)
+		})
+	}
+
+	return moves, nil
+}
+
+// CalcResult calculates the result of the game with passed positions and the reason of its termination.
+//
+// The active color wins if it has no pieces or no possible moves. The game is drawn by fifty moves or repetition.
+func (variant VariantAntichess) CalcResult(positions []*Position, _ []Move) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	colorBitboard, err := position.board.GetColorBitboard(position.activeColor)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("GetColorBitboard(%s): %w", position.activeColor, err)
+	}
+
+	termination := TerminationNil
+
+	if colorBitboard == BitboardNil {
+		termination = TerminationVariantEnd
+	} else {
+		moves, err := variant.CalcMoves(position)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+		}
+
+		if len(moves) == 0 {
+			termination = TerminationStalemate
+		}
+	}
+
+	if termination == TerminationNil {
+		return calcDrawResult(positions)
+	}
+
+	result, err := NewResultWon(position.activeColor)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("NewResultWon(%s): %w", position.activeColor, err)
+	}
+
+	return result, termination, nil
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantAntichess) Name() string {
+	return "Antichess"
+}
+
+// NewPositionStart creates the start position of the Antichess.
+func (variant VariantAntichess) NewPositionStart() (*Position, error) {
+	position, err := NewPositionFromFEN(antichessStartFEN)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", antichessStartFEN, err)
+	}
+
+	return position, nil
+}
+
+// checkColorInsufficientMaterial checks that passed color has no enough material to win. The material is never
+// insufficient, because the player wins by losing all pieces.
+func (variant VariantAntichess) checkColorInsufficientMaterial(_ *Position, _ Color) (bool, error) {
+	return false, nil
+}
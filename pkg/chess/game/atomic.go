@@ -0,0 +1,310 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VariantAtomic represents the rules of the Atomic chess, where the captures explode the capturing piece and all
+// pieces around the destination except pawns. The player wins by exploding the opponent king. Kings cannot capture
+// and the touching kings do not give check.
+type VariantAtomic struct {
+	engine Engine
+}
+
+// NewVariantAtomic creates a new Atomic variant, which uses passed engine.
+func NewVariantAtomic(engine Engine) VariantAtomic {
+	return VariantAtomic{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+func (variant VariantAtomic) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.calcMoveFromSAN(position, san, variant.CalcMoves)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+func (variant VariantAtomic) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.calcMoveSAN(position, move, variant.CalcMoves, newVariantRawMover(variant))
+}
+
+// CalcMoves calculates all possible moves in passed position for active color pieces.
+//
+// The move is possible if it does not explode the own king and does not leave it in check. Exploding the opponent king
+// is possible even if the own king is in check.
+func (variant VariantAtomic) CalcMoves(position *Position) ([]Move, error) {
+	rawMoves, err := variant.engine.CalcRawMoves(position)
+	if err != nil {
+		return nil, fmt.Errorf("CalcRawMoves(): %w", err)
+	}
+
+	color := position.activeColor
+
+	opponentColor, err := color.Opposite()
+	if err != nil {
+		return nil, fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	king, err := NewPiece(color, RoleKing)
+	if err != nil {
+		return nil, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
+	}
+
+	moves := make([]Move, 0, len(rawMoves))
+
+	for _, rawMove := range rawMoves {
+		capture := rawMove.tags.Contains(MoveTagCapture) || rawMove.tags.Contains(MoveTagEnPassantCapture)
+
+		kingMove, err := position.board.bitboards[king].Occupied(rawMove.origin)
+		if err != nil {
+			return nil, fmt.Errorf("0x%X.Occupied(%s): %w", position.board.bitboards[king], rawMove.origin, err)
+		}
+
+		if kingMove && capture {
+			continue
+		}
+
+		newPosition, err := position.DeepCopy()
+		if err != nil {
+			return nil, fmt.Errorf("DeepCopy(): %w", err)
+		}
+
+		if err := newPosition.MoveAtomicRaw(rawMove); err != nil {
+			return nil, fmt.Errorf("MoveAtomicRaw(%+v): %w", rawMove, err)
+		}
+
+		hasKing, err := checkColorHasKing(newPosition, color)
+		if err != nil {
+			return nil, fmt.Errorf("checkColorHasKing(%s): %w", color, err)
+		}
+
+		if !hasKing {
+			continue
+		}
+
+		checked, err := variant.checkChecked(newPosition, color)
+		if err != nil {
+			return nil, fmt.Errorf("checkChecked(%s): %w", color, err)
+		}
+
+		if checked {
+			continue
+		}
+
+		opponentChecked, err := variant.checkChecked(newPosition, opponentColor)
+		if err != nil {
+			return nil, fmt.Errorf("checkChecked(%s): %w", opponentColor, err)
+		}
+
+		if opponentChecked {
+			rawMove.tags.Set(MoveTagCheck)
+		}
+
+		moves = append(moves, rawMove)
+	}
+
+	return moves, nil
+}
+
+// CalcResult calculates the result of the game with passed positions and the reason of its termination.
+//
+// The game ends by the king explosion, checkmate, stalemate, insufficient material, fifty moves or repetition. The
+// material is insufficient only if there are kings alone.
+func (variant VariantAtomic) CalcResult(positions []*Position, _ []Move) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	for _, color := range [...]Color{ColorWhite, ColorBlack} {
+		hasKing, err := checkColorHasKing(position, color)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("checkColorHasKing(%s): %w", color, err)
+		}
+
+		if hasKing {
+			continue
+		}
+
+		result, err := newResultLost(color)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("newResultLost(%s): %w", color, err)
+		}
+
+		return result, TerminationVariantEnd, nil
+	}
+
+	moves, err := variant.CalcMoves(position)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		checked, err := variant.checkChecked(position, position.activeColor)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("checkChecked(%s): %w", position.activeColor, err)
+		}
+
+		if !checked {
+			return ResultDraw, TerminationStalemate, nil
+		}
+
+		result, err := newResultLost(position.activeColor)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("newResultLost(%s): %w", position.activeColor, err)
+		}
+
+		return result, TerminationCheckmate, nil
+	}
+
+	if checkOnlyKings(position.board) {
+		return ResultDraw, TerminationInsufficientMaterial, nil
+	}
+
+	return calcDrawResult(positions)
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantAtomic) Name() string {
+	return "Atomic"
+}
+
+// NewPositionStart creates the start position, which is the same as in the standard chess.
+func (variant VariantAtomic) NewPositionStart() (*Position, error) {
+	return NewPositionStart()
+}
+
+// moveRaw makes passed move in passed position with the explosion of the capture.
+func (variant VariantAtomic) moveRaw(position *Position, move Move) error {
+	if err := position.MoveAtomicRaw(move); err != nil {
+		return fmt.Errorf("MoveAtomicRaw(%+v): %w", move, err)
+	}
+
+	return nil
+}
+
+// checkColorInsufficientMaterial checks that passed color has no enough material to win.
+//
+// The bare king cannot win. While the opponent has pieces except the king, any material can explode the opponent
+// king next to them, unless there are only bishops, which cannot capture each other. Against the bare king a queen or
+// a pawn wins, while a single piece or two knights cannot.
+func (variant VariantAtomic) checkColorInsufficientMaterial(position *Position, color Color) (bool, error) {
+	opponentColor, err := color.Opposite()
+	if err != nil {
+		return false, fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	hasKing, err := checkColorHasKing(position, opponentColor)
+	if err != nil {
+		return false, fmt.Errorf("checkColorHasKing(%s): %w", opponentColor, err)
+	}
+
+	if !hasKing {
+		return false, nil
+	}
+
+	counts, piecesCount, err := calcColorPiecesCounts(position.board, color)
+	if err != nil {
+		return false, fmt.Errorf("calcColorPiecesCounts(%s): %w", color, err)
+	}
+
+	opponentCounts, opponentPiecesCount, err := calcColorPiecesCounts(position.board, opponentColor)
+	if err != nil {
+		return false, fmt.Errorf("calcColorPiecesCounts(%s): %w", opponentColor, err)
+	}
+
+	switch {
+	case piecesCount == 0:
+		return true, nil
+	case opponentPiecesCount != 0:
+		if piecesCount != counts[RoleBishop] || opponentPiecesCount != opponentCounts[RoleBishop] {
+			return false, nil
+		}
+
+		return variant.checkBishopsApart(position.board, color)
+	case counts[RoleQueen] != 0 || counts[RolePawn] != 0:
+		return false, nil
+	case piecesCount == 1:
+		return true, nil
+	default:
+		return piecesCount == counts[RoleKnight] && piecesCount <= 2, nil //nolint:mnd // Two knights.
+	}
+}
+
+// checkBishopsApart checks that the bishops of passed color and the opponent bishops stand on the squares of
+// different colors, so they cannot capture each other.
+func (variant VariantAtomic) checkBishopsApart(board *Board, color Color) (bool, error) {
+	opponentColor, err := color.Opposite()
+	if err != nil {
+		return false, fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	squareColors := make(map[Color]map[bool]bool, 2) //nolint:mnd // Two colors.
+
+	for _, bishopColor := range [...]Color{color, opponentColor} {
+		bishop, err := NewPiece(bishopColor, RoleBishop)
+		if err != nil {
+			return false, fmt.Errorf("NewPiece(%s, %s): %w", bishopColor, RoleBishop, err)
+		}
+
+		squareColors[bishopColor] = make(map[bool]bool, 2) //nolint:mnd // Light and dark squares.
+
+		for _, square := range board.bitboards[bishop].GetSquares() {
+			dark, err := checkSquareDark(square)
+			if err != nil {
+				return false, fmt.Errorf("checkSquareDark(%s): %w", square, err)
+			}
+
+			squareColors[bishopColor][dark] = true
+		}
+	}
+
+	for _, dark := range [...]bool{true, false} {
+		if !squareColors[color][dark] && !squareColors[opponentColor][!dark] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// checkChecked checks that the king of passed color is in check. The king is never in check if any king is missing
+// or the kings touch each other, because the opponent king cannot capture and any capture of the own king explodes
+// the opponent king too.
+func (variant VariantAtomic) checkChecked(position *Position, color Color) (bool, error) {
+	king, err := NewPiece(color, RoleKing)
+	if err != nil {
+		return false, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
+	}
+
+	opponentColor, err := color.Opposite()
+	if err != nil {
+		return false, fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	opponentKing, err := NewPiece(opponentColor, RoleKing)
+	if err != nil {
+		return false, fmt.Errorf("NewPiece(%s, %s): %w", opponentColor, RoleKing, err)
+	}
+
+	kingSquares := position.board.bitboards[king].GetSquares()
+	if len(kingSquares) != 1 || position.board.bitboards[opponentKing] == BitboardNil {
+		return false, nil
+	}
+
+	ringBitboard, ok := moveKingRawDestBitboards[kingSquares[0]]
+	if !ok {
+		return false, fmt.Errorf("king bitboard of %s not found", kingSquares[0])
+	}
+
+	if ringBitboard&position.board.bitboards[opponentKing] != BitboardNil {
+		return false, nil
+	}
+
+	checked, err := variant.engine.CheckChecked(position, color)
+	if err != nil {
+		return false, fmt.Errorf("CheckChecked(%s): %w", color, err)
+	}
+
+	return checked, nil
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Start position of the Crazyhouse with empty pockets.
+const crazyhouseStartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR[] w KQkq - 0 1"
+
+// VariantCrazyhouse represents the rules of the Crazyhouse, where the captured pieces go to the pocket of the
+// capturing player and can be dropped on the board instead of a move.
+type VariantCrazyhouse struct {
+	engine Engine
+}
+
+// NewVariantCrazyhouse creates a new Crazyhouse variant, which uses passed engine.
+func NewVariantCrazyhouse(engine Engine) VariantCrazyhouse {
+	return VariantCrazyhouse{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+//
+// SAN argument examples: "e4", "Nbd7", "N@f3", "@e4", "P@e4+".
+func (variant VariantCrazyhouse) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.calcMoveFromSAN(position, san, variant.CalcMoves)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+//
+// Return examples: "e4", "Nbd7", "N@f3", "P@e4+".
+func (variant VariantCrazyhouse) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.calcMoveSAN(position, move, variant.CalcMoves, newVariantRawMover(variant))
+}
+
+// CalcMoves calculates all possible moves and drops in passed position for active color pieces.
+func (variant VariantCrazyhouse) CalcMoves(position *Position) ([]Move, error) {
+	moves, err := variant.engine.CalcMoves(position)
+	if err != nil {
+		return nil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	drops, err := variant.calcDrops(position)
+	if err != nil {
+		return nil, fmt.Errorf("calcDrops(): %w", err)
+	}
+
+	return append(moves, drops...), nil
+}
+
+// CalcResult calculates the result of the game with passed positions and the reason of its termination.
+//
+// The game ends by checkmate, stalemate, fifty moves or repetition. The material is never insufficient, because the
+// pieces can be dropped.
+func (variant VariantCrazyhouse) CalcResult(positions []*Position, _ []Move) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	moves, err := variant.CalcMoves(position)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		return calcNoMovesResult(variant.engine, position)
+	}
+
+	return calcDrawResult(positions)
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantCrazyhouse) Name() string {
+	return "Crazyhouse"
+}
+
+// NewPositionStart creates the start position of the Crazyhouse with empty pockets.
+func (variant VariantCrazyhouse) NewPositionStart() (*Position, error) {
+	position, err := NewPositionFromFEN(crazyhouseStartFEN)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", crazyhouseStartFEN, err)
+	}
+
+	return position, nil
+}
+
+// checkColorInsufficientMaterial checks that passed color has no enough material to win. The material is never
+// insufficient, because the pieces can be dropped.
+func (variant VariantCrazyhouse) checkColorInsufficientMaterial(_ *Position, _ Color) (bool, error) {
+	return false, nil
+}
+
+// calcDrops calculates all possible drops of the active color pieces from the pocket to the empty squares.
+//
+// Pawns cannot be dropped on the first and the last ranks and drops cannot leave the own king in check.
+func (variant VariantCrazyhouse) calcDrops(position *Position) ([]Move, error) {
+	if len(position.pockets) == 0 {
+		return nil, nil
+	}
+
+	pieces, err := NewPiecesOfColor(position.activeColor)
+	if err != nil {
+		return nil, fmt.Errorf("NewPiecesOfColor(%s): %w", position.activeColor, err)
+	}
+
+	occupiedBitboard, err := position.board.GetOccupiedBitboard()
+	if err != nil {
+		return nil, fmt.Errorf("GetOccupiedBitboard(): %w", err)
+	}
+
+	emptySquares := (^occupiedBitboard).GetSquares()
+
+	var drops []Move
+
+	for _, piece := range pieces {
+		if position.pockets[piece] == 0 {
+			continue
+		}
+
+		role, err := piece.Role()
+		if err != nil {
+			return nil, fmt.Errorf("%s.Role(): %w", piece, err)
+		}
+
+		for _, dest := range emptySquares {
+			rank, err := dest.Rank()
+			if err != nil {
+				return nil, fmt.Errorf("%s.Rank(): %w", dest, err)
+			}
+
+			if !role.CanBeInRank(rank) {
+				continue
+			}
+
+			drop := NewMoveDrop(piece, dest, MoveTagsNil)
+
+			putsHisColorInCheck, err := variant.engine.checkPutsColorInCheck(position, drop, position.activeColor)
+			if err != nil {
+				return nil, fmt.Errorf("checkPutsColorInCheck(%+v, %s): %w", drop, position.activeColor, err)
+			}
+
+			if putsHisColorInCheck {
+				continue
+			}
+
+			if err := variant.engine.addRawMoveCheckTag(position, &drop, position.activeColor); err != nil {
+				return nil, fmt.Errorf("addRawMoveCheckTag(%+v, %s): %w", drop, position.activeColor, err)
+			}
+
+			drops = append(drops, drop)
+		}
+	}
+
+	return drops, nil
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Cursor steps through the positions of the game without its mutation. It is safe for concurrent use. The navigation
+// methods return the position, to which the cursor moved, so the readers sharing the cursor see the consistent
+// position. The readers may also create their own cursors cheaply, because the positions are shared, not copied.
+//
+// The cursor keeps the positions and the moves of the game at the moment of its creation, so the moves made later are
+// not visible. The ply is the count of the moves made before the current position: zero is the position before the
+// first move.
+type Cursor struct {
+	variant   Variant
+	positions []*Position
+	moves     []Move
+
+	mu  sync.RWMutex
+	ply int
+}
+
+// NewCursor creates a new Cursor over the current positions and moves of passed game, which points to the current
+// position of the game.
+func NewCursor(game *Game) *Cursor {
+	positions, moves := game.history()
+
+	return &Cursor{
+		variant:   game.Variant(),
+		positions: positions,
+		moves:     moves,
+		ply:       len(moves),
+	}
+}
+
+// First moves the cursor to the position before the first move and returns it.
+func (cursor *Cursor) First() *Position {
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+
+	cursor.ply = 0
+
+	return cursor.positions[cursor.ply]
+}
+
+// Prev moves the cursor to the previous position and returns it.
+//
+// Returns false if the cursor already points to the first position.
+func (cursor *Cursor) Prev() (*Position, bool) {
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+
+	if cursor.ply == 0 {
+		return cursor.positions[cursor.ply], false
+	}
+
+	cursor.ply--
+
+	return cursor.positions[cursor.ply], true
+}
+
+// Next moves the cursor to the next position and returns it.
+//
+// Returns false if the cursor already points to the last position.
+func (cursor *Cursor) Next() (*Position, bool) {
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+
+	if cursor.ply == len(cursor.moves) {
+		return cursor.positions[cursor.ply], false
+	}
+
+	cursor.ply++
+
+	return cursor.positions[cursor.ply], true
+}
+
+// Last moves the cursor to the last position and returns it.
+func (cursor *Cursor) Last() *Position {
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+
+	cursor.ply = len(cursor.moves)
+
+	return cursor.positions[cursor.ply]
+}
+
+// GoTo moves the cursor to the position after passed count of moves and returns it.
+func (cursor *Cursor) GoTo(ply int) (*Position, error) {
+	if ply < 0 || ply > len(cursor.moves) {
+		return nil, fmt.Errorf("ply %d is out of [0, %d]", ply, len(cursor.moves))
+	}
+
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+
+	cursor.ply = ply
+
+	return cursor.positions[cursor.ply], nil
+}
+
+// Ply returns the count of the moves made before the current position.
+func (cursor *Cursor) Ply() int {
+	cursor.mu.RLock()
+	defer cursor.mu.RUnlock()
+
+	return cursor.ply
+}
+
+// Plies returns the count of the moves of the game, which is the ply of the last position.
+func (cursor *Cursor) Plies() int {
+	return len(cursor.moves)
+}
+
+// Position returns the current position.
+//
+// Please note that the position is not copied, so do not modify it.
+func (cursor *Cursor) Position() *Position {
+	cursor.mu.RLock()
+	defer cursor.mu.RUnlock()
+
+	return cursor.positions[cursor.ply]
+}
+
+// LastMove returns the move, which led to the current position.
+//
+// Returns false if the cursor points to the position before the first move.
+func (cursor *Cursor) LastMove() (Move, bool) {
+	cursor.mu.RLock()
+	defer cursor.mu.RUnlock()
+
+	if cursor.ply == 0 {
+		return Move{}, false
+	}
+
+	return cursor.moves[cursor.ply-1], true
+}
+
+// LastMoveSAN returns standard algebraic notation of the move, which led to the current position.
+//
+// Returns the empty string if the cursor points to the position before the first move.
+func (cursor *Cursor) LastMoveSAN() (string, error) {
+	cursor.mu.RLock()
+	ply := cursor.ply
+	cursor.mu.RUnlock()
+
+	if ply == 0 {
+		return "", nil
+	}
+
+	san, err := cursor.variant.CalcMoveSAN(cursor.positions[ply-1], cursor.moves[ply-1])
+	if err != nil {
+		return "", fmt.Errorf("CalcMoveSAN(%+v): %w", cursor.moves[ply-1], err)
+	}
+
+	return san, nil
+}
@@ -0,0 +1,160 @@
+package game
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t, "e2e4", "e7e5", "g1f3")
+
+	cursor := NewCursor(game)
+	positions, moves := game.Positions(), game.Moves()
+
+	if cursor.Ply() != 3 || cursor.Plies() != 3 || cursor.Position() != positions[3] {
+		t.Fatalf("NewCursor() expected the cursor at the last position but got ply %d", cursor.Ply())
+	}
+
+	if position, ok := cursor.Next(); ok || position != positions[3] {
+		t.Fatalf("Next() expected false at the last position but got %t", ok)
+	}
+
+	tests := []struct {
+		step func() *Position
+		ply  int
+		san  string
+	}{
+		{
+			func() *Position {
+				position, _ := cursor.Prev()
+				return position
+			},
+			2,
+			"e5",
+		},
+		{cursor.First, 0, ""},
+		{
+			func() *Position {
+				position, _ := cursor.Next()
+				return position
+			},
+			1,
+			"e4",
+		},
+		{cursor.Last, 3, "Nf3"},
+		{
+			func() *Position {
+				position, _ := cursor.GoTo(2)
+				return position
+			},
+			2,
+			"e5",
+		},
+	}
+
+	for index, test := range tests {
+		if position := test.step(); position != positions[test.ply] || cursor.Ply() != test.ply {
+			t.Fatalf("step #%d expected ply %d but got %d", index, test.ply, cursor.Ply())
+		}
+
+		move, ok := cursor.LastMove()
+		if ok != (test.ply > 0) || (ok && move != moves[test.ply-1]) {
+			t.Fatalf("step #%d, LastMove() expected move of ply %d but got %+v, %t", index, test.ply, move, ok)
+		}
+
+		san, err := cursor.LastMoveSAN()
+		if err != nil || san != test.san {
+			t.Fatalf("step #%d, LastMoveSAN() expected %q but got %q, %v", index, test.san, san, err)
+		}
+	}
+
+	if _, err := cursor.GoTo(4); err == nil || err.Error() != "ply 4 is out of [0, 3]" {
+		t.Fatalf("GoTo(4) expected error but got %v", err)
+	}
+
+	cursor.First()
+
+	if position, ok := cursor.Prev(); ok || position != positions[0] {
+		t.Fatalf("Prev() expected false at the first position but got %t", ok)
+	}
+
+	moveTestGame(t, game, "b8c6")
+
+	if position := cursor.Last(); position != positions[3] || cursor.Plies() != 3 {
+		t.Fatalf("Last() expected the cursor to ignore the later moves but got %d plies", cursor.Plies())
+	}
+}
+
+func TestCursorConcurrent(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t, "d2d4", "d7d5", "c2c4")
+
+	cursor := NewCursor(game)
+
+	var wg sync.WaitGroup
+
+	for reader := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for step := range 100 {
+				if (reader+step)%2 == 0 {
+					cursor.Prev()
+				} else {
+					cursor.Next()
+				}
+
+				if _, err := cursor.LastMoveSAN(); err != nil {
+					t.Errorf("LastMoveSAN(): %v", err)
+					return
+				}
+
+				cursor.Position()
+				cursor.LastMove()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestCursorDuringMoves(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				cursor := NewCursor(game)
+
+				if cursor.Ply() != cursor.Plies() || cursor.Last() == nil {
+					t.Errorf("NewCursor() expected the cursor at the last of %d plies but got %d", cursor.Plies(), cursor.Ply())
+					return
+				}
+
+				if _, err := cursor.LastMoveSAN(); err != nil {
+					t.Errorf("LastMoveSAN(): %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	for _, uci := range [...]string{"e2e4", "e7e5", "g1f3", "b8c6", "f1b5", "a7a6", "b5a4", "g8f6"} {
+		moveTestGame(t, game, uci)
+	}
+
+	wg.Wait()
+}
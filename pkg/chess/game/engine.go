@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"slices"
 )
 
 // The engine is responsible for the logic of movement and interaction.
@@ -10,8 +11,11 @@ type Engine struct{}
 
 // CalcMoves calculates all possible moves in passed position for active color pieces.
 //
+// The order of the moves is deterministic: by piece as in NewPiecesOfColor, then by origin square in ascending order,
+// then by destination square and promotion, and the castlings follow the king moves. So the index of the move in the
+// list identifies it in the position.
+//
 // TODO: test.
-// TODO: generate default moves and castlings.
 func (engine Engine) CalcMoves(position *Position) ([]Move, error) {
 	if position == nil {
 		return nil, errors.New("position is nil")
@@ -36,16 +40,69 @@ func (engine Engine) CalcMoves(position *Position) ([]Move, error) {
 	return moves, nil
 }
 
-// CalcPieceMoves calculates all possible piece moves in the passed position.
+// CalcMoveFromUCI finds the possible move in passed position, which corresponds to passed UCI long algebraic notation.
+//
+// UCI argument examples: "e2e4", "e1g1", "e7e8q".
 //
 // TODO: test.
-// TODO: generate default moves and castlings.
-func (engine Engine) CalcPieceMoves(position *Position, piece Piece) ([]Move, error) {
+func (engine Engine) CalcMoveFromUCI(position *Position, uci string) (Move, error) {
+	moves, err := engine.CalcMoves(position)
+	if err != nil {
+		return Move{}, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	for _, move := range moves {
+		moveUCI, err := move.UCI()
+		if err != nil {
+			return Move{}, fmt.Errorf("UCI(%+v): %w", move, err)
+		}
+
+		if moveUCI == uci {
+			return move, nil
+		}
+	}
+
+	return Move{}, fmt.Errorf("no possible move %q", uci)
+}
+
+// CalcRawMoves calculates all raw moves in passed position for active color pieces with capture tags.
+//
+// Note that the moves are raw, that is, for example, the king moves can put him in checkmate, and have no check tags.
+// This is useful for variants, where the king can be captured or is absent at all.
+//
+// TODO: test.
+func (engine Engine) CalcRawMoves(position *Position) ([]Move, error) {
 	if position == nil {
 		return nil, errors.New("position is nil")
 	}
 
-	TODO CASTLING
+	pieces, err := NewPiecesOfColor(position.activeColor)
+	if err != nil {
+		return nil, fmt.Errorf("NewPiecesOfColor(%s): %w", position.activeColor, err)
+	}
+
+	var moves []Move
+
+	for _, piece := range pieces {
+		for _, origin := range position.board.bitboards[piece].GetSquares() {
+			pieceMoves, err := engine.calcPieceRawMovesFromOrigin(position, piece, origin)
+			if err != nil {
+				return nil, fmt.Errorf("calcPieceRawMovesFromOrigin(%s, %s): %w", piece, origin, err)
+			}
+
+			moves = append(moves, pieceMoves...)
+		}
+	}
+
+	return moves, nil
+}
+
+// CalcPieceMoves calculates all possible piece moves in the passed position. The castlings follow the other king
+// moves.
+func (engine Engine) CalcPieceMoves(position *Position, piece Piece) ([]Move, error) {
+	if position == nil {
+		return nil, errors.New("position is nil")
+	}
 
 	color, err := piece.Color()
 	if err != nil {
@@ -72,15 +129,60 @@ func (engine Engine) CalcPieceMoves(position *Position, piece Piece) ([]Move, er
 		moves = append(moves, movesFromOrigin...)
 	}
 
+	role, err := piece.Role()
+	if err != nil {
+		return nil, fmt.Errorf("%s.Role(): %w", piece, err)
+	}
+
+	if role != RoleKing {
+		return moves, nil
+	}
+
+	for _, origin := range bitboard.GetSquares() {
+		castlings, err := engine.calcCastlings(position, origin, color)
+		if err != nil {
+			return nil, fmt.Errorf("calcCastlings(%s, %s): %w", origin, color, err)
+		}
+
+		moves = append(moves, castlings...)
+	}
+
 	return moves, nil
 }
 
-// addRawMoveAttackTags adds, for example, capture or check tags to the passed move if needed.
+// CheckChecked checks that the passed color king in check in passed position.
+//
+// TODO: test.
+func (engine Engine) CheckChecked(position *Position, color Color) (bool, error) {
+	if position == nil {
+		return false, errors.New("position is nil")
+	}
+
+	king, err := NewPiece(color, RoleKing)
+	if err != nil {
+		return false, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
+	}
+
+	kingSquares := position.board.bitboards[king].GetSquares()
+	if len(kingSquares) != 1 {
+		return false, fmt.Errorf("expected 1 king square but got %d", len(kingSquares))
+	}
+	kingSquare := kingSquares[0]
+
+	inCheck, err := engine.checkSquareOpenToAttack(position, color, kingSquare)
+	if err != nil {
+		return false, fmt.Errorf("checkSquareOpenToAttack(%s, %s): %w", color, kingSquare, err)
+	}
+
+	return inCheck, nil
+}
+
+// addRawMoveCaptureTags adds capture and en passant capture tags to the passed move if needed.
 //
 // Note that the moves are raw, that is, for example, the king moves can put him in checkmate.
 //
 // TODO: test.
-func (engine Engine) addRawMoveAttackTags(position *Position, move *Move, attackColor Color) error {
+func (engine Engine) addRawMoveCaptureTags(position *Position, move *Move, attackColor Color) error {
 	if position == nil {
 		return errors.New("position is nil")
 	}
@@ -121,6 +223,24 @@ func (engine Engine) addRawMoveAttackTags(position *Position, move *Move, attack
 		move.tags.Set(MoveTagEnPassantCapture)
 	}
 
+	return nil
+}
+
+// addRawMoveCheckTag adds check tag to the passed move if it puts the opposite of the attack color in check.
+//
+// Note that the moves are raw, that is, for example, the king moves can put him in checkmate.
+//
+// TODO: test.
+func (engine Engine) addRawMoveCheckTag(position *Position, move *Move, attackColor Color) error {
+	if move == nil {
+		return errors.New("move is nil")
+	}
+
+	defendColor, err := attackColor.Opposite()
+	if err != nil {
+		return fmt.Errorf("%s.Opposite(): %w", attackColor, err)
+	}
+
 	putsDefendColorInCheck, err := engine.checkPutsColorInCheck(position, *move, defendColor)
 	if err != nil {
 		return fmt.Errorf("checkPutsColorInCheck(%+v, %s): %w", *move, defendColor, err)
@@ -133,6 +253,157 @@ func (engine Engine) addRawMoveAttackTags(position *Position, move *Move, attack
 	return nil
 }
 
+// calcCastlings calculates all possible castlings of passed color king from passed origin in passed position.
+//
+// The castling is possible if the color side has the castling right, the squares between the king and its destination
+// and between the rook and its destination are empty except the castling king and rook, as in Chess960, and the king
+// is not in check and does not pass through or land on the attacked square.
+func (engine Engine) calcCastlings(position *Position, origin Square, color Color) ([]Move, error) {
+	var moves []Move
+
+	for _, colorSide := range [...]ColorSide{
+		ColorSideWhiteKing, ColorSideWhiteQueen, ColorSideBlackKing, ColorSideBlackQueen,
+	} {
+		colorSideColor, err := colorSide.Color()
+		if err != nil {
+			return nil, fmt.Errorf("%s.Color(): %w", colorSide, err)
+		}
+
+		if colorSideColor != color || !slices.Contains(position.castlingRights, colorSide) {
+			continue
+		}
+
+		move, ok, err := engine.calcCastling(position, origin, colorSide)
+		if err != nil {
+			return nil, fmt.Errorf("calcCastling(%s, %s): %w", origin, colorSide, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		putsHisColorInCheck, err := engine.checkPutsColorInCheck(position, move, color)
+		if err != nil {
+			return nil, fmt.Errorf("checkPutsColorInCheck(%+v, %s): %w", move, color, err)
+		}
+
+		// The rook may shield the king destination before the castling, as in Chess960.
+		if putsHisColorInCheck {
+			continue
+		}
+
+		if err := engine.addRawMoveCheckTag(position, &move, color); err != nil {
+			return nil, fmt.Errorf("addRawMoveCheckTag(%+v, %s): %w", move, color, err)
+		}
+
+		moves = append(moves, move)
+	}
+
+	return moves, nil
+}
+
+// calcCastling calculates the castling of the king from passed origin on passed color side. Returns false if the
+// castling is impossible.
+//
+// Note that the castling may leave the king in check, if the castling rook shielded the king destination.
+func (engine Engine) calcCastling(position *Position, origin Square, colorSide ColorSide) (Move, bool, error) {
+	color, err := colorSide.Color()
+	if err != nil {
+		return Move{}, false, fmt.Errorf("%s.Color(): %w", colorSide, err)
+	}
+
+	rank, err := colorSide.Rank()
+	if err != nil {
+		return Move{}, false, fmt.Errorf("%s.Rank(): %w", colorSide, err)
+	}
+
+	originRank, err := origin.Rank()
+	if err != nil {
+		return Move{}, false, fmt.Errorf("%s.Rank(): %w", origin, err)
+	}
+
+	if originRank != rank {
+		return Move{}, false, nil
+	}
+
+	rook, err := NewPiece(color, RoleRook)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleRook, err)
+	}
+
+	rookOrigin, err := position.CastlingRookSquare(colorSide)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("CastlingRookSquare(%s): %w", colorSide, err)
+	}
+
+	rookOriginPiece, err := position.board.GetPieceFromSquare(rookOrigin)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("GetPieceFromSquare(%s): %w", rookOrigin, err)
+	}
+
+	if rookOriginPiece != rook {
+		return Move{}, false, nil
+	}
+
+	dest, err := NewSquare(rank, colorSide.KingDestFile())
+	if err != nil {
+		return Move{}, false, fmt.Errorf("NewSquare(%s, %s): %w", rank, colorSide.KingDestFile(), err)
+	}
+
+	rookDest, err := NewSquare(rank, colorSide.RookDestFile())
+	if err != nil {
+		return Move{}, false, fmt.Errorf("NewSquare(%s, %s): %w", rank, colorSide.RookDestFile(), err)
+	}
+
+	kingPath, err := calcRankPath(origin, dest)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("calcRankPath(%s, %s): %w", origin, dest, err)
+	}
+
+	rookPath, err := calcRankPath(rookOrigin, rookDest)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("calcRankPath(%s, %s): %w", rookOrigin, rookDest, err)
+	}
+
+	for _, square := range slices.Concat(kingPath, rookPath) {
+		if square == origin || square == rookOrigin {
+			continue
+		}
+
+		piece, err := position.board.GetPieceFromSquare(square)
+		if err != nil {
+			return Move{}, false, fmt.Errorf("GetPieceFromSquare(%s): %w", square, err)
+		}
+
+		if piece != PieceNil {
+			return Move{}, false, nil
+		}
+	}
+
+	// The king is moved to each square of the path, because the pawns attack only occupied squares. The path includes
+	// the origin, so the king in check cannot castle.
+	for _, square := range kingPath {
+		checked, err := engine.checkPutsColorInCheck(position, NewMove(origin, square, MoveTagsNil, RoleNil), color)
+		if err != nil {
+			return Move{}, false, fmt.Errorf("checkPutsColorInCheck(%s, %s, %s): %w", origin, square, color, err)
+		}
+
+		if checked {
+			return Move{}, false, nil
+		}
+	}
+
+	tag := MoveTagQueenSideCastle
+	if colorSide.IsKingSide() {
+		tag = MoveTagKingSideCastle
+	}
+
+	var tags MoveTags
+	tags.Set(tag)
+
+	return NewMove(origin, dest, tags, RoleNil), true, nil
+}
+
 // calcPieceMovesFromOrigin calculates all possible piece moves in the passed position from passed origin.
 //
 // Before calling this function make sure the piece is actually on the passed origin.
@@ -140,6 +411,44 @@ func (engine Engine) addRawMoveAttackTags(position *Position, move *Move, attack
 // TODO: test
 // TODO: generate default moves and castlings.
 func (engine Engine) calcPieceMovesFromOrigin(position *Position, piece Piece, origin Square) ([]Move, error) {
+	color, err := piece.Color()
+	if err != nil {
+		return nil, fmt.Errorf("%s.Color(): %w", piece, err)
+	}
+
+	rawMoves, err := engine.calcPieceRawMovesFromOrigin(position, piece, origin)
+	if err != nil {
+		return nil, fmt.Errorf("calcPieceRawMovesFromOrigin(%s, %s): %w", piece, origin, err)
+	}
+
+	moves := make([]Move, 0, len(rawMoves))
+
+	for _, rawMove := range rawMoves {
+		putsHisColorInCheck, err := engine.checkPutsColorInCheck(position, rawMove, color)
+		if err != nil {
+			return nil, fmt.Errorf("checkPutsColorInCheck(%+v, %s): %w", rawMove, color, err)
+		}
+
+		if putsHisColorInCheck {
+			continue
+		}
+
+		if err := engine.addRawMoveCheckTag(position, &rawMove, color); err != nil {
+			return nil, fmt.Errorf("addRawMoveCheckTag(%+v, %s): %w", rawMove, color, err)
+		}
+
+		moves = append(moves, rawMove)
+	}
+
+	return moves, nil
+}
+
+// calcPieceRawMovesFromOrigin calculates piece raw moves with capture tags in the passed position from passed origin.
+//
+// Note that the moves are raw, that is, for example, the piece moves can put their king in checkmate.
+//
+// TODO: test.
+func (engine Engine) calcPieceRawMovesFromOrigin(position *Position, piece Piece, origin Square) ([]Move, error) {
 	if position == nil {
 		return nil, errors.New("position is nil")
 	}
@@ -156,7 +465,7 @@ func (engine Engine) calcPieceMovesFromOrigin(position *Position, piece Piece, o
 
 	rawDests := rawDestsBitboard.GetSquares()
 
-	moves := make([]Move, 0, len(rawDests))
+	rawMoves := make([]Move, 0, len(rawDests))
 
 	for _, rawDest := range rawDests {
 		rank, err := rawDest.Rank()
@@ -164,33 +473,20 @@ func (engine Engine) calcPieceMovesFromOrigin(position *Position, piece Piece, o
 			return nil, fmt.Errorf("%s.Rank(): %w", rawDest, err)
 		}
 
-		rawMoves := make([]Move, 0, 1)
-
 		if piece.NeedPromoInRank(rank) {
-			rawMoves = NewMovesPromo(origin, rawDest, MoveTagsNil)
+			rawMoves = append(rawMoves, NewMovesPromo(origin, rawDest, MoveTagsNil)...)
 		} else {
 			rawMoves = append(rawMoves, NewMove(origin, rawDest, MoveTagsNil, RoleNil))
 		}
+	}
 
-		for _, rawMove := range rawMoves {
-			putsHisColorInCheck, err := engine.checkPutsColorInCheck(position, rawMove, color)
-			if err != nil {
-				return nil, fmt.Errorf("checkPutsColorInCheck(%+v, %s): %w", rawMove, color, err)
-			}
-
-			if putsHisColorInCheck {
-				continue
-			}
-
-			if err := engine.addRawMoveAttackTags(position, &rawMove, color); err != nil {
-				return nil, fmt.Errorf("addRawMoveAttackTags(%+v, %s): %w", rawMove, color, err)
-			}
-
-			moves = append(moves, rawMove)
+	for index := range rawMoves {
+		if err := engine.addRawMoveCaptureTags(position, &rawMoves[index], color); err != nil {
+			return nil, fmt.Errorf("addRawMoveCaptureTags(%+v, %s): %w", rawMoves[index], color, err)
 		}
 	}
 
-	return moves, nil
+	return rawMoves, nil
 }
 
 // calcBishopRawMoveDestsBitboard calculates passed color bishop raw move destinations bitboard in passed position from
@@ -615,33 +911,6 @@ func (engine Engine) checkAnySquaresOpenToAttack(
 	return false, nil
 }
 
-// checkChecked checks that the passed color king in check in passed position.
-//
-// TODO: test.
-func (engine Engine) checkChecked(position *Position, color Color) (bool, error) {
-	if position == nil {
-		return false, errors.New("position is nil")
-	}
-
-	king, err := NewPiece(color, RoleKing)
-	if err != nil {
-		return false, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
-	}
-
-	kingSquares := position.board.bitboards[king].GetSquares()
-	if len(kingSquares) != 1 {
-		return false, fmt.Errorf("expected 1 king square but got %d", len(kingSquares))
-	}
-	kingSquare := kingSquares[0]
-
-	inCheck, err := engine.checkSquareOpenToAttack(position, color, kingSquare)
-	if err != nil {
-		return false, fmt.Errorf("checkSquareOpenToAttack(%s, %s): %w", color, kingSquare, err)
-	}
-
-	return inCheck, nil
-}
-
 // checkMovePutsInCheck checks that passed move will put the king of passed color in check.
 //
 // TODO: test.
@@ -659,9 +928,9 @@ func (engine Engine) checkPutsColorInCheck(position *Position, move Move, color
 		return false, fmt.Errorf("Move(%+v): %w", move, err)
 	}
 
-	checked, err := engine.checkChecked(newPosition, color)
+	checked, err := engine.CheckChecked(newPosition, color)
 	if err != nil {
-		return false, fmt.Errorf("checkChecked(%s): %w", color, err)
+		return false, fmt.Errorf("CheckChecked(%s): %w", color, err)
 	}
 
 	return checked, nil
@@ -763,3 +1032,35 @@ func (engine Engine) checkSquareOpenToAttack(position *Position, defendColor Col
 
 	return false, nil
 }
+
+// calcRankPath calculates the squares from passed origin to passed destination on the same rank including both.
+func calcRankPath(origin, dest Square) ([]Square, error) {
+	rank, err := origin.Rank()
+	if err != nil {
+		return nil, fmt.Errorf("%s.Rank(): %w", origin, err)
+	}
+
+	originFile, err := origin.File()
+	if err != nil {
+		return nil, fmt.Errorf("%s.File(): %w", origin, err)
+	}
+
+	destFile, err := dest.File()
+	if err != nil {
+		return nil, fmt.Errorf("%s.File(): %w", dest, err)
+	}
+
+	fromFile, toFile := min(originFile, destFile), max(originFile, destFile)
+	squares := make([]Square, 0, toFile-fromFile+1)
+
+	for file := fromFile; file <= toFile; file++ {
+		square, err := NewSquare(rank, file)
+		if err != nil {
+			return nil, fmt.Errorf("NewSquare(%s, %s): %w", rank, file, err)
+		}
+
+		squares = append(squares, square)
+	}
+
+	return squares, nil
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEngineCalcPieceMovesCastling(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fen  string
+		ucis []string
+	}{
+		{"both sides", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", []string{"e1c1", "e1g1"}},
+		{"no rights", "r3k2r/8/8/8/8/8/8/R3K2R w kq - 0 1", nil},
+		{"rook path blocked", "r3k2r/8/8/8/8/8/8/RN2K2R w KQkq - 0 1", []string{"e1g1"}},
+		{"king path blocked", "r3k2r/8/8/8/8/8/8/R3KB1R w KQkq - 0 1", []string{"e1c1"}},
+		{"king in check", "4k3/8/8/8/8/8/4r3/R3K2R w KQ - 0 1", nil},
+		{"king passes attacked square", "4kr2/8/8/8/8/8/8/R3K2R w KQ - 0 1", []string{"e1c1"}},
+		{"king passes pawn attack", "4k3/8/8/8/8/8/4p3/R3K2R w KQ - 0 1", nil},
+		{"king lands on attacked square", "4k1r1/8/8/8/8/8/8/R3K2R w KQ - 0 1", []string{"e1c1"}},
+		{"rook path attacked", "1r2k3/8/8/8/8/8/8/R3K2R w KQ - 0 1", []string{"e1c1", "e1g1"}},
+		{"black", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", []string{"e8c8", "e8g8"}},
+		{"chess960", "2k5/8/8/8/8/8/8/1RK3R1 w KQ - 0 1", []string{"c1c1", "c1g1"}},
+		{"chess960 king on rook destination", "4k3/8/8/8/8/8/8/5KR1 w K - 0 1", []string{"f1g1"}},
+		{"chess960 attacked king path", "2k2r2/8/8/8/8/8/8/1RK3R1 w KQ - 0 1", []string{"c1c1"}},
+		{"chess960 rook shields destination", "2k5/8/8/8/8/8/8/rRK5 w Q - 0 1", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			king, err := NewPiece(position.activeColor, RoleKing)
+			if err != nil {
+				t.Fatalf("NewPiece(%s, %s) expected no error but got %v", position.activeColor, RoleKing, err)
+			}
+
+			moves, err := Engine{}.CalcPieceMoves(position, king)
+			if err != nil {
+				t.Fatalf("CalcPieceMoves(%s) expected no error but got %v", king, err)
+			}
+
+			var ucis []string
+
+			for _, move := range moves {
+				if !move.tags.Contains(MoveTagKingSideCastle) && !move.tags.Contains(MoveTagQueenSideCastle) {
+					continue
+				}
+
+				uci, err := move.UCI()
+				if err != nil {
+					t.Fatalf("UCI(%+v) expected no error but got %v", move, err)
+				}
+
+				ucis = append(ucis, uci)
+			}
+
+			slices.Sort(ucis)
+
+			if !slices.Equal(ucis, test.ucis) {
+				t.Fatalf("CalcPieceMoves(%s) expected castlings %v but got %v", king, test.ucis, ucis)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// EventType represents the kind of the change of the game.
+type EventType uint8
+
+const (
+	// EventTypeNil means that there is no change.
+	EventTypeNil EventType = iota
+	// EventTypeMove means that the move was made.
+	EventTypeMove
+	// EventTypeTakeback means that the moves were taken back.
+	EventTypeTakeback
+	// EventTypeResult means that the game is over.
+	EventTypeResult
+)
+
+// String returns string representation of current event type.
+func (eventType EventType) String() string {
+	switch eventType {
+	case EventTypeNil:
+		return "EventTypeNil"
+	case EventTypeMove:
+		return "EventTypeMove"
+	case EventTypeTakeback:
+		return "EventTypeTakeback"
+	case EventTypeResult:
+		return "EventTypeResult"
+	default:
+		return fmt.Sprintf("<unknown EventType=%d>", eventType)
+	}
+}
+
+// Event is the change of the game passed to the listeners.
+type Event struct {
+	Type EventType
+	// Made move and its standard algebraic notation for the move event.
+	Move Move
+	SAN  string
+	// Count of the taken back moves for the takeback event.
+	Plies int
+	// Position after the move or the takeback.
+	Position *Position
+	// Result and the reason of the game end for the result event.
+	Result      Result
+	Termination Termination
+}
+
+// gameListener is the listener of the game events with its identifier for the removal.
+type gameListener struct {
+	id       uint64
+	listener func(event Event)
+}
+
+// Listen adds passed listener of the game events and returns the copy of the game at the moment of the addition, so
+// the listener sees all following changes after the copy.
+//
+// The listener is called with the game locked in the order of the changes, so it must be fast and must not call the
+// methods of the game.
+//
+// Returns the function, which removes the listener.
+func (game *Game) Listen(listener func(event Event)) (*Game, func()) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	game.nextListenerID++
+	id := game.nextListenerID
+
+	game.listeners = append(game.listeners, gameListener{id: id, listener: listener})
+
+	remove := func() {
+		game.mu.Lock()
+		defer game.mu.Unlock()
+
+		game.listeners = slices.DeleteFunc(game.listeners, func(listener gameListener) bool {
+			return listener.id == id
+		})
+	}
+
+	snapshot := NewGame(game.variant, slices.Clone(game.positions), slices.Clone(game.moves))
+	snapshot.result, snapshot.termination = game.result, game.termination
+
+	return snapshot, remove
+}
+
+// emitLocked passes passed event to the listeners.
+//
+// The game must be locked.
+func (game *Game) emitLocked(event Event) {
+	for _, listener := range game.listeners {
+		listener.listener(event)
+	}
+}
+
+// emitResultLocked passes the result event to the listeners if the game is over.
+//
+// The game must be locked.
+func (game *Game) emitResultLocked() error {
+	if len(game.listeners) == 0 {
+		return nil
+	}
+
+	result, termination, err := game.resultLocked()
+	if err != nil {
+		return fmt.Errorf("resultLocked(): %w", err)
+	}
+
+	if result != ResultNil {
+		game.emitLocked(Event{
+			Type:        EventTypeResult,
+			Position:    game.positions[len(game.positions)-1],
+			Result:      result,
+			Termination: termination,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGameListen(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t, "f2f3")
+
+	var (
+		events []EventType
+		sans   []string
+	)
+
+	snapshot, remove := game.Listen(func(event Event) {
+		events = append(events, event.Type)
+
+		if event.Type == EventTypeMove {
+			sans = append(sans, event.SAN)
+		}
+	})
+
+	if positions, moves := snapshot.Positions(), snapshot.Moves(); len(positions) != 2 || len(moves) != 1 {
+		t.Fatalf("Listen() expected 2 positions and 1 move but got %d and %d", len(positions), len(moves))
+	}
+
+	moveTestGame(t, game, "e7e5")
+
+	if err := game.OfferTakeback(ColorBlack); err != nil {
+		t.Fatalf("OfferTakeback(): %v", err)
+	}
+
+	if err := game.AcceptTakeback(ColorWhite); err != nil {
+		t.Fatalf("AcceptTakeback(): %v", err)
+	}
+
+	for _, uci := range [...]string{"e7e5", "g2g4", "d8h4"} {
+		moveTestGame(t, game, uci)
+	}
+
+	expected := []EventType{
+		EventTypeMove, EventTypeTakeback, EventTypeMove, EventTypeMove, EventTypeMove, EventTypeResult,
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Listen() expected events %v but got %v", expected, events)
+	}
+
+	if expectedSANs := []string{"e5", "e5", "g4", "Qh4#"}; !reflect.DeepEqual(sans, expectedSANs) {
+		t.Fatalf("Listen() expected moves %v but got %v", expectedSANs, sans)
+	}
+
+	remove()
+
+	game = newTestGame(t)
+
+	events = nil
+	_, remove = game.Listen(func(event Event) {
+		events = append(events, event.Type)
+	})
+
+	remove()
+
+	if err := game.Resign(ColorWhite); err != nil {
+		t.Fatalf("Resign(): %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("Listen() expected no events after the removal but got %v", events)
+	}
+}
@@ -1,17 +1,110 @@
 package game
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
 
-// Game represents chess game with all position history.
+// variantRawMover is implemented by the variants, which make moves in the position differently from the standard
+// chess.
+type variantRawMover interface {
+	moveRaw(position *Position, move Move) error
+}
+
+// variantMaterialChecker is implemented by the variants, where the material to win differs from the standard chess.
+type variantMaterialChecker interface {
+	checkColorInsufficientMaterial(position *Position, color Color) (bool, error)
+}
+
+// newVariantRawMover returns the function, which makes raw moves by the rules of passed variant.
+func newVariantRawMover(variant Variant) rawMover {
+	if mover, ok := variant.(variantRawMover); ok {
+		return mover.moveRaw
+	}
+
+	return (*Position).MoveRaw
+}
+
+// Game represents chess game of some variant with all position history. It is safe for concurrent use. The changes of
+// the game are passed to the listeners added by Listen.
 type Game struct {
+	variant Variant
+
+	mu        sync.RWMutex
 	positions []*Position
+	moves     []Move
+
+	// Result and the reason of the game end, which is not calculated from the positions, for example, the timeout.
+	result      Result
+	termination Termination
+
+	// Colors, which offered the draw and the takeback, or ColorNil if there are no offers.
+	drawOffer     Color
+	takebackOffer Color
+
+	// Queued premoves in UCI and lines of the conditional moves of the colors.
+	premoves     map[Color][]string
+	conditionals map[Color][][]Move
+
+	listeners      []gameListener
+	nextListenerID uint64
 }
 
 // NewGame creates a new game with passed parameters.
-func NewGame(positions []*Position) *Game {
+//
+// Note that the positions must contain one more element than the moves: the position before the first move.
+func NewGame(variant Variant, positions []*Position, moves []Move) *Game {
 	return &Game{
+		variant:   variant,
 		positions: positions,
+		moves:     moves,
+	}
+}
+
+// NewGameFromFEN creates a new game, which starts from the position described by passed FEN.
+//
+// FEN argument example: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1".
+func NewGameFromFEN(fen string) (*Game, error) {
+	position, err := NewPositionFromFEN(fen)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+	}
+
+	return NewGame(NewVariantStandard(Engine{}), []*Position{position}, nil), nil
+}
+
+// NewGameFromUCIs creates a new game of the standard chess, which starts from the position described by passed FEN,
+// and makes passed moves in UCI. Empty FEN means the start position.
+//
+// FEN argument example: "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1".
+//
+// UCIs argument example: "e2e4", "e7e5".
+func NewGameFromUCIs(fen string, ucis ...string) (*Game, error) {
+	game, err := NewGameStart()
+	if err != nil {
+		return nil, fmt.Errorf("NewGameStart(): %w", err)
+	}
+
+	if fen != "" {
+		if game, err = NewGameFromFEN(fen); err != nil {
+			return nil, fmt.Errorf("NewGameFromFEN(%q): %w", fen, err)
+		}
 	}
+
+	for _, uci := range ucis {
+		move, err := Engine{}.CalcMoveFromUCI(game.Position(), uci)
+		if err != nil {
+			return nil, fmt.Errorf("CalcMoveFromUCI(%q): %w", uci, err)
+		}
+
+		if err := game.Move(move); err != nil {
+			return nil, fmt.Errorf("Move(%q): %w", uci, err)
+		}
+	}
+
+	return game, nil
 }
 
 // NewGameStart creates a start of the game.
@@ -23,5 +116,257 @@ func NewGameStart() (*Game, error) {
 
 	positions := []*Position{position}
 
-	return NewGame(positions), nil
+	return NewGame(NewVariantStandard(Engine{}), positions, nil), nil
+}
+
+// NewGameVariantStart creates a start of the game of passed variant.
+func NewGameVariantStart(variant Variant) (*Game, error) {
+	if variant == nil {
+		return nil, errors.New("variant is nil")
+	}
+
+	position, err := variant.NewPositionStart()
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionStart(): %w", err)
+	}
+
+	return NewGame(variant, []*Position{position}, nil), nil
+}
+
+// Move makes passed move in the current position if the game is in progress and the move is possible. The draw and
+// the takeback offers and the premoves of the moving color expire.
+//
+// TODO: test.
+func (game *Game) Move(move Move) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	color := game.positions[len(game.positions)-1].activeColor
+
+	if err := game.moveLocked(move); err != nil {
+		return fmt.Errorf("moveLocked(%+v): %w", move, err)
+	}
+
+	delete(game.premoves, color)
+
+	return nil
+}
+
+// moveLocked makes passed move like Move, but keeps the premoves. The conditional moves, which do not follow passed
+// move, are cancelled.
+//
+// The game must be locked.
+func (game *Game) moveLocked(move Move) error {
+	result, _, err := game.resultLocked()
+	if err != nil {
+		return fmt.Errorf("resultLocked(): %w", err)
+	}
+
+	if result != ResultNil {
+		return errors.New("game is over")
+	}
+
+	position := game.positions[len(game.positions)-1]
+
+	moves, err := game.variant.CalcMoves(position)
+	if err != nil {
+		return fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if !slices.Contains(moves, move) {
+		return fmt.Errorf("move %+v is not possible", move)
+	}
+
+	newPosition, err := position.DeepCopy()
+	if err != nil {
+		return fmt.Errorf("DeepCopy(): %w", err)
+	}
+
+	if err := newVariantRawMover(game.variant)(newPosition, move); err != nil {
+		return fmt.Errorf("moveRaw(%+v): %w", move, err)
+	}
+
+	// The listeners get the SAN of the move, so they do not calculate it with the game locked.
+	var san string
+
+	if len(game.listeners) > 0 {
+		san, err = game.variant.CalcMoveSAN(position, move)
+		if err != nil {
+			return fmt.Errorf("CalcMoveSAN(%+v): %w", move, err)
+		}
+	}
+
+	if game.drawOffer == position.activeColor {
+		game.drawOffer = ColorNil
+	}
+
+	if game.takebackOffer == position.activeColor {
+		game.takebackOffer = ColorNil
+	}
+
+	game.positions = append(game.positions, newPosition)
+	game.moves = append(game.moves, move)
+	game.advanceConditionalsLocked(move)
+	game.emitLocked(Event{Type: EventTypeMove, Move: move, SAN: san, Position: newPosition})
+
+	if err := game.emitResultLocked(); err != nil {
+		return fmt.Errorf("emitResultLocked(): %w", err)
+	}
+
+	return nil
+}
+
+// Moves returns all made moves.
+func (game *Game) Moves() []Move {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return slices.Clone(game.moves)
+}
+
+// Position returns the current position of the game.
+//
+// Please note that the position is not copied, so do not modify it.
+func (game *Game) Position() *Position {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return game.positions[len(game.positions)-1]
+}
+
+// Positions returns all positions of the game from the position before the first move to the current one.
+//
+// Please note that the positions are not copied, so do not modify them.
+func (game *Game) Positions() []*Position {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return slices.Clone(game.positions)
+}
+
+// history returns the copies of the positions and the moves of the game, which are taken at the same moment, so the
+// count of the positions is always one more than the count of the moves.
+func (game *Game) history() ([]*Position, []Move) {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return slices.Clone(game.positions), slices.Clone(game.moves)
+}
+
+// Result calculates the result of the game and the reason of its termination using the rules of the game variant.
+//
+// The game ended by the Timeout has the result of the timeout.
+//
+// ResultNil and TerminationNil are returned if the game is in progress.
+//
+// TODO: test.
+func (game *Game) Result() (Result, Termination, error) {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return game.resultLocked()
+}
+
+// resultLocked calculates the result of the game like Result.
+//
+// The game must be locked.
+func (game *Game) resultLocked() (Result, Termination, error) {
+	if game.result != ResultNil {
+		return game.result, game.termination, nil
+	}
+
+	result, termination, err := game.variant.CalcResult(game.positions, game.moves)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcResult(): %w", err)
+	}
+
+	return result, termination, nil
+}
+
+// End ends the game in progress with passed result and termination, which are not calculated from the positions, for
+// example, after the restoring of the game ended by the timeout.
+func (game *Game) End(result Result, termination Termination) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.endLocked(result, termination)
+}
+
+// endLocked ends the game in progress like End and cancels the offers.
+//
+// The game must be locked.
+func (game *Game) endLocked(result Result, termination Termination) error {
+	if result == ResultNil || termination == TerminationNil {
+		return errors.New("no result")
+	}
+
+	current, _, err := game.resultLocked()
+	if err != nil {
+		return fmt.Errorf("resultLocked(): %w", err)
+	}
+
+	if current != ResultNil {
+		return errors.New("game is over")
+	}
+
+	game.result, game.termination = result, termination
+	game.drawOffer, game.takebackOffer = ColorNil, ColorNil
+	game.emitLocked(Event{
+		Type:        EventTypeResult,
+		Position:    game.positions[len(game.positions)-1],
+		Result:      result,
+		Termination: termination,
+	})
+
+	return nil
+}
+
+// Timeout ends the game in progress, because passed color has run out of time.
+//
+// The opponent wins if it has enough material to win by the rules of the variant, otherwise the game is drawn.
+func (game *Game) Timeout(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponentColor, err := color.Opposite()
+	if err != nil {
+		return fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	var (
+		position             = game.positions[len(game.positions)-1]
+		insufficientMaterial bool
+	)
+
+	if checker, ok := game.variant.(variantMaterialChecker); ok {
+		insufficientMaterial, err = checker.checkColorInsufficientMaterial(position, opponentColor)
+	} else {
+		insufficientMaterial, err = checkColorInsufficientMaterial(position.board, opponentColor)
+	}
+
+	if err != nil {
+		return fmt.Errorf("checkColorInsufficientMaterial(%s): %w", opponentColor, err)
+	}
+
+	result, termination := ResultDraw, TerminationTimeoutVsInsufficientMaterial
+
+	if !insufficientMaterial {
+		result, err = newResultLost(color)
+		if err != nil {
+			return fmt.Errorf("newResultLost(%s): %w", color, err)
+		}
+
+		termination = TerminationTimeout
+	}
+
+	if err := game.endLocked(result, termination); err != nil {
+		return fmt.Errorf("End(%s, %s): %w", result, termination, err)
+	}
+
+	return nil
+}
+
+// Variant returns the variant of the game.
+func (game *Game) Variant() Variant {
+	return game.variant
 }
@@ -13,7 +13,7 @@ func TestNewGameStart(t *testing.T) {
 		t.Fatalf("NewPositionStart(): %v", err)
 	}
 
-	expectedGame := NewGame([]*Position{startPosition})
+	expectedGame := NewGame(NewVariantStandard(Engine{}), []*Position{startPosition}, nil)
 
 	gotGame, err := NewGameStart()
 	if err != nil {
@@ -24,3 +24,207 @@ func TestNewGameStart(t *testing.T) {
 		t.Fatalf("NewGameStart() expected %+v but got %+v", expectedGame, gotGame)
 	}
 }
+
+func TestNewGameFromUCIs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		fen       string
+		ucis      []string
+		lastFEN   string
+		errString string
+	}{
+		{"start", "", nil, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", ""},
+		{
+			"start with moves",
+			"",
+			[]string{"e2e4", "e7e5"},
+			"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2",
+			"",
+		},
+		{"fen with moves", "4k3/8/8/8/8/8/4P3/4K3 w - - 0 30", []string{"e2e4"}, "4k3/8/8/8/4P3/8/8/4K3 b - e3 0 30", ""},
+		{"impossible move", "", []string{"e2e5"}, "", "CalcMoveFromUCI(\"e2e5\"): no possible move \"e2e5\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			game, err := NewGameFromUCIs(test.fen, test.ucis...)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewGameFromUCIs(%q, %v) expected error %q but got %q", test.fen, test.ucis, test.errString, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if fen, err := game.Position().FEN(); err != nil || fen != test.lastFEN || len(game.Moves()) != len(test.ucis) {
+				t.Fatalf("NewGameFromUCIs(%q, %v) expected %q but got %q, %v", test.fen, test.ucis, test.lastFEN, fen, err)
+			}
+		})
+	}
+}
+
+func TestGameTimeout(t *testing.T) {
+	t.Parallel()
+
+	standard := NewVariantStandard(Engine{})
+
+	tests := []struct {
+		name        string
+		variant     Variant
+		fen         string
+		color       Color
+		result      Result
+		termination Termination
+		errString   string
+	}{
+		{"black flagged", standard, "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", ColorBlack, ResultWhiteWon, TerminationTimeout, ""},
+		{"white flagged", standard, "4k3/p7/8/8/8/8/8/4K3 w - - 0 1", ColorWhite, ResultBlackWon, TerminationTimeout, ""},
+		{
+			"rook and knight",
+			standard,
+			"4k3/8/8/8/8/8/8/RN2K3 w - - 0 1",
+			ColorBlack,
+			ResultWhiteWon,
+			TerminationTimeout,
+			"",
+		},
+		{
+			"insufficient material",
+			standard,
+			"4k3/8/8/8/8/8/8/R3KN2 w - - 0 1",
+			ColorWhite,
+			ResultDraw,
+			TerminationTimeoutVsInsufficientMaterial,
+			"",
+		},
+		{
+			"game over",
+			standard,
+			"R3k3/8/4K3/8/8/8/8/8 b - - 0 1",
+			ColorBlack,
+			ResultWhiteWon,
+			TerminationCheckmate,
+			"End(ResultWhiteWon, TerminationTimeout): game is over",
+		},
+		{
+			"crazyhouse pocket",
+			NewVariantCrazyhouse(Engine{}),
+			"4k3/8/8/8/8/8/8/R3K3[q] w - - 0 1",
+			ColorWhite,
+			ResultBlackWon,
+			TerminationTimeout,
+			"",
+		},
+		{
+			"antichess king",
+			NewVariantAntichess(Engine{}),
+			"4k3/8/8/8/8/8/8/R3K3 w - - 0 1",
+			ColorWhite,
+			ResultBlackWon,
+			TerminationTimeout,
+			"",
+		},
+		{
+			"three-check knight",
+			NewVariantThreeCheck(Engine{}),
+			"4k3/8/8/8/8/8/8/4KN2 w - - 0 1",
+			ColorBlack,
+			ResultWhiteWon,
+			TerminationTimeout,
+			"",
+		},
+		{
+			"three-check bare king",
+			NewVariantThreeCheck(Engine{}),
+			"4k3/p7/8/8/8/8/8/4K3 w - - 0 1",
+			ColorBlack,
+			ResultDraw,
+			TerminationTimeoutVsInsufficientMaterial,
+			"",
+		},
+		{
+			"atomic rook vs bare king",
+			NewVariantAtomic(Engine{}),
+			"4k3/8/8/8/8/8/8/R3K3 w - - 0 1",
+			ColorBlack,
+			ResultDraw,
+			TerminationTimeoutVsInsufficientMaterial,
+			"",
+		},
+		{
+			"atomic rook vs pawn",
+			NewVariantAtomic(Engine{}),
+			"4k3/p7/8/8/8/8/8/R3K3 w - - 0 1",
+			ColorBlack,
+			ResultWhiteWon,
+			TerminationTimeout,
+			"",
+		},
+		{
+			"atomic bishops apart",
+			NewVariantAtomic(Engine{}),
+			"2b1k3/8/8/8/8/8/8/2B1K3 w - - 0 1",
+			ColorBlack,
+			ResultDraw,
+			TerminationTimeoutVsInsufficientMaterial,
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			game := NewGame(test.variant, []*Position{position}, nil)
+
+			err = game.Timeout(test.color)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("Timeout(%s) expected error %q but got %q", test.color, test.errString, err)
+			}
+
+			result, termination, err := game.Result()
+			if err != nil {
+				t.Fatalf("Result(): %v", err)
+			}
+
+			if result != test.result || termination != test.termination {
+				t.Fatalf("Result() expected %s, %s but got %s, %s", test.result, test.termination, result, termination)
+			}
+		})
+	}
+}
+
+func TestGameEnd(t *testing.T) {
+	t.Parallel()
+
+	game, err := NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	if err := game.End(ResultNil, TerminationAdjudication); err == nil || err.Error() != "no result" {
+		t.Fatalf("End(ResultNil) expected error %q but got %q", "no result", err)
+	}
+
+	if err := game.End(ResultDraw, TerminationAdjudication); err != nil {
+		t.Fatalf("End(ResultDraw): %v", err)
+	}
+
+	if err := game.End(ResultWhiteWon, TerminationTimeout); err == nil || err.Error() != "game is over" {
+		t.Fatalf("End(ResultWhiteWon) expected error %q but got %q", "game is over", err)
+	}
+
+	result, termination, err := game.Result()
+	if err != nil || result != ResultDraw || termination != TerminationAdjudication {
+		t.Fatalf("Result() expected draw by adjudication but got %s, %s, %v", result, termination, err)
+	}
+}
@@ -0,0 +1,29 @@
+package game
+
+import "testing"
+
+// newTestGame creates the game of the standard chess from the start position and makes passed moves in UCI.
+func newTestGame(t *testing.T, ucis ...string) *Game {
+	t.Helper()
+
+	game, err := NewGameFromUCIs("", ucis...)
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(%v): %v", ucis, err)
+	}
+
+	return game
+}
+
+// moveTestGame makes the move with passed UCI in passed game.
+func moveTestGame(t *testing.T, game *Game, uci string) {
+	t.Helper()
+
+	move, err := Engine{}.CalcMoveFromUCI(game.Position(), uci)
+	if err != nil {
+		t.Fatalf("CalcMoveFromUCI(%q): %v", uci, err)
+	}
+
+	if err := game.Move(move); err != nil {
+		t.Fatalf("Move(%q): %v", uci, err)
+	}
+}
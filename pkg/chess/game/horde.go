@@ -0,0 +1,196 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Start position of the Horde, where the white has 36 pawns and no king.
+const hordeStartFEN = "rnbqkbnr/pppppppp/8/1PP2PP1/PPPPPPPP/PPPPPPPP/PPPPPPPP/PPPPPPPP w kq - 0 1"
+
+// VariantHorde represents the rules of the Horde, where the white wins by checkmating the black and the black wins by
+// capturing all white pieces. The white pawns on the first rank can also move two squares forward.
+type VariantHorde struct {
+	engine Engine
+}
+
+// NewVariantHorde creates a new Horde variant, which uses passed engine.
+func NewVariantHorde(engine Engine) VariantHorde {
+	return VariantHorde{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+func (variant VariantHorde) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.calcMoveFromSAN(position, san, variant.CalcMoves)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+func (variant VariantHorde) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.calcMoveSAN(position, move, variant.CalcMoves, newVariantRawMover(variant))
+}
+
+// CalcMoves calculates all possible moves in passed position for active color pieces.
+//
+// Only the colors with the king are checked for the king safety, so the white moves are not restricted.
+func (variant VariantHorde) CalcMoves(position *Position) ([]Move, error) {
+	rawMoves, err := variant.engine.CalcRawMoves(position)
+	if err != nil {
+		return nil, fmt.Errorf("CalcRawMoves(): %w", err)
+	}
+
+	if position.activeColor == ColorWhite {
+		firstRankMoves, err := variant.calcFirstRankPawnLongMoves(position)
+		if err != nil {
+			return nil, fmt.Errorf("calcFirstRankPawnLongMoves(): %w", err)
+		}
+
+		rawMoves = append(rawMoves, firstRankMoves...)
+	}
+
+	opponentColor, err := position.activeColor.Opposite()
+	if err != nil {
+		return nil, fmt.Errorf("%s.Opposite(): %w", position.activeColor, err)
+	}
+
+	hasKing, err := checkColorHasKing(position, position.activeColor)
+	if err != nil {
+		return nil, fmt.Errorf("checkColorHasKing(%s): %w", position.activeColor, err)
+	}
+
+	opponentHasKing, err := checkColorHasKing(position, opponentColor)
+	if err != nil {
+		return nil, fmt.Errorf("checkColorHasKing(%s): %w", opponentColor, err)
+	}
+
+	moves := make([]Move, 0, len(rawMoves))
+
+	for _, rawMove := range rawMoves {
+		if hasKing {
+			putsHisColorInCheck, err := variant.engine.checkPutsColorInCheck(position, rawMove, position.activeColor)
+			if err != nil {
+				return nil, fmt.Errorf("checkPutsColorInCheck(%+v, %s): %w", rawMove, position.activeColor, err)
+			}
+
+			if putsHisColorInCheck {
+				continue
+			}
+		}
+
+		if opponentHasKing {
+			if err := variant.engine.addRawMoveCheckTag(position, &rawMove, position.activeColor); err != nil {
+				return nil, fmt.Errorf("addRawMoveCheckTag(%+v, %s): %w", rawMove, position.activeColor, err)
+			}
+		}
+
+		moves = append(moves, rawMove)
+	}
+
+	return moves, nil
+}
+
+// CalcResult calculates the result of the game with passed positions and the reason of its termination.
+//
+// The game ends when the white has no pieces, by checkmate, stalemate, fifty moves or repetition.
+func (variant VariantHorde) CalcResult(positions []*Position, _ []Move) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	whiteBitboard, err := position.board.GetColorBitboard(ColorWhite)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("GetColorBitboard(%s): %w", ColorWhite, err)
+	}
+
+	if whiteBitboard == BitboardNil {
+		return ResultBlackWon, TerminationVariantEnd, nil
+	}
+
+	moves, err := variant.CalcMoves(position)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		hasKing, err := checkColorHasKing(position, position.activeColor)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("checkColorHasKing(%s): %w", position.activeColor, err)
+		}
+
+		if !hasKing {
+			return ResultDraw, TerminationStalemate, nil
+		}
+
+		return calcNoMovesResult(variant.engine, position)
+	}
+
+	return calcDrawResult(positions)
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantHorde) Name() string {
+	return "Horde"
+}
+
+// NewPositionStart creates the start position of the Horde.
+func (variant VariantHorde) NewPositionStart() (*Position, error) {
+	position, err := NewPositionFromFEN(hordeStartFEN)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", hordeStartFEN, err)
+	}
+
+	return position, nil
+}
+
+// calcFirstRankPawnLongMoves calculates the moves of the white pawns from the first rank two squares forward.
+//
+// Note that the moves are raw, so they do not have check tags.
+func (variant VariantHorde) calcFirstRankPawnLongMoves(position *Position) ([]Move, error) {
+	var moves []Move
+
+	for _, origin := range position.board.bitboards[PieceWhitePawn].GetSquares() {
+		rank, err := origin.Rank()
+		if err != nil {
+			return nil, fmt.Errorf("%s.Rank(): %w", origin, err)
+		}
+
+		if rank != Rank1 {
+			continue
+		}
+
+		file, err := origin.File()
+		if err != nil {
+			return nil, fmt.Errorf("%s.File(): %w", origin, err)
+		}
+
+		free := true
+
+		for _, pathRank := range [...]Rank{Rank2, Rank3} {
+			square, err := NewSquare(pathRank, file)
+			if err != nil {
+				return nil, fmt.Errorf("NewSquare(%s, %s): %w", pathRank, file, err)
+			}
+
+			piece, err := position.board.GetPieceFromSquare(square)
+			if err != nil {
+				return nil, fmt.Errorf("GetPieceFromSquare(%s): %w", square, err)
+			}
+
+			free = free && piece == PieceNil
+		}
+
+		if !free {
+			continue
+		}
+
+		dest, err := NewSquare(Rank3, file)
+		if err != nil {
+			return nil, fmt.Errorf("NewSquare(%s, %s): %w", Rank3, file, err)
+		}
+
+		moves = append(moves, NewMove(origin, dest, MoveTagsNil, RoleNil))
+	}
+
+	return moves, nil
+}
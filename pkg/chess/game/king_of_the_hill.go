@@ -0,0 +1,95 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VariantKingOfTheHill represents the rules of the King of the Hill chess, where the player also wins by bringing the
+// king to one of the central squares.
+type VariantKingOfTheHill struct {
+	engine Engine
+}
+
+// NewVariantKingOfTheHill creates a new King of the Hill variant, which uses passed engine.
+func NewVariantKingOfTheHill(engine Engine) VariantKingOfTheHill {
+	return VariantKingOfTheHill{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+func (variant VariantKingOfTheHill) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.CalcMoveFromSAN(position, san)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+func (variant VariantKingOfTheHill) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.CalcMoveSAN(position, move)
+}
+
+// CalcMoves calculates all possible moves in passed position for active color pieces. The moves are the same as in
+// the standard chess.
+func (variant VariantKingOfTheHill) CalcMoves(position *Position) ([]Move, error) {
+	return variant.engine.CalcMoves(position)
+}
+
+// CalcResult calculates the result of the game with passed positions and the reason of its termination.
+//
+// The game ends when the king reaches the hill, by checkmate, stalemate, fifty moves or repetition. The material is
+// never insufficient, because the king alone can reach the hill.
+func (variant VariantKingOfTheHill) CalcResult(positions []*Position, _ []Move) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	hillBitboard, err := BitboardNil.SetSquares(SquareD4, SquareE4, SquareD5, SquareE5)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("SetSquares(): %w", err)
+	}
+
+	for _, king := range [...]Piece{PieceWhiteKing, PieceBlackKing} {
+		if position.board.bitboards[king]&hillBitboard == BitboardNil {
+			continue
+		}
+
+		color, err := king.Color()
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("%s.Color(): %w", king, err)
+		}
+
+		result, err := NewResultWon(color)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("NewResultWon(%s): %w", color, err)
+		}
+
+		return result, TerminationVariantEnd, nil
+	}
+
+	moves, err := variant.CalcMoves(position)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		return calcNoMovesResult(variant.engine, position)
+	}
+
+	return calcDrawResult(positions)
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantKingOfTheHill) Name() string {
+	return "King of the Hill"
+}
+
+// NewPositionStart creates the start position, which is the same as in the standard chess.
+func (variant VariantKingOfTheHill) NewPositionStart() (*Position, error) {
+	return NewPositionStart()
+}
+
+// checkColorInsufficientMaterial checks that passed color has no enough material to win. The material is never
+// insufficient, because the king alone can reach the hill.
+func (variant VariantKingOfTheHill) checkColorInsufficientMaterial(_ *Position, _ Color) (bool, error) {
+	return false, nil
+}
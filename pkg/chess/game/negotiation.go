@@ -0,0 +1,228 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Resign ends the game in progress, because passed color has resigned. The opponent wins.
+func (game *Game) Resign(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	result, err := newResultLost(color)
+	if err != nil {
+		return fmt.Errorf("newResultLost(%s): %w", color, err)
+	}
+
+	if err := game.endLocked(result, TerminationResignation); err != nil {
+		return fmt.Errorf("endLocked(%s, %s): %w", result, TerminationResignation, err)
+	}
+
+	return nil
+}
+
+// DrawOffer returns the color, which offered the draw, or ColorNil if there is no offer.
+func (game *Game) DrawOffer() Color {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return game.drawOffer
+}
+
+// OfferDraw offers the draw from passed color. The game is drawn by agreement if the opponent has already offered the
+// draw.
+//
+// The offer expires when passed color moves.
+func (game *Game) OfferDraw(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponent, err := game.checkOfferLocked(color)
+	if err != nil {
+		return fmt.Errorf("checkOfferLocked(%s): %w", color, err)
+	}
+
+	if game.drawOffer == color {
+		return errors.New("draw is already offered")
+	}
+
+	if game.drawOffer != opponent {
+		game.drawOffer = color
+		return nil
+	}
+
+	if err := game.endLocked(ResultDraw, TerminationAgreement); err != nil {
+		return fmt.Errorf("endLocked(%s, %s): %w", ResultDraw, TerminationAgreement, err)
+	}
+
+	return nil
+}
+
+// AcceptDraw accepts the draw offered by the opponent of passed color. The game is drawn by agreement.
+func (game *Game) AcceptDraw(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponent, err := game.checkOfferLocked(color)
+	if err != nil {
+		return fmt.Errorf("checkOfferLocked(%s): %w", color, err)
+	}
+
+	if game.drawOffer != opponent {
+		return errors.New("no draw offer")
+	}
+
+	if err := game.endLocked(ResultDraw, TerminationAgreement); err != nil {
+		return fmt.Errorf("endLocked(%s, %s): %w", ResultDraw, TerminationAgreement, err)
+	}
+
+	return nil
+}
+
+// DeclineDraw declines the draw offered by the opponent of passed color.
+func (game *Game) DeclineDraw(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponent, err := game.checkOfferLocked(color)
+	if err != nil {
+		return fmt.Errorf("checkOfferLocked(%s): %w", color, err)
+	}
+
+	if game.drawOffer != opponent {
+		return errors.New("no draw offer")
+	}
+
+	game.drawOffer = ColorNil
+
+	return nil
+}
+
+// TakebackOffer returns the color, which offered the takeback, or ColorNil if there is no offer.
+func (game *Game) TakebackOffer() Color {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return game.takebackOffer
+}
+
+// OfferTakeback offers the takeback of the last move of passed color. Only one takeback may be offered at a time.
+//
+// The offer expires when passed color moves.
+func (game *Game) OfferTakeback(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponent, err := game.checkOfferLocked(color)
+	if err != nil {
+		return fmt.Errorf("checkOfferLocked(%s): %w", color, err)
+	}
+
+	if game.takebackOffer == color {
+		return errors.New("takeback is already offered")
+	}
+
+	if game.takebackOffer == opponent {
+		return errors.New("opponent has offered the takeback")
+	}
+
+	if game.calcTakebackPliesLocked(color) == 0 {
+		return errors.New("no move to take back")
+	}
+
+	game.takebackOffer = color
+
+	return nil
+}
+
+// AcceptTakeback accepts the takeback offered by the opponent of passed color. The last move of the opponent and the
+// following move of passed color, if any, are taken back, so the opponent is to move again. The premoves and the
+// conditional moves are cancelled.
+func (game *Game) AcceptTakeback(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponent, err := game.checkOfferLocked(color)
+	if err != nil {
+		return fmt.Errorf("checkOfferLocked(%s): %w", color, err)
+	}
+
+	if game.takebackOffer != opponent {
+		return errors.New("no takeback offer")
+	}
+
+	plies := game.calcTakebackPliesLocked(opponent)
+	if plies == 0 {
+		return errors.New("no move to take back")
+	}
+
+	game.positions = game.positions[:len(game.positions)-plies]
+	game.moves = game.moves[:len(game.moves)-plies]
+	game.drawOffer, game.takebackOffer = ColorNil, ColorNil
+	game.premoves, game.conditionals = nil, nil
+	game.emitLocked(Event{Type: EventTypeTakeback, Plies: plies, Position: game.positions[len(game.positions)-1]})
+
+	return nil
+}
+
+// DeclineTakeback declines the takeback offered by the opponent of passed color.
+func (game *Game) DeclineTakeback(color Color) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	opponent, err := game.checkOfferLocked(color)
+	if err != nil {
+		return fmt.Errorf("checkOfferLocked(%s): %w", color, err)
+	}
+
+	if game.takebackOffer != opponent {
+		return errors.New("no takeback offer")
+	}
+
+	game.takebackOffer = ColorNil
+
+	return nil
+}
+
+// checkOfferLocked checks that passed color may offer, accept or decline in the game in progress.
+//
+// Returns the opponent of passed color.
+//
+// The game must be locked.
+func (game *Game) checkOfferLocked(color Color) (Color, error) {
+	opponent, err := color.Opposite()
+	if err != nil {
+		return ColorNil, fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	result, _, err := game.resultLocked()
+	if err != nil {
+		return ColorNil, fmt.Errorf("resultLocked(): %w", err)
+	}
+
+	if result != ResultNil {
+		return ColorNil, errors.New("game is over")
+	}
+
+	return opponent, nil
+}
+
+// calcTakebackPliesLocked calculates the count of the moves to take back the last move of passed color: one if
+// passed color made the last move and two if the opponent has already replied.
+//
+// Returns zero if passed color has not moved yet.
+//
+// The game must be locked.
+func (game *Game) calcTakebackPliesLocked(color Color) int {
+	plies := 1
+	if game.positions[len(game.positions)-1].activeColor == color {
+		plies = 2
+	}
+
+	if plies > len(game.moves) {
+		return 0
+	}
+
+	return plies
+}
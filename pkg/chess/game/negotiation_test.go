@@ -0,0 +1,238 @@
+package game
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGameResign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		color     Color
+		result    Result
+		errString string
+	}{
+		{"white resigned", ColorWhite, ResultBlackWon, ""},
+		{"black resigned", ColorBlack, ResultWhiteWon, ""},
+		{"no color", ColorNil, ResultNil, "newResultLost(ColorNil): ColorNil.Opposite(): no opposite"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			game, err := NewGameStart()
+			if err != nil {
+				t.Fatalf("NewGameStart(): %v", err)
+			}
+
+			err = game.Resign(test.color)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("Resign(%s) expected error %q but got %q", test.color, test.errString, err)
+			}
+
+			result, termination, err := game.Result()
+			if err != nil {
+				t.Fatalf("Result(): %v", err)
+			}
+
+			if result != test.result || (result != ResultNil && termination != TerminationResignation) {
+				t.Fatalf("Resign(%s) expected %s but got %s, %s", test.color, test.result, result, termination)
+			}
+		})
+	}
+}
+
+func TestGameDraw(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t, "e2e4", "e7e5")
+
+	if err := game.AcceptDraw(ColorBlack); err == nil || err.Error() != "no draw offer" {
+		t.Fatalf("AcceptDraw() expected error %q but got %q", "no draw offer", err)
+	}
+
+	if err := game.OfferDraw(ColorWhite); err != nil {
+		t.Fatalf("OfferDraw(white): %v", err)
+	}
+
+	if err := game.OfferDraw(ColorWhite); err == nil || err.Error() != "draw is already offered" {
+		t.Fatalf("OfferDraw(white) expected error %q but got %q", "draw is already offered", err)
+	}
+
+	if err := game.AcceptDraw(ColorWhite); err == nil || err.Error() != "no draw offer" {
+		t.Fatalf("AcceptDraw(white) expected error %q but got %q", "no draw offer", err)
+	}
+
+	if err := game.DeclineDraw(ColorBlack); err != nil {
+		t.Fatalf("DeclineDraw(black): %v", err)
+	}
+
+	if offer := game.DrawOffer(); offer != ColorNil {
+		t.Fatalf("DeclineDraw() expected no offer but got %s", offer)
+	}
+
+	// The offer of black stays after the move of white and expires after the move of black.
+	if err := game.OfferDraw(ColorBlack); err != nil {
+		t.Fatalf("OfferDraw(black): %v", err)
+	}
+
+	moveTestGame(t, game, "g1f3")
+
+	if offer := game.DrawOffer(); offer != ColorBlack {
+		t.Fatalf("DrawOffer() expected the offer of black but got %s", offer)
+	}
+
+	moveTestGame(t, game, "b8c6")
+
+	if offer := game.DrawOffer(); offer != ColorNil {
+		t.Fatalf("DrawOffer() expected the expired offer but got %s", offer)
+	}
+
+	// Mutual offers are the agreement.
+	if err := game.OfferDraw(ColorWhite); err != nil {
+		t.Fatalf("OfferDraw(white): %v", err)
+	}
+
+	if err := game.OfferDraw(ColorBlack); err != nil {
+		t.Fatalf("OfferDraw(black): %v", err)
+	}
+
+	result, termination, err := game.Result()
+	if err != nil || result != ResultDraw || termination != TerminationAgreement {
+		t.Fatalf("Result() expected draw by agreement but got %s, %s, %v", result, termination, err)
+	}
+
+	if err := game.OfferDraw(ColorWhite); err == nil || err.Error() != "checkOfferLocked(ColorWhite): game is over" {
+		t.Fatalf("OfferDraw() after the end expected error but got %q", err)
+	}
+}
+
+func TestGameTakeback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		color     Color
+		moves     int
+		errString string
+	}{
+		{"last move", ColorBlack, 1, ""},
+		{"replied move", ColorWhite, 0, ""},
+		{"no color", ColorNil, 2, "checkOfferLocked(ColorNil): ColorNil.Opposite(): no opposite"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			game := newTestGame(t, "e2e4", "e7e5")
+
+			err := game.OfferTakeback(test.color)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("OfferTakeback(%s) expected error %q but got %q", test.color, test.errString, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			opponent, err := test.color.Opposite()
+			if err != nil {
+				t.Fatalf("%s.Opposite(): %v", test.color, err)
+			}
+
+			if err := game.AcceptTakeback(opponent); err != nil {
+				t.Fatalf("AcceptTakeback(%s): %v", opponent, err)
+			}
+
+			if moves := len(game.Moves()); moves != test.moves || len(game.Positions()) != moves+1 {
+				t.Fatalf("AcceptTakeback() expected %d moves but got %d", test.moves, moves)
+			}
+
+			if color := game.Position().ActiveColor(); color != test.color || game.TakebackOffer() != ColorNil {
+				t.Fatalf("AcceptTakeback() expected %s to move without offers but got %s", test.color, color)
+			}
+		})
+	}
+}
+
+func TestGameTakebackErrors(t *testing.T) {
+	t.Parallel()
+
+	game, err := NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	if err := game.OfferTakeback(ColorWhite); err == nil || err.Error() != "no move to take back" {
+		t.Fatalf("OfferTakeback() expected error %q but got %q", "no move to take back", err)
+	}
+
+	moveTestGame(t, game, "e2e4")
+
+	if err := game.OfferTakeback(ColorWhite); err != nil {
+		t.Fatalf("OfferTakeback(white): %v", err)
+	}
+
+	if err := game.OfferTakeback(ColorBlack); err == nil || err.Error() != "opponent has offered the takeback" {
+		t.Fatalf("OfferTakeback(black) expected error but got %q", err)
+	}
+
+	if err := game.DeclineTakeback(ColorBlack); err != nil {
+		t.Fatalf("DeclineTakeback(black): %v", err)
+	}
+
+	if err := game.AcceptTakeback(ColorBlack); err == nil || err.Error() != "no takeback offer" {
+		t.Fatalf("AcceptTakeback() expected error %q but got %q", "no takeback offer", err)
+	}
+
+	// The offer of white expires after the next move of white.
+	if err := game.OfferTakeback(ColorWhite); err != nil {
+		t.Fatalf("OfferTakeback(white): %v", err)
+	}
+
+	moveTestGame(t, game, "e7e5")
+	moveTestGame(t, game, "g1f3")
+
+	if offer := game.TakebackOffer(); offer != ColorNil {
+		t.Fatalf("TakebackOffer() expected the expired offer but got %s", offer)
+	}
+}
+
+func TestGameNegotiationConcurrent(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t, "e2e4", "e7e5")
+
+	var wg sync.WaitGroup
+
+	for player := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			color := [...]Color{ColorWhite, ColorBlack}[player%2]
+
+			for range 100 {
+				game.OfferDraw(color)       //nolint:errcheck // The offers race with each other.
+				game.DeclineDraw(color)     //nolint:errcheck // The offers race with each other.
+				game.OfferTakeback(color)   //nolint:errcheck // The offers race with each other.
+				game.DeclineTakeback(color) //nolint:errcheck // The offers race with each other.
+				game.DrawOffer()
+				game.Positions()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// The game is still in progress or drawn by the mutual offers.
+	result, _, err := game.Result()
+	if err != nil || (result != ResultNil && result != ResultDraw) {
+		t.Fatalf("Result() expected no result or draw but got %s, %v", result, err)
+	}
+}
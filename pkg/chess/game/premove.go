@@ -0,0 +1,251 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Premove queues passed move in UCI of passed color, which waits for the move of the opponent. The queued moves are
+// played by PlayQueuedMove on the next turns of the color in the order of queuing.
+//
+// The move is not validated until its turn, because the move of the opponent is not known yet.
+//
+// UCI argument examples: "e2e4", "e7e8q".
+func (game *Game) Premove(color Color, uci string) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if err := game.checkQueueLocked(color); err != nil {
+		return fmt.Errorf("checkQueueLocked(%s): %w", color, err)
+	}
+
+	if game.premoves == nil {
+		game.premoves = make(map[Color][]string)
+	}
+
+	game.premoves[color] = append(game.premoves[color], uci)
+
+	return nil
+}
+
+// Premoves returns the queued premoves of passed color in UCI.
+func (game *Game) Premoves(color Color) []string {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	return slices.Clone(game.premoves[color])
+}
+
+// CancelPremoves cancels the queued premoves of passed color.
+func (game *Game) CancelPremoves(color Color) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	delete(game.premoves, color)
+}
+
+// AddConditionalMoves adds the line of the conditional moves of passed color, which waits for the move of the
+// opponent. The line consists of the pairs of the expected move of the opponent and the reply of passed color in
+// standard algebraic notation starting from the current position.
+//
+// The reply is played by PlayQueuedMove if the opponent played the expected move, otherwise the line is cancelled.
+// The lines must not have the different replies to the same moves of the opponent.
+//
+// SANs argument example: ["Nf3", "Nc6", "Bb5", "a6"] means "if Nf3 then Nc6, if Bb5 then a6".
+func (game *Game) AddConditionalMoves(color Color, sans []string) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if err := game.checkQueueLocked(color); err != nil {
+		return fmt.Errorf("checkQueueLocked(%s): %w", color, err)
+	}
+
+	if len(sans) == 0 || len(sans)%2 != 0 {
+		return errors.New("moves are not pairs of the opponent move and the reply")
+	}
+
+	replay := NewGame(game.variant, []*Position{game.positions[len(game.positions)-1]}, nil)
+	line := make([]Move, 0, len(sans))
+
+	for index, san := range sans {
+		move, err := game.variant.CalcMoveFromSAN(replay.Position(), san)
+		if err != nil {
+			return fmt.Errorf("move #%d, CalcMoveFromSAN(%q): %w", index, san, err)
+		}
+
+		if err := replay.Move(move); err != nil {
+			return fmt.Errorf("move #%d, Move(%q): %w", index, san, err)
+		}
+
+		line = append(line, move)
+	}
+
+	for _, existing := range game.conditionals[color] {
+		if checkConditionalsConflict(existing, line) {
+			return errors.New("line has the different reply than the added line")
+		}
+	}
+
+	if game.conditionals == nil {
+		game.conditionals = make(map[Color][][]Move)
+	}
+
+	game.conditionals[color] = append(game.conditionals[color], line)
+
+	return nil
+}
+
+// ConditionalMoves returns the remaining lines of the conditional moves of passed color. The line starts with the
+// expected move of the opponent if passed color waits for it and with the reply otherwise.
+func (game *Game) ConditionalMoves(color Color) [][]Move {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	lines := make([][]Move, 0, len(game.conditionals[color]))
+
+	for _, line := range game.conditionals[color] {
+		lines = append(lines, slices.Clone(line))
+	}
+
+	return lines
+}
+
+// CancelConditionalMoves cancels all lines of the conditional moves of passed color.
+func (game *Game) CancelConditionalMoves(color Color) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	delete(game.conditionals, color)
+}
+
+// PlayQueuedMove plays the queued move of the color to move. It should be called after each move, so the queued move
+// is played the moment the opponent moves.
+//
+// The reply of the conditional moves is played first. Otherwise the next premove is validated against the possible
+// moves and played. All premoves of the color are cancelled if the premove is not possible.
+//
+// Returns false if no move was played, for example, if the game is over.
+func (game *Game) PlayQueuedMove() (Move, bool, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	result, _, err := game.resultLocked()
+	if err != nil {
+		return Move{}, false, fmt.Errorf("resultLocked(): %w", err)
+	}
+
+	if result != ResultNil {
+		return Move{}, false, nil
+	}
+
+	position := game.positions[len(game.positions)-1]
+	color := position.activeColor
+
+	if lines := game.conditionals[color]; len(lines) > 0 {
+		// The lines of the color to move start with the same reply.
+		move := lines[0][0]
+
+		if err := game.moveLocked(move); err != nil {
+			return Move{}, false, fmt.Errorf("conditional moveLocked(%+v): %w", move, err)
+		}
+
+		return move, true, nil
+	}
+
+	premoves := game.premoves[color]
+	if len(premoves) == 0 {
+		return Move{}, false, nil
+	}
+
+	game.premoves[color] = premoves[1:]
+	if len(premoves) == 1 {
+		delete(game.premoves, color)
+	}
+
+	moves, err := game.variant.CalcMoves(position)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	for _, move := range moves {
+		uci, err := move.UCI()
+		if err != nil {
+			return Move{}, false, fmt.Errorf("UCI(%+v): %w", move, err)
+		}
+
+		if uci != premoves[0] {
+			continue
+		}
+
+		if err := game.moveLocked(move); err != nil {
+			return Move{}, false, fmt.Errorf("premove moveLocked(%+v): %w", move, err)
+		}
+
+		return move, true, nil
+	}
+
+	delete(game.premoves, color)
+
+	return Move{}, false, nil
+}
+
+// checkQueueLocked checks that passed color may queue the moves in the game in progress, because it waits for the move
+// of the opponent.
+//
+// The game must be locked.
+func (game *Game) checkQueueLocked(color Color) error {
+	if _, err := color.Opposite(); err != nil {
+		return fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	result, _, err := game.resultLocked()
+	if err != nil {
+		return fmt.Errorf("resultLocked(): %w", err)
+	}
+
+	if result != ResultNil {
+		return errors.New("game is over")
+	}
+
+	if game.positions[len(game.positions)-1].activeColor == color {
+		return errors.New("color is to move")
+	}
+
+	return nil
+}
+
+// advanceConditionalsLocked removes passed move from the start of the lines of the conditional moves. The lines,
+// which do not start with passed move, are cancelled.
+//
+// The game must be locked.
+func (game *Game) advanceConditionalsLocked(move Move) {
+	for color, lines := range game.conditionals {
+		var advanced [][]Move
+
+		for _, line := range lines {
+			if line[0] == move && len(line) > 1 {
+				advanced = append(advanced, line[1:])
+			}
+		}
+
+		if len(advanced) == 0 {
+			delete(game.conditionals, color)
+		} else {
+			game.conditionals[color] = advanced
+		}
+	}
+}
+
+// checkConditionalsConflict checks that passed lines of the conditional moves of the same color have the different
+// replies to the same moves of the opponent.
+func checkConditionalsConflict(first, second []Move) bool {
+	for index := range min(len(first), len(second)) {
+		if first[index] != second[index] {
+			// The moves of the opponent are at the even indexes.
+			return index%2 == 1
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGamePlayQueuedMovePremoves(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		premoves []string
+		// Moves of black, after each of which the queued move is played.
+		replies []string
+		played  []string
+	}{
+		{"premoves", []string{"g1f3", "f1c4"}, []string{"e7e5", "b8c6"}, []string{"g1f3", "f1c4"}},
+		{"impossible premove", []string{"e4e5", "g1f3"}, []string{"e7e5"}, []string{""}},
+		{"promotion", []string{"e4e5q"}, []string{"g8f6"}, []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			game := newTestGame(t, "e2e4")
+
+			for _, uci := range test.premoves {
+				if err := game.Premove(ColorWhite, uci); err != nil {
+					t.Fatalf("Premove(%q): %v", uci, err)
+				}
+			}
+
+			for index, reply := range test.replies {
+				moveTestGame(t, game, reply)
+
+				move, ok, err := game.PlayQueuedMove()
+				if err != nil {
+					t.Fatalf("move #%d, PlayQueuedMove(): %v", index, err)
+				}
+
+				if !ok {
+					if test.played[index] != "" {
+						t.Fatalf("move #%d, PlayQueuedMove() expected %s but got nothing", index, test.played[index])
+					}
+
+					continue
+				}
+
+				if uci, err := move.UCI(); err != nil || uci != test.played[index] {
+					t.Fatalf("move #%d, PlayQueuedMove() expected %q but got %q, %v", index, test.played[index], uci, err)
+				}
+			}
+
+			if premoves := game.Premoves(ColorWhite); len(premoves) != 0 {
+				t.Fatalf("Premoves() expected no premoves but got %v", premoves)
+			}
+		})
+	}
+}
+
+func TestGamePremoveErrors(t *testing.T) {
+	t.Parallel()
+
+	game := newTestGame(t, "e2e4", "e7e5")
+
+	if err := game.Premove(ColorWhite, "g1f3"); err == nil ||
+		err.Error() != "checkQueueLocked(ColorWhite): color is to move" {
+		t.Fatalf("Premove(white) expected error but got %q", err)
+	}
+
+	if err := game.Premove(ColorNil, "g1f3"); err == nil ||
+		err.Error() != "checkQueueLocked(ColorNil): ColorNil.Opposite(): no opposite" {
+		t.Fatalf("Premove(nil) expected error but got %q", err)
+	}
+
+	if err := game.Premove(ColorBlack, "b8c6"); err != nil {
+		t.Fatalf("Premove(black): %v", err)
+	}
+
+	if err := game.Premove(ColorBlack, "g8f6"); err != nil {
+		t.Fatalf("Premove(black): %v", err)
+	}
+
+	if premoves := game.Premoves(ColorBlack); !reflect.DeepEqual(premoves, []string{"b8c6", "g8f6"}) {
+		t.Fatalf("Premoves() expected two premoves but got %v", premoves)
+	}
+
+	game.CancelPremoves(ColorBlack)
+
+	if err := game.Premove(ColorBlack, "b8c6"); err != nil {
+		t.Fatalf("Premove(black): %v", err)
+	}
+
+	// The move of black made by itself cancels its premoves.
+	moveTestGame(t, game, "g1f3")
+	moveTestGame(t, game, "g8f6")
+
+	if premoves := game.Premoves(ColorBlack); len(premoves) != 0 {
+		t.Fatalf("Premoves() expected no premoves after the move but got %v", premoves)
+	}
+}
+
+func TestGameConditionalMoves(t *testing.T) {
+	t.Parallel()
+
+	game, err := NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	for _, line := range [][]string{{"e4", "e5", "Nf3", "Nc6"}, {"d4", "d5"}, {"e4", "e5", "Bc4", "Bc5"}} {
+		if err := game.AddConditionalMoves(ColorBlack, line); err != nil {
+			t.Fatalf("AddConditionalMoves(%v): %v", line, err)
+		}
+	}
+
+	tests := []struct {
+		sans      []string
+		errString string
+	}{
+		{[]string{"e4", "c5"}, "line has the different reply than the added line"},
+		{[]string{"e4"}, "moves are not pairs of the opponent move and the reply"},
+		{[]string{"e4", "e4"}, `move #1, CalcMoveFromSAN("e4"): no possible move "e4"`},
+	}
+
+	for _, test := range tests {
+		err := game.AddConditionalMoves(ColorBlack, test.sans)
+		if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+			t.Fatalf("AddConditionalMoves(%v) expected error %q but got %q", test.sans, test.errString, err)
+		}
+	}
+
+	if err := game.AddConditionalMoves(ColorWhite, []string{"e4", "e5"}); err == nil {
+		t.Fatal("AddConditionalMoves(white) expected error but got nil")
+	}
+
+	for index, step := range []struct {
+		uci    string
+		played string
+		lines  int
+	}{
+		{"e2e4", "e7e5", 2},
+		{"g1f3", "b8c6", 0},
+		{"f1c4", "", 0},
+	} {
+		moveTestGame(t, game, step.uci)
+
+		move, ok, err := game.PlayQueuedMove()
+		if err != nil {
+			t.Fatalf("step #%d, PlayQueuedMove(): %v", index, err)
+		}
+
+		if ok != (step.played != "") {
+			t.Fatalf("step #%d, PlayQueuedMove() expected %q but got %t", index, step.played, ok)
+		}
+
+		if ok {
+			if uci, err := move.UCI(); err != nil || uci != step.played {
+				t.Fatalf("step #%d, PlayQueuedMove() expected %q but got %q, %v", index, step.played, uci, err)
+			}
+		}
+
+		if lines := game.ConditionalMoves(ColorBlack); len(lines) != step.lines {
+			t.Fatalf("step #%d, ConditionalMoves() expected %d lines but got %d", index, step.lines, len(lines))
+		}
+	}
+}
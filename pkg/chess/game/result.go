@@ -0,0 +1,206 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// Half move clock value, after which the game is drawn by the fifty moves rule.
+	resultFiftyMovesHalfMoveClock = 100
+
+	// Count of the same positions, after which the game is drawn by the repetition rule.
+	resultRepetitionsCount = 3
+)
+
+// Result represents the outcome of the game.
+type Result uint8
+
+const (
+	// ResultNil means that the game is in progress.
+	ResultNil Result = iota
+	ResultWhiteWon
+	ResultBlackWon
+	ResultDraw
+)
+
+// NewResultWon returns the result, where passed color won.
+func NewResultWon(color Color) (Result, error) {
+	switch color {
+	case ColorWhite:
+		return ResultWhiteWon, nil
+	case ColorBlack:
+		return ResultBlackWon, nil
+	case ColorNil:
+		return ResultNil, errors.New("no result")
+	default:
+		return ResultNil, errors.New("unknown color")
+	}
+}
+
+// NewResultFromPGN creates a new Result from passed PGN representation.
+//
+// PGN argument examples: "1-0", "0-1", "1/2-1/2", "*".
+func NewResultFromPGN(pgn string) (Result, error) {
+	switch pgn {
+	case "*":
+		return ResultNil, nil
+	case "1-0":
+		return ResultWhiteWon, nil
+	case "0-1":
+		return ResultBlackWon, nil
+	case "1/2-1/2":
+		return ResultDraw, nil
+	default:
+		return ResultNil, errors.New("unknown result")
+	}
+}
+
+// PGN returns PGN representation of current result.
+//
+// Return examples: "1-0", "0-1", "1/2-1/2", "*".
+func (result Result) PGN() (string, error) {
+	switch result {
+	case ResultNil:
+		return "*", nil
+	case ResultWhiteWon:
+		return "1-0", nil
+	case ResultBlackWon:
+		return "0-1", nil
+	case ResultDraw:
+		return "1/2-1/2", nil
+	default:
+		return "", errors.New("unknown result")
+	}
+}
+
+// String returns string representation of current result.
+func (result Result) String() string {
+	switch result {
+	case ResultNil:
+		return "ResultNil"
+	case ResultWhiteWon:
+		return "ResultWhiteWon"
+	case ResultBlackWon:
+		return "ResultBlackWon"
+	case ResultDraw:
+		return "ResultDraw"
+	default:
+		return fmt.Sprintf("<unknown Result=%d>", result)
+	}
+}
+
+// Termination represents the reason of the game end.
+type Termination uint8
+
+const (
+	// TerminationNil means that the game is in progress.
+	TerminationNil Termination = iota
+	TerminationCheckmate
+	TerminationStalemate
+	TerminationInsufficientMaterial
+	TerminationFiftyMoves
+	TerminationRepetition
+	// TerminationAdjudication means that the game was ended by the external decision, for example, by the arbiter.
+	TerminationAdjudication
+	// TerminationVariantEnd means that the game was ended by the variant rule, for example, by the third check.
+	TerminationVariantEnd
+	// TerminationTimeout means that the player has run out of time.
+	TerminationTimeout
+	// TerminationTimeoutVsInsufficientMaterial means that the player has run out of time, but the opponent has no
+	// material to checkmate, so the game is drawn.
+	TerminationTimeoutVsInsufficientMaterial
+	// TerminationResignation means that the player has resigned.
+	TerminationResignation
+	// TerminationAgreement means that the players have agreed to the draw.
+	TerminationAgreement
+)
+
+// String returns string representation of current termination.
+func (termination Termination) String() string {
+	switch termination {
+	case TerminationNil:
+		return "TerminationNil"
+	case TerminationCheckmate:
+		return "TerminationCheckmate"
+	case TerminationStalemate:
+		return "TerminationStalemate"
+	case TerminationInsufficientMaterial:
+		return "TerminationInsufficientMaterial"
+	case TerminationFiftyMoves:
+		return "TerminationFiftyMoves"
+	case TerminationRepetition:
+		return "TerminationRepetition"
+	case TerminationAdjudication:
+		return "TerminationAdjudication"
+	case TerminationVariantEnd:
+		return "TerminationVariantEnd"
+	case TerminationTimeout:
+		return "TerminationTimeout"
+	case TerminationTimeoutVsInsufficientMaterial:
+		return "TerminationTimeoutVsInsufficientMaterial"
+	case TerminationResignation:
+		return "TerminationResignation"
+	case TerminationAgreement:
+		return "TerminationAgreement"
+	default:
+		return fmt.Sprintf("<unknown Termination=%d>", termination)
+	}
+}
+
+// checkInsufficientMaterial checks that there is no enough material on the passed board to checkmate.
+//
+// TODO: kings with bishops of the same square color.
+func checkInsufficientMaterial(board *Board) bool {
+	for _, piece := range [...]Piece{
+		PieceWhiteQueen, PieceWhiteRook, PieceWhitePawn, PieceBlackQueen, PieceBlackRook, PieceBlackPawn,
+	} {
+		if board.bitboards[piece] != BitboardNil {
+			return false
+		}
+	}
+
+	var minorsCount int
+
+	for _, piece := range [...]Piece{PieceWhiteBishop, PieceWhiteKnight, PieceBlackBishop, PieceBlackKnight} {
+		minorsCount += len(board.bitboards[piece].GetSquares())
+	}
+
+	return minorsCount <= 1
+}
+
+// checkColorInsufficientMaterial checks that passed color has no enough material on the passed board to checkmate: only
+// the king or the king with one minor piece.
+func checkColorInsufficientMaterial(board *Board, color Color) (bool, error) {
+	var minorsCount int
+
+	for _, role := range [...]Role{RoleQueen, RoleRook, RolePawn, RoleBishop, RoleKnight} {
+		piece, err := NewPiece(color, role)
+		if err != nil {
+			return false, fmt.Errorf("NewPiece(%s, %s): %w", color, role, err)
+		}
+
+		count := len(board.bitboards[piece].GetSquares())
+
+		switch {
+		case count == 0:
+		case role == RoleBishop || role == RoleKnight:
+			minorsCount += count
+		default:
+			return false, nil
+		}
+	}
+
+	return minorsCount <= 1, nil
+}
+
+// checkOnlyKings checks that there are no pieces except kings on the passed board.
+func checkOnlyKings(board *Board) bool {
+	for piece, bitboard := range board.bitboards {
+		if piece != PieceWhiteKing && piece != PieceBlackKing && bitboard != BitboardNil {
+			return false
+		}
+	}
+
+	return true
+}
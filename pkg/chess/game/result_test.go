@@ -0,0 +1,97 @@
+package game
+
+import "testing"
+
+func TestNewResultFromPGN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pgn       string
+		result    Result
+		errString string
+	}{
+		{"*", ResultNil, ""},
+		{"1-0", ResultWhiteWon, ""},
+		{"0-1", ResultBlackWon, ""},
+		{"1/2-1/2", ResultDraw, ""},
+		{"1-1", ResultNil, "unknown result"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pgn, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := NewResultFromPGN(test.pgn)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewResultFromPGN(%q) expected error %q but got %q", test.pgn, test.errString, err)
+			}
+
+			if result != test.result {
+				t.Fatalf("NewResultFromPGN(%q) expected %s but got %s", test.pgn, test.result, result)
+			}
+		})
+	}
+}
+
+func TestResultPGN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		result    Result
+		pgn       string
+		errString string
+	}{
+		{ResultNil, "*", ""},
+		{ResultWhiteWon, "1-0", ""},
+		{ResultBlackWon, "0-1", ""},
+		{ResultDraw, "1/2-1/2", ""},
+		{Result(123), "", "unknown result"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.result.String(), func(t *testing.T) {
+			t.Parallel()
+
+			pgn, err := test.result.PGN()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("%s.PGN() expected error %q but got %q", test.result, test.errString, err)
+			}
+
+			if pgn != test.pgn {
+				t.Fatalf("%s.PGN() expected %q but got %q", test.result, test.pgn, pgn)
+			}
+		})
+	}
+}
+
+func TestCheckInsufficientMaterial(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fen          string
+		insufficient bool
+	}{
+		{"4k3/8/8/8/8/8/8/4K3", true},
+		{"4k3/8/8/8/8/8/8/4KB2", true},
+		{"4k3/8/8/8/8/8/8/4KN2", true},
+		{"4kn2/8/8/8/8/8/8/4KB2", false},
+		{"4k3/8/8/8/8/8/8/4KNN1", false},
+		{"4k3/8/8/8/8/8/4P3/4K3", false},
+		{"4k3/8/8/8/8/8/8/R3K3", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fen, func(t *testing.T) {
+			t.Parallel()
+
+			board, err := NewBoardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.fen, err)
+			}
+
+			if insufficient := checkInsufficientMaterial(board); insufficient != test.insufficient {
+				t.Fatalf("checkInsufficientMaterial(%q) expected %t but got %t", test.fen, test.insufficient, insufficient)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SAN of the king side and queen side castlings.
+	sanKingSideCastle  = "O-O"
+	sanQueenSideCastle = "O-O-O"
+
+	// Symbols, which are appended to the SAN of moves, which put the opponent in check and checkmate.
+	sanCheckSuffix     = "+"
+	sanCheckmateSuffix = "#"
+)
+
+// movesCalculator calculates all possible moves in passed position.
+type movesCalculator func(position *Position) ([]Move, error)
+
+// rawMover makes passed move in passed position.
+type rawMover func(position *Position, move Move) error
+
+var (
+	// Matches non-castling SAN moves without check and annotation suffixes. Groups are the role letter, origin file,
+	// origin rank, capture symbol, destination and promotion role letter. Promotions to the king are possible in
+	// some variants.
+	sanRegexp = regexp.MustCompile(`^([KQRBN])?([a-h])?([1-8])?(x)?([a-h][1-8])(?:=?([KQRBN]))?$`)
+
+	// Matches SAN drop moves without check and annotation suffixes. Groups are the role letter and destination.
+	sanDropRegexp = regexp.MustCompile(`^([QRBNP])?@([a-h][1-8])$`)
+
+	// Roles of the SAN role letters. Pawns have no letter, but the letter is allowed in drops.
+	sanRoles = map[string]Role{
+		"K": RoleKing,
+		"Q": RoleQueen,
+		"R": RoleRook,
+		"B": RoleBishop,
+		"N": RoleKnight,
+		"P": RolePawn,
+		"":  RolePawn,
+	}
+
+	// SAN role letters of the roles.
+	sanRoleLetters = map[Role]string{
+		RoleKing:   "K",
+		RoleQueen:  "Q",
+		RoleRook:   "R",
+		RoleBishop: "B",
+		RoleKnight: "N",
+		RolePawn:   "",
+	}
+)
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+//
+// Check, checkmate and annotation suffixes are ignored. Castlings written with zeros are accepted too.
+//
+// SAN argument examples: "e4", "Nbd7", "exd6", "R1a3", "e8=Q+", "O-O-O", "Qh4#", "Nf3!?", "N@f3".
+func (engine Engine) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return engine.calcMoveFromSAN(position, san, engine.CalcMoves)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+//
+// Return examples: "e4", "Nbd7", "exd6", "R1a3", "e8=Q+", "O-O-O", "Qh4#", "N@f3".
+//
+// TODO: test.
+func (engine Engine) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return engine.calcMoveSAN(position, move, engine.CalcMoves, (*Position).MoveRaw)
+}
+
+// calcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+// Possible moves are calculated by passed function, so the variants can use their own rules.
+func (engine Engine) calcMoveFromSAN(position *Position, san string, calcMoves movesCalculator) (Move, error) {
+	moves, err := calcMoves(position)
+	if err != nil {
+		return Move{}, fmt.Errorf("calcMoves(): %w", err)
+	}
+
+	trimmedSAN := strings.ReplaceAll(strings.TrimRight(san, "+#!?"), "0", "O")
+
+	if trimmedSAN == sanKingSideCastle || trimmedSAN == sanQueenSideCastle {
+		castleTag := MoveTagKingSideCastle
+		if trimmedSAN == sanQueenSideCastle {
+			castleTag = MoveTagQueenSideCastle
+		}
+
+		for _, move := range moves {
+			if move.tags.Contains(castleTag) {
+				return move, nil
+			}
+		}
+
+		return Move{}, fmt.Errorf("no possible move %q", san)
+	}
+
+	if dropMatches := sanDropRegexp.FindStringSubmatch(trimmedSAN); dropMatches != nil {
+		move, err := engine.findDropMoveFromSAN(moves, sanRoles[dropMatches[1]], dropMatches[2])
+		if err != nil {
+			return Move{}, fmt.Errorf("findDropMoveFromSAN(%q): %w", san, err)
+		}
+
+		return move, nil
+	}
+
+	matches := sanRegexp.FindStringSubmatch(trimmedSAN)
+	if matches == nil {
+		return Move{}, fmt.Errorf("invalid SAN %q", san)
+	}
+
+	role := sanRoles[matches[1]]
+
+	dest, err := NewSquareFromFEN(matches[5])
+	if err != nil {
+		return Move{}, fmt.Errorf("NewSquareFromFEN(%q): %w", matches[5], err)
+	}
+
+	promoRole := RoleNil
+	if matches[6] != "" {
+		promoRole = sanRoles[matches[6]]
+	}
+
+	var (
+		found      Move
+		foundCount int
+	)
+
+	for _, move := range moves {
+		if move.dest != dest || move.promoRole != promoRole {
+			continue
+		}
+
+		ok, err := engine.checkMoveMatchesSAN(position, move, role, matches[2], matches[3])
+		if err != nil {
+			return Move{}, fmt.Errorf("checkMoveMatchesSAN(%+v): %w", move, err)
+		}
+
+		if ok {
+			found = move
+			foundCount++
+		}
+	}
+
+	switch foundCount {
+	case 0:
+		return Move{}, fmt.Errorf("no possible move %q", san)
+	case 1:
+		return found, nil
+	default:
+		return Move{}, fmt.Errorf("ambiguous move %q", san)
+	}
+}
+
+// calcMoveSAN calculates standard algebraic notation of passed possible move in passed position. Possible moves are
+// calculated and the move is made by passed functions, so the variants can use their own rules.
+func (engine Engine) calcMoveSAN(
+	position *Position, move Move, calcMoves movesCalculator, moveRaw rawMover,
+) (string, error) {
+	moves, err := calcMoves(position)
+	if err != nil {
+		return "", fmt.Errorf("calcMoves(): %w", err)
+	}
+
+	san, err := engine.calcMoveSANWithoutSuffix(position, moves, move)
+	if err != nil {
+		return "", fmt.Errorf("calcMoveSANWithoutSuffix(%+v): %w", move, err)
+	}
+
+	if !move.tags.Contains(MoveTagCheck) {
+		return san, nil
+	}
+
+	newPosition, err := position.DeepCopy()
+	if err != nil {
+		return "", fmt.Errorf("DeepCopy(): %w", err)
+	}
+
+	if err := moveRaw(newPosition, move); err != nil {
+		return "", fmt.Errorf("moveRaw(%+v): %w", move, err)
+	}
+
+	opponentMoves, err := calcMoves(newPosition)
+	if err != nil {
+		return "", fmt.Errorf("calcMoves(): %w", err)
+	}
+
+	if len(opponentMoves) == 0 {
+		return san + sanCheckmateSuffix, nil
+	}
+
+	return san + sanCheckSuffix, nil
+}
+
+// calcMoveSANWithoutSuffix calculates standard algebraic notation of passed possible move in passed position without
+// check and checkmate suffixes. Passed moves are all possible moves in the position.
+func (engine Engine) calcMoveSANWithoutSuffix(position *Position, moves []Move, move Move) (string, error) {
+	if position == nil {
+		return "", errors.New("position is nil")
+	}
+
+	switch {
+	case move.tags.Contains(MoveTagKingSideCastle):
+		return sanKingSideCastle, nil
+	case move.tags.Contains(MoveTagQueenSideCastle):
+		return sanQueenSideCastle, nil
+	case move.dropPiece != PieceNil:
+		// Drop SAN is the same as UCI, for example, "N@f3" or "P@e4".
+		san, err := move.UCI()
+		if err != nil {
+			return "", fmt.Errorf("UCI(): %w", err)
+		}
+
+		return san, nil
+	}
+
+	role, err := engine.getOriginRole(position, move)
+	if err != nil {
+		return "", fmt.Errorf("getOriginRole(%+v): %w", move, err)
+	}
+
+	origin, err := move.origin.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.origin, err)
+	}
+
+	dest, err := move.dest.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.dest, err)
+	}
+
+	capture := move.tags.Contains(MoveTagCapture) || move.tags.Contains(MoveTagEnPassantCapture)
+
+	var builder strings.Builder
+
+	builder.WriteString(sanRoleLetters[role])
+
+	if role == RolePawn {
+		if capture {
+			builder.WriteString(origin[:1])
+		}
+	} else {
+		disambiguation, err := engine.calcSANDisambiguation(position, moves, move, role)
+		if err != nil {
+			return "", fmt.Errorf("calcSANDisambiguation(%+v, %s): %w", move, role, err)
+		}
+
+		builder.WriteString(disambiguation)
+	}
+
+	if capture {
+		builder.WriteString("x")
+	}
+
+	builder.WriteString(dest)
+
+	if move.promoRole != RoleNil {
+		builder.WriteString("=" + sanRoleLetters[move.promoRole])
+	}
+
+	return builder.String(), nil
+}
+
+// calcSANDisambiguation calculates the origin file, rank or square, which distinguish passed move of the piece with
+// passed role from passed possible moves of other same pieces to the same destination.
+//
+// Return examples: "", "b", "1", "d2".
+func (engine Engine) calcSANDisambiguation(position *Position, moves []Move, move Move, role Role) (string, error) {
+	origin, err := move.origin.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.origin, err)
+	}
+
+	var ambiguous, sameFile, sameRank bool
+
+	for _, other := range moves {
+		if other.dest != move.dest || other.origin == move.origin {
+			continue
+		}
+
+		otherRole, err := engine.getOriginRole(position, other)
+		if err != nil {
+			return "", fmt.Errorf("getOriginRole(%+v): %w", other, err)
+		}
+
+		if otherRole != role {
+			continue
+		}
+
+		otherOrigin, err := other.origin.FEN()
+		if err != nil {
+			return "", fmt.Errorf("%s.FEN(): %w", other.origin, err)
+		}
+
+		ambiguous = true
+		sameFile = sameFile || otherOrigin[0] == origin[0]
+		sameRank = sameRank || otherOrigin[1] == origin[1]
+	}
+
+	switch {
+	case !ambiguous:
+		return "", nil
+	case !sameFile:
+		return origin[:1], nil
+	case !sameRank:
+		return origin[1:], nil
+	default:
+		return origin, nil
+	}
+}
+
+// checkMoveMatchesSAN checks that the piece on the origin of passed move has passed role and the origin has passed
+// SAN file and rank. Empty file or rank matches any.
+func (engine Engine) checkMoveMatchesSAN(position *Position, move Move, role Role, file, rank string) (bool, error) {
+	originRole, err := engine.getOriginRole(position, move)
+	if err != nil {
+		return false, fmt.Errorf("getOriginRole(%+v): %w", move, err)
+	}
+
+	if originRole != role {
+		return false, nil
+	}
+
+	origin, err := move.origin.FEN()
+	if err != nil {
+		return false, fmt.Errorf("%s.FEN(): %w", move.origin, err)
+	}
+
+	return (file == "" || origin[:1] == file) && (rank == "" || origin[1:] == rank), nil
+}
+
+// findDropMoveFromSAN finds the drop move of the piece with passed role to passed SAN destination in passed possible
+// moves.
+func (engine Engine) findDropMoveFromSAN(moves []Move, role Role, destSAN string) (Move, error) {
+	dest, err := NewSquareFromFEN(destSAN)
+	if err != nil {
+		return Move{}, fmt.Errorf("NewSquareFromFEN(%q): %w", destSAN, err)
+	}
+
+	for _, move := range moves {
+		if move.dropPiece == PieceNil || move.dest != dest {
+			continue
+		}
+
+		dropRole, err := move.dropPiece.Role()
+		if err != nil {
+			return Move{}, fmt.Errorf("%s.Role(): %w", move.dropPiece, err)
+		}
+
+		if dropRole == role {
+			return move, nil
+		}
+	}
+
+	return Move{}, errors.New("no possible drop")
+}
+
+// getOriginRole returns the role of the piece on the origin of passed move.
+func (engine Engine) getOriginRole(position *Position, move Move) (Role, error) {
+	originPiece, err := position.board.GetPieceFromSquare(move.origin)
+	if err != nil {
+		return RoleNil, fmt.Errorf("GetPieceFromSquare(%s): %w", move.origin, err)
+	}
+
+	if originPiece == PieceNil {
+		return RoleNil, fmt.Errorf("no piece on the origin %s", move.origin)
+	}
+
+	role, err := originPiece.Role()
+	if err != nil {
+		return RoleNil, fmt.Errorf("%s.Role(): %w", originPiece, err)
+	}
+
+	return role, nil
+}
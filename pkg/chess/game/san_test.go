@@ -0,0 +1,54 @@
+package game
+
+import "testing"
+
+func TestEngineCalcMoveFromSAN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fen       string
+		san       string
+		uci       string
+		errString string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e4", "e2e4", ""},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Nf3!?", "g1f3", ""},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Ke2", "", `no possible move "Ke2"`},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Z9", "", `invalid SAN "Z9"`},
+		{"4k3/8/8/8/8/8/8/R3K2R w - - 0 1", "Rd1", "", `ambiguous move "Rd1"`},
+		{"4k3/8/8/8/8/8/8/R3K2R w - - 0 1", "Rhf1", "h1f1", ""},
+		{"4k3/8/8/8/8/8/8/R3K2R w - - 0 1", "Ra8+", "a1a8", ""},
+		{"4k3/3P4/8/8/8/8/8/4K3 w - - 0 1", "d8=Q+", "d7d8q", ""},
+		{"4k3/3P4/8/8/8/8/8/4K3 w - - 0 1", "d8N", "d7d8n", ""},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "exd6", "e5d6", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fen+" "+test.san, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			move, err := Engine{}.CalcMoveFromSAN(position, test.san)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("CalcMoveFromSAN(%q) expected error %q but got %q", test.san, test.errString, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			uci, err := move.UCI()
+			if err != nil {
+				t.Fatalf("%+v.UCI() expected no error but got %v", move, err)
+			}
+
+			if uci != test.uci {
+				t.Fatalf("CalcMoveFromSAN(%q) expected %q but got %q", test.san, test.uci, uci)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package game
+
+import "fmt"
+
+// Count of the checks, after which the checking color wins the Three-check game.
+const threeCheckChecksCount = 3
+
+// VariantThreeCheck represents the rules of the Three-check chess, where the player also wins by checking the
+// opponent three times.
+//
+// Note that the checks are counted using the moves of the game, so the check counters of the FEN are not supported.
+type VariantThreeCheck struct {
+	engine Engine
+}
+
+// NewVariantThreeCheck creates a new Three-check variant, which uses passed engine.
+func NewVariantThreeCheck(engine Engine) VariantThreeCheck {
+	return VariantThreeCheck{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+func (variant VariantThreeCheck) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.CalcMoveFromSAN(position, san)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+func (variant VariantThreeCheck) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.CalcMoveSAN(position, move)
+}
+
+// CalcMoves calculates all possible moves in passed position for active color pieces. The moves are the same as in
+// the standard chess.
+func (variant VariantThreeCheck) CalcMoves(position *Position) ([]Move, error) {
+	return variant.engine.CalcMoves(position)
+}
+
+// CalcResult calculates the result of the game with passed positions and moves and the reason of its termination.
+//
+// The game ends by the third check, checkmate, stalemate, fifty moves or repetition. The material is insufficient
+// only if there are kings alone, because any other piece can check.
+func (variant VariantThreeCheck) CalcResult(positions []*Position, moves []Move) (Result, Termination, error) {
+	if len(positions) != len(moves)+1 {
+		return ResultNil, TerminationNil, fmt.Errorf("expected %d positions but got %d", len(moves)+1, len(positions))
+	}
+
+	checksCounts := make(map[Color]int, 2) //nolint:mnd // Two colors.
+
+	for index, move := range moves {
+		if !move.tags.Contains(MoveTagCheck) {
+			continue
+		}
+
+		color := positions[index].activeColor
+		checksCounts[color]++
+
+		if checksCounts[color] < threeCheckChecksCount {
+			continue
+		}
+
+		result, err := NewResultWon(color)
+		if err != nil {
+			return ResultNil, TerminationNil, fmt.Errorf("NewResultWon(%s): %w", color, err)
+		}
+
+		return result, TerminationVariantEnd, nil
+	}
+
+	position := positions[len(positions)-1]
+
+	possibleMoves, err := variant.CalcMoves(position)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(possibleMoves) == 0 {
+		return calcNoMovesResult(variant.engine, position)
+	}
+
+	if checkOnlyKings(position.board) {
+		return ResultDraw, TerminationInsufficientMaterial, nil
+	}
+
+	return calcDrawResult(positions)
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantThreeCheck) Name() string {
+	return "Three-check"
+}
+
+// NewPositionStart creates the start position, which is the same as in the standard chess.
+func (variant VariantThreeCheck) NewPositionStart() (*Position, error) {
+	return NewPositionStart()
+}
+
+// checkColorInsufficientMaterial checks that passed color has no enough material to win. Only the bare king is
+// insufficient, because any other piece can check three times.
+func (variant VariantThreeCheck) checkColorInsufficientMaterial(position *Position, color Color) (bool, error) {
+	for _, role := range [...]Role{RoleQueen, RoleRook, RoleBishop, RoleKnight, RolePawn} {
+		piece, err := NewPiece(color, role)
+		if err != nil {
+			return false, fmt.Errorf("NewPiece(%s, %s): %w", color, role, err)
+		}
+
+		if position.board.bitboards[piece] != BitboardNil {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,229 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Variant represents the rules of the chess variant: the start position, the possible moves, the game end conditions
+// and the notation quirks.
+type Variant interface {
+	// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic
+	// notation.
+	CalcMoveFromSAN(position *Position, san string) (Move, error)
+
+	// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+	CalcMoveSAN(position *Position, move Move) (string, error)
+
+	// CalcMoves calculates all possible moves in passed position for active color pieces.
+	//
+	// The order of the moves is deterministic, so the index of the move in the list identifies it in the position.
+	CalcMoves(position *Position) ([]Move, error)
+
+	// CalcResult calculates the result of the game with passed positions and moves and the reason of its termination.
+	//
+	// Note that the positions contain one more element than the moves: the position before the first move. ResultNil
+	// and TerminationNil are returned if the game is in progress.
+	CalcResult(positions []*Position, moves []Move) (Result, Termination, error)
+
+	// Name returns the name of the variant as in the PGN Variant tag.
+	//
+	// Return examples: "Standard", "King of the Hill", "Three-check", "Antichess", "Horde".
+	Name() string
+
+	// NewPositionStart creates the start position of the variant.
+	NewPositionStart() (*Position, error)
+}
+
+// NewVariantFromName creates the variant with passed name as in the PGN Variant tag, which uses passed engine.
+//
+// Name argument examples: "Standard", "King of the Hill", "Crazyhouse".
+func NewVariantFromName(engine Engine, name string) (Variant, error) {
+	for _, variant := range [...]Variant{
+		NewVariantStandard(engine),
+		NewVariantKingOfTheHill(engine),
+		NewVariantThreeCheck(engine),
+		NewVariantAntichess(engine),
+		NewVariantHorde(engine),
+		NewVariantCrazyhouse(engine),
+		NewVariantAtomic(engine),
+	} {
+		if variant.Name() == name {
+			return variant, nil
+		}
+	}
+
+	return nil, errors.New("unknown variant")
+}
+
+// VariantStandard represents the rules of the standard chess.
+type VariantStandard struct {
+	engine Engine
+}
+
+// NewVariantStandard creates a new standard chess variant, which uses passed engine.
+func NewVariantStandard(engine Engine) VariantStandard {
+	return VariantStandard{engine: engine}
+}
+
+// CalcMoveFromSAN finds the possible move in passed position, which corresponds to passed standard algebraic notation.
+func (variant VariantStandard) CalcMoveFromSAN(position *Position, san string) (Move, error) {
+	return variant.engine.CalcMoveFromSAN(position, san)
+}
+
+// CalcMoveSAN calculates standard algebraic notation of passed possible move in passed position.
+func (variant VariantStandard) CalcMoveSAN(position *Position, move Move) (string, error) {
+	return variant.engine.CalcMoveSAN(position, move)
+}
+
+// CalcMoves calculates all possible moves in passed position for active color pieces.
+func (variant VariantStandard) CalcMoves(position *Position) ([]Move, error) {
+	return variant.engine.CalcMoves(position)
+}
+
+// CalcResult calculates the result of the game with passed positions and the reason of its termination.
+//
+// The game ends by checkmate, stalemate, insufficient material, fifty moves or repetition.
+func (variant VariantStandard) CalcResult(positions []*Position, _ []Move) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	moves, err := variant.CalcMoves(position)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		return calcNoMovesResult(variant.engine, position)
+	}
+
+	if checkInsufficientMaterial(position.board) {
+		return ResultDraw, TerminationInsufficientMaterial, nil
+	}
+
+	return calcDrawResult(positions)
+}
+
+// Name returns the name of the variant as in the PGN Variant tag.
+func (variant VariantStandard) Name() string {
+	return "Standard"
+}
+
+// NewPositionStart creates the start position of the standard chess.
+func (variant VariantStandard) NewPositionStart() (*Position, error) {
+	return NewPositionStart()
+}
+
+// calcDrawResult calculates the draw by the fifty moves or repetition rules with passed positions.
+//
+// ResultNil and TerminationNil are returned if there is no draw.
+func calcDrawResult(positions []*Position) (Result, Termination, error) {
+	if len(positions) == 0 {
+		return ResultNil, TerminationNil, errors.New("no positions")
+	}
+
+	position := positions[len(positions)-1]
+
+	if position.halfMoveClock >= resultFiftyMovesHalfMoveClock {
+		return ResultDraw, TerminationFiftyMoves, nil
+	}
+
+	var repetitionsCount int
+
+	for _, previousPosition := range positions {
+		if position.Repeats(previousPosition) {
+			repetitionsCount++
+		}
+	}
+
+	if repetitionsCount >= resultRepetitionsCount {
+		return ResultDraw, TerminationRepetition, nil
+	}
+
+	return ResultNil, TerminationNil, nil
+}
+
+// calcNoMovesResult calculates the result of passed position, where the active color has no possible moves: checkmate
+// if the active color king is in check and stalemate otherwise.
+func calcNoMovesResult(engine Engine, position *Position) (Result, Termination, error) {
+	checked, err := engine.CheckChecked(position, position.activeColor)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("CheckChecked(%s): %w", position.activeColor, err)
+	}
+
+	if !checked {
+		return ResultDraw, TerminationStalemate, nil
+	}
+
+	result, err := newResultLost(position.activeColor)
+	if err != nil {
+		return ResultNil, TerminationNil, fmt.Errorf("newResultLost(%s): %w", position.activeColor, err)
+	}
+
+	return result, TerminationCheckmate, nil
+}
+
+// checkColorHasKing checks that passed color has a king on the board of passed position.
+func checkColorHasKing(position *Position, color Color) (bool, error) {
+	king, err := NewPiece(color, RoleKing)
+	if err != nil {
+		return false, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
+	}
+
+	return position.board.bitboards[king] != BitboardNil, nil
+}
+
+// calcColorPiecesCounts calculates the counts of passed color pieces on passed board by roles except the king and
+// their total count.
+func calcColorPiecesCounts(board *Board, color Color) (map[Role]int, int, error) {
+	var (
+		roles      = [...]Role{RoleQueen, RoleRook, RoleBishop, RoleKnight, RolePawn}
+		counts     = make(map[Role]int, len(roles))
+		totalCount int
+	)
+
+	for _, role := range roles {
+		piece, err := NewPiece(color, role)
+		if err != nil {
+			return nil, 0, fmt.Errorf("NewPiece(%s, %s): %w", color, role, err)
+		}
+
+		counts[role] = len(board.bitboards[piece].GetSquares())
+		totalCount += counts[role]
+	}
+
+	return counts, totalCount, nil
+}
+
+// checkSquareDark checks that passed square is dark like a1.
+func checkSquareDark(square Square) (bool, error) {
+	file, err := square.File()
+	if err != nil {
+		return false, fmt.Errorf("File(): %w", err)
+	}
+
+	rank, err := square.Rank()
+	if err != nil {
+		return false, fmt.Errorf("Rank(): %w", err)
+	}
+
+	return (uint8(file)+uint8(rank))%2 == 0, nil //nolint:mnd // Squares alternate.
+}
+
+// newResultLost returns the result, where passed color lost.
+func newResultLost(color Color) (Result, error) {
+	winner, err := color.Opposite()
+	if err != nil {
+		return ResultNil, fmt.Errorf("%s.Opposite(): %w", color, err)
+	}
+
+	result, err := NewResultWon(winner)
+	if err != nil {
+		return ResultNil, fmt.Errorf("NewResultWon(%s): %w", winner, err)
+	}
+
+	return result, nil
+}
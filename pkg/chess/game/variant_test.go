@@ -0,0 +1,393 @@
+package game
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestVariantCalcResult(t *testing.T) {
+	t.Parallel()
+
+	var (
+		standard   = NewVariantStandard(Engine{})
+		hill       = NewVariantKingOfTheHill(Engine{})
+		threeCheck = NewVariantThreeCheck(Engine{})
+		antichess  = NewVariantAntichess(Engine{})
+		horde      = NewVariantHorde(Engine{})
+		atomic     = NewVariantAtomic(Engine{})
+	)
+
+	tests := []struct {
+		name        string
+		variant     Variant
+		fen         string
+		result      Result
+		termination Termination
+	}{
+		{"standard checkmate", standard, "R3k3/8/4K3/8/8/8/8/8 b - - 0 1", ResultWhiteWon, TerminationCheckmate},
+		{"standard kings", standard, "4k3/8/8/8/8/8/8/4K3 w - - 0 1", ResultDraw, TerminationInsufficientMaterial},
+		{"hill reached", hill, "4k3/8/8/8/3K4/8/8/8 b - - 0 1", ResultWhiteWon, TerminationVariantEnd},
+		{"hill kings", hill, "4k3/8/8/8/8/8/8/4K3 w - - 0 1", ResultNil, TerminationNil},
+		{"three-check kings", threeCheck, "4k3/8/8/8/8/8/8/4K3 w - - 0 1", ResultDraw, TerminationInsufficientMaterial},
+		{"three-check knight", threeCheck, "4k3/8/8/8/8/8/8/4KN2 w - - 0 1", ResultNil, TerminationNil},
+		{"antichess no pieces", antichess, "8/8/8/8/8/8/8/r7 w - - 0 1", ResultWhiteWon, TerminationVariantEnd},
+		{"antichess in progress", antichess, "8/8/8/8/8/8/8/R6r b - - 0 1", ResultNil, TerminationNil},
+		{"horde no pieces", horde, "4k3/8/8/8/8/8/8/8 w - - 0 1", ResultBlackWon, TerminationVariantEnd},
+		{"horde checkmate", horde, "k7/8/1Q6/8/8/8/8/R7 b - - 0 1", ResultWhiteWon, TerminationCheckmate},
+		{"atomic explosion", atomic, "8/8/8/8/8/8/8/4K3 b - - 0 1", ResultWhiteWon, TerminationVariantEnd},
+		{"atomic kings", atomic, "4k3/8/8/8/8/8/8/4K3 w - - 0 1", ResultDraw, TerminationInsufficientMaterial},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			result, termination, err := test.variant.CalcResult([]*Position{position}, nil)
+			if err != nil {
+				t.Fatalf("CalcResult() expected no error but got %v", err)
+			}
+
+			if result != test.result || termination != test.termination {
+				t.Fatalf("CalcResult() expected %s, %s but got %s, %s", test.result, test.termination, result, termination)
+			}
+		})
+	}
+}
+
+func TestVariantCalcMoves(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		variant Variant
+		fen     string
+		ucis    []string
+	}{
+		{"antichess capture", NewVariantAntichess(Engine{}), "8/8/8/8/8/8/8/R1r5 w - - 0 1", []string{"a1c1"}},
+		{"antichess king promo", NewVariantAntichess(Engine{}), "8/P7/8/8/8/8/8/7r w - - 0 1", []string{
+			"a7a8q", "a7a8k", "a7a8r", "a7a8b", "a7a8n",
+		}},
+		{"horde first rank", NewVariantHorde(Engine{}), "4k3/8/8/8/8/8/8/P7 w - - 0 1", []string{"a1a2", "a1a3"}},
+		{"atomic king capture", NewVariantAtomic(Engine{}), "4k3/8/8/8/8/8/4p3/4K3 w - - 0 1", []string{"e1d2", "e1f2"}},
+		{"atomic self explosion", NewVariantAtomic(Engine{}), "7k/8/8/8/8/8/8/R2nK3 w - - 0 1", []string{
+			"e1d2", "e1e2", "e1f1", "a1a2", "a1a3", "a1a4", "a1a5", "a1a6", "a1a7", "a1a8", "a1b1", "a1c1",
+		}},
+		{"horde blocked first rank", NewVariantHorde(Engine{}), "4k3/8/8/8/8/p7/8/P7 w - - 0 1", []string{"a1a2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			moves, err := test.variant.CalcMoves(position)
+			if err != nil {
+				t.Fatalf("CalcMoves() expected no error but got %v", err)
+			}
+
+			ucis := make([]string, 0, len(moves))
+
+			for _, move := range moves {
+				uci, err := move.UCI()
+				if err != nil {
+					t.Fatalf("UCI(%+v) expected no error but got %v", move, err)
+				}
+
+				ucis = append(ucis, uci)
+			}
+
+			slices.Sort(ucis)
+
+			expectedUCIs := slices.Sorted(slices.Values(test.ucis))
+			if !slices.Equal(ucis, expectedUCIs) {
+				t.Fatalf("CalcMoves() expected %v but got %v", expectedUCIs, ucis)
+			}
+		})
+	}
+}
+
+func TestVariantThreeCheckCalcResult(t *testing.T) {
+	t.Parallel()
+
+	fens := []string{
+		"4k3/8/8/8/8/8/8/R3K3 w - - 0 1",
+		"R3k3/8/8/8/8/8/8/4K3 b - - 1 1",
+		"R7/3k4/8/8/8/8/8/4K3 w - - 2 2",
+		"8/R2k4/8/8/8/8/8/4K3 b - - 3 2",
+		"8/R7/3k4/8/8/8/8/4K3 w - - 4 3",
+		"8/8/R2k4/8/8/8/8/4K3 b - - 5 3",
+	}
+
+	positions := make([]*Position, 0, len(fens))
+
+	for _, fen := range fens {
+		position, err := NewPositionFromFEN(fen)
+		if err != nil {
+			t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", fen, err)
+		}
+
+		positions = append(positions, position)
+	}
+
+	moves := []Move{
+		NewMove(SquareA1, SquareA8, MoveTags(MoveTagCheck), RoleNil),
+		NewMove(SquareE8, SquareD7, MoveTagsNil, RoleNil),
+		NewMove(SquareA8, SquareA7, MoveTags(MoveTagCheck), RoleNil),
+		NewMove(SquareD7, SquareD6, MoveTagsNil, RoleNil),
+		NewMove(SquareA7, SquareA6, MoveTags(MoveTagCheck), RoleNil),
+	}
+
+	tests := []struct {
+		name        string
+		movesCount  int
+		result      Result
+		termination Termination
+	}{
+		{"one check", 2, ResultNil, TerminationNil},
+		{"two checks", 4, ResultNil, TerminationNil},
+		{"three checks", 5, ResultWhiteWon, TerminationVariantEnd},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, termination, err := NewVariantThreeCheck(Engine{}).CalcResult(
+				positions[:test.movesCount+1], moves[:test.movesCount])
+			if err != nil {
+				t.Fatalf("CalcResult() expected no error but got %v", err)
+			}
+
+			if result != test.result || termination != test.termination {
+				t.Fatalf("CalcResult() expected %s, %s but got %s, %s", test.result, test.termination, result, termination)
+			}
+		})
+	}
+}
+
+func TestNewGameVariantStart(t *testing.T) {
+	t.Parallel()
+
+	variants := []Variant{
+		NewVariantStandard(Engine{}),
+		NewVariantKingOfTheHill(Engine{}),
+		NewVariantThreeCheck(Engine{}),
+		NewVariantAntichess(Engine{}),
+		NewVariantHorde(Engine{}),
+		NewVariantCrazyhouse(Engine{}),
+		NewVariantAtomic(Engine{}),
+	}
+
+	for _, variant := range variants {
+		t.Run(variant.Name(), func(t *testing.T) {
+			t.Parallel()
+
+			startPosition, err := variant.NewPositionStart()
+			if err != nil {
+				t.Fatalf("NewPositionStart() expected no error but got %v", err)
+			}
+
+			gotGame, err := NewGameVariantStart(variant)
+			if err != nil {
+				t.Fatalf("NewGameVariantStart() expected no error but got %v", err)
+			}
+
+			expectedGame := NewGame(variant, []*Position{startPosition}, nil)
+			if !reflect.DeepEqual(gotGame, expectedGame) {
+				t.Fatalf("NewGameVariantStart() expected %+v but got %+v", expectedGame, gotGame)
+			}
+
+			if gotGame.Variant() != variant {
+				t.Fatalf("Variant() expected %s but got %s", variant.Name(), gotGame.Variant().Name())
+			}
+		})
+	}
+}
+
+func TestVariantCrazyhouseCalcMoves(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		fen         string
+		dropsCount  int
+		san         string
+		uci         string
+		notDropUCIs []string
+	}{
+		{"pawn", "4k3/8/8/8/8/8/8/4K3[P] w - - 0 1", 48, "@e4", "P@e4", []string{"P@a1", "P@h8"}},
+		{"knight evasion", "4k3/8/8/8/8/8/8/r3K3[N] w - - 0 1", 3, "N@d1", "N@d1", []string{"N@f3", "N@e2"}},
+		{"empty pocket", "4k3/8/8/8/8/8/8/4K3[p] w - - 0 1", 0, "", "", []string{"P@e4"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			variant := NewVariantCrazyhouse(Engine{})
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			moves, err := variant.CalcMoves(position)
+			if err != nil {
+				t.Fatalf("CalcMoves() expected no error but got %v", err)
+			}
+
+			var dropUCIs []string
+
+			for _, move := range moves {
+				if !move.IsDrop() {
+					continue
+				}
+
+				uci, err := move.UCI()
+				if err != nil {
+					t.Fatalf("UCI(%+v) expected no error but got %v", move, err)
+				}
+
+				dropUCIs = append(dropUCIs, uci)
+			}
+
+			if len(dropUCIs) != test.dropsCount {
+				t.Fatalf("CalcMoves() expected %d drops but got %v", test.dropsCount, dropUCIs)
+			}
+
+			for _, uci := range test.notDropUCIs {
+				if slices.Contains(dropUCIs, uci) {
+					t.Fatalf("CalcMoves() expected no drop %q", uci)
+				}
+			}
+
+			if test.san == "" {
+				return
+			}
+
+			move, err := variant.CalcMoveFromSAN(position, test.san)
+			if err != nil {
+				t.Fatalf("CalcMoveFromSAN(%q) expected no error but got %v", test.san, err)
+			}
+
+			san, err := variant.CalcMoveSAN(position, move)
+			if err != nil {
+				t.Fatalf("CalcMoveSAN(%+v) expected no error but got %v", move, err)
+			}
+
+			if san != test.uci {
+				t.Fatalf("CalcMoveSAN(CalcMoveFromSAN(%q)) expected %q but got %q", test.san, test.uci, san)
+			}
+		})
+	}
+}
+
+func TestGameMoveAtomic(t *testing.T) {
+	t.Parallel()
+
+	position, err := NewPositionFromFEN("4k3/8/8/8/8/8/1n6/R1b1K3 w - - 0 1")
+	if err != nil {
+		t.Fatalf("NewPositionFromFEN() expected no error but got %v", err)
+	}
+
+	game := NewGame(NewVariantAtomic(Engine{}), []*Position{position}, nil)
+
+	move, err := game.Variant().CalcMoveFromSAN(position, "Rxc1")
+	if err != nil {
+		t.Fatalf("CalcMoveFromSAN(%q) expected no error but got %v", "Rxc1", err)
+	}
+
+	if err := game.Move(move); err != nil {
+		t.Fatalf("Move(%+v) expected no error but got %v", move, err)
+	}
+
+	expectedBoard, err := NewBoardFromFEN("4k3/8/8/8/8/8/8/4K3")
+	if err != nil {
+		t.Fatalf("NewBoardFromFEN() expected no error but got %v", err)
+	}
+
+	if !game.Position().Board().Equals(expectedBoard) {
+		t.Fatalf("Move(%+v) expected board %+v but got %+v", move, expectedBoard, game.Position().Board())
+	}
+}
+
+func TestVariantAtomicCalcMoveSAN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fen  string
+		move string
+		san  string
+	}{
+		{"check", "7k/8/8/8/8/8/8/R3K3 w - - 0 1", "Ra8", "Ra8+"},
+		{"explosion opens the mate", "R3b2k/3n2pp/1N6/8/8/8/8/1K6 w - - 0 1", "Nxd7", "Nxd7#"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			variant := NewVariantAtomic(Engine{})
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", test.fen, err)
+			}
+
+			move, err := variant.CalcMoveFromSAN(position, test.move)
+			if err != nil {
+				t.Fatalf("CalcMoveFromSAN(%q) expected no error but got %v", test.move, err)
+			}
+
+			san, err := variant.CalcMoveSAN(position, move)
+			if err != nil {
+				t.Fatalf("CalcMoveSAN(%+v) expected no error but got %v", move, err)
+			}
+
+			if san != test.san {
+				t.Fatalf("CalcMoveSAN(CalcMoveFromSAN(%q)) expected %q but got %q", test.move, test.san, san)
+			}
+		})
+	}
+}
+
+func TestNewVariantFromName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		variant   Variant
+		errString string
+	}{
+		{"Standard", NewVariantStandard(Engine{}), ""},
+		{"King of the Hill", NewVariantKingOfTheHill(Engine{}), ""},
+		{"Atomic", NewVariantAtomic(Engine{}), ""},
+		{"Chess", nil, "unknown variant"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			variant, err := NewVariantFromName(Engine{}, test.name)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewVariantFromName(%q) expected error %q but got %q", test.name, test.errString, err)
+			}
+
+			if variant != test.variant {
+				t.Fatalf("NewVariantFromName(%q) expected %v but got %v", test.name, test.variant, variant)
+			}
+		})
+	}
+}
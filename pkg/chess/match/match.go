@@ -0,0 +1,165 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+// GameReport contains the information about the single played game of the match.
+type GameReport struct {
+	Index       uint
+	Opening     string
+	White       string
+	Black       string
+	Result      game.Result
+	Termination game.Termination
+	Moves       []string
+}
+
+// Match plays the games between two players.
+type Match struct {
+	first    Player
+	second   Player
+	openings []string
+	limits   search.Limits
+	maxPlies int
+}
+
+// NewMatch creates a new Match with passed parameters.
+//
+// Openings are FENs of the start positions. Limits are passed to the players on each move. If the game is not
+// finished after passed count of plies, it is adjudicated as a draw. Zero max plies means no limit.
+func NewMatch(first, second Player, openings []string, limits search.Limits, maxPlies int) *Match {
+	return &Match{
+		first:    first,
+		second:   second,
+		openings: openings,
+		limits:   limits,
+		maxPlies: maxPlies,
+	}
+}
+
+// Run plays passed count of games and returns the stats from the point of view of the first player.
+//
+// Each opening is played twice with alternated colors. Passed callback, if not nil, is called after each game and
+// the match stops if it returns false, for example, if the SPRT verdict is reached.
+func (match *Match) Run(ctx context.Context, gamesCount uint, onGame func(GameReport, Stats) bool) (Stats, error) {
+	if len(match.openings) == 0 {
+		return Stats{}, errors.New("no openings")
+	}
+
+	var stats Stats
+
+	for index := range gamesCount {
+		opening := match.openings[int(index/2)%len(match.openings)] //nolint:mnd // Each opening is played twice.
+
+		firstIsWhite := index%2 == 0
+		white, black := match.first, match.second
+
+		if !firstIsWhite {
+			white, black = black, white
+		}
+
+		report, err := match.playGame(ctx, white, black, opening)
+		if err != nil {
+			return stats, fmt.Errorf("game #%d, playGame(%s, %s, %q): %w", index, white.Name(), black.Name(), opening, err)
+		}
+
+		report.Index = index
+
+		if err := stats.Add(report.Result, firstIsWhite); err != nil {
+			return stats, fmt.Errorf("game #%d, Add(%s, %t): %w", index, report.Result, firstIsWhite, err)
+		}
+
+		if onGame != nil && !onGame(report, stats) {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+// playGame plays single game between passed players from passed opening.
+//
+// The player, which made an impossible move, loses the game by adjudication.
+func (match *Match) playGame(ctx context.Context, white, black Player, opening string) (GameReport, error) {
+	report := GameReport{
+		Opening: opening,
+		White:   white.Name(),
+		Black:   black.Name(),
+	}
+
+	g, err := game.NewGameFromFEN(opening)
+	if err != nil {
+		return report, fmt.Errorf("NewGameFromFEN(%q): %w", opening, err)
+	}
+
+	for _, player := range [...]Player{white, black} {
+		if err := player.NewGame(ctx); err != nil {
+			return report, fmt.Errorf("%s.NewGame(): %w", player.Name(), err)
+		}
+	}
+
+	engine := game.Engine{}
+
+	for {
+		report.Result, report.Termination, err = g.Result()
+		if err != nil {
+			return report, fmt.Errorf("Result(): %w", err)
+		}
+
+		if report.Result != game.ResultNil {
+			return report, nil
+		}
+
+		if match.maxPlies > 0 && len(report.Moves) >= match.maxPlies {
+			report.Result, report.Termination = game.ResultDraw, game.TerminationAdjudication
+			return report, nil
+		}
+
+		position := g.Position()
+
+		player := white
+		if position.ActiveColor() == piece.ColorBlack {
+			player = black
+		}
+
+		uci, err := player.Move(ctx, Turn{
+			StartFEN: opening,
+			Moves:    report.Moves,
+			Position: position,
+			Limits:   match.limits,
+		})
+		if err != nil {
+			return report, fmt.Errorf("%s.Move(): %w", player.Name(), err)
+		}
+
+		move, err := engine.CalcMoveFromUCI(position, uci)
+		if err != nil {
+			winner, err := position.ActiveColor().Opposite()
+			if err != nil {
+				return report, fmt.Errorf("%s.Opposite(): %w", position.ActiveColor(), err)
+			}
+
+			report.Result, err = game.NewResultWon(winner)
+			if err != nil {
+				return report, fmt.Errorf("NewResultWon(%s): %w", winner, err)
+			}
+
+			report.Termination = game.TerminationAdjudication
+
+			return report, nil
+		}
+
+		if err := g.Move(move); err != nil {
+			return report, fmt.Errorf("Move(%+v): %w", move, err)
+		}
+
+		report.Moves = append(report.Moves, uci)
+	}
+}
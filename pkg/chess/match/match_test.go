@@ -0,0 +1,52 @@
+package match
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+func TestMatchRun(t *testing.T) {
+	t.Parallel()
+
+	first := NewSearcherPlayer("first", search.NewSearcher(game.Engine{}, 1))
+	second := NewSearcherPlayer("second", search.NewSearcher(game.Engine{}, 1))
+
+	openings := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1",
+	}
+
+	var reports []GameReport
+
+	stats, err := NewMatch(first, second, openings, search.Limits{Depth: 1}, 2).Run(
+		context.Background(),
+		4,
+		func(report GameReport, _ Stats) bool {
+			reports = append(reports, report)
+			return true
+		},
+	)
+	if err != nil {
+		t.Fatalf("Run(): %v", err)
+	}
+
+	// The start position games are adjudicated, the back rank mate is found by the white player.
+	if expected := (Stats{Wins: 1, Draws: 2, Losses: 1}); stats != expected {
+		t.Fatalf("Run() expected %+v but got %+v", expected, stats)
+	}
+
+	expectedWhites := []string{"first", "second", "first", "second"}
+
+	for index, report := range reports {
+		if report.White != expectedWhites[index] {
+			t.Fatalf("game #%d expected white %q but got %q", index, expectedWhites[index], report.White)
+		}
+	}
+
+	if reports[2].Termination != game.TerminationCheckmate || reports[2].Moves[0] != "a1a8" {
+		t.Fatalf("game #2 expected checkmate by a1a8 but got %+v", reports[2])
+	}
+}
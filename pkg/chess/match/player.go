@@ -0,0 +1,76 @@
+package match
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+// Turn contains the information about the position in which the player should move.
+type Turn struct {
+	// FEN of the position before the first move.
+	StartFEN string
+	// Moves made since the start position in UCI long algebraic notation.
+	Moves []string
+	// The current position.
+	Position *position.Position
+	Limits   search.Limits
+}
+
+// Player is the participant of the match.
+type Player interface {
+	// Name returns the player name for the reports.
+	Name() string
+	// NewGame prepares the player to the new game.
+	NewGame(ctx context.Context) error
+	// Move returns the player move in UCI long algebraic notation.
+	Move(ctx context.Context, turn Turn) (string, error)
+	// Close releases all player resources.
+	Close() error
+}
+
+// SearcherPlayer is the player, which uses the in-process searcher.
+type SearcherPlayer struct {
+	name     string
+	searcher *search.Searcher
+}
+
+// NewSearcherPlayer creates a new SearcherPlayer with passed parameters.
+func NewSearcherPlayer(name string, searcher *search.Searcher) *SearcherPlayer {
+	return &SearcherPlayer{
+		name:     name,
+		searcher: searcher,
+	}
+}
+
+// Name returns the player name for the reports.
+func (player *SearcherPlayer) Name() string {
+	return player.name
+}
+
+// NewGame prepares the player to the new game.
+func (player *SearcherPlayer) NewGame(context.Context) error {
+	return nil
+}
+
+// Move returns the player move in UCI long algebraic notation.
+func (player *SearcherPlayer) Move(ctx context.Context, turn Turn) (string, error) {
+	move, err := player.searcher.Search(ctx, turn.Position, turn.Limits, nil)
+	if err != nil {
+		return "", fmt.Errorf("Search(%+v): %w", turn.Limits, err)
+	}
+
+	uci, err := move.UCI()
+	if err != nil {
+		return "", fmt.Errorf("UCI(%+v): %w", move, err)
+	}
+
+	return uci, nil
+}
+
+// Close releases all player resources.
+func (player *SearcherPlayer) Close() error {
+	return nil
+}
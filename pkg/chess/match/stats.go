@@ -0,0 +1,187 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+const (
+	// Quantile of the standard normal distribution for the 95% confidence interval.
+	statsConfidenceQuantile = 1.959964
+
+	// Scale of the logistic Elo model.
+	statsEloScale = 400
+)
+
+// CalcSPRTBounds calculates the log-likelihood ratio bounds of the sequential probability ratio test: the lower bound
+// to accept the null hypothesis and the upper bound to accept the alternative hypothesis.
+func CalcSPRTBounds(alpha, beta float64) (float64, float64) {
+	return math.Log(beta / (1 - alpha)), math.Log((1 - beta) / alpha)
+}
+
+// Verdict represents the decision of the sequential probability ratio test.
+type Verdict uint8
+
+const (
+	// VerdictNil means that more games are needed to decide.
+	VerdictNil Verdict = iota
+	// VerdictFail means that the null hypothesis is accepted: the first player is not stronger by elo1.
+	VerdictFail
+	// VerdictPass means that the alternative hypothesis is accepted: the first player is stronger by elo1.
+	VerdictPass
+)
+
+// String returns string representation of current verdict.
+func (verdict Verdict) String() string {
+	switch verdict {
+	case VerdictNil:
+		return "VerdictNil"
+	case VerdictFail:
+		return "VerdictFail"
+	case VerdictPass:
+		return "VerdictPass"
+	default:
+		return fmt.Sprintf("<unknown Verdict=%d>", verdict)
+	}
+}
+
+// Stats contains the match results from the point of view of the first player.
+//
+// Zero value is ready to use.
+type Stats struct {
+	Wins   uint
+	Draws  uint
+	Losses uint
+}
+
+// Add adds passed game result to the stats.
+func (stats *Stats) Add(result game.Result, firstIsWhite bool) error {
+	switch result {
+	case game.ResultWhiteWon:
+		if firstIsWhite {
+			stats.Wins++
+		} else {
+			stats.Losses++
+		}
+	case game.ResultBlackWon:
+		if firstIsWhite {
+			stats.Losses++
+		} else {
+			stats.Wins++
+		}
+	case game.ResultDraw:
+		stats.Draws++
+	case game.ResultNil:
+		return errors.New("game is in progress")
+	default:
+		return fmt.Errorf("unknown result %s", result)
+	}
+
+	return nil
+}
+
+// CalcElo calculates the Elo difference between the first and the second players and the margin of the 95%
+// confidence interval.
+//
+// The margin may be infinite if the interval does not fit into the logistic model.
+func (stats Stats) CalcElo() (float64, float64, error) {
+	score, variance, err := stats.calcScoreAndVariance()
+	if err != nil {
+		return 0, 0, fmt.Errorf("calcScoreAndVariance(): %w", err)
+	}
+
+	if score == 0 || score == 1 {
+		return 0, 0, fmt.Errorf("no finite Elo for the score %.2f", score)
+	}
+
+	deviation := statsConfidenceQuantile * math.Sqrt(variance/float64(stats.Games()))
+
+	lower := calcEloFromScore(score - deviation)
+	upper := calcEloFromScore(score + deviation)
+
+	return calcEloFromScore(score), (upper - lower) / 2, nil //nolint:mnd // Half of the interval.
+}
+
+// CalcSPRT calculates the log-likelihood ratio of the sequential probability ratio test and the verdict, where the
+// null hypothesis is that the Elo difference is elo0 and the alternative hypothesis is that it is elo1.
+//
+// Alpha and beta are the probabilities of the false positive and the false negative verdicts.
+//
+// See https://www.chessprogramming.org/Sequential_Probability_Ratio_Test for details.
+func (stats Stats) CalcSPRT(elo0, elo1, alpha, beta float64) (float64, Verdict, error) {
+	if alpha <= 0 || alpha >= 1 || beta <= 0 || beta >= 1 {
+		return 0, VerdictNil, errors.New("alpha and beta must be in (0, 1)")
+	}
+
+	if elo0 >= elo1 {
+		return 0, VerdictNil, errors.New("elo0 must be less than elo1")
+	}
+
+	score, variance, err := stats.calcScoreAndVariance()
+	if err != nil {
+		return 0, VerdictNil, fmt.Errorf("calcScoreAndVariance(): %w", err)
+	}
+
+	if variance == 0 {
+		return 0, VerdictNil, nil
+	}
+
+	score0 := calcScoreFromElo(elo0)
+	score1 := calcScoreFromElo(elo1)
+
+	llr := float64(stats.Games()) * (score1 - score0) * (2*score - score0 - score1) / (2 * variance) //nolint:mnd
+
+	lower, upper := CalcSPRTBounds(alpha, beta)
+
+	switch {
+	case llr >= upper:
+		return llr, VerdictPass, nil
+	case llr <= lower:
+		return llr, VerdictFail, nil
+	default:
+		return llr, VerdictNil, nil
+	}
+}
+
+// Games returns the count of played games.
+func (stats Stats) Games() uint {
+	return stats.Wins + stats.Draws + stats.Losses
+}
+
+// calcScoreAndVariance calculates the mean score of the first player per game and the variance of the game score.
+func (stats Stats) calcScoreAndVariance() (float64, float64, error) {
+	games := float64(stats.Games())
+	if games == 0 {
+		return 0, 0, errors.New("no games")
+	}
+
+	wins := float64(stats.Wins) / games
+	draws := float64(stats.Draws) / games
+	losses := float64(stats.Losses) / games
+
+	score := wins + draws/2 //nolint:mnd // Draw is a half of the point.
+	variance := wins*math.Pow(1-score, 2) + draws*math.Pow(0.5-score, 2) + losses*math.Pow(score, 2)
+
+	return score, variance, nil
+}
+
+// calcEloFromScore converts the expected score to the Elo difference using the logistic model.
+func calcEloFromScore(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	}
+
+	if score >= 1 {
+		return math.Inf(1)
+	}
+
+	return -statsEloScale * math.Log10(1/score-1)
+}
+
+// calcScoreFromElo converts the Elo difference to the expected score using the logistic model.
+func calcScoreFromElo(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/statsEloScale)) //nolint:mnd // Logistic function.
+}
@@ -0,0 +1,104 @@
+package match
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestStatsAdd(t *testing.T) {
+	t.Parallel()
+
+	var stats Stats
+
+	for _, result := range []struct {
+		result       game.Result
+		firstIsWhite bool
+	}{
+		{game.ResultWhiteWon, true},
+		{game.ResultWhiteWon, false},
+		{game.ResultBlackWon, false},
+		{game.ResultDraw, true},
+		{game.ResultDraw, false},
+	} {
+		if err := stats.Add(result.result, result.firstIsWhite); err != nil {
+			t.Fatalf("Add(%s, %t): %v", result.result, result.firstIsWhite, err)
+		}
+	}
+
+	if expected := (Stats{Wins: 2, Draws: 2, Losses: 1}); stats != expected {
+		t.Fatalf("Add() expected %+v but got %+v", expected, stats)
+	}
+
+	if err := stats.Add(game.ResultNil, true); err == nil || err.Error() != "game is in progress" {
+		t.Fatalf("Add(%s) expected error %q but got %q", game.ResultNil, "game is in progress", err)
+	}
+}
+
+func TestStatsCalcElo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		stats     Stats
+		elo       float64
+		margin    float64
+		errString string
+	}{
+		{"equal", Stats{Wins: 400, Draws: 200, Losses: 400}, 0, 19.28, ""},
+		{"stronger", Stats{Wins: 450, Draws: 200, Losses: 350}, 34.86, 19.35, ""},
+		{"weaker", Stats{Wins: 350, Draws: 200, Losses: 450}, -34.86, 19.35, ""},
+		{"only wins", Stats{Wins: 10}, 0, 0, "no finite Elo for the score 1.00"},
+		{"no games", Stats{}, 0, 0, "calcScoreAndVariance(): no games"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			elo, margin, err := test.stats.CalcElo()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("CalcElo(%+v) expected error %q but got %q", test.stats, test.errString, err)
+			}
+
+			if math.Abs(elo-test.elo) > 0.01 || math.Abs(margin-test.margin) > 0.01 {
+				t.Fatalf("CalcElo(%+v) expected %.2f +- %.2f but got %.2f +- %.2f",
+					test.stats, test.elo, test.margin, elo, margin)
+			}
+		})
+	}
+}
+
+func TestStatsCalcSPRT(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		stats     Stats
+		llr       float64
+		verdict   Verdict
+		errString string
+	}{
+		{"inconclusive", Stats{Wins: 450, Draws: 200, Losses: 350}, 1.69, VerdictNil, ""},
+		{"pass", Stats{Wins: 4500, Draws: 2000, Losses: 3500}, 16.91, VerdictPass, ""},
+		{"fail", Stats{Wins: 3500, Draws: 2000, Losses: 4500}, -19.53, VerdictFail, ""},
+		{"only draws", Stats{Draws: 3}, 0, VerdictNil, ""},
+		{"no games", Stats{}, 0, VerdictNil, "calcScoreAndVariance(): no games"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			llr, verdict, err := test.stats.CalcSPRT(0, 5, 0.05, 0.05)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("CalcSPRT(%+v) expected error %q but got %q", test.stats, test.errString, err)
+			}
+
+			if math.Abs(llr-test.llr) > 0.01 || verdict != test.verdict {
+				t.Fatalf("CalcSPRT(%+v) expected %.2f %s but got %.2f %s", test.stats, test.llr, test.verdict, llr, verdict)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package match
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+// UCIPlayer is the player, which is the external engine binary speaking UCI protocol over stdin and stdout.
+type UCIPlayer struct {
+	name string
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+
+	// Serializes the command-response exchanges with the engine.
+	mutex sync.Mutex
+}
+
+// NewUCIPlayer starts passed engine binary and does the UCI handshake.
+//
+// The player name is the engine name from "id name" response or the path if the engine did not send its name.
+func NewUCIPlayer(ctx context.Context, path string, args ...string) (*UCIPlayer, error) {
+	cmd := exec.CommandContext(ctx, path, args...) //nolint:gosec // Engine binary is passed by the match organizer.
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdinPipe(): %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdoutPipe(): %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Start(): %w", err)
+	}
+
+	player := &UCIPlayer{
+		name:   path,
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewScanner(stdout),
+	}
+
+	if err := player.handshake(); err != nil {
+		return nil, errors.Join(fmt.Errorf("handshake(): %w", err), player.Close())
+	}
+
+	return player, nil
+}
+
+// Name returns the player name for the reports.
+func (player *UCIPlayer) Name() string {
+	return player.name
+}
+
+// NewGame prepares the player to the new game.
+func (player *UCIPlayer) NewGame(context.Context) error {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if err := player.writeLine("ucinewgame"); err != nil {
+		return fmt.Errorf("writeLine(ucinewgame): %w", err)
+	}
+
+	if err := player.writeLine("isready"); err != nil {
+		return fmt.Errorf("writeLine(isready): %w", err)
+	}
+
+	if _, err := player.readUntil("readyok"); err != nil {
+		return fmt.Errorf("readUntil(readyok): %w", err)
+	}
+
+	return nil
+}
+
+// Move returns the player move in UCI long algebraic notation.
+//
+// Note that the passed context is not used: the engine process is killed when the context passed to NewUCIPlayer is
+// done.
+func (player *UCIPlayer) Move(_ context.Context, turn Turn) (string, error) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	positionLine := "position fen " + turn.StartFEN
+	if len(turn.Moves) > 0 {
+		positionLine += " moves " + strings.Join(turn.Moves, " ")
+	}
+
+	if err := player.writeLine(positionLine); err != nil {
+		return "", fmt.Errorf("writeLine(%q): %w", positionLine, err)
+	}
+
+	goLine := "go " + formatGoArgs(turn.Limits)
+
+	if err := player.writeLine(goLine); err != nil {
+		return "", fmt.Errorf("writeLine(%q): %w", goLine, err)
+	}
+
+	bestMoveLine, err := player.readUntil("bestmove")
+	if err != nil {
+		return "", fmt.Errorf("readUntil(bestmove): %w", err)
+	}
+
+	fields := strings.Fields(bestMoveLine)
+	if len(fields) < 2 { //nolint:mnd // Keyword and the move.
+		return "", fmt.Errorf("invalid best move line %q", bestMoveLine)
+	}
+
+	return fields[1], nil
+}
+
+// Close asks the engine to quit and waits for the process exit.
+func (player *UCIPlayer) Close() error {
+	// Write error is ignored, because the engine may be already dead.
+	_ = player.writeLine("quit")
+
+	if err := player.stdin.Close(); err != nil {
+		return fmt.Errorf("stdin.Close(): %w", err)
+	}
+
+	if err := player.cmd.Wait(); err != nil {
+		return fmt.Errorf("Wait(): %w", err)
+	}
+
+	return nil
+}
+
+// handshake switches the engine to UCI mode and waits until it is ready.
+func (player *UCIPlayer) handshake() error {
+	if err := player.writeLine("uci"); err != nil {
+		return fmt.Errorf("writeLine(uci): %w", err)
+	}
+
+	for {
+		line, err := player.readLine()
+		if err != nil {
+			return fmt.Errorf("readLine(): %w", err)
+		}
+
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			player.name = name
+		}
+
+		if line == "uciok" {
+			break
+		}
+	}
+
+	if err := player.writeLine("isready"); err != nil {
+		return fmt.Errorf("writeLine(isready): %w", err)
+	}
+
+	if _, err := player.readUntil("readyok"); err != nil {
+		return fmt.Errorf("readUntil(readyok): %w", err)
+	}
+
+	return nil
+}
+
+// readLine reads the next engine output line.
+func (player *UCIPlayer) readLine() (string, error) {
+	if !player.stdout.Scan() {
+		if err := player.stdout.Err(); err != nil {
+			return "", fmt.Errorf("Scan(): %w", err)
+		}
+
+		return "", io.ErrUnexpectedEOF
+	}
+
+	return strings.TrimSpace(player.stdout.Text()), nil
+}
+
+// readUntil skips the engine output until the line starting with passed command and returns this line.
+func (player *UCIPlayer) readUntil(command string) (string, error) {
+	for {
+		line, err := player.readLine()
+		if err != nil {
+			return "", fmt.Errorf("readLine(): %w", err)
+		}
+
+		if line == command || strings.HasPrefix(line, command+" ") {
+			return line, nil
+		}
+	}
+}
+
+// writeLine sends passed line to the engine.
+func (player *UCIPlayer) writeLine(line string) error {
+	if _, err := io.WriteString(player.stdin, line+"\n"); err != nil {
+		return fmt.Errorf("WriteString(): %w", err)
+	}
+
+	return nil
+}
+
+// formatGoArgs formats passed search limits to the "go" command arguments.
+//
+// Returns example: "depth 5 movetime 1000".
+func formatGoArgs(limits search.Limits) string {
+	var args []string
+
+	if limits.Infinite {
+		args = append(args, "infinite")
+	}
+
+	if limits.Depth > 0 {
+		args = append(args, fmt.Sprintf("depth %d", limits.Depth))
+	}
+
+	if limits.Nodes > 0 {
+		args = append(args, fmt.Sprintf("nodes %d", limits.Nodes))
+	}
+
+	if limits.MoveTime > 0 {
+		args = append(args, fmt.Sprintf("movetime %d", limits.MoveTime.Milliseconds()))
+	}
+
+	if limits.WhiteTime != 0 || limits.BlackTime != 0 {
+		args = append(args, fmt.Sprintf(
+			"wtime %d btime %d winc %d binc %d",
+			limits.WhiteTime.Milliseconds(),
+			limits.BlackTime.Milliseconds(),
+			limits.WhiteInc.Milliseconds(),
+			limits.BlackInc.Milliseconds(),
+		))
+	}
+
+	if limits.MovesToGo > 0 {
+		args = append(args, fmt.Sprintf("movestogo %d", limits.MovesToGo))
+	}
+
+	return strings.Join(args, " ")
+}
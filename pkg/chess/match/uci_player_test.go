@@ -0,0 +1,38 @@
+package match
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+func TestFormatGoArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		limits search.Limits
+		args   string
+	}{
+		{"empty", search.Limits{}, ""},
+		{"depth", search.Limits{Depth: 5}, "depth 5"},
+		{"nodes and move time", search.Limits{Nodes: 1000, MoveTime: time.Second}, "nodes 1000 movetime 1000"},
+		{
+			"clock",
+			search.Limits{WhiteTime: time.Minute, BlackTime: 30 * time.Second, WhiteInc: time.Second, MovesToGo: 20},
+			"wtime 60000 btime 30000 winc 1000 binc 0 movestogo 20",
+		},
+		{"infinite", search.Limits{Infinite: true}, "infinite"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if args := formatGoArgs(test.limits); args != test.args {
+				t.Fatalf("formatGoArgs(%+v) expected %q but got %q", test.limits, test.args, args)
+			}
+		})
+	}
+}
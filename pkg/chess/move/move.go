@@ -1,5 +1,10 @@
 package move
 
+import (
+	"fmt"
+	"strings"
+)
+
 var (
 
 	// Contains bitboards of all possible antidiagonal destinations from passed origin.
@@ -442,11 +447,14 @@ var (
 )
 
 // Move represents single chess move.
+//
+// Drop moves, for example, in Crazyhouse, have no origin and place the piece from the pocket to the destination.
 type Move struct {
 	origin    Square
 	dest      Square
 	tags      MoveTags
 	promoRole Role
+	dropPiece Piece
 }
 
 // NewMove creates a new Move with passed parameters.
@@ -459,6 +467,17 @@ func NewMove(origin, dest Square, tags MoveTags, promoRole Role) Move {
 	}
 }
 
+// NewMoveDrop creates a new Move, which drops passed piece from the pocket to passed destination.
+func NewMoveDrop(dropPiece Piece, dest Square, tags MoveTags) Move {
+	return Move{
+		origin:    SquareNil,
+		dest:      dest,
+		tags:      tags,
+		promoRole: RoleNil,
+		dropPiece: dropPiece,
+	}
+}
+
 // NewMovesPromo creates new equal moves but with all different promotions.
 //
 // TODO: test.
@@ -472,6 +491,88 @@ func NewMovesPromo(origin, dest Square, tags MoveTags) []Move {
 	return moves
 }
 
+// Dest returns destination square of the current move.
+func (move Move) Dest() Square {
+	return move.dest
+}
+
+// DropPiece returns the piece dropped by the current move or PieceNil if the move is not a drop.
+func (move Move) DropPiece() Piece {
+	return move.dropPiece
+}
+
+// IsDrop checks that the current move drops a piece from the pocket.
+func (move Move) IsDrop() bool {
+	return move.dropPiece != PieceNil
+}
+
+// Origin returns origin square of the current move or SquareNil if the move is a drop.
+func (move Move) Origin() Square {
+	return move.origin
+}
+
+// PromoRole returns promotion role of the current move or RoleNil if there is no promotion.
+func (move Move) PromoRole() Role {
+	return move.promoRole
+}
+
+// Tags returns tags of the current move.
+func (move Move) Tags() MoveTags {
+	return move.tags
+}
+
+// UCI returns move representation in UCI long algebraic notation.
+//
+// Return examples: "e2e4", "e1g1", "e7e8q", "N@f3".
+func (move Move) UCI() (string, error) {
+	if move.dropPiece != PieceNil {
+		return move.dropUCI()
+	}
+
+	origin, err := move.origin.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.origin, err)
+	}
+
+	dest, err := move.dest.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.dest, err)
+	}
+
+	if move.promoRole == RoleNil {
+		return origin + dest, nil
+	}
+
+	promoRole, err := move.promoRole.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.promoRole, err)
+	}
+
+	return origin + dest + promoRole, nil
+}
+
+// dropUCI returns drop move representation in UCI notation with the upper case role letter.
+//
+// Return examples: "N@f3", "P@e4".
+func (move Move) dropUCI() (string, error) {
+	role, err := move.dropPiece.Role()
+	if err != nil {
+		return "", fmt.Errorf("%s.Role(): %w", move.dropPiece, err)
+	}
+
+	roleFEN, err := role.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", role, err)
+	}
+
+	dest, err := move.dest.FEN()
+	if err != nil {
+		return "", fmt.Errorf("%s.FEN(): %w", move.dest, err)
+	}
+
+	return strings.ToUpper(roleFEN) + "@" + dest, nil
+}
+
 // MoveTag represents cached useful notes about move.
 //
 // TODO move MoveTag and MoveTags to separate files.
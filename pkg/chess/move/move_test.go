@@ -0,0 +1,43 @@
+package move
+
+import "testing"
+
+func TestMoveUCI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		move      Move
+		uci       string
+		errString string
+	}{
+		{"pawn", NewMove(SquareE2, SquareE4, MoveTagsNil, RoleNil), "e2e4", ""},
+		{"castle", NewMove(SquareE1, SquareG1, MoveTags(MoveTagKingSideCastle), RoleNil), "e1g1", ""},
+		{"promo", NewMove(SquareE7, SquareE8, MoveTagsNil, RoleQueen), "e7e8q", ""},
+		{"promo capture", NewMove(SquareB2, SquareA1, MoveTags(MoveTagCapture), RoleKnight), "b2a1n", ""},
+		{"drop", NewMoveDrop(PieceWhiteKnight, SquareF3, MoveTagsNil), "N@f3", ""},
+		{"pawn drop", NewMoveDrop(PieceBlackPawn, SquareE4, MoveTags(MoveTagCheck)), "P@e4", ""},
+		{"no origin", NewMove(SquareNil, SquareE4, MoveTagsNil, RoleNil), "", "SquareNil.FEN(): Rank(): unknown square"},
+		{
+			"invalid promo",
+			NewMove(SquareE7, SquareE8, MoveTagsNil, Role(123)),
+			"",
+			"<unknown Role=123>.FEN(): unknown role",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			uci, err := test.move.UCI()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("UCI(%+v) expected error %q but got %q", test.move, test.errString, err)
+			}
+
+			if uci != test.uci {
+				t.Fatalf("UCI(%+v) expected %q but got %q", test.move, test.uci, uci)
+			}
+		})
+	}
+}
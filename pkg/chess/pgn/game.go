@@ -0,0 +1,127 @@
+package pgn
+
+import (
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Tag represents single PGN tag pair.
+//
+// Tag example: [White "Kasparov, Garry"].
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Game represents single game of the PGN file.
+type Game struct {
+	// Tags in the order of their appearance.
+	Tags []Tag
+	// Moves of the main line in standard algebraic notation without move numbers, comments and variations.
+	Moves []string
+	// Result from the game termination marker.
+	Result game.Result
+}
+
+// GetTag returns the value of the first tag with passed name.
+//
+// Returns false if there is no such tag.
+func (pgnGame *Game) GetTag(name string) (string, bool) {
+	for _, tag := range pgnGame.Tags {
+		if tag.Name == name {
+			return tag.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// NewGame creates a new game from the start position or the position from "FEN" tag and makes all moves in it.
+//
+// TODO: test.
+func (pgnGame *Game) NewGame() (*game.Game, error) {
+	g, err := pgnGame.NewGameStart()
+	if err != nil {
+		return nil, fmt.Errorf("NewGameStart(): %w", err)
+	}
+
+	engine := game.Engine{}
+
+	for index, san := range pgnGame.Moves {
+		move, err := engine.CalcMoveFromSAN(g.Position(), san)
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, CalcMoveFromSAN(%q): %w", index, san, err)
+		}
+
+		if err := g.Move(move); err != nil {
+			return nil, fmt.Errorf("move #%d, Move(%+v): %w", index, move, err)
+		}
+	}
+
+	return g, nil
+}
+
+// NewVariantGame creates a new game of the variant from "Variant" tag, which uses passed engine, from the start
+// position of the variant or the position from "FEN" tag and makes all moves in it.
+//
+// The game without "Variant" tag is the standard one.
+func (pgnGame *Game) NewVariantGame(engine game.Engine) (*game.Game, error) {
+	name, ok := pgnGame.GetTag("Variant")
+	if !ok {
+		name = game.NewVariantStandard(engine).Name()
+	}
+
+	variant, err := game.NewVariantFromName(engine, name)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantFromName(%q): %w", name, err)
+	}
+
+	g, err := game.NewGameVariantStart(variant)
+	if err != nil {
+		return nil, fmt.Errorf("NewGameVariantStart(%s): %w", name, err)
+	}
+
+	if fen, ok := pgnGame.GetTag("FEN"); ok {
+		pos, err := position.NewPositionFromFEN(fen)
+		if err != nil {
+			return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+		}
+
+		g = game.NewGame(variant, []*position.Position{pos}, nil)
+	}
+
+	for index, san := range pgnGame.Moves {
+		move, err := variant.CalcMoveFromSAN(g.Position(), san)
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, CalcMoveFromSAN(%q): %w", index, san, err)
+		}
+
+		if err := g.Move(move); err != nil {
+			return nil, fmt.Errorf("move #%d, Move(%+v): %w", index, move, err)
+		}
+	}
+
+	return g, nil
+}
+
+// NewGameStart creates a new game from the start position or the position from "FEN" tag without moves.
+func (pgnGame *Game) NewGameStart() (*game.Game, error) {
+	fen, ok := pgnGame.GetTag("FEN")
+	if !ok {
+		g, err := game.NewGameStart()
+		if err != nil {
+			return nil, fmt.Errorf("NewGameStart(): %w", err)
+		}
+
+		return g, nil
+	}
+
+	g, err := game.NewGameFromFEN(fen)
+	if err != nil {
+		return nil, fmt.Errorf("NewGameFromFEN(%q): %w", fen, err)
+	}
+
+	return g, nil
+}
@@ -0,0 +1,238 @@
+package pgn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+// Symbols, which terminate the move text symbol.
+const readerSymbolTerminators = "[]{}();$"
+
+// Reader reads games from the PGN file one by one.
+//
+// Comments, numeric annotation glyphs, recursive variations and move numbers are skipped.
+type Reader struct {
+	reader *bufio.Reader
+}
+
+// NewReader creates a new Reader, which reads the games from passed reader.
+func NewReader(reader io.Reader) *Reader {
+	return &Reader{reader: bufio.NewReader(reader)}
+}
+
+// Read reads the next game.
+//
+// Returns io.EOF if there are no more games. The game without the termination marker at the end of the file is
+// returned with ResultNil.
+func (reader *Reader) Read() (*Game, error) {
+	var (
+		pgnGame Game
+		started bool
+	)
+
+	for {
+		if err := reader.skipSpacesAndComments(); err != nil {
+			if errors.Is(err, io.EOF) && started {
+				return &pgnGame, nil
+			}
+
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+
+			return nil, fmt.Errorf("skipSpacesAndComments(): %w", err)
+		}
+
+		next, err := reader.reader.Peek(1)
+		if err != nil {
+			return nil, fmt.Errorf("Peek(1): %w", err)
+		}
+
+		switch next[0] {
+		case '[':
+			// The tag after the moves starts the next game, which has no termination marker.
+			if len(pgnGame.Moves) > 0 {
+				return &pgnGame, nil
+			}
+
+			tag, err := reader.readTag()
+			if err != nil {
+				return nil, fmt.Errorf("readTag(): %w", err)
+			}
+
+			pgnGame.Tags = append(pgnGame.Tags, tag)
+		case ']', ')', '}':
+			return nil, fmt.Errorf("unexpected %q", next[0])
+		default:
+			symbol, err := reader.readSymbol()
+			if err != nil {
+				return nil, fmt.Errorf("readSymbol(): %w", err)
+			}
+
+			if result, err := game.NewResultFromPGN(symbol); err == nil {
+				pgnGame.Result = result
+				return &pgnGame, nil
+			}
+
+			if san := trimMoveNumber(symbol); san != "" {
+				pgnGame.Moves = append(pgnGame.Moves, san)
+			}
+		}
+
+		started = true
+	}
+}
+
+// readSymbol reads the move text symbol, for example, the move with or without move number.
+func (reader *Reader) readSymbol() (string, error) {
+	var builder strings.Builder
+
+	for {
+		r, _, err := reader.reader.ReadRune()
+		if errors.Is(err, io.EOF) {
+			return builder.String(), nil
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("ReadRune(): %w", err)
+		}
+
+		if unicode.IsSpace(r) || strings.ContainsRune(readerSymbolTerminators, r) {
+			if err := reader.reader.UnreadRune(); err != nil {
+				return "", fmt.Errorf("UnreadRune(): %w", err)
+			}
+
+			return builder.String(), nil
+		}
+
+		builder.WriteRune(r)
+	}
+}
+
+// readTag reads the tag pair.
+//
+// Tag example: [White "Kasparov, Garry"].
+func (reader *Reader) readTag() (Tag, error) {
+	if _, err := reader.reader.ReadString('['); err != nil {
+		return Tag{}, fmt.Errorf("ReadString([): %w", err)
+	}
+
+	name, err := reader.reader.ReadString('"')
+	if err != nil {
+		return Tag{}, fmt.Errorf("ReadString(\"): %w", err)
+	}
+
+	var (
+		value   strings.Builder
+		escaped bool
+	)
+
+	for {
+		r, _, err := reader.reader.ReadRune()
+		if err != nil {
+			return Tag{}, fmt.Errorf("ReadRune(): %w", err)
+		}
+
+		if r == '"' && !escaped {
+			break
+		}
+
+		escaped = r == '\\' && !escaped
+		if !escaped {
+			value.WriteRune(r)
+		}
+	}
+
+	if _, err := reader.reader.ReadString(']'); err != nil {
+		return Tag{}, fmt.Errorf("ReadString(]): %w", err)
+	}
+
+	return Tag{
+		Name:  strings.TrimSpace(strings.TrimSuffix(name, `"`)),
+		Value: value.String(),
+	}, nil
+}
+
+// skipSpacesAndComments skips spaces, comments, numeric annotation glyphs, recursive variations and escaped lines.
+func (reader *Reader) skipSpacesAndComments() error {
+	for {
+		r, _, err := reader.reader.ReadRune()
+		if err != nil {
+			return fmt.Errorf("ReadRune(): %w", err)
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r == '{':
+			if _, err := reader.reader.ReadString('}'); err != nil {
+				return fmt.Errorf("ReadString(}): %w", err)
+			}
+		case r == ';' || r == '%':
+			if _, err := reader.reader.ReadString('\n'); err != nil {
+				return fmt.Errorf("ReadString(\\n): %w", err)
+			}
+		case r == '$':
+			if _, err := reader.readSymbol(); err != nil {
+				return fmt.Errorf("readSymbol(): %w", err)
+			}
+		case r == '(':
+			if err := reader.skipVariation(); err != nil {
+				return fmt.Errorf("skipVariation(): %w", err)
+			}
+		default:
+			if err := reader.reader.UnreadRune(); err != nil {
+				return fmt.Errorf("UnreadRune(): %w", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// skipVariation skips the recursive variation with all nested variations after the opening parenthesis.
+func (reader *Reader) skipVariation() error {
+	depth := 1
+
+	for depth > 0 {
+		r, _, err := reader.reader.ReadRune()
+		if err != nil {
+			return fmt.Errorf("ReadRune(): %w", err)
+		}
+
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '{':
+			if _, err := reader.reader.ReadString('}'); err != nil {
+				return fmt.Errorf("ReadString(}): %w", err)
+			}
+		case ';':
+			if _, err := reader.reader.ReadString('\n'); err != nil {
+				return fmt.Errorf("ReadString(\\n): %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// trimMoveNumber removes the move number indication from passed symbol.
+//
+// Return examples: "e4" for "1.e4", "" for "12...", "Nf3" for "Nf3".
+func trimMoveNumber(symbol string) string {
+	trimmed := strings.TrimLeft(symbol, "0123456789")
+	if trimmed == symbol || !strings.HasPrefix(trimmed, ".") {
+		return symbol
+	}
+
+	return strings.TrimLeft(trimmed, ".")
+}
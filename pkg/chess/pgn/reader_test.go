@@ -0,0 +1,93 @@
+package pgn
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestReaderRead(t *testing.T) {
+	t.Parallel()
+
+	const text = `[Event "Casual \"blitz\""]
+[White "Kasparov, Garry"]
+[WhiteElo "2851"]
+
+1. e4 e5 {Open game} 2. Nf3 (2. f4 exf4 (2... d5) 3. Nf3) 2... Nc6 $1 3.Bb5 a6!? ; Ruy Lopez
+4. O-O 1-0
+
+% Escaped line.
+[Event "Second"]
+
+1. d4 d5 *
+
+1. c4`
+
+	tests := []struct {
+		tags   []Tag
+		moves  []string
+		result game.Result
+	}{
+		{
+			[]Tag{{"Event", `Casual "blitz"`}, {"White", "Kasparov, Garry"}, {"WhiteElo", "2851"}},
+			[]string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6!?", "O-O"},
+			game.ResultWhiteWon,
+		},
+		{[]Tag{{"Event", "Second"}}, []string{"d4", "d5"}, game.ResultNil},
+		{nil, []string{"c4"}, game.ResultNil},
+	}
+
+	reader := NewReader(strings.NewReader(text))
+
+	for index, test := range tests {
+		pgnGame, err := reader.Read()
+		if err != nil {
+			t.Fatalf("game #%d, Read() expected no error but got %v", index, err)
+		}
+
+		if !slices.Equal(pgnGame.Tags, test.tags) {
+			t.Fatalf("game #%d, Read() expected tags %v but got %v", index, test.tags, pgnGame.Tags)
+		}
+
+		if !slices.Equal(pgnGame.Moves, test.moves) {
+			t.Fatalf("game #%d, Read() expected moves %v but got %v", index, test.moves, pgnGame.Moves)
+		}
+
+		if pgnGame.Result != test.result {
+			t.Fatalf("game #%d, Read() expected result %s but got %s", index, test.result, pgnGame.Result)
+		}
+	}
+
+	if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Read() expected %v but got %v", io.EOF, err)
+	}
+}
+
+func TestTrimMoveNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		symbol  string
+		trimmed string
+	}{
+		{"1.e4", "e4"},
+		{"12...", ""},
+		{"12.", ""},
+		{"Nf3", "Nf3"},
+		{"0-0", "0-0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.symbol, func(t *testing.T) {
+			t.Parallel()
+
+			if trimmed := trimMoveNumber(test.symbol); trimmed != test.trimmed {
+				t.Fatalf("trimMoveNumber(%q) expected %q but got %q", test.symbol, test.trimmed, trimmed)
+			}
+		})
+	}
+}
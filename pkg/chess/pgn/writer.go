@@ -0,0 +1,195 @@
+package pgn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Maximum length of the move text line.
+const writerLineLength = 80
+
+// Writer writes games to the PGN file one by one.
+type Writer struct {
+	writer io.Writer
+}
+
+// NewWriter creates a new Writer, which writes the games to passed writer.
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{writer: writer}
+}
+
+// Write writes the tags, the move text with move numbers and the termination marker of passed game followed by the
+// empty line.
+//
+// The move numbers start from the position of "FEN" tag if it exists.
+func (writer *Writer) Write(pgnGame *Game) error {
+	result, err := pgnGame.Result.PGN()
+	if err != nil {
+		return fmt.Errorf("%s.PGN(): %w", pgnGame.Result, err)
+	}
+
+	number, color := uint16(1), piece.ColorWhite
+
+	if fen, ok := pgnGame.GetTag("FEN"); ok {
+		pos, err := position.NewPositionFromFEN(fen)
+		if err != nil {
+			return fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+		}
+
+		number, color = pos.FullMoveNumber(), pos.ActiveColor()
+	}
+
+	var builder strings.Builder
+
+	for _, tag := range pgnGame.Tags {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(tag.Value)
+		fmt.Fprintf(&builder, "[%s \"%s\"]\n", tag.Name, value)
+	}
+
+	if len(pgnGame.Tags) > 0 {
+		builder.WriteByte('\n')
+	}
+
+	symbols := make([]string, 0, len(pgnGame.Moves)+1)
+
+	for index, san := range pgnGame.Moves {
+		switch {
+		case color == piece.ColorWhite:
+			symbols = append(symbols, strconv.Itoa(int(number))+".")
+		case index == 0:
+			symbols = append(symbols, strconv.Itoa(int(number))+"...")
+		}
+
+		symbols = append(symbols, san)
+
+		if color == piece.ColorBlack {
+			number, color = number+1, piece.ColorWhite
+		} else {
+			color = piece.ColorBlack
+		}
+	}
+
+	symbols = append(symbols, result)
+
+	writeMoveText(&builder, symbols)
+
+	if _, err := io.WriteString(writer.writer, builder.String()); err != nil {
+		return fmt.Errorf("WriteString(): %w", err)
+	}
+
+	return nil
+}
+
+// NewGameFromGame creates the PGN game from passed game with passed tags and the moves in standard algebraic notation.
+//
+// "Variant", "SetUp" and "FEN" tags are added if the game is not the standard one from the start position. "Result"
+// tag is set to the result of the game.
+func NewGameFromGame(g *game.Game, tags []Tag) (*Game, error) {
+	positions, moves := g.Positions(), g.Moves()
+	if len(positions) == 0 {
+		return nil, errors.New("no positions")
+	}
+
+	result, _, err := g.Result()
+	if err != nil {
+		return nil, fmt.Errorf("Result(): %w", err)
+	}
+
+	resultPGN, err := result.PGN()
+	if err != nil {
+		return nil, fmt.Errorf("%s.PGN(): %w", result, err)
+	}
+
+	pgnGame := &Game{Moves: make([]string, 0, len(moves)), Result: result}
+
+	for _, tag := range tags {
+		switch tag.Name {
+		case "Result", "Variant", "SetUp", "FEN":
+		default:
+			pgnGame.Tags = append(pgnGame.Tags, tag)
+		}
+	}
+
+	pgnGame.Tags = append(pgnGame.Tags, Tag{"Result", resultPGN})
+
+	variant := g.Variant()
+	if variant.Name() != game.NewVariantStandard(game.Engine{}).Name() {
+		pgnGame.Tags = append(pgnGame.Tags, Tag{"Variant", variant.Name()})
+	}
+
+	fen, err := calcStartFEN(variant, positions[0])
+	if err != nil {
+		return nil, fmt.Errorf("calcStartFEN(): %w", err)
+	}
+
+	if fen != "" {
+		pgnGame.Tags = append(pgnGame.Tags, Tag{"SetUp", "1"}, Tag{"FEN", fen})
+	}
+
+	for index, m := range moves {
+		san, err := variant.CalcMoveSAN(positions[index], m)
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, CalcMoveSAN(%+v): %w", index, m, err)
+		}
+
+		pgnGame.Moves = append(pgnGame.Moves, san)
+	}
+
+	return pgnGame, nil
+}
+
+// calcStartFEN returns FEN of passed start position or the empty string if it is the start position of the variant.
+func calcStartFEN(variant game.Variant, pos *position.Position) (string, error) {
+	fen, err := pos.FEN()
+	if err != nil {
+		return "", fmt.Errorf("FEN(): %w", err)
+	}
+
+	variantPos, err := variant.NewPositionStart()
+	if err != nil {
+		return "", fmt.Errorf("NewPositionStart(): %w", err)
+	}
+
+	variantFEN, err := variantPos.FEN()
+	if err != nil {
+		return "", fmt.Errorf("variant FEN(): %w", err)
+	}
+
+	if fen == variantFEN {
+		return "", nil
+	}
+
+	return fen, nil
+}
+
+// writeMoveText writes passed move text symbols separated by spaces and lines of limited length.
+func writeMoveText(builder *strings.Builder, symbols []string) {
+	var lineLength int
+
+	for _, symbol := range symbols {
+		switch {
+		case lineLength == 0:
+		case lineLength+1+len(symbol) > writerLineLength:
+			builder.WriteByte('\n')
+
+			lineLength = 0
+		default:
+			builder.WriteByte(' ')
+
+			lineLength++
+		}
+
+		builder.WriteString(symbol)
+
+		lineLength += len(symbol)
+	}
+
+	builder.WriteString("\n\n")
+}
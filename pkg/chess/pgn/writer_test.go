@@ -0,0 +1,91 @@
+package pgn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestWriterWrite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pgnGame   *Game
+		text      string
+		errString string
+	}{
+		{
+			&Game{
+				Tags:   []Tag{{"Event", `Casual "blitz"`}, {"White", `Back\slash`}},
+				Moves:  []string{"e4", "e5", "Nf3"},
+				Result: game.ResultWhiteWon,
+			},
+			"[Event \"Casual \\\"blitz\\\"\"]\n[White \"Back\\\\slash\"]\n\n1. e4 e5 2. Nf3 1-0\n\n",
+			"",
+		},
+		{
+			&Game{
+				Tags:  []Tag{{"FEN", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 7"}},
+				Moves: []string{"e5", "Nf3", "Nc6"},
+			},
+			"[FEN \"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 7\"]\n\n7... e5 8. Nf3 Nc6 *\n\n",
+			"",
+		},
+		{&Game{}, "*\n\n", ""},
+		{&Game{Result: game.Result(100)}, "", "<unknown Result=100>.PGN(): unknown result"},
+		{
+			&Game{Tags: []Tag{{"FEN", "bad"}}},
+			"",
+			`NewPositionFromFEN("bad"): FEN parts required 6 but got 1`,
+		},
+	}
+
+	for index, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			t.Parallel()
+
+			var builder strings.Builder
+
+			err := NewWriter(&builder).Write(test.pgnGame)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("test #%d, Write() expected error %q but got %q", index, test.errString, err)
+			}
+
+			if builder.String() != test.text {
+				t.Fatalf("test #%d, Write() expected %q but got %q", index, test.text, builder.String())
+			}
+		})
+	}
+}
+
+func TestWriterWriteLongMoveText(t *testing.T) {
+	t.Parallel()
+
+	pgnGame := &Game{Moves: make([]string, 0, 120)}
+
+	for range 30 {
+		pgnGame.Moves = append(pgnGame.Moves, "Nf3", "Nf6", "Ng1", "Ng8")
+	}
+
+	var builder strings.Builder
+
+	if err := NewWriter(&builder).Write(pgnGame); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+
+	for _, line := range strings.Split(builder.String(), "\n") {
+		if len(line) > writerLineLength {
+			t.Fatalf("Write() expected lines of at most %d symbols but got %q", writerLineLength, line)
+		}
+	}
+
+	read, err := NewReader(strings.NewReader(builder.String())).Read()
+	if err != nil {
+		t.Fatalf("Read(): %v", err)
+	}
+
+	if strings.Join(read.Moves, " ") != strings.Join(pgnGame.Moves, " ") {
+		t.Fatalf("Read() expected moves %v but got %v", pgnGame.Moves, read.Moves)
+	}
+}
@@ -1,6 +1,9 @@
 package chess
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Role represents Piece role.
 type Role uint8
@@ -24,6 +27,30 @@ func (role Role) CanBeInRank(rank Rank) bool {
 	return role != RolePawn || (rank != Rank1 && rank != Rank8)
 }
 
+// FEN returns lower case FEN representation of current role.
+//
+// Return examples: "k", "q", "r", "b", "n", "p".
+func (role Role) FEN() (string, error) {
+	switch role {
+	case RoleKing:
+		return "k", nil
+	case RoleQueen:
+		return "q", nil
+	case RoleRook:
+		return "r", nil
+	case RoleBishop:
+		return "b", nil
+	case RoleKnight:
+		return "n", nil
+	case RolePawn:
+		return "p", nil
+	case RoleNil:
+		return "", errors.New("no FEN")
+	default:
+		return "", errors.New("unknown role")
+	}
+}
+
 // String returns string representation of current role.
 func (role Role) String() string {
 	switch role {
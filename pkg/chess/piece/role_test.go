@@ -32,3 +32,37 @@ func TestCanBeInRank(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleFEN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		role      Role
+		fen       string
+		errString string
+	}{
+		{RoleKing, "k", ""},
+		{RoleQueen, "q", ""},
+		{RoleRook, "r", ""},
+		{RoleBishop, "b", ""},
+		{RoleKnight, "n", ""},
+		{RolePawn, "p", ""},
+		{RoleNil, "", "no FEN"},
+		{Role(123), "", "unknown role"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.role.String(), func(t *testing.T) {
+			t.Parallel()
+
+			fen, err := test.role.FEN()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("%s.FEN() expected error %q but got %q", test.role, test.errString, err)
+			}
+
+			if fen != test.fen {
+				t.Fatalf("%s.FEN() expected %q but got %q", test.role, test.fen, fen)
+			}
+		})
+	}
+}
@@ -1,35 +1,251 @@
 package position
 
 import (
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 )
 
+var errCastlingRightNotFile = errors.New("castling right is not a file letter")
+
 // CastlingRights is a slice of color sides available for castling.
 type CastlingRights []ColorSide
 
-// NewRightsFromFEN parses FEN to Rights structure.
+// CastlingRookFiles contains the start files of the castling rooks by color sides. Color sides without a file have the
+// rook on the standard file: H for the king side and A for the queen side.
+type CastlingRookFiles map[ColorSide]File
+
+// NewCastlingRightsFromFEN parses standard FEN to CastlingRights structure. Use NewRightsFromFEN to parse Chess960
+// castling rights.
 //
 // FEN argument example: "kqKQ".
-func NewRightsFromFEN(fen string) (Rights, error) {
+func NewCastlingRightsFromFEN(fen string) (CastlingRights, error) {
+	rights, _, err := NewRightsFromFEN(fen, nil)
+
+	return rights, err
+}
+
+// NewRightsFromFEN parses FEN to CastlingRights structure using passed board to find the castling rooks.
+//
+// Standard FEN, Shredder-FEN and X-FEN are supported: letters "K", "Q", "k" and "q" denote the outermost rook on the
+// side of the king and file letters denote the rook on the file. Rook files are returned only if they differ from the
+// standard ones. Board may be nil if the FEN has no file letters.
+//
+// FEN argument examples: "kqKQ", "HAha", "Kb".
+func NewRightsFromFEN(fen string, board *Board) (CastlingRights, CastlingRookFiles, error) {
 	if fen == "-" {
-		return Rights(nil), nil
+		return CastlingRights(nil), nil, nil
 	}
 
-	rights := make([]ColorSide, 0, len(fen))
+	var (
+		rights = make([]ColorSide, 0, len(fen))
+		files  CastlingRookFiles
+	)
 
 	for _, bytee := range []byte(fen) {
-		colorSide, err := NewColorSideFromFEN(string(bytee))
-		if err != nil {
-			return nil, fmt.Errorf("NewColorSideFromFEN(%q): %w", bytee, err)
+		colorSide, file, err := newCastlingRightFromFileFEN(bytee, board)
+		if errors.Is(err, errCastlingRightNotFile) {
+			colorSide, err = NewColorSideFromFEN(string(bytee))
+			if err != nil {
+				return nil, nil, fmt.Errorf("NewColorSideFromFEN(%q): %w", bytee, err)
+			}
+
+			file, err = findCastlingRookFile(board, colorSide)
+			if err != nil {
+				return nil, nil, fmt.Errorf("findCastlingRookFile(%s): %w", colorSide, err)
+			}
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("newCastlingRightFromFileFEN(%q): %w", bytee, err)
 		}
 
 		if slices.Contains(rights, colorSide) {
-			return nil, fmt.Errorf("duplicate of %q found", bytee)
+			return nil, nil, fmt.Errorf("duplicate of %q found", bytee)
 		}
 
 		rights = append(rights, colorSide)
+
+		if file != colorSide.RookStandardFile() {
+			if files == nil {
+				files = make(CastlingRookFiles)
+			}
+
+			files[colorSide] = file
+		}
+	}
+
+	return CastlingRights(rights), files, nil
+}
+
+// FEN returns FEN representation of the current castling rights. Rooks on the standard files are denoted by "K", "Q",
+// "k" and "q" and other rooks are denoted by their file letters from passed files as in Shredder-FEN.
+//
+// Return examples: "KQkq", "-", "HAha", "Kb".
+func (rights CastlingRights) FEN(files CastlingRookFiles) (string, error) {
+	if len(rights) == 0 {
+		return "-", nil
+	}
+
+	var builder strings.Builder
+
+	for _, colorSide := range [...]ColorSide{
+		ColorSideWhiteKing, ColorSideWhiteQueen, ColorSideBlackKing, ColorSideBlackQueen,
+	} {
+		if !slices.Contains(rights, colorSide) {
+			continue
+		}
+
+		color, err := colorSide.Color()
+		if err != nil {
+			return "", fmt.Errorf("%s.Color(): %w", colorSide, err)
+		}
+
+		letter := byte('q')
+		if colorSide.IsKingSide() {
+			letter = 'k'
+		}
+
+		if file, ok := files[colorSide]; ok {
+			letter = byte('a' + file - FileA)
+		}
+
+		if color == ColorWhite {
+			letter -= 'a' - 'A'
+		}
+
+		builder.WriteByte(letter)
+	}
+
+	return builder.String(), nil
+}
+
+// newCastlingRightFromFileFEN parses one Shredder-FEN castling rights file letter to the color side and the rook
+// file.
+//
+// Returns errCastlingRightNotFile if passed letter is not a file letter.
+//
+// FEN argument examples: 'H', 'b'.
+func newCastlingRightFromFileFEN(fen byte, board *Board) (ColorSide, File, error) {
+	var (
+		kingSide, queenSide ColorSide
+		file                File
+	)
+
+	switch {
+	case 'A' <= fen && fen <= 'H':
+		kingSide, queenSide, file = ColorSideWhiteKing, ColorSideWhiteQueen, File(fen-'A')+FileA
+	case 'a' <= fen && fen <= 'h':
+		kingSide, queenSide, file = ColorSideBlackKing, ColorSideBlackQueen, File(fen-'a')+FileA
+	default:
+		return ColorSideNil, FileNil, errCastlingRightNotFile
+	}
+
+	kingFile, err := findCastlingKingFile(board, kingSide)
+	if err != nil {
+		return ColorSideNil, FileNil, fmt.Errorf("findCastlingKingFile(%s): %w", kingSide, err)
+	}
+
+	switch {
+	case kingFile == FileNil:
+		return ColorSideNil, FileNil, errors.New("no king on the back rank")
+	case file == kingFile:
+		return ColorSideNil, FileNil, errors.New("rook file is the king file")
+	case file < kingFile:
+		return queenSide, file, nil
+	default:
+		return kingSide, file, nil
+	}
+}
+
+// findCastlingKingFile returns the file of the king of passed color side on its back rank or FileNil if the king is
+// not there.
+func findCastlingKingFile(board *Board, colorSide ColorSide) (File, error) {
+	if board == nil {
+		return FileNil, errors.New("board is nil")
+	}
+
+	color, err := colorSide.Color()
+	if err != nil {
+		return FileNil, fmt.Errorf("%s.Color(): %w", colorSide, err)
+	}
+
+	king, err := NewPiece(color, RoleKing)
+	if err != nil {
+		return FileNil, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
+	}
+
+	return findBackRankPieceFile(board, colorSide, king, Files[:])
+}
+
+// findCastlingRookFile returns the file of the outermost rook on the side of the king of passed color side.
+//
+// The standard rook file is returned if the board is nil, the king is not on its back rank or there are no rooks.
+func findCastlingRookFile(board *Board, colorSide ColorSide) (File, error) {
+	if board == nil {
+		return colorSide.RookStandardFile(), nil
+	}
+
+	kingFile, err := findCastlingKingFile(board, colorSide)
+	if err != nil {
+		return FileNil, fmt.Errorf("findCastlingKingFile(%s): %w", colorSide, err)
+	}
+
+	if kingFile == FileNil {
+		return colorSide.RookStandardFile(), nil
+	}
+
+	color, err := colorSide.Color()
+	if err != nil {
+		return FileNil, fmt.Errorf("%s.Color(): %w", colorSide, err)
+	}
+
+	rook, err := NewPiece(color, RoleRook)
+	if err != nil {
+		return FileNil, fmt.Errorf("NewPiece(%s, %s): %w", color, RoleRook, err)
+	}
+
+	// Search from the board edge to the king.
+	files := slices.Clone(Files[:kingFile-1])
+	if colorSide.IsKingSide() {
+		files = slices.Clone(Files[kingFile:])
+		slices.Reverse(files)
+	}
+
+	file, err := findBackRankPieceFile(board, colorSide, rook, files)
+	if err != nil {
+		return FileNil, fmt.Errorf("findBackRankPieceFile(%s): %w", rook, err)
+	}
+
+	if file == FileNil {
+		return colorSide.RookStandardFile(), nil
+	}
+
+	return file, nil
+}
+
+// findBackRankPieceFile returns the first of passed files, where passed piece stands on the back rank of passed
+// color side, or FileNil if there is no such file.
+func findBackRankPieceFile(board *Board, colorSide ColorSide, piece Piece, files []File) (File, error) {
+	rank, err := colorSide.Rank()
+	if err != nil {
+		return FileNil, fmt.Errorf("%s.Rank(): %w", colorSide, err)
+	}
+
+	for _, file := range files {
+		square, err := NewSquare(rank, file)
+		if err != nil {
+			return FileNil, fmt.Errorf("NewSquare(%s, %s): %w", rank, file, err)
+		}
+
+		squarePiece, err := board.GetPieceFromSquare(square)
+		if err != nil {
+			return FileNil, fmt.Errorf("GetPieceFromSquare(%s): %w", square, err)
+		}
+
+		if squarePiece == piece {
+			return file, nil
+		}
 	}
 
-	return Rights(rights), nil
+	return FileNil, nil
 }
@@ -40,3 +40,133 @@ func TestNewCastlingRightsFromFEN(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRightsFromFEN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fen       string
+		rights    CastlingRights
+		errString string
+	}{
+		{
+			"kqKQ",
+			CastlingRights([]ColorSide{ColorSideBlackKing, ColorSideBlackQueen, ColorSideWhiteKing, ColorSideWhiteQueen}),
+			"",
+		},
+		{"-", nil, ""},
+		{"H", nil, "newCastlingRightFromFileFEN('H'): findCastlingKingFile(ColorSideWhiteKing): board is nil"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fen, func(t *testing.T) {
+			t.Parallel()
+
+			rights, files, err := NewRightsFromFEN(test.fen, nil)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewRightsFromFEN(%q) expected error %q but got %q", test.fen, test.errString, err)
+			}
+
+			if !reflect.DeepEqual(rights, test.rights) {
+				t.Fatalf("NewRightsFromFEN(%q) expected %+v but got %+v", test.fen, test.rights, rights)
+			}
+
+			if files != nil {
+				t.Fatalf("NewRightsFromFEN(%q) expected no rook files but got %+v", test.fen, files)
+			}
+		})
+	}
+}
+
+func TestNewRightsFromFENChess960(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		board     string
+		fen       string
+		rights    CastlingRights
+		files     CastlingRookFiles
+		errString string
+	}{
+		{
+			"Shredder-FEN standard",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR",
+			"HAha",
+			CastlingRights{ColorSideWhiteKing, ColorSideWhiteQueen, ColorSideBlackKing, ColorSideBlackQueen},
+			nil,
+			"",
+		},
+		{
+			"Shredder-FEN",
+			"nrkbbqrn/pppppppp/8/8/8/8/PPPPPPPP/NRKBBQRN",
+			"GBgb",
+			CastlingRights{ColorSideWhiteKing, ColorSideWhiteQueen, ColorSideBlackKing, ColorSideBlackQueen},
+			CastlingRookFiles{
+				ColorSideWhiteKing:  FileG,
+				ColorSideWhiteQueen: FileB,
+				ColorSideBlackKing:  FileG,
+				ColorSideBlackQueen: FileB,
+			},
+			"",
+		},
+		{
+			"X-FEN outermost rooks",
+			"nrkbbqrn/pppppppp/8/8/8/8/PPPPPPPP/NRKBBQRN",
+			"KQkq",
+			CastlingRights{ColorSideWhiteKing, ColorSideWhiteQueen, ColorSideBlackKing, ColorSideBlackQueen},
+			CastlingRookFiles{
+				ColorSideWhiteKing:  FileG,
+				ColorSideWhiteQueen: FileB,
+				ColorSideBlackKing:  FileG,
+				ColorSideBlackQueen: FileB,
+			},
+			"",
+		},
+		{
+			"X-FEN inner rook",
+			"4k3/8/8/8/8/8/8/1R2K1RR",
+			"KB",
+			CastlingRights{ColorSideWhiteKing, ColorSideWhiteQueen},
+			CastlingRookFiles{ColorSideWhiteQueen: FileB},
+			"",
+		},
+		{
+			"no king on the back rank",
+			"4k3/8/8/8/8/8/4K3/R6R",
+			"A",
+			nil,
+			nil,
+			"newCastlingRightFromFileFEN('A'): no king on the back rank",
+		},
+		{
+			"rook file is the king file",
+			"4k3/8/8/8/8/8/8/R3K2R",
+			"e",
+			nil,
+			nil,
+			"newCastlingRightFromFileFEN('e'): rook file is the king file",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			board, err := NewBoardFromFEN(test.board)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.board, err)
+			}
+
+			rights, files, err := NewRightsFromFEN(test.fen, board)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewRightsFromFEN(%q) expected error %q but got %q", test.fen, test.errString, err)
+			}
+
+			if !reflect.DeepEqual(rights, test.rights) || !reflect.DeepEqual(files, test.files) {
+				t.Fatalf("NewRightsFromFEN(%q) expected %+v and %+v but got %+v and %+v", test.fen, test.rights,
+					test.files, rights, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package position
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Count of the Chess960 start positions.
+	chess960PositionsCount = 960
+
+	// Index of the standard chess start position in the Chess960 numbering.
+	Chess960StandardIndex = 518
+)
+
+// Placements of two knights on five squares remaining after the bishops and the queen placements.
+var chess960KnightsPlacements = [...][2]int{
+	{0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4},
+}
+
+// NewPosition960 creates the Chess960 start position with passed index using Scharnagl numbering.
+//
+// Index argument examples: 0 for "bbqnnrkr", 518 for the standard chess start position, 959 for "rkrnnqbb".
+func NewPosition960(index int) (*Position, error) {
+	backRank, err := calcChess960BackRank(index)
+	if err != nil {
+		return nil, fmt.Errorf("calcChess960BackRank(%d): %w", index, err)
+	}
+
+	// X-FEN castling rights denote the outermost rooks, which are the only rooks in the start position.
+	fen := fmt.Sprintf("%s/pppppppp/8/8/8/8/PPPPPPPP/%s w KQkq - 0 1", backRank, strings.ToUpper(backRank))
+
+	position, err := NewPositionFromFEN(fen)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+	}
+
+	return position, nil
+}
+
+// calcChess960BackRank calculates the black back rank FEN of the Chess960 start position with passed index.
+//
+// Return examples: "bbqnnrkr", "rnbqkbnr", "rkrnnqbb".
+func calcChess960BackRank(index int) (string, error) {
+	if index < 0 || index >= chess960PositionsCount {
+		return "", fmt.Errorf("index %d is out of range", index)
+	}
+
+	var backRank [8]byte
+
+	// Bishops stand on the squares of different colors: the first one on the files B, D, F, H and the second one on
+	// the files A, C, E, G.
+	backRank[index%4*2+1] = 'b'
+	index /= 4
+	backRank[index%4*2] = 'b'
+	index /= 4
+
+	// The queen and the knights stand on the empty squares.
+	placeOnEmpty := func(letter byte, emptyIndex int) {
+		for file := range backRank {
+			if backRank[file] != 0 {
+				continue
+			}
+
+			if emptyIndex == 0 {
+				backRank[file] = letter
+				return
+			}
+
+			emptyIndex--
+		}
+	}
+
+	placeOnEmpty('q', index%6) //nolint:mnd // Six empty squares.
+	index /= 6
+
+	// The second knight is placed after the first one, so its index among the empty squares is shifted.
+	knights := chess960KnightsPlacements[index]
+	placeOnEmpty('n', knights[0])
+	placeOnEmpty('n', knights[1]-1)
+
+	// The king stands between the rooks on the remaining squares.
+	placeOnEmpty('r', 0)
+	placeOnEmpty('k', 0)
+	placeOnEmpty('r', 0)
+
+	return string(backRank[:]), nil
+}
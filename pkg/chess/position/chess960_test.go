@@ -0,0 +1,58 @@
+package position
+
+import "testing"
+
+func TestCalcChess960BackRank(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		index     int
+		backRank  string
+		errString string
+	}{
+		{0, "bbqnnrkr", ""},
+		{Chess960StandardIndex, "rnbqkbnr", ""},
+		{959, "rkrnnqbb", ""},
+		{-1, "", "index -1 is out of range"},
+		{960, "", "index 960 is out of range"},
+	}
+
+	for _, test := range tests {
+		backRank, err := calcChess960BackRank(test.index)
+		if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+			t.Fatalf("calcChess960BackRank(%d) expected error %q but got %q", test.index, test.errString, err)
+		}
+
+		if backRank != test.backRank {
+			t.Fatalf("calcChess960BackRank(%d) expected %q but got %q", test.index, test.backRank, backRank)
+		}
+	}
+}
+
+func TestNewPosition960(t *testing.T) {
+	t.Parallel()
+
+	position, err := NewPosition960(Chess960StandardIndex)
+	if err != nil {
+		t.Fatalf("NewPosition960(%d): %v", Chess960StandardIndex, err)
+	}
+
+	if !position.Repeats(testPositionStart) {
+		t.Fatalf("NewPosition960(%d) expected %+v but got %+v", Chess960StandardIndex, testPositionStart, position)
+	}
+
+	// Back rank "nrkbbqrn" has rooks on the B and G files.
+	position, err = NewPosition960(345)
+	if err != nil {
+		t.Fatalf("NewPosition960(345): %v", err)
+	}
+
+	rookSquare, err := position.CastlingRookSquare(ColorSideBlackQueen)
+	if err != nil {
+		t.Fatalf("CastlingRookSquare(): %v", err)
+	}
+
+	if rookSquare != SquareB8 {
+		t.Fatalf("CastlingRookSquare() expected %s but got %s", SquareB8, rookSquare)
+	}
+}
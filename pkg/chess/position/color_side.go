@@ -51,3 +51,66 @@ func (colorSide ColorSide) String() string {
 		return fmt.Sprintf("<unknown ColorSide=%d>", colorSide)
 	}
 }
+
+// Color returns the color of current color side.
+func (colorSide ColorSide) Color() (Color, error) {
+	switch colorSide {
+	case ColorSideWhiteKing, ColorSideWhiteQueen:
+		return ColorWhite, nil
+	case ColorSideBlackKing, ColorSideBlackQueen:
+		return ColorBlack, nil
+	case ColorSideNil:
+		return ColorNil, errors.New("no color")
+	default:
+		return ColorNil, errors.New("unknown color side")
+	}
+}
+
+// IsKingSide returns true if current color side is the king side of any color.
+func (colorSide ColorSide) IsKingSide() bool {
+	return colorSide == ColorSideWhiteKing || colorSide == ColorSideBlackKing
+}
+
+// KingDestFile returns the file of the king after castling, which is the same in chess and Chess960: G for the king
+// side and C for the queen side.
+func (colorSide ColorSide) KingDestFile() File {
+	if colorSide.IsKingSide() {
+		return FileG
+	}
+
+	return FileC
+}
+
+// Rank returns the back rank of current color side, where the king and the rook castle.
+func (colorSide ColorSide) Rank() (Rank, error) {
+	color, err := colorSide.Color()
+	if err != nil {
+		return RankNil, fmt.Errorf("Color(): %w", err)
+	}
+
+	if color == ColorWhite {
+		return Rank1, nil
+	}
+
+	return Rank8, nil
+}
+
+// RookDestFile returns the file of the rook after castling, which is the same in chess and Chess960: F for the king
+// side and D for the queen side.
+func (colorSide ColorSide) RookDestFile() File {
+	if colorSide.IsKingSide() {
+		return FileF
+	}
+
+	return FileD
+}
+
+// RookStandardFile returns the file of the castling rook in the standard chess: H for the king side and A for the
+// queen side.
+func (colorSide ColorSide) RookStandardFile() File {
+	if colorSide.IsKingSide() {
+		return FileH
+	}
+
+	return FileA
+}
@@ -61,3 +61,37 @@ func TestColorSideString(t *testing.T) {
 		})
 	}
 }
+
+func TestColorSideCastlingSquares(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		colorSide        ColorSide
+		rank             Rank
+		kingDestFile     File
+		rookDestFile     File
+		rookStandardFile File
+	}{
+		{ColorSideWhiteKing, Rank1, FileG, FileF, FileH},
+		{ColorSideWhiteQueen, Rank1, FileC, FileD, FileA},
+		{ColorSideBlackKing, Rank8, FileG, FileF, FileH},
+		{ColorSideBlackQueen, Rank8, FileC, FileD, FileA},
+	}
+
+	for _, test := range tests {
+		rank, err := test.colorSide.Rank()
+		if err != nil {
+			t.Fatalf("%s.Rank(): %v", test.colorSide, err)
+		}
+
+		if rank != test.rank || test.colorSide.KingDestFile() != test.kingDestFile ||
+			test.colorSide.RookDestFile() != test.rookDestFile ||
+			test.colorSide.RookStandardFile() != test.rookStandardFile {
+			t.Fatalf("%s has unexpected castling squares", test.colorSide)
+		}
+	}
+
+	if _, err := ColorSideNil.Rank(); err == nil {
+		t.Fatal("ColorSideNil.Rank() expected error")
+	}
+}
@@ -0,0 +1,181 @@
+package position
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Marks the promoted piece in the board FEN of Crazyhouse, for example, "Q~".
+const pocketsPromotedMark = '~'
+
+// Pockets contains the counts of the captured pieces of each color, which can be dropped in Crazyhouse.
+//
+// Pieces are stored with the color of the owner of the pocket. Zero counts are not stored.
+type Pockets map[Piece]uint8
+
+// NewPocketsFromFEN parses the pockets FEN part, which is written in square brackets after the board.
+//
+// FEN argument examples: "", "Qn", "PPNbb".
+func NewPocketsFromFEN(fen string) (Pockets, error) {
+	pockets := make(Pockets)
+
+	for index, letter := range []byte(fen) {
+		piece, err := NewPieceFromFEN(string(letter))
+		if err != nil {
+			return nil, fmt.Errorf("byte #%d, NewPieceFromFEN(%q): %w", index, letter, err)
+		}
+
+		if piece == PieceWhiteKing || piece == PieceBlackKing {
+			return nil, fmt.Errorf("byte #%d, king cannot be in the pocket", index)
+		}
+
+		pockets[piece]++
+	}
+
+	return pockets, nil
+}
+
+// FEN returns FEN representation of the current pockets without square brackets. White pieces go first and the
+// pieces of each color are ordered from the queen to the pawn.
+//
+// Return examples: "", "Qn", "PPNbb".
+func (pockets Pockets) FEN() (string, error) {
+	var builder strings.Builder
+
+	for _, color := range [...]Color{ColorWhite, ColorBlack} {
+		for _, role := range [...]Role{RoleQueen, RoleRook, RoleBishop, RoleKnight, RolePawn} {
+			piece, err := NewPiece(color, role)
+			if err != nil {
+				return "", fmt.Errorf("NewPiece(%s, %s): %w", color, role, err)
+			}
+
+			letter, err := role.FEN()
+			if err != nil {
+				return "", fmt.Errorf("%s.FEN(): %w", role, err)
+			}
+
+			if color == ColorWhite {
+				letter = strings.ToUpper(letter)
+			}
+
+			builder.WriteString(strings.Repeat(letter, int(pockets[piece])))
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// add adds passed piece to the pocket.
+func (pockets Pockets) add(piece Piece) {
+	pockets[piece]++
+}
+
+// remove removes passed piece from the pocket.
+func (pockets Pockets) remove(piece Piece) error {
+	if pockets[piece] == 0 {
+		return fmt.Errorf("no %s in the pocket", piece)
+	}
+
+	pockets[piece]--
+
+	if pockets[piece] == 0 {
+		delete(pockets, piece)
+	}
+
+	return nil
+}
+
+// addPromotedToBoardFEN adds the promoted marks after the pieces on passed squares to passed board FEN.
+//
+// FEN argument example: "rnbqkQ1r/ppp3pp/8/8/8/8/PPPP2PP/RNBQKBNR".
+func addPromotedToBoardFEN(fen string, promoted Bitboard) (string, error) {
+	if promoted == BitboardNil {
+		return fen, nil
+	}
+
+	var builder strings.Builder
+
+	rank, file := Rank8, FileA
+
+	for index, letter := range []byte(fen) {
+		builder.WriteByte(letter)
+
+		switch {
+		case letter == '/':
+			rank, file = rank-1, FileA
+		case '1' <= letter && letter <= '8':
+			file += File(letter - '0')
+		default:
+			square, err := NewSquare(rank, file)
+			if err != nil {
+				return "", fmt.Errorf("byte #%d, NewSquare(%s, %s): %w", index, rank, file, err)
+			}
+
+			marked, err := promoted.Occupied(square)
+			if err != nil {
+				return "", fmt.Errorf("byte #%d, 0x%X.Occupied(%s): %w", index, promoted, square, err)
+			}
+
+			if marked {
+				builder.WriteRune(pocketsPromotedMark)
+			}
+
+			file++
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// cutPromotedFromBoardFEN removes the promoted marks from passed board FEN and returns the squares of the marked
+// pieces.
+//
+// FEN argument example: "rnbqkQ~1r/ppp3pp/8/8/8/8/PPPP2PP/RNBQKBNR".
+func cutPromotedFromBoardFEN(fen string) (string, Bitboard, error) {
+	if !strings.ContainsRune(fen, pocketsPromotedMark) {
+		return fen, BitboardNil, nil
+	}
+
+	var (
+		builder  strings.Builder
+		promoted Bitboard
+		previous Square
+	)
+
+	rank, file := Rank8, FileA
+
+	for index, letter := range []byte(fen) {
+		switch {
+		case letter == pocketsPromotedMark:
+			if previous == SquareNil {
+				return "", BitboardNil, fmt.Errorf("byte #%d, promoted mark without a piece", index)
+			}
+
+			var err error
+
+			promoted, err = promoted.SetSquares(previous)
+			if err != nil {
+				return "", BitboardNil, fmt.Errorf("byte #%d, SetSquares(%s): %w", index, previous, err)
+			}
+
+			previous = SquareNil
+
+			continue
+		case letter == '/':
+			rank, file, previous = rank-1, FileA, SquareNil
+		case '1' <= letter && letter <= '8':
+			file, previous = file+File(letter-'0'), SquareNil
+		default:
+			square, err := NewSquare(rank, file)
+			if err != nil {
+				return "", BitboardNil, fmt.Errorf("byte #%d, NewSquare(%s, %s): %w", index, rank, file, err)
+			}
+
+			file, previous = file+1, square
+		}
+
+		builder.WriteByte(letter)
+	}
+
+	return builder.String(), promoted, nil
+}
@@ -0,0 +1,186 @@
+package position
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPocketsFromFEN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fen       string
+		pockets   Pockets
+		errString string
+	}{
+		{"", Pockets{}, ""},
+		{"Qn", Pockets{PieceWhiteQueen: 1, PieceBlackKnight: 1}, ""},
+		{"PPNbb", Pockets{PieceWhitePawn: 2, PieceWhiteKnight: 1, PieceBlackBishop: 2}, ""},
+		{"Qk", nil, "byte #1, king cannot be in the pocket"},
+		{"Qx", nil, "byte #1, NewPieceFromFEN('x'): unknown FEN"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fen, func(t *testing.T) {
+			t.Parallel()
+
+			pockets, err := NewPocketsFromFEN(test.fen)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewPocketsFromFEN(%q) expected error %q but got %q", test.fen, test.errString, err)
+			}
+
+			if !reflect.DeepEqual(pockets, test.pockets) {
+				t.Fatalf("NewPocketsFromFEN(%q) expected %+v but got %+v", test.fen, test.pockets, pockets)
+			}
+
+			if err != nil {
+				return
+			}
+
+			fen, err := pockets.FEN()
+			if err != nil {
+				t.Fatalf("FEN() expected no error but got %v", err)
+			}
+
+			if fen != test.fen {
+				t.Fatalf("FEN() expected %q but got %q", test.fen, fen)
+			}
+		})
+	}
+}
+
+func TestCutPromotedFromBoardFEN(t *testing.T) {
+	t.Parallel()
+
+	promotedBitboard, err := BitboardNil.SetSquares(SquareF6, SquareA1)
+	if err != nil {
+		t.Fatalf("SetSquares(): %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		fen       string
+		cutFEN    string
+		promoted  Bitboard
+		errString string
+	}{
+		{"no marks", "4k3/8/8/8/8/8/8/4K3", "4k3/8/8/8/8/8/8/4K3", BitboardNil, ""},
+		{"marks", "4k3/8/5q~2/8/8/8/8/N~3K3", "4k3/8/5q2/8/8/8/8/N3K3", promotedBitboard, ""},
+		{"mark without piece", "4k3/8/5~3/8/8/8/8/4K3", "", BitboardNil, "byte #7, promoted mark without a piece"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cutFEN, promoted, err := cutPromotedFromBoardFEN(test.fen)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("cutPromotedFromBoardFEN(%q) expected error %q but got %q", test.fen, test.errString, err)
+			}
+
+			if cutFEN != test.cutFEN || promoted != test.promoted {
+				t.Fatalf("cutPromotedFromBoardFEN(%q) expected %q, 0x%X but got %q, 0x%X",
+					test.fen, test.cutFEN, test.promoted, cutFEN, promoted)
+			}
+		})
+	}
+}
+
+func TestPositionMoveRawCrazyhouse(t *testing.T) {
+	t.Parallel()
+
+	var captureTags MoveTags
+	captureTags.Set(MoveTagCapture)
+
+	promotedA8, err := BitboardNil.SetSquares(SquareA8)
+	if err != nil {
+		t.Fatalf("SetSquares(): %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		fen       string
+		move      Move
+		pockets   Pockets
+		promoted  Bitboard
+		errString string
+	}{
+		{
+			"capture",
+			"4k3/8/5q2/8/4N3/8/8/4K3[] w - - 0 1",
+			NewMove(SquareE4, SquareF6, captureTags, RoleNil),
+			Pockets{PieceWhiteQueen: 1},
+			BitboardNil,
+			"",
+		},
+		{
+			"capture promoted",
+			"4k3/8/5q~2/8/4N3/8/8/4K3[b] w - - 0 1",
+			NewMove(SquareE4, SquareF6, captureTags, RoleNil),
+			Pockets{PieceWhitePawn: 1, PieceBlackBishop: 1},
+			BitboardNil,
+			"",
+		},
+		{
+			"promotion",
+			"4k3/P7/8/8/8/8/8/4K3[] w - - 0 1",
+			NewMove(SquareA7, SquareA8, MoveTagsNil, RoleQueen),
+			Pockets{},
+			promotedA8,
+			"",
+		},
+		{
+			"drop",
+			"4k3/8/8/8/8/8/8/4K3[NN] w - - 0 1",
+			NewMoveDrop(PieceWhiteKnight, SquareF3, MoveTagsNil),
+			Pockets{PieceWhiteKnight: 1},
+			BitboardNil,
+			"",
+		},
+		{
+			"drop from empty pocket",
+			"4k3/8/8/8/8/8/8/4K3[n] w - - 0 1",
+			NewMoveDrop(PieceWhiteKnight, SquareF3, MoveTagsNil),
+			nil,
+			BitboardNil,
+			"remove(PieceWhiteKnight): no PieceWhiteKnight in the pocket",
+		},
+		{
+			"drop without pockets",
+			"4k3/8/8/8/8/8/8/4K3 w - - 0 1",
+			NewMoveDrop(PieceWhiteKnight, SquareF3, MoveTagsNil),
+			nil,
+			BitboardNil,
+			"no pockets in the position",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			err = position.MoveRaw(test.move)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("MoveRaw(%+v) expected error %q but got %q", test.move, test.errString, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(position.Pockets(), test.pockets) {
+				t.Fatalf("MoveRaw(%+v) expected pockets %+v but got %+v", test.move, test.pockets, position.Pockets())
+			}
+
+			if position.PromotedBitboard() != test.promoted {
+				t.Fatalf("MoveRaw(%+v) expected promoted 0x%X but got 0x%X",
+					test.move, test.promoted, position.PromotedBitboard())
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package position
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
@@ -23,6 +25,13 @@ type Position struct {
 	enPassantSquare Square
 	halfMoveClock   uint8
 	fullMoveNumber  uint16
+
+	// Start files of the castling rooks, which differ from the standard ones, for example, in Chess960.
+	castlingRookFiles CastlingRookFiles
+
+	// Pieces in hand and squares of the promoted pieces in Crazyhouse. Pockets are nil in other variants.
+	pockets          Pockets
+	promotedBitboard Bitboard
 }
 
 // NewPosition creates a new position with passed parameters.
@@ -56,16 +65,44 @@ func NewPositionStart() (*Position, error) {
 
 // NewPositionFromFEN parses FEN to the Position structure.
 //
-// FEN argument example: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1".
+// Crazyhouse pockets are written in square brackets after the board and promoted pieces are marked with "~".
+//
+// FEN argument examples: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+// "r1bqkbnr/pppp1ppp/2n5/8/8/8/PPPP1PPP/RNBQKB1R[Pn] w KQkq - 0 4".
 func NewPositionFromFEN(fen string) (*Position, error) {
 	parts := strings.Split(fen, " ")
 	if len(parts) != positionFENPartsCount {
 		return nil, fmt.Errorf("FEN parts required %d but got %d", positionFENPartsCount, len(parts))
 	}
 
-	board, err := NewBoardFromFEN(parts[0])
+	boardFEN, pocketsFEN, crazyhouse := strings.Cut(parts[0], "[")
+
+	var pockets Pockets
+
+	if crazyhouse {
+		var (
+			ok  bool
+			err error
+		)
+
+		if pocketsFEN, ok = strings.CutSuffix(pocketsFEN, "]"); !ok {
+			return nil, errors.New("pockets are not closed")
+		}
+
+		pockets, err = NewPocketsFromFEN(pocketsFEN)
+		if err != nil {
+			return nil, fmt.Errorf("NewPocketsFromFEN(%q): %w", pocketsFEN, err)
+		}
+	}
+
+	piecesFEN, promotedBitboard, err := cutPromotedFromBoardFEN(boardFEN)
 	if err != nil {
-		return nil, fmt.Errorf("NewBoardFromFEN(%q): %w", parts[0], err)
+		return nil, fmt.Errorf("cutPromotedFromBoardFEN(%q): %w", boardFEN, err)
+	}
+
+	board, err := NewBoardFromFEN(piecesFEN)
+	if err != nil {
+		return nil, fmt.Errorf("NewBoardFromFEN(%q): %w", piecesFEN, err)
 	}
 
 	activeColor, err := NewColorFromFEN(parts[1])
@@ -73,9 +110,9 @@ func NewPositionFromFEN(fen string) (*Position, error) {
 		return nil, fmt.Errorf("NewColorFromFEN(%q): %w", parts[1], err)
 	}
 
-	castlingRights, err := NewCastlingRightsFromFEN(parts[2])
+	castlingRights, castlingRookFiles, err := NewRightsFromFEN(parts[2], board)
 	if err != nil {
-		return nil, fmt.Errorf("NewCastlingRightsFromFEN(%q): %w", parts[2], err)
+		return nil, fmt.Errorf("NewRightsFromFEN(%q): %w", parts[2], err)
 	}
 
 	enPassantSquare, err := NewSquareEnPassantFromFEN(parts[3])
@@ -95,7 +132,147 @@ func NewPositionFromFEN(fen string) (*Position, error) {
 	}
 	fullMoveNumber := uint16(fullMoveNumberUint64)
 
-	return NewPosition(board, activeColor, castlingRights, enPassantSquare, halfMoveClock, fullMoveNumber), nil
+	position := NewPosition(board, activeColor, castlingRights, enPassantSquare, halfMoveClock, fullMoveNumber)
+	position.castlingRookFiles = castlingRookFiles
+	position.pockets = pockets
+	position.promotedBitboard = promotedBitboard
+
+	return position, nil
+}
+
+// ActiveColor returns the color of the side to move.
+func (position *Position) ActiveColor() Color {
+	return position.activeColor
+}
+
+// Board returns the board of the current position.
+//
+// Please note that the board is not copied, so do not modify it.
+func (position *Position) Board() *Board {
+	return position.board
+}
+
+// CastlingRights returns a copy of the castling rights of the current position.
+func (position *Position) CastlingRights() CastlingRights {
+	return slices.Clone(position.castlingRights)
+}
+
+// CastlingRookSquare returns the start square of the rook, which castles on passed color side.
+//
+// Note that the square is returned even if the color side has no castling right.
+func (position *Position) CastlingRookSquare(colorSide ColorSide) (Square, error) {
+	rank, err := colorSide.Rank()
+	if err != nil {
+		return SquareNil, fmt.Errorf("%s.Rank(): %w", colorSide, err)
+	}
+
+	file, ok := position.castlingRookFiles[colorSide]
+	if !ok {
+		file = colorSide.RookStandardFile()
+	}
+
+	square, err := NewSquare(rank, file)
+	if err != nil {
+		return SquareNil, fmt.Errorf("NewSquare(%s, %s): %w", rank, file, err)
+	}
+
+	return square, nil
+}
+
+// EnPassantSquare returns the en passant square of the current position or SquareNil if there is no en passant.
+func (position *Position) EnPassantSquare() Square {
+	return position.enPassantSquare
+}
+
+// FEN returns FEN representation of the current position. Crazyhouse pockets and promoted marks are written only if the
+// position has pockets.
+//
+// Return examples: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+// "r1bqkbnr/pppp1ppp/2n5/8/8/8/PPPP1PPP/RNBQKB1R[Pn] w KQkq - 0 4".
+func (position *Position) FEN() (string, error) {
+	boardFEN, err := position.board.FEN()
+	if err != nil {
+		return "", fmt.Errorf("board.FEN(): %w", err)
+	}
+
+	if position.pockets != nil {
+		boardFEN, err = addPromotedToBoardFEN(boardFEN, position.promotedBitboard)
+		if err != nil {
+			return "", fmt.Errorf("addPromotedToBoardFEN(%q, 0x%X): %w", boardFEN, position.promotedBitboard, err)
+		}
+
+		pocketsFEN, err := position.pockets.FEN()
+		if err != nil {
+			return "", fmt.Errorf("pockets.FEN(): %w", err)
+		}
+
+		boardFEN += "[" + pocketsFEN + "]"
+	}
+
+	activeColorFEN := "w"
+	if position.activeColor == ColorBlack {
+		activeColorFEN = "b"
+	}
+
+	castlingRightsFEN, err := position.castlingRights.FEN(position.castlingRookFiles)
+	if err != nil {
+		return "", fmt.Errorf("castlingRights.FEN(): %w", err)
+	}
+
+	enPassantSquareFEN := "-"
+	if position.enPassantSquare != SquareNil {
+		enPassantSquareFEN, err = position.enPassantSquare.FEN()
+		if err != nil {
+			return "", fmt.Errorf("%s.FEN(): %w", position.enPassantSquare, err)
+		}
+	}
+
+	return fmt.Sprintf(
+		"%s %s %s %s %d %d",
+		boardFEN,
+		activeColorFEN,
+		castlingRightsFEN,
+		enPassantSquareFEN,
+		position.halfMoveClock,
+		position.fullMoveNumber,
+	), nil
+}
+
+// Pockets returns the copy of the pieces in hand or nil if the position is not from Crazyhouse.
+func (position *Position) Pockets() Pockets {
+	return maps.Clone(position.pockets)
+}
+
+// PromotedBitboard returns the squares of the promoted pieces, which are returned to the pockets as pawns after the
+// capture in Crazyhouse.
+func (position *Position) PromotedBitboard() Bitboard {
+	return position.promotedBitboard
+}
+
+// HalfMoveClock returns the count of half moves since the last capture or pawn move.
+func (position *Position) HalfMoveClock() uint8 {
+	return position.halfMoveClock
+}
+
+// FullMoveNumber returns the number of the full move, which starts at 1 and is incremented after the black move.
+func (position *Position) FullMoveNumber() uint16 {
+	return position.fullMoveNumber
+}
+
+// Repeats checks that the current position is the repetition of passed position.
+//
+// Positions are the same if they have the same pieces on the same squares, the same active color, the same castling
+// rights with the same rooks, the same en passant square and the same pockets. Clocks are not compared.
+//
+// TODO: test.
+func (position *Position) Repeats(other *Position) bool {
+	return position.board.Equals(other.board) &&
+		position.activeColor == other.activeColor &&
+		slices.Equal(position.castlingRights, other.castlingRights) &&
+		maps.Equal(position.castlingRookFiles, other.castlingRookFiles) &&
+		position.enPassantSquare == other.enPassantSquare &&
+		maps.Equal(position.pockets, other.pockets) &&
+		position.promotedBitboard == other.promotedBitboard
 }
 
 // Copy deeply copies current position.
@@ -109,14 +286,17 @@ func (position *Position) DeepCopy() (*Position, error) {
 //
 // TODO: test.
 func (position *Position) MoveRaw(move Move) error {
-	if err := position.board.MoveRaw(move); err != nil {
-		return fmt.Errorf("board.MoveRaw(%+v): %w", move, err)
+	if move.dropPiece != PieceNil {
+		return position.moveDropRaw(move)
 	}
 
-	if err := position.updateActiveColor(); err != nil {
-		return fmt.Errorf("updateActiveColor(): %w", err)
+	// Start square of the castling rook depends on the position, for example, in Chess960, so the board does not know it.
+	castleRookOrigin, err := position.calcCastleRookOrigin(move)
+	if err != nil {
+		return fmt.Errorf("calcCastleRookOrigin(%+v): %w", move, err)
 	}
 
+	// The updates below inspect the origin piece and the active color, so they are done before the board move.
 	if err := position.updateCastlingRightsRaw(move); err != nil {
 		return fmt.Errorf("updateCastlingRightsRaw(%+v): %w", move, err)
 	}
@@ -129,8 +309,146 @@ func (position *Position) MoveRaw(move Move) error {
 		return fmt.Errorf("updateHalfMoveClockRaw(%+v): %w", move, err)
 	}
 
+	if position.pockets != nil && castleRookOrigin == SquareNil {
+		if err := position.updatePocketsRaw(move); err != nil {
+			return fmt.Errorf("updatePocketsRaw(%+v): %w", move, err)
+		}
+	}
+
 	position.updateFullMoveNumber()
 
+	if castleRookOrigin != SquareNil {
+		if err := position.board.MoveCastleRaw(move, castleRookOrigin); err != nil {
+			return fmt.Errorf("board.MoveCastleRaw(%+v, %s): %w", move, castleRookOrigin, err)
+		}
+	} else if err := position.board.MoveRaw(move); err != nil {
+		return fmt.Errorf("board.MoveRaw(%+v): %w", move, err)
+	}
+
+	if err := position.updateActiveColor(); err != nil {
+		return fmt.Errorf("updateActiveColor(): %w", err)
+	}
+
+	return nil
+}
+
+// MoveAtomicRaw makes a raw move in the current position of Atomic chess, where the capture explodes the destination
+// square: the capturing piece and all pieces around, except pawns, are removed. Castling rights of the exploded kings
+// and rooks are lost.
+//
+// Note that the move is raw, so it can, for example, explode the own king.
+func (position *Position) MoveAtomicRaw(move Move) error {
+	if err := position.MoveRaw(move); err != nil {
+		return fmt.Errorf("MoveRaw(%+v): %w", move, err)
+	}
+
+	if !move.tags.Contains(MoveTagCapture) && !move.tags.Contains(MoveTagEnPassantCapture) {
+		return nil
+	}
+
+	if err := position.board.ExplodeRaw(move.dest); err != nil {
+		return fmt.Errorf("board.ExplodeRaw(%s): %w", move.dest, err)
+	}
+
+	if err := position.removeExplodedCastlingRights(); err != nil {
+		return fmt.Errorf("removeExplodedCastlingRights(): %w", err)
+	}
+
+	return nil
+}
+
+// calcCastleRookOrigin returns the start square of the rook, which castles by passed move, or SquareNil if the move
+// is not a castling.
+func (position *Position) calcCastleRookOrigin(move Move) (Square, error) {
+	var colorSide ColorSide
+
+	switch {
+	case position.activeColor == ColorWhite && move.tags.Contains(MoveTagKingSideCastle):
+		colorSide = ColorSideWhiteKing
+	case position.activeColor == ColorWhite && move.tags.Contains(MoveTagQueenSideCastle):
+		colorSide = ColorSideWhiteQueen
+	case position.activeColor == ColorBlack && move.tags.Contains(MoveTagKingSideCastle):
+		colorSide = ColorSideBlackKing
+	case position.activeColor == ColorBlack && move.tags.Contains(MoveTagQueenSideCastle):
+		colorSide = ColorSideBlackQueen
+	default:
+		return SquareNil, nil
+	}
+
+	square, err := position.CastlingRookSquare(colorSide)
+	if err != nil {
+		return SquareNil, fmt.Errorf("CastlingRookSquare(%s): %w", colorSide, err)
+	}
+
+	return square, nil
+}
+
+// moveDropRaw makes a raw drop move in the current position of Crazyhouse.
+//
+// Note that the move is raw, so it can, for example, put the active color in check or drop a pawn on the last rank.
+func (position *Position) moveDropRaw(move Move) error {
+	if position.pockets == nil {
+		return errors.New("no pockets in the position")
+	}
+
+	if err := position.pockets.remove(move.dropPiece); err != nil {
+		return fmt.Errorf("remove(%s): %w", move.dropPiece, err)
+	}
+
+	if err := position.board.MoveRaw(move); err != nil {
+		return fmt.Errorf("board.MoveRaw(%+v): %w", move, err)
+	}
+
+	position.enPassantSquare = SquareNil
+	position.halfMoveClock++
+	position.updateFullMoveNumber()
+
+	if err := position.updateActiveColor(); err != nil {
+		return fmt.Errorf("updateActiveColor(): %w", err)
+	}
+
+	return nil
+}
+
+// removeExplodedCastlingRights removes the castling rights, whose king or rook is not on the board anymore.
+func (position *Position) removeExplodedCastlingRights() error {
+	colorSidesToDelete := make(CastlingRights, 0, len(position.castlingRights))
+
+	for _, colorSide := range position.castlingRights {
+		color, err := colorSide.Color()
+		if err != nil {
+			return fmt.Errorf("%s.Color(): %w", colorSide, err)
+		}
+
+		rookSquare, err := position.CastlingRookSquare(colorSide)
+		if err != nil {
+			return fmt.Errorf("CastlingRookSquare(%s): %w", colorSide, err)
+		}
+
+		rook, err := NewPiece(color, RoleRook)
+		if err != nil {
+			return fmt.Errorf("NewPiece(%s, %s): %w", color, RoleRook, err)
+		}
+
+		king, err := NewPiece(color, RoleKing)
+		if err != nil {
+			return fmt.Errorf("NewPiece(%s, %s): %w", color, RoleKing, err)
+		}
+
+		rookExists, err := position.board.GetPieceBitboard(rook).Occupied(rookSquare)
+		if err != nil {
+			return fmt.Errorf("0x%X.Occupied(%s): %w", position.board.GetPieceBitboard(rook), rookSquare, err)
+		}
+
+		if !rookExists || position.board.GetPieceBitboard(king) == BitboardNil {
+			colorSidesToDelete = append(colorSidesToDelete, colorSide)
+		}
+	}
+
+	position.castlingRights = slices.DeleteFunc(position.castlingRights, func(colorSide ColorSide) bool {
+		return slices.Contains(colorSidesToDelete, colorSide)
+	})
+
 	return nil
 }
 
@@ -163,37 +481,122 @@ func (position *Position) updateCastlingRightsRaw(move Move) error {
 		return fmt.Errorf("no piece on the origin %s", move.origin)
 	}
 
-	var colorSideToDelete ColorSide
-
-	if originPiece == PieceWhiteKing || move.origin == SquareA1 || move.dest == SquareA1 {
-		colorSideToDelete = ColorSideWhiteQueen
+	originColor, err := originPiece.Color()
+	if err != nil {
+		return fmt.Errorf("%s.Color(): %w", originPiece, err)
 	}
 
-	if originPiece == PieceWhiteKing || move.origin == SquareH1 || move.dest == SquareH1 {
-		colorSideToDelete = ColorSideWhiteKing
+	originRole, err := originPiece.Role()
+	if err != nil {
+		return fmt.Errorf("%s.Role(): %w", originPiece, err)
 	}
 
-	if originPiece == PieceBlackKing || move.origin == SquareA8 || move.dest == SquareA8 {
-		colorSideToDelete = ColorSideBlackQueen
-	}
+	// The right is lost if the king moves or the rook moves or is captured.
+	colorSidesToDelete := make(CastlingRights, 0, len(position.castlingRights))
+
+	for _, colorSide := range position.castlingRights {
+		color, err := colorSide.Color()
+		if err != nil {
+			return fmt.Errorf("%s.Color(): %w", colorSide, err)
+		}
 
-	if originPiece == PieceBlackKing || move.origin == SquareH8 || move.dest == SquareH8 {
-		colorSideToDelete = ColorSideBlackKing
+		rookSquare, err := position.CastlingRookSquare(colorSide)
+		if err != nil {
+			return fmt.Errorf("CastlingRookSquare(%s): %w", colorSide, err)
+		}
+
+		if (originRole == RoleKing && originColor == color) || move.origin == rookSquare || move.dest == rookSquare {
+			colorSidesToDelete = append(colorSidesToDelete, colorSide)
+		}
 	}
 
 	position.castlingRights = slices.DeleteFunc(position.castlingRights, func(colorSide ColorSide) bool {
-		return colorSide == colorSideToDelete
+		return slices.Contains(colorSidesToDelete, colorSide)
 	})
 
 	return nil
 }
 
+// updatePocketsRaw adds the piece captured by passed move to the pocket of the active color and moves the promoted
+// marks. Promoted pieces are added to the pocket as pawns.
+//
+// Note that the move is raw, so it can, for example, put the active color in check.
+func (position *Position) updatePocketsRaw(move Move) error {
+	capturedSquare := move.dest
+
+	if move.tags.Contains(MoveTagEnPassantCapture) {
+		rank, err := move.origin.Rank()
+		if err != nil {
+			return fmt.Errorf("%s.Rank(): %w", move.origin, err)
+		}
+
+		file, err := move.dest.File()
+		if err != nil {
+			return fmt.Errorf("%s.File(): %w", move.dest, err)
+		}
+
+		capturedSquare, err = NewSquare(rank, file)
+		if err != nil {
+			return fmt.Errorf("NewSquare(%s, %s): %w", rank, file, err)
+		}
+	}
+
+	captured, err := position.board.GetPieceFromSquare(capturedSquare)
+	if err != nil {
+		return fmt.Errorf("GetPieceFromSquare(%s): %w", capturedSquare, err)
+	}
+
+	if captured != PieceNil {
+		capturedRole, err := captured.Role()
+		if err != nil {
+			return fmt.Errorf("%s.Role(): %w", captured, err)
+		}
+
+		capturedPromoted, err := position.promotedBitboard.Occupied(capturedSquare)
+		if err != nil {
+			return fmt.Errorf("0x%X.Occupied(%s): %w", position.promotedBitboard, capturedSquare, err)
+		}
+
+		if capturedPromoted {
+			capturedRole = RolePawn
+		}
+
+		pocketPiece, err := NewPiece(position.activeColor, capturedRole)
+		if err != nil {
+			return fmt.Errorf("NewPiece(%s, %s): %w", position.activeColor, capturedRole, err)
+		}
+
+		position.pockets.add(pocketPiece)
+	}
+
+	originPromoted, err := position.promotedBitboard.Occupied(move.origin)
+	if err != nil {
+		return fmt.Errorf("0x%X.Occupied(%s): %w", position.promotedBitboard, move.origin, err)
+	}
+
+	position.promotedBitboard, err = position.promotedBitboard.UnsetSquares(move.origin, capturedSquare)
+	if err != nil {
+		return fmt.Errorf("0x%X.UnsetSquares(%s, %s): %w", position.promotedBitboard, move.origin, capturedSquare, err)
+	}
+
+	if originPromoted || move.promoRole != RoleNil {
+		position.promotedBitboard, err = position.promotedBitboard.SetSquares(move.dest)
+		if err != nil {
+			return fmt.Errorf("0x%X.SetSquares(%s): %w", position.promotedBitboard, move.dest, err)
+		}
+	}
+
+	return nil
+}
+
 // updateEnPassantSquareRaw updates En Passant square depending on the passed move.
 //
 // Note that the move is raw, so it can, for example, put the active color in check.
 //
 // TODO: test.
 func (position *Position) updateEnPassantSquareRaw(move Move) error {
+	position.enPassantSquare = SquareNil
+
 	piece, err := position.board.GetPieceFromSquare(move.origin)
 	if err != nil {
 		return fmt.Errorf("GetPieceFromSquare(%s): %w", move.origin, err)
@@ -250,6 +653,8 @@ func (position *Position) updateEnPassantSquareRaw(move Move) error {
 
 // updateFullMoveNumber updates full move number.
 //
+// Note that the number is updated before the active color update, so it is incremented after the black move.
+//
 // TODO: test.
 func (position *Position) updateFullMoveNumber() {
 	if position.activeColor == ColorBlack {
@@ -278,6 +683,7 @@ func (position *Position) updateHalfMoveClockRaw(move Move) error {
 	}
 
 	if originPieceRole == RolePawn || move.tags.Contains(MoveTagCapture) {
+		position.halfMoveClock = 0
 		return nil
 	}
 
@@ -3,6 +3,7 @@ package position
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -63,6 +64,18 @@ func TestNewPositionFromFEN(t *testing.T) {
 			nil,
 			"NewBoardFromFEN(\"rnbqkbnr/ppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR\"): invalid files count in part #1",
 		},
+		{
+			"unclosed pockets",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR[Qn w KQkq - 0 1",
+			nil,
+			"pockets are not closed",
+		},
+		{
+			"invalid pockets",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR[Kn] w KQkq - 0 1",
+			nil,
+			"NewPocketsFromFEN(\"Kn\"): byte #0, king cannot be in the pocket",
+		},
 		{
 			"invalid active color",
 			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
@@ -73,7 +86,7 @@ func TestNewPositionFromFEN(t *testing.T) {
 			"invalid castling rights",
 			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQQq - 0 1",
 			nil,
-			"NewCastlingRightsFromFEN(\"KQQq\"): duplicate of 'Q' found",
+			"NewRightsFromFEN(\"KQQq\"): duplicate of 'Q' found",
 		},
 		{
 			"invalid En Passant",
@@ -110,3 +123,149 @@ func TestNewPositionFromFEN(t *testing.T) {
 		})
 	}
 }
+
+func TestPositionFEN(t *testing.T) {
+	t.Parallel()
+
+	fens := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2",
+		"4k3/8/8/8/8/8/8/4K3 b - - 12 40",
+		"1r2k1r1/8/8/8/8/8/8/1R2K1R1 w GBgb - 0 1",
+		"r1bqkbnr/pppp1ppp/2n5/8/8/8/PPPP1PPP/RNBQKB1R[Pn] w KQkq - 0 4",
+		"rnbqkQ~1r/ppp3pp/8/8/8/8/PPPP2PP/RNBQKBNR[] b KQq - 0 7",
+	}
+
+	for _, fen := range fens {
+		t.Run(fen, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q) expected no error but got %v", fen, err)
+			}
+
+			gotFEN, err := position.FEN()
+			if err != nil {
+				t.Fatalf("FEN() expected no error but got %v", err)
+			}
+
+			if gotFEN != fen {
+				t.Fatalf("FEN() expected %q but got %q", fen, gotFEN)
+			}
+		})
+	}
+}
+
+func TestPositionMoveRawCastleChess960(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		origin   Square
+		dest     Square
+		tag      MoveTag
+		boardFEN string
+	}{
+		{"king side", SquareC1, SquareG1, MoveTagKingSideCastle, "1rk3r1/8/8/8/8/8/8/1R3RK1"},
+		{"queen side", SquareC1, SquareC1, MoveTagQueenSideCastle, "1rk3r1/8/8/8/8/8/8/2KR2R1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN("1rk3r1/8/8/8/8/8/8/1RK3R1 w KQkq - 0 1")
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(): %v", err)
+			}
+
+			var tags MoveTags
+			tags.Set(test.tag)
+
+			if err := position.MoveRaw(NewMove(test.origin, test.dest, tags, RoleNil)); err != nil {
+				t.Fatalf("MoveRaw(): %v", err)
+			}
+
+			board, err := NewBoardFromFEN(test.boardFEN)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.boardFEN, err)
+			}
+
+			if !position.Board().Equals(board) {
+				t.Fatalf("MoveRaw() expected board %q", test.boardFEN)
+			}
+
+			rights := CastlingRights{ColorSideBlackKing, ColorSideBlackQueen}
+			if !reflect.DeepEqual(position.CastlingRights(), rights) {
+				t.Fatalf("MoveRaw() expected castling rights %+v but got %+v", rights, position.CastlingRights())
+			}
+		})
+	}
+}
+
+func TestPositionMoveAtomicRaw(t *testing.T) {
+	t.Parallel()
+
+	var captureTags MoveTags
+	captureTags.Set(MoveTagCapture)
+
+	tests := []struct {
+		name     string
+		fen      string
+		move     Move
+		boardFEN string
+		rights   CastlingRights
+	}{
+		{
+			"quiet",
+			"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+			NewMove(SquareA1, SquareA2, MoveTagsNil, RoleNil),
+			"r3k2r/8/8/8/8/8/R7/4K2R",
+			CastlingRights{ColorSideWhiteKing, ColorSideBlackKing, ColorSideBlackQueen},
+		},
+		{
+			"explosion",
+			"r3k2r/6p1/8/8/8/8/8/R3K2R w KQkq - 0 1",
+			NewMove(SquareH1, SquareH8, captureTags, RoleNil),
+			"r3k3/6p1/8/8/8/8/8/R3K3",
+			CastlingRights{ColorSideWhiteQueen, ColorSideBlackQueen},
+		},
+		{
+			"king explosion",
+			"4k3/3pn3/8/8/1B6/8/8/4K3 w - - 0 1",
+			NewMove(SquareB4, SquareE7, captureTags, RoleNil),
+			"8/3p4/8/8/8/8/8/4K3",
+			CastlingRights{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			position, err := NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			if err := position.MoveAtomicRaw(test.move); err != nil {
+				t.Fatalf("MoveAtomicRaw(%+v): %v", test.move, err)
+			}
+
+			board, err := NewBoardFromFEN(test.boardFEN)
+			if err != nil {
+				t.Fatalf("NewBoardFromFEN(%q): %v", test.boardFEN, err)
+			}
+
+			if !position.Board().Equals(board) {
+				t.Fatalf("MoveAtomicRaw(%+v) expected board %q", test.move, test.boardFEN)
+			}
+
+			if !slices.Equal(position.CastlingRights(), test.rights) {
+				t.Fatalf("MoveAtomicRaw(%+v) expected castling rights %+v but got %+v",
+					test.move, test.rights, position.CastlingRights())
+			}
+		})
+	}
+}
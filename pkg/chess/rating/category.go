@@ -0,0 +1,69 @@
+package rating
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+)
+
+const (
+	// Count of moves, which is used to estimate the duration of the game.
+	categoryEstimatedMoves = 40
+
+	// Upper bounds of the estimated game durations of the categories.
+	categoryBulletMaxDuration = 3 * time.Minute
+	categoryBlitzMaxDuration  = 8 * time.Minute
+	categoryRapidMaxDuration  = 25 * time.Minute
+)
+
+// Category is the time control category, which has its own rating.
+type Category uint8
+
+const (
+	CategoryNil Category = iota
+	CategoryBullet
+	CategoryBlitz
+	CategoryRapid
+	CategoryClassical
+)
+
+// NewCategoryFromTimeControl determines the category of passed time control by the estimated duration of the game of
+// 40 moves: the time of the first period plus 40 increments and delays.
+func NewCategoryFromTimeControl(control clock.TimeControl) (Category, error) {
+	if len(control.Periods) == 0 {
+		return CategoryNil, fmt.Errorf("no periods in %+v", control)
+	}
+
+	period := control.Periods[0]
+	duration := period.Time + categoryEstimatedMoves*(period.Increment+control.Delay)
+
+	switch {
+	case duration < categoryBulletMaxDuration:
+		return CategoryBullet, nil
+	case duration < categoryBlitzMaxDuration:
+		return CategoryBlitz, nil
+	case duration < categoryRapidMaxDuration:
+		return CategoryRapid, nil
+	default:
+		return CategoryClassical, nil
+	}
+}
+
+// String returns string representation of current category.
+func (category Category) String() string {
+	switch category {
+	case CategoryNil:
+		return "CategoryNil"
+	case CategoryBullet:
+		return "CategoryBullet"
+	case CategoryBlitz:
+		return "CategoryBlitz"
+	case CategoryRapid:
+		return "CategoryRapid"
+	case CategoryClassical:
+		return "CategoryClassical"
+	default:
+		return fmt.Sprintf("<unknown Category=%d>", category)
+	}
+}
@@ -0,0 +1,52 @@
+package rating
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/clock"
+)
+
+func TestNewCategoryFromTimeControl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		control   clock.TimeControl
+		category  Category
+		errString string
+	}{
+		{"1+0", clock.NewTimeControlSuddenDeath(time.Minute), CategoryBullet, ""},
+		{"2+1", clock.NewTimeControlFischer(2*time.Minute, time.Second), CategoryBullet, ""},
+		{"3+0", clock.NewTimeControlSuddenDeath(3 * time.Minute), CategoryBlitz, ""},
+		{"5+3", clock.NewTimeControlFischer(5*time.Minute, 3*time.Second), CategoryBlitz, ""},
+		{"10+0 delay 5", clock.NewTimeControlSimpleDelay(10*time.Minute, 5*time.Second), CategoryRapid, ""},
+		{"15+10", clock.NewTimeControlFischer(15*time.Minute, 10*time.Second), CategoryRapid, ""},
+		{"30+0", clock.NewTimeControlSuddenDeath(30 * time.Minute), CategoryClassical, ""},
+		{
+			"40/90+30",
+			clock.TimeControl{Periods: []clock.Period{
+				{Moves: 40, Time: 90 * time.Minute, Increment: 30 * time.Second},
+				{Time: 30 * time.Minute, Increment: 30 * time.Second},
+			}},
+			CategoryClassical,
+			"",
+		},
+		{"no periods", clock.TimeControl{}, CategoryNil, "no periods in {Periods:[] Delay:0s DelayMode:DelayModeNil}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			category, err := NewCategoryFromTimeControl(test.control)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewCategoryFromTimeControl(%+v) expected error %q but got %q", test.control, test.errString, err)
+			}
+
+			if category != test.category {
+				t.Fatalf("NewCategoryFromTimeControl(%+v) expected %s but got %s", test.control, test.category, category)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// Rating, deviation and volatility of the new player.
+	DefaultRating     = 1500
+	DefaultDeviation  = 350
+	DefaultVolatility = 0.06
+
+	// DefaultTau is the system constant, which constrains the change of the volatility.
+	DefaultTau = 0.5
+
+	// ProvisionalDeviation is the deviation, above which the rating is provisional.
+	ProvisionalDeviation = 110
+
+	// Ratio between the Glicko and the Glicko-2 scales.
+	glickoScale = 173.7178
+
+	// Convergence tolerance of the volatility iteration.
+	glickoEpsilon = 0.000001
+
+	// Maximum count of the volatility iterations, which protects from the infinite loop on the invalid input.
+	glickoMaxIterations = 100
+)
+
+// Rating is the Glicko-2 rating of the player in the Glicko scale.
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// NewRating creates the rating of the new player.
+func NewRating() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+// Provisional checks that the rating is not reliable yet because of its high deviation.
+func (rating Rating) Provisional() bool {
+	return rating.Deviation > ProvisionalDeviation
+}
+
+// Outcome is the result of one game of the rating period.
+type Outcome struct {
+	// Rating of the opponent at the start of the rating period.
+	Opponent Rating
+	// Score of the player: 1 for the win, 0.5 for the draw and 0 for the loss.
+	Score float64
+}
+
+// System calculates the Glicko-2 rating updates.
+type System struct {
+	// Tau is the system constant. Zero means DefaultTau.
+	Tau float64
+}
+
+// CalcExpectedScore calculates the expected score of the player with passed rating against passed opponent.
+func (system System) CalcExpectedScore(rating, opponent Rating) float64 {
+	mu, _ := toGlicko2(rating)
+	opponentMu, opponentPhi := toGlicko2(opponent)
+
+	return calcExpectedScore(mu, opponentMu, opponentPhi)
+}
+
+// Update calculates the rating after the rating period with passed outcomes. The deviation of the player without
+// games grows and is capped at DefaultDeviation.
+func (system System) Update(rating Rating, outcomes []Outcome) (Rating, error) {
+	if rating.Deviation <= 0 || rating.Volatility <= 0 {
+		return Rating{}, errors.New("non-positive deviation or volatility")
+	}
+
+	mu, phi := toGlicko2(rating)
+
+	if len(outcomes) == 0 {
+		phi = math.Min(math.Sqrt(phi*phi+rating.Volatility*rating.Volatility), DefaultDeviation/glickoScale)
+		return fromGlicko2(mu, phi, rating.Volatility), nil
+	}
+
+	var variance, improvement float64
+
+	for index, outcome := range outcomes {
+		if outcome.Score < 0 || outcome.Score > 1 {
+			return Rating{}, fmt.Errorf("outcome #%d has invalid score %f", index, outcome.Score)
+		}
+
+		opponentMu, opponentPhi := toGlicko2(outcome.Opponent)
+		g := calcG(opponentPhi)
+		expected := calcExpectedScore(mu, opponentMu, opponentPhi)
+
+		variance += g * g * expected * (1 - expected)
+		improvement += g * (outcome.Score - expected)
+	}
+
+	variance = 1 / variance
+	delta := variance * improvement
+
+	volatility, err := system.calcVolatility(phi, rating.Volatility, variance, delta)
+	if err != nil {
+		return Rating{}, fmt.Errorf("calcVolatility(): %w", err)
+	}
+
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	newMu := mu + newPhi*newPhi*improvement
+
+	return fromGlicko2(newMu, newPhi, volatility), nil
+}
+
+// calcVolatility calculates the new volatility using the Illinois algorithm as described in the Glicko-2 paper.
+func (system System) calcVolatility(phi, volatility, variance, delta float64) (float64, error) {
+	tau := system.Tau
+	if tau == 0 {
+		tau = DefaultTau
+	}
+
+	a := math.Log(volatility * volatility)
+	f := func(x float64) float64 {
+		exp := math.Exp(x)
+		numerator := exp * (delta*delta - phi*phi - variance - exp)
+		denominator := phi*phi + variance + exp
+
+		return numerator/(2*denominator*denominator) - (x-a)/(tau*tau) //nolint:mnd // Formula.
+	}
+
+	lower := a
+
+	var upper float64
+
+	if delta*delta > phi*phi+variance {
+		upper = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+
+		upper = a - k*tau
+	}
+
+	fLower, fUpper := f(lower), f(upper)
+
+	for range glickoMaxIterations {
+		if math.Abs(upper-lower) <= glickoEpsilon {
+			return math.Exp(lower / 2), nil //nolint:mnd // Square root.
+		}
+
+		middle := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fMiddle := f(middle)
+
+		if fMiddle*fUpper <= 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2 //nolint:mnd // Illinois step.
+		}
+
+		upper, fUpper = middle, fMiddle
+	}
+
+	return 0, errors.New("volatility does not converge")
+}
+
+// calcG reduces the impact of the game against the opponent with passed deviation in the Glicko-2 scale.
+func calcG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi)) //nolint:mnd // Formula.
+}
+
+// calcExpectedScore calculates the expected score in the Glicko-2 scale.
+func calcExpectedScore(mu, opponentMu, opponentPhi float64) float64 {
+	return 1 / (1 + math.Exp(-calcG(opponentPhi)*(mu-opponentMu)))
+}
+
+// toGlicko2 converts the rating and the deviation to the Glicko-2 scale.
+func toGlicko2(rating Rating) (float64, float64) {
+	return (rating.Rating - DefaultRating) / glickoScale, rating.Deviation / glickoScale
+}
+
+// fromGlicko2 converts the rating and the deviation from the Glicko-2 scale.
+func fromGlicko2(mu, phi, volatility float64) Rating {
+	return Rating{Rating: mu*glickoScale + DefaultRating, Deviation: phi * glickoScale, Volatility: volatility}
+}
@@ -0,0 +1,86 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSystemUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rating   Rating
+		outcomes []Outcome
+		expected Rating
+	}{
+		{
+			// Example from the Glicko-2 paper.
+			"paper",
+			Rating{Rating: 1500, Deviation: 200, Volatility: 0.06},
+			[]Outcome{
+				{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: 1},
+				{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: 0},
+				{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: 0},
+			},
+			Rating{Rating: 1464.05, Deviation: 151.52, Volatility: 0.05999},
+		},
+		{
+			"inactivity",
+			Rating{Rating: 1800, Deviation: 50, Volatility: 0.06},
+			nil,
+			Rating{Rating: 1800, Deviation: 51.07, Volatility: 0.06},
+		},
+		{"inactivity cap", NewRating(), nil, NewRating()},
+		{
+			"draw of equals",
+			NewRating(),
+			[]Outcome{{Opponent: NewRating(), Score: 0.5}},
+			Rating{Rating: 1500, Deviation: 290.32, Volatility: 0.06},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			rating, err := System{}.Update(test.rating, test.outcomes)
+			if err != nil {
+				t.Fatalf("Update(%+v): %v", test.rating, err)
+			}
+
+			if math.Abs(rating.Rating-test.expected.Rating) > 0.01 ||
+				math.Abs(rating.Deviation-test.expected.Deviation) > 0.01 ||
+				math.Abs(rating.Volatility-test.expected.Volatility) > 0.00001 {
+				t.Fatalf("Update(%+v) expected %+v but got %+v", test.rating, test.expected, rating)
+			}
+		})
+	}
+}
+
+func TestSystemUpdateInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := (System{}).Update(Rating{Rating: 1500}, nil); err == nil {
+		t.Fatal("Update() expected error on zero deviation")
+	}
+
+	outcomes := []Outcome{{Opponent: NewRating(), Score: 2}}
+	errString := "outcome #0 has invalid score 2.000000"
+
+	if _, err := (System{}).Update(NewRating(), outcomes); err == nil || err.Error() != errString {
+		t.Fatalf("Update() expected error %q but got %q", errString, err)
+	}
+}
+
+func TestRatingProvisional(t *testing.T) {
+	t.Parallel()
+
+	if !NewRating().Provisional() {
+		t.Fatal("Provisional() expected new rating to be provisional")
+	}
+
+	if (Rating{Rating: 1500, Deviation: 80, Volatility: DefaultVolatility}).Provisional() {
+		t.Fatal("Provisional() expected rating with low deviation to be established")
+	}
+}
@@ -0,0 +1,262 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+// Scores of the game results.
+const (
+	scoreWin  = 1
+	scoreDraw = 0.5
+	scoreLoss = 0
+)
+
+// PlayerPreview is shown to the player before the rated game.
+type PlayerPreview struct {
+	Rating Rating
+	// Expected score of the player against the opponent.
+	Expected float64
+	// Rating changes after the win, the draw and the loss if the game is the only one in the rating period.
+	Win  float64
+	Draw float64
+	Loss float64
+}
+
+// Preview is shown to the players before the rated game.
+type Preview struct {
+	White PlayerPreview
+	Black PlayerPreview
+}
+
+// Pool keeps the ratings of the players per category and the outcomes of the current rating period. It is safe for
+// concurrent use.
+type Pool struct {
+	system System
+
+	mu       sync.Mutex
+	ratings  map[Category]map[string]Rating
+	outcomes map[Category]map[string][]Outcome
+}
+
+// NewPool creates a new Pool without players, which uses passed system to update the ratings.
+func NewPool(system System) *Pool {
+	return &Pool{
+		system:   system,
+		ratings:  make(map[Category]map[string]Rating),
+		outcomes: make(map[Category]map[string][]Outcome),
+	}
+}
+
+// Rating returns the rating of passed player in passed category. The new player has NewRating.
+func (pool *Pool) Rating(category Category, player string) Rating {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.getRatingLocked(category, player)
+}
+
+// SetRating sets the rating of passed player in passed category, for example, after the loading from the storage.
+func (pool *Pool) SetRating(category Category, player string, rating Rating) error {
+	if err := checkCategory(category); err != nil {
+		return fmt.Errorf("checkCategory(%s): %w", category, err)
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.ratings[category] == nil {
+		pool.ratings[category] = make(map[string]Rating)
+	}
+
+	pool.ratings[category][player] = rating
+
+	return nil
+}
+
+// AddGame adds the outcomes of the ended rated game to the current rating period. The ratings are updated on
+// ClosePeriod.
+func (pool *Pool) AddGame(
+	category Category, white, black string, result game.Result, termination game.Termination,
+) error {
+	if err := checkCategory(category); err != nil {
+		return fmt.Errorf("checkCategory(%s): %w", category, err)
+	}
+
+	if white == black {
+		return errors.New("same players")
+	}
+
+	whiteScore, err := NewScoreFromResult(result, termination)
+	if err != nil {
+		return fmt.Errorf("NewScoreFromResult(%s, %s): %w", result, termination, err)
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	whiteRating, blackRating := pool.getRatingLocked(category, white), pool.getRatingLocked(category, black)
+
+	if pool.outcomes[category] == nil {
+		pool.outcomes[category] = make(map[string][]Outcome)
+	}
+
+	outcomes := pool.outcomes[category]
+	outcomes[white] = append(outcomes[white], Outcome{Opponent: blackRating, Score: whiteScore})
+	outcomes[black] = append(outcomes[black], Outcome{Opponent: whiteRating, Score: scoreWin - whiteScore})
+
+	return nil
+}
+
+// ClosePeriod updates the ratings of all players using the outcomes of the current rating period and starts the next
+// period. The deviations of the players without games grow.
+func (pool *Pool) ClosePeriod() error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	ratings := make(map[Category]map[string]Rating, len(pool.ratings))
+
+	for category := range pool.calcCategoriesLocked() {
+		ratings[category] = make(map[string]Rating)
+
+		for player := range pool.calcPlayersLocked(category) {
+			rating, err := pool.system.Update(pool.getRatingLocked(category, player), pool.outcomes[category][player])
+			if err != nil {
+				return fmt.Errorf("%s of %q, Update(): %w", category, player, err)
+			}
+
+			ratings[category][player] = rating
+		}
+	}
+
+	pool.ratings = ratings
+	pool.outcomes = make(map[Category]map[string][]Outcome)
+
+	return nil
+}
+
+// Preview calculates the expected scores and the rating changes of the players before the game in passed category.
+func (pool *Pool) Preview(category Category, white, black string) (Preview, error) {
+	if err := checkCategory(category); err != nil {
+		return Preview{}, fmt.Errorf("checkCategory(%s): %w", category, err)
+	}
+
+	pool.mu.Lock()
+	whiteRating, blackRating := pool.getRatingLocked(category, white), pool.getRatingLocked(category, black)
+	pool.mu.Unlock()
+
+	whitePreview, err := pool.calcPlayerPreview(whiteRating, blackRating)
+	if err != nil {
+		return Preview{}, fmt.Errorf("calcPlayerPreview(white): %w", err)
+	}
+
+	blackPreview, err := pool.calcPlayerPreview(blackRating, whiteRating)
+	if err != nil {
+		return Preview{}, fmt.Errorf("calcPlayerPreview(black): %w", err)
+	}
+
+	return Preview{White: whitePreview, Black: blackPreview}, nil
+}
+
+// calcPlayerPreview calculates the preview of the player with passed rating against passed opponent.
+func (pool *Pool) calcPlayerPreview(rating, opponent Rating) (PlayerPreview, error) {
+	preview := PlayerPreview{Rating: rating, Expected: pool.system.CalcExpectedScore(rating, opponent)}
+
+	for _, change := range [...]struct {
+		score float64
+		diff  *float64
+	}{
+		{scoreWin, &preview.Win},
+		{scoreDraw, &preview.Draw},
+		{scoreLoss, &preview.Loss},
+	} {
+		updated, err := pool.system.Update(rating, []Outcome{{Opponent: opponent, Score: change.score}})
+		if err != nil {
+			return PlayerPreview{}, fmt.Errorf("Update(%f): %w", change.score, err)
+		}
+
+		*change.diff = updated.Rating - rating.Rating
+	}
+
+	return preview, nil
+}
+
+// getRatingLocked returns the rating of passed player or NewRating if the player is new.
+//
+// The pool must be locked.
+func (pool *Pool) getRatingLocked(category Category, player string) Rating {
+	if rating, ok := pool.ratings[category][player]; ok {
+		return rating
+	}
+
+	return NewRating()
+}
+
+// calcCategoriesLocked returns the categories with the ratings or the outcomes.
+//
+// The pool must be locked.
+func (pool *Pool) calcCategoriesLocked() map[Category]struct{} {
+	categories := make(map[Category]struct{})
+
+	for category := range pool.ratings {
+		categories[category] = struct{}{}
+	}
+
+	for category := range pool.outcomes {
+		categories[category] = struct{}{}
+	}
+
+	return categories
+}
+
+// calcPlayersLocked returns the players of passed category with the ratings or the outcomes.
+//
+// The pool must be locked.
+func (pool *Pool) calcPlayersLocked(category Category) map[string]struct{} {
+	players := make(map[string]struct{})
+
+	for player := range pool.ratings[category] {
+		players[player] = struct{}{}
+	}
+
+	for player := range pool.outcomes[category] {
+		players[player] = struct{}{}
+	}
+
+	return players
+}
+
+// NewScoreFromResult returns the score of white in the game with passed result. The game must be terminated.
+func NewScoreFromResult(result game.Result, termination game.Termination) (float64, error) {
+	if termination == game.TerminationNil {
+		return 0, errors.New("game is not terminated")
+	}
+
+	switch result {
+	case game.ResultWhiteWon:
+		return scoreWin, nil
+	case game.ResultBlackWon:
+		return scoreLoss, nil
+	case game.ResultDraw:
+		return scoreDraw, nil
+	case game.ResultNil:
+		return 0, errors.New("no result")
+	default:
+		return 0, errors.New("unknown result")
+	}
+}
+
+// checkCategory checks that passed category is known.
+func checkCategory(category Category) error {
+	switch category {
+	case CategoryBullet, CategoryBlitz, CategoryRapid, CategoryClassical:
+		return nil
+	case CategoryNil:
+		return errors.New("no category")
+	default:
+		return errors.New("unknown category")
+	}
+}
@@ -0,0 +1,133 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestPoolClosePeriod(t *testing.T) {
+	t.Parallel()
+
+	pool := NewPool(System{})
+
+	if err := pool.SetRating(CategoryBlitz, "carol", Rating{Rating: 1700, Deviation: 60, Volatility: 0.06}); err != nil {
+		t.Fatalf("SetRating(carol): %v", err)
+	}
+
+	games := []struct {
+		white       string
+		black       string
+		result      game.Result
+		termination game.Termination
+	}{
+		{"alice", "bob", game.ResultWhiteWon, game.TerminationCheckmate},
+		{"bob", "alice", game.ResultDraw, game.TerminationRepetition},
+		{"alice", "bob", game.ResultBlackWon, game.TerminationTimeout},
+	}
+
+	for _, g := range games {
+		if err := pool.AddGame(CategoryBlitz, g.white, g.black, g.result, g.termination); err != nil {
+			t.Fatalf("AddGame(%s, %s): %v", g.white, g.black, err)
+		}
+	}
+
+	if err := pool.AddGame(CategoryBullet, "alice", "bob", game.ResultWhiteWon, game.TerminationCheckmate); err != nil {
+		t.Fatalf("AddGame(bullet): %v", err)
+	}
+
+	if rating := pool.Rating(CategoryBlitz, "alice"); rating != NewRating() {
+		t.Fatalf("Rating(alice) expected new rating before the period end but got %+v", rating)
+	}
+
+	if err := pool.ClosePeriod(); err != nil {
+		t.Fatalf("ClosePeriod(): %v", err)
+	}
+
+	alice, bob := pool.Rating(CategoryBlitz, "alice"), pool.Rating(CategoryBlitz, "bob")
+	if math.Abs(alice.Rating-DefaultRating) > 0.01 || math.Abs(bob.Rating-DefaultRating) > 0.01 ||
+		alice.Deviation >= DefaultDeviation || !alice.Provisional() {
+		t.Fatalf("Rating() expected equal provisional ratings after the even score but got %+v and %+v", alice, bob)
+	}
+
+	if bullet := pool.Rating(CategoryBullet, "alice"); bullet.Rating <= DefaultRating {
+		t.Fatalf("Rating(bullet) expected increased rating after the win but got %+v", bullet)
+	}
+
+	if carol := pool.Rating(CategoryBlitz, "carol"); carol.Rating != 1700 || carol.Deviation <= 60 {
+		t.Fatalf("Rating(carol) expected increased deviation without games but got %+v", carol)
+	}
+}
+
+func TestPoolAddGame(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		category    Category
+		white       string
+		result      game.Result
+		termination game.Termination
+		errString   string
+	}{
+		{"valid", CategoryRapid, "alice", game.ResultDraw, game.TerminationStalemate, ""},
+		{
+			"no category",
+			CategoryNil,
+			"alice",
+			game.ResultDraw,
+			game.TerminationStalemate,
+			"checkCategory(CategoryNil): no category",
+		},
+		{"same players", CategoryRapid, "bob", game.ResultDraw, game.TerminationStalemate, "same players"},
+		{
+			"not terminated",
+			CategoryRapid,
+			"alice",
+			game.ResultNil,
+			game.TerminationNil,
+			"NewScoreFromResult(ResultNil, TerminationNil): game is not terminated",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewPool(System{}).AddGame(test.category, test.white, "bob", test.result, test.termination)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("AddGame() expected error %q but got %q", test.errString, err)
+			}
+		})
+	}
+}
+
+func TestPoolPreview(t *testing.T) {
+	t.Parallel()
+
+	pool := NewPool(System{})
+
+	if err := pool.SetRating(CategoryBlitz, "alice", Rating{Rating: 1800, Deviation: 80, Volatility: 0.06}); err != nil {
+		t.Fatalf("SetRating(alice): %v", err)
+	}
+
+	preview, err := pool.Preview(CategoryBlitz, "alice", "bob")
+	if err != nil {
+		t.Fatalf("Preview(): %v", err)
+	}
+
+	if math.Abs(preview.White.Expected+preview.Black.Expected-1) > 0.2 || preview.White.Expected <= 0.5 {
+		t.Fatalf("Preview() expected favourite white but got %+v", preview)
+	}
+
+	for _, player := range [...]PlayerPreview{preview.White, preview.Black} {
+		if player.Win <= player.Draw || player.Draw <= player.Loss || player.Win <= 0 || player.Loss >= 0 {
+			t.Fatalf("Preview() expected ordered rating changes but got %+v", player)
+		}
+	}
+
+	if preview.White.Draw >= 0 || preview.Black.Draw <= 0 || !preview.Black.Rating.Provisional() {
+		t.Fatalf("Preview() expected draw to favour provisional black but got %+v", preview)
+	}
+}
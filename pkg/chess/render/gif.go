@@ -0,0 +1,324 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+const (
+	// Size of the square side in pixels if it is not set in the options.
+	gifDefaultSquareSize = 48
+
+	// Delay between the frames if it is not set in the options.
+	gifDefaultDelay = time.Second
+
+	// Side of the piece sprite in sprite pixels.
+	gifSpriteSize = 12
+)
+
+// Indexes of the colors in the GIF palette.
+const (
+	gifColorLightSquare uint8 = iota
+	gifColorDarkSquare
+	gifColorLightLastMove
+	gifColorDarkLastMove
+	gifColorCheck
+	gifColorWhitePiece
+	gifColorBlackPiece
+)
+
+var (
+	// Palette of all GIF frames, indexed by the gifColor constants.
+	gifPalette = color.Palette{
+		color.RGBA{R: 0xf0, G: 0xd9, B: 0xb5, A: 0xff},
+		color.RGBA{R: 0xb5, G: 0x88, B: 0x63, A: 0xff},
+		color.RGBA{R: 0xcd, G: 0xd2, B: 0x6a, A: 0xff},
+		color.RGBA{R: 0xaa, G: 0xa2, B: 0x3a, A: 0xff},
+		color.RGBA{R: 0xe0, G: 0x40, B: 0x40, A: 0xff},
+		color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+	}
+
+	// Pixel sprites of the pieces, where "#" is the body of the piece.
+	gifSprites = map[piece.Role][gifSpriteSize]string{
+		piece.RoleKing: {
+			".....##.....",
+			"....####....",
+			".....##.....",
+			"..##.##.##..",
+			".##########.",
+			".##########.",
+			"..########..",
+			"...######...",
+			"...######...",
+			"..########..",
+			".##########.",
+			"............",
+		},
+		piece.RoleQueen: {
+			".#...##...#.",
+			".##..##..##.",
+			".###.##.###.",
+			".##########.",
+			"..########..",
+			"..########..",
+			"...######...",
+			"...######...",
+			"..########..",
+			"..########..",
+			".##########.",
+			"............",
+		},
+		piece.RoleRook: {
+			"............",
+			"..##.##.##..",
+			"..########..",
+			"...######...",
+			"...######...",
+			"...######...",
+			"...######...",
+			"...######...",
+			"..########..",
+			"..########..",
+			".##########.",
+			"............",
+		},
+		piece.RoleBishop: {
+			"............",
+			".....##.....",
+			"....####....",
+			"...##.###...",
+			"...#.####...",
+			"...######...",
+			"....####....",
+			".....##.....",
+			"....####....",
+			"...######...",
+			"..########..",
+			"............",
+		},
+		piece.RoleKnight: {
+			"............",
+			".....#.#....",
+			"....######..",
+			"...########.",
+			"..####.####.",
+			"..###..####.",
+			".......####.",
+			"......#####.",
+			".....#####..",
+			"....######..",
+			"...########.",
+			"............",
+		},
+		piece.RolePawn: {
+			"............",
+			"............",
+			".....##.....",
+			"....####....",
+			"....####....",
+			".....##.....",
+			"....####....",
+			".....##.....",
+			"....####....",
+			"...######...",
+			"..########..",
+			"............",
+		},
+	}
+)
+
+// GIFOptions configures the GIF rendering of the game.
+//
+// Zero value renders the game from the white perspective with 48 pixels squares and one second between moves.
+type GIFOptions struct {
+	// Size of the square side in pixels. It is rounded down to the multiple of 12 pixels. Zero means 48.
+	SquareSize uint
+	// Render the board from the black perspective.
+	Flipped bool
+	// Delay between the frames. Zero means one second.
+	Delay time.Duration
+}
+
+// GIF writes passed game as animated GIF image, where each frame is the position after the next move. The last move
+// and the king in check are highlighted.
+func (renderer *Renderer) GIF(writer io.Writer, g *game.Game, options GIFOptions) error {
+	squareSize := int(options.SquareSize) / gifSpriteSize * gifSpriteSize
+	if options.SquareSize == 0 {
+		squareSize = gifDefaultSquareSize
+	}
+
+	if squareSize == 0 {
+		return fmt.Errorf("square size %d is less than %d", options.SquareSize, gifSpriteSize)
+	}
+
+	delay := options.Delay
+	if delay == 0 {
+		delay = gifDefaultDelay
+	}
+
+	positions, moves := g.Positions(), g.Moves()
+	if len(positions) == 0 {
+		return errors.New("no positions")
+	}
+
+	animation := &gif.GIF{
+		Image: make([]*image.Paletted, 0, len(positions)),
+		Delay: make([]int, 0, len(positions)),
+	}
+
+	for index, pos := range positions {
+		var lastMove move.Move
+		if index > 0 && index <= len(moves) {
+			lastMove = moves[index-1]
+		}
+
+		frame, err := renderer.drawGIFFrame(pos, lastMove, squareSize, options.Flipped)
+		if err != nil {
+			return fmt.Errorf("position #%d, drawGIFFrame(): %w", index, err)
+		}
+
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, int(delay/(10*time.Millisecond)))
+	}
+
+	if err := gif.EncodeAll(writer, animation); err != nil {
+		return fmt.Errorf("EncodeAll(): %w", err)
+	}
+
+	return nil
+}
+
+// drawGIFFrame draws passed position with passed last move highlighted.
+func (renderer *Renderer) drawGIFFrame(
+	pos *position.Position, lastMove move.Move, squareSize int, flipped bool,
+) (*image.Paletted, error) {
+	boardSize := squareSize * len(square.Files)
+	frame := image.NewPaletted(image.Rect(0, 0, boardSize, boardSize), gifPalette)
+
+	checkedKingSquare, err := renderer.calcCheckedKingSquare(pos)
+	if err != nil {
+		return nil, fmt.Errorf("calcCheckedKingSquare(): %w", err)
+	}
+
+	lastMoveSquares := calcMoveSquares(lastMove)
+
+	for _, sq := range calcAllSquares() {
+		column, row, err := calcSquareCell(sq, flipped)
+		if err != nil {
+			return nil, fmt.Errorf("calcSquareCell(%s): %w", sq, err)
+		}
+
+		dark := (column+row)%2 == 1
+
+		colorIndex := gifColorLightSquare
+
+		switch {
+		case sq == checkedKingSquare:
+			colorIndex = gifColorCheck
+		case slices.Contains(lastMoveSquares, sq) && dark:
+			colorIndex = gifColorDarkLastMove
+		case slices.Contains(lastMoveSquares, sq):
+			colorIndex = gifColorLightLastMove
+		case dark:
+			colorIndex = gifColorDarkSquare
+		}
+
+		cell := image.Rect(column*squareSize, row*squareSize, (column+1)*squareSize, (row+1)*squareSize)
+		fillGIFRect(frame, cell, colorIndex)
+
+		p, err := pos.Board().GetPieceFromSquare(sq)
+		if err != nil {
+			return nil, fmt.Errorf("GetPieceFromSquare(%s): %w", sq, err)
+		}
+
+		if p == piece.PieceNil {
+			continue
+		}
+
+		if err := drawGIFPiece(frame, cell, p); err != nil {
+			return nil, fmt.Errorf("drawGIFPiece(%s): %w", p, err)
+		}
+	}
+
+	return frame, nil
+}
+
+// drawGIFPiece draws the sprite of passed piece in passed cell. The body has the piece color and the outline has the
+// opposite color.
+func drawGIFPiece(frame *image.Paletted, cell image.Rectangle, p piece.Piece) error {
+	role, err := p.Role()
+	if err != nil {
+		return fmt.Errorf("%s.Role(): %w", p, err)
+	}
+
+	pieceColor, err := p.Color()
+	if err != nil {
+		return fmt.Errorf("%s.Color(): %w", p, err)
+	}
+
+	bodyIndex, outlineIndex := gifColorWhitePiece, gifColorBlackPiece
+	if pieceColor == piece.ColorBlack {
+		bodyIndex, outlineIndex = gifColorBlackPiece, gifColorWhitePiece
+	}
+
+	sprite := gifSprites[role]
+	scale := cell.Dx() / gifSpriteSize
+
+	for y := range gifSpriteSize {
+		for x := range gifSpriteSize {
+			colorIndex, ok := calcGIFSpritePixel(sprite, x, y, bodyIndex, outlineIndex)
+			if !ok {
+				continue
+			}
+
+			pixel := image.Rect(x*scale, y*scale, (x+1)*scale, (y+1)*scale).Add(cell.Min)
+			fillGIFRect(frame, pixel, colorIndex)
+		}
+	}
+
+	return nil
+}
+
+// calcGIFSpritePixel returns the color of the sprite pixel. Body pixels have the body color, empty pixels next to the
+// body have the outline color and other pixels are transparent.
+func calcGIFSpritePixel(sprite [gifSpriteSize]string, x, y int, bodyIndex, outlineIndex uint8) (uint8, bool) {
+	if sprite[y][x] == '#' {
+		return bodyIndex, true
+	}
+
+	for _, step := range [...][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		neighbourX, neighbourY := x+step[0], y+step[1]
+
+		if neighbourX < 0 || neighbourX >= gifSpriteSize || neighbourY < 0 || neighbourY >= gifSpriteSize {
+			continue
+		}
+
+		if sprite[neighbourY][neighbourX] == '#' {
+			return outlineIndex, true
+		}
+	}
+
+	return 0, false
+}
+
+// fillGIFRect fills passed rectangle of the frame with the color of passed palette index.
+func fillGIFRect(frame *image.Paletted, rect image.Rectangle, colorIndex uint8) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			frame.SetColorIndex(x, y, colorIndex)
+		}
+	}
+}
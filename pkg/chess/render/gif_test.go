@@ -0,0 +1,93 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestRendererGIF(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sans       []string
+		options    GIFOptions
+		boardSize  int
+		delay      int
+		colorIndex uint8
+		pixelX     int
+		pixelY     int
+		errString  string
+	}{
+		{"start", nil, GIFOptions{}, 384, 100, gifColorLightSquare, 0, 0, ""},
+		{"last move", []string{"e4"}, GIFOptions{SquareSize: 25}, 192, 100, gifColorLightLastMove, 97, 145, ""},
+		{
+			"flipped check",
+			[]string{"e4", "f5", "Qh5"},
+			GIFOptions{SquareSize: 12, Flipped: true, Delay: 500 * time.Millisecond},
+			96, 50, gifColorCheck, 36, 84, "",
+		},
+		{"small square", nil, GIFOptions{SquareSize: 11}, 0, 0, 0, 0, 0, "square size 11 is less than 12"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := game.NewGameStart()
+			if err != nil {
+				t.Fatalf("NewGameStart(): %v", err)
+			}
+
+			for _, san := range test.sans {
+				m, err := g.Variant().CalcMoveFromSAN(g.Position(), san)
+				if err != nil {
+					t.Fatalf("CalcMoveFromSAN(%q): %v", san, err)
+				}
+
+				if err := g.Move(m); err != nil {
+					t.Fatalf("Move(%+v): %v", m, err)
+				}
+			}
+
+			var buffer bytes.Buffer
+
+			err = NewRenderer(game.Engine{}).GIF(&buffer, g, test.options)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("GIF() expected error %q but got %q", test.errString, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			animation, err := gif.DecodeAll(&buffer)
+			if err != nil {
+				t.Fatalf("DecodeAll(): %v", err)
+			}
+
+			if len(animation.Image) != len(test.sans)+1 {
+				t.Fatalf("DecodeAll() expected %d frames but got %d", len(test.sans)+1, len(animation.Image))
+			}
+
+			frame := animation.Image[len(animation.Image)-1]
+
+			if size := frame.Bounds().Dx(); size != test.boardSize || frame.Bounds().Dy() != test.boardSize {
+				t.Fatalf("DecodeAll() expected %dx%d frame but got %v", test.boardSize, test.boardSize, frame.Bounds())
+			}
+
+			if delay := animation.Delay[len(animation.Delay)-1]; delay != test.delay {
+				t.Fatalf("DecodeAll() expected delay %d but got %d", test.delay, delay)
+			}
+
+			colorIndex := frame.ColorIndexAt(test.pixelX, test.pixelY)
+			if colorIndex != test.colorIndex {
+				t.Fatalf("ColorIndexAt(%d, %d) expected %d but got %d", test.pixelX, test.pixelY, test.colorIndex, colorIndex)
+			}
+		})
+	}
+}
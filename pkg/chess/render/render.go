@@ -0,0 +1,89 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+// Renderer draws positions and games as images.
+type Renderer struct {
+	engine game.Engine
+}
+
+// NewRenderer creates a new Renderer, which uses passed engine to find the checked king.
+func NewRenderer(engine game.Engine) *Renderer {
+	return &Renderer{engine: engine}
+}
+
+// calcCheckedKingSquare returns the square of the active color king if it is in check or square.SquareNil otherwise.
+func (renderer *Renderer) calcCheckedKingSquare(pos *position.Position) (square.Square, error) {
+	checked, err := renderer.engine.CheckChecked(pos, pos.ActiveColor())
+	if err != nil {
+		return square.SquareNil, fmt.Errorf("CheckChecked(%s): %w", pos.ActiveColor(), err)
+	}
+
+	if !checked {
+		return square.SquareNil, nil
+	}
+
+	king, err := piece.NewPiece(pos.ActiveColor(), piece.RoleKing)
+	if err != nil {
+		return square.SquareNil, fmt.Errorf("NewPiece(%s, %s): %w", pos.ActiveColor(), piece.RoleKing, err)
+	}
+
+	kingSquares := pos.Board().GetPieceBitboard(king).GetSquares()
+	if len(kingSquares) != 1 {
+		return square.SquareNil, nil
+	}
+
+	return kingSquares[0], nil
+}
+
+// calcSquareCell returns the column and the row of passed square on the image, where the zero cell is on the top left.
+func calcSquareCell(sq square.Square, flipped bool) (int, int, error) {
+	rank, err := sq.Rank()
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s.Rank(): %w", sq, err)
+	}
+
+	file, err := sq.File()
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s.File(): %w", sq, err)
+	}
+
+	if flipped {
+		return int(square.FileH - file), int(rank - square.Rank1), nil
+	}
+
+	return int(file - square.FileA), int(square.Rank8 - rank), nil
+}
+
+// calcMoveSquares returns the squares of passed move, which are highlighted as the last move. Drops have no origin and
+// the zero move has no squares.
+func calcMoveSquares(m move.Move) []square.Square {
+	if m.Dest() == square.SquareNil {
+		return nil
+	}
+
+	if m.IsDrop() {
+		return []square.Square{m.Dest()}
+	}
+
+	return []square.Square{m.Origin(), m.Dest()}
+}
+
+// calcAllSquares returns all squares of the board from A1 to H8.
+func calcAllSquares() []square.Square {
+	squares := make([]square.Square, 0, len(square.Ranks)*len(square.Files))
+
+	for sq := square.SquareA1; sq <= square.SquareH8; sq++ {
+		squares = append(squares, sq)
+	}
+
+	return squares
+}
@@ -0,0 +1,249 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+const (
+	// Size of the board side in pixels if it is not set in the options.
+	svgDefaultSize = 400
+
+	// Colors of the board.
+	svgLightSquareColor = "#f0d9b5"
+	svgDarkSquareColor  = "#b58863"
+	svgLastMoveColor    = "#cdd26a"
+	svgCheckColor       = "#ff0000"
+
+	// Color of the annotations if it is not set.
+	svgDefaultAnnotationColor = "#15781b"
+)
+
+// Mapping of pieces to Unicode chess glyphs. White and black glyphs are filled with the piece color, so the black
+// glyphs are used for both colors.
+var svgPieceGlyphs = map[piece.Role]string{
+	piece.RoleKing:   "♚",
+	piece.RoleQueen:  "♛",
+	piece.RoleRook:   "♜",
+	piece.RoleBishop: "♝",
+	piece.RoleKnight: "♞",
+	piece.RolePawn:   "♟",
+}
+
+// Arrow is the annotation from one square to another.
+type Arrow struct {
+	Origin square.Square
+	Dest   square.Square
+	// CSS color of the arrow. Empty color means the default green.
+	Color string
+}
+
+// Circle is the annotation around the square.
+type Circle struct {
+	Square square.Square
+	// CSS color of the circle. Empty color means the default green.
+	Color string
+}
+
+// SVGOptions configures the SVG rendering of the position.
+//
+// Zero value renders the board from the white perspective without highlights and annotations.
+type SVGOptions struct {
+	// Size of the board side in pixels. Zero means 400.
+	Size uint
+	// Render the board from the black perspective.
+	Flipped bool
+	// Move to highlight. Zero value means no highlight.
+	LastMove move.Move
+	// Highlight the active color king if it is in check.
+	Check bool
+	// Annotations drawn over the pieces.
+	Arrows  []Arrow
+	Circles []Circle
+}
+
+// SVG renders passed position as SVG image.
+func (renderer *Renderer) SVG(pos *position.Position, options SVGOptions) (string, error) {
+	size := int(options.Size)
+	if size == 0 {
+		size = svgDefaultSize
+	}
+
+	cellSize := float64(size) / float64(len(square.Files))
+
+	var builder strings.Builder
+
+	fmt.Fprintf(
+		&builder,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		size, size, size, size)
+
+	builder.WriteString(`<defs><marker id="arrowhead" viewBox="0 0 4 4" refX="2" refY="2" markerWidth="4" ` +
+		`markerHeight="4" orient="auto"><path d="M0,0 L4,2 L0,4 z" fill="context-stroke"/></marker></defs>`)
+
+	lastMoveSquares := calcMoveSquares(options.LastMove)
+
+	for _, sq := range calcAllSquares() {
+		column, row, err := calcSquareCell(sq, options.Flipped)
+		if err != nil {
+			return "", fmt.Errorf("calcSquareCell(%s): %w", sq, err)
+		}
+
+		x, y := float64(column)*cellSize, float64(row)*cellSize
+
+		color := svgLightSquareColor
+		if (column+row)%2 == 1 {
+			color = svgDarkSquareColor
+		}
+
+		fmt.Fprintf(&builder, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`, x, y, cellSize, cellSize, color)
+
+		if slices.Contains(lastMoveSquares, sq) {
+			fmt.Fprintf(
+				&builder,
+				`<rect x="%g" y="%g" width="%g" height="%g" fill="%s" fill-opacity="0.6"/>`,
+				x, y, cellSize, cellSize, svgLastMoveColor)
+		}
+	}
+
+	if options.Check {
+		if err := renderer.writeSVGCheck(&builder, pos, cellSize, options.Flipped); err != nil {
+			return "", fmt.Errorf("writeSVGCheck(): %w", err)
+		}
+	}
+
+	if err := writeSVGPieces(&builder, pos, cellSize, options.Flipped); err != nil {
+		return "", fmt.Errorf("writeSVGPieces(): %w", err)
+	}
+
+	for _, circle := range options.Circles {
+		x, y, err := calcSVGSquareCorner(circle.Square, cellSize, options.Flipped)
+		if err != nil {
+			return "", fmt.Errorf("calcSVGSquareCorner(%s): %w", circle.Square, err)
+		}
+
+		fmt.Fprintf(
+			&builder,
+			`<circle cx="%g" cy="%g" r="%g" fill="none" stroke="%s" stroke-width="%g" stroke-opacity="0.8"/>`,
+			x+cellSize/2, y+cellSize/2, cellSize*0.45, calcSVGAnnotationColor(circle.Color), cellSize/16)
+	}
+
+	for _, arrow := range options.Arrows {
+		originX, originY, err := calcSVGSquareCorner(arrow.Origin, cellSize, options.Flipped)
+		if err != nil {
+			return "", fmt.Errorf("calcSVGSquareCorner(%s): %w", arrow.Origin, err)
+		}
+
+		destX, destY, err := calcSVGSquareCorner(arrow.Dest, cellSize, options.Flipped)
+		if err != nil {
+			return "", fmt.Errorf("calcSVGSquareCorner(%s): %w", arrow.Dest, err)
+		}
+
+		fmt.Fprintf(
+			&builder,
+			`<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%s" stroke-width="%g" stroke-opacity="0.8" `+
+				`stroke-linecap="round" marker-end="url(#arrowhead)"/>`,
+			originX+cellSize/2, originY+cellSize/2, destX+cellSize/2, destY+cellSize/2,
+			calcSVGAnnotationColor(arrow.Color), cellSize/6)
+	}
+
+	builder.WriteString("</svg>")
+
+	return builder.String(), nil
+}
+
+// writeSVGCheck writes the red gradient under the active color king if it is in check.
+func (renderer *Renderer) writeSVGCheck(
+	builder *strings.Builder, pos *position.Position, cellSize float64, flipped bool,
+) error {
+	kingSquare, err := renderer.calcCheckedKingSquare(pos)
+	if err != nil {
+		return fmt.Errorf("calcCheckedKingSquare(): %w", err)
+	}
+
+	if kingSquare == square.SquareNil {
+		return nil
+	}
+
+	x, y, err := calcSVGSquareCorner(kingSquare, cellSize, flipped)
+	if err != nil {
+		return fmt.Errorf("calcSVGSquareCorner(%s): %w", kingSquare, err)
+	}
+
+	fmt.Fprintf(
+		builder,
+		`<defs><radialGradient id="check"><stop offset="0%%" stop-color="%s" stop-opacity="1"/>`+
+			`<stop offset="100%%" stop-color="%s" stop-opacity="0"/></radialGradient></defs>`+
+			`<rect x="%g" y="%g" width="%g" height="%g" fill="url(#check)"/>`,
+		svgCheckColor, svgCheckColor, x, y, cellSize, cellSize)
+
+	return nil
+}
+
+// writeSVGPieces writes the glyphs of all pieces on the board.
+func writeSVGPieces(builder *strings.Builder, pos *position.Position, cellSize float64, flipped bool) error {
+	for _, sq := range calcAllSquares() {
+		p, err := pos.Board().GetPieceFromSquare(sq)
+		if err != nil {
+			return fmt.Errorf("GetPieceFromSquare(%s): %w", sq, err)
+		}
+
+		if p == piece.PieceNil {
+			continue
+		}
+
+		role, err := p.Role()
+		if err != nil {
+			return fmt.Errorf("%s.Role(): %w", p, err)
+		}
+
+		color, err := p.Color()
+		if err != nil {
+			return fmt.Errorf("%s.Color(): %w", p, err)
+		}
+
+		fill, stroke := "#ffffff", "#000000"
+		if color == piece.ColorBlack {
+			fill, stroke = "#000000", "#ffffff"
+		}
+
+		x, y, err := calcSVGSquareCorner(sq, cellSize, flipped)
+		if err != nil {
+			return fmt.Errorf("calcSVGSquareCorner(%s): %w", sq, err)
+		}
+
+		fmt.Fprintf(
+			builder,
+			`<text x="%g" y="%g" font-size="%g" text-anchor="middle" dominant-baseline="central" fill="%s" `+
+				`stroke="%s" stroke-width="%g">%s</text>`,
+			x+cellSize/2, y+cellSize/2, cellSize*0.8, fill, stroke, cellSize/40, svgPieceGlyphs[role])
+	}
+
+	return nil
+}
+
+// calcSVGSquareCorner returns the coordinates of the top left corner of passed square.
+func calcSVGSquareCorner(sq square.Square, cellSize float64, flipped bool) (float64, float64, error) {
+	column, row, err := calcSquareCell(sq, flipped)
+	if err != nil {
+		return 0, 0, fmt.Errorf("calcSquareCell(%s): %w", sq, err)
+	}
+
+	return float64(column) * cellSize, float64(row) * cellSize, nil
+}
+
+// calcSVGAnnotationColor returns the escaped annotation color or the default one if passed color is empty.
+func calcSVGAnnotationColor(color string) string {
+	if color == "" {
+		return svgDefaultAnnotationColor
+	}
+
+	return html.EscapeString(color)
+}
@@ -0,0 +1,112 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+func TestRendererSVG(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		fen         string
+		options     SVGOptions
+		contains    []string
+		notContains []string
+	}{
+		{
+			"start",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			SVGOptions{},
+			[]string{
+				`width="400" height="400"`,
+				`<rect x="0" y="0" width="50" height="50" fill="#f0d9b5"/>`,
+				`<rect x="0" y="350" width="50" height="50" fill="#b58863"/>`,
+				`<text x="225" y="375" font-size="40" text-anchor="middle" dominant-baseline="central" ` +
+					`fill="#ffffff" stroke="#000000" stroke-width="1.25">♚</text>`,
+			},
+			[]string{"fill-opacity", "radialGradient", "<line", "<circle"},
+		},
+		{
+			"flipped",
+			"4k3/8/8/8/8/8/8/4K3 w - - 0 1",
+			SVGOptions{Size: 80, Flipped: true},
+			[]string{`<text x="35" y="5" font-size="8"`, `<text x="35" y="75" font-size="8"`},
+			nil,
+		},
+		{
+			"last move and check",
+			"4k3/8/8/8/8/8/8/4K2R b - - 0 1",
+			SVGOptions{
+				Size:     80,
+				LastMove: move.NewMove(square.SquareA8, square.SquareE8, move.MoveTagsNil, piece.RoleNil),
+				Check:    true,
+			},
+			[]string{
+				`<rect x="0" y="0" width="10" height="10" fill="#cdd26a" fill-opacity="0.6"/>`,
+				`<rect x="40" y="0" width="10" height="10" fill="#cdd26a" fill-opacity="0.6"/>`,
+			},
+			[]string{"radialGradient"},
+		},
+		{
+			"check",
+			"4R2k/8/8/8/8/8/8/4K3 b - - 0 1",
+			SVGOptions{Size: 80, Check: true},
+			[]string{`<rect x="70" y="0" width="10" height="10" fill="url(#check)"/>`},
+			nil,
+		},
+		{
+			"annotations",
+			"4k3/8/8/8/8/8/8/4K3 w - - 0 1",
+			SVGOptions{
+				Size:    80,
+				Arrows:  []Arrow{{Origin: square.SquareE1, Dest: square.SquareE4}},
+				Circles: []Circle{{Square: square.SquareD5, Color: `red"`}},
+			},
+			[]string{
+				`<line x1="45" y1="75" x2="45" y2="45" stroke="#15781b"`,
+				`<circle cx="35" cy="35" r="4.5" fill="none" stroke="red&#34;"`,
+			},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			svg, err := NewRenderer(game.Engine{}).SVG(pos, test.options)
+			if err != nil {
+				t.Fatalf("SVG(): %v", err)
+			}
+
+			if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>") {
+				t.Fatalf("SVG() expected the svg element but got %s", svg)
+			}
+
+			for _, str := range test.contains {
+				if !strings.Contains(svg, str) {
+					t.Fatalf("SVG() expected %s in %s", str, svg)
+				}
+			}
+
+			for _, str := range test.notContains {
+				if strings.Contains(svg, str) {
+					t.Fatalf("SVG() expected no %s in %s", str, svg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package retro
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/rylenko/limbo/pkg/chess/bitboard"
+	"github.com/rylenko/limbo/pkg/chess/board"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+	"github.com/rylenko/limbo/pkg/chess/syzygy"
+)
+
+// Material key of the bare kings, which is always a draw.
+const solverKingsKey = "KvK"
+
+var (
+	// Rank and file steps of the pieces, which move one step.
+	solverKingSteps   = [...][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+	solverKnightSteps = [...][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}
+
+	// Rank and file directions of the sliding pieces.
+	solverBishopDirections = [...][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+	solverRookDirections   = [...][2]int{{-1, 0}, {0, -1}, {0, 1}, {1, 0}}
+)
+
+// Solver generates distance to mate tables by retrograde analysis.
+//
+// Solved tables are cached, so the tables reachable by captures and promotions are solved once.
+type Solver struct {
+	engine game.Engine
+	tables map[string]*Table
+}
+
+// NewSolver creates a new Solver, which uses passed engine to generate moves.
+func NewSolver(engine game.Engine) *Solver {
+	return &Solver{
+		engine: engine,
+		tables: make(map[string]*Table),
+	}
+}
+
+// AddTable adds passed table, for example, read from the disk, so it is not solved again.
+func (solver *Solver) AddTable(table *Table) {
+	solver.tables[table.key] = table
+}
+
+// Solve solves the table with passed material key and all tables reachable from it by captures and promotions.
+//
+// Note that the solving of the tables with four pieces takes a lot of time and memory.
+//
+// Key argument examples: "KQvK", "KPvK", "KRvKN".
+func (solver *Solver) Solve(key string) (*Table, error) {
+	if table, ok := solver.tables[key]; ok {
+		return table, nil
+	}
+
+	table, err := newTable(key)
+	if err != nil {
+		return nil, fmt.Errorf("newTable(%q): %w", key, err)
+	}
+
+	if err := solver.solve(table); err != nil {
+		return nil, fmt.Errorf("solve(%q): %w", key, err)
+	}
+
+	solver.tables[key] = table
+
+	return table, nil
+}
+
+// Tables returns all solved and added tables sorted by material key.
+func (solver *Solver) Tables() []*Table {
+	keys := slices.Sorted(maps.Keys(solver.tables))
+	tables := make([]*Table, 0, len(keys))
+
+	for _, key := range keys {
+		tables = append(tables, solver.tables[key])
+	}
+
+	return tables
+}
+
+// solveState contains the intermediate data of the table solving.
+type solveState struct {
+	table *Table
+
+	// Resolved positions have final values. Illegal positions are resolved as draws.
+	resolved []bool
+	// Count of the moves staying in the table, which do not lead to the resolved wins of the opponent.
+	counts []uint8
+	// Best value of the moves leaving the table by the captures and the promotions.
+	exits    []byte
+	hasExits []bool
+
+	// Positions, which should be resolved with the count of plies equal to the level index. Odd levels are wins and
+	// even levels are losses.
+	levels [][]uint32
+}
+
+// schedule schedules passed position to be resolved with passed count of plies.
+func (state *solveState) schedule(index, plies int) {
+	for len(state.levels) <= plies {
+		state.levels = append(state.levels, nil)
+	}
+
+	//nolint:gosec // Tables have less than 2^32 positions.
+	state.levels[plies] = append(state.levels[plies], uint32(index))
+}
+
+// solve fills the values of passed table.
+//
+// Starting with the checkmates, each lost position makes its predecessors won with one more ply. Position is lost
+// when all its moves lead to the won positions of the opponent. Positions, which are not resolved, are draws.
+func (solver *Solver) solve(table *Table) error {
+	size := table.calcSize()
+
+	table.values = make([]byte, size)
+	state := &solveState{
+		table:    table,
+		resolved: make([]bool, size),
+		counts:   make([]uint8, size),
+		exits:    make([]byte, size),
+		hasExits: make([]bool, size),
+	}
+
+	for index := range size {
+		if err := solver.initPosition(state, index); err != nil {
+			return fmt.Errorf("initPosition(%d): %w", index, err)
+		}
+	}
+
+	var predecessors []int
+
+	for plies := 0; plies < len(state.levels); plies++ {
+		outcome := OutcomeLoss
+		if plies%2 == 1 {
+			outcome = OutcomeWin
+		}
+
+		value, err := encodeValue(outcome, plies)
+		if err != nil {
+			return fmt.Errorf("encodeValue(%s, %d): %w", outcome, plies, err)
+		}
+
+		for _, index := range state.levels[plies] {
+			if state.resolved[index] {
+				continue
+			}
+
+			state.resolved[index] = true
+			table.values[index] = value
+
+			predecessors = table.calcPredecessors(int(index), predecessors)
+
+			for _, predecessor := range predecessors {
+				if state.resolved[predecessor] {
+					continue
+				}
+
+				// Predecessor of the lost position is won with one more ply.
+				if outcome == OutcomeLoss {
+					state.schedule(predecessor, plies+1)
+					continue
+				}
+
+				state.counts[predecessor]--
+				if state.counts[predecessor] == 0 {
+					solver.scheduleLoss(state, predecessor, plies+1)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// initPosition resolves or schedules passed position using its moves.
+func (solver *Solver) initPosition(state *solveState, index int) error {
+	pos, ok, err := solver.newLegalPosition(state.table, index)
+	if err != nil {
+		return fmt.Errorf("newLegalPosition(): %w", err)
+	}
+
+	if !ok {
+		state.resolved[index] = true
+		return nil
+	}
+
+	moves, err := solver.engine.CalcMoves(pos)
+	if err != nil {
+		return fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		checked, err := solver.engine.CheckChecked(pos, pos.ActiveColor())
+		if err != nil {
+			return fmt.Errorf("CheckChecked(%s): %w", pos.ActiveColor(), err)
+		}
+
+		// Checkmate is lost in zero plies and stalemate is a draw.
+		if checked {
+			state.schedule(index, 0)
+		} else {
+			state.resolved[index] = true
+		}
+
+		return nil
+	}
+
+	for _, m := range moves {
+		if !m.Tags().Contains(move.MoveTagCapture) && !m.Tags().Contains(move.MoveTagEnPassantCapture) &&
+			m.PromoRole() == piece.RoleNil {
+			state.counts[index]++
+			continue
+		}
+
+		value, err := solver.probeExit(pos, m)
+		if err != nil {
+			return fmt.Errorf("probeExit(%+v): %w", m, err)
+		}
+
+		if !state.hasExits[index] || calcValueScore(value) > calcValueScore(state.exits[index]) {
+			state.exits[index], state.hasExits[index] = value, true
+		}
+	}
+
+	if state.hasExits[index] {
+		if outcome, plies := decodeValue(state.exits[index]); outcome == OutcomeWin {
+			state.schedule(index, plies)
+		}
+	}
+
+	if state.counts[index] == 0 {
+		solver.scheduleLoss(state, index, 0)
+	}
+
+	return nil
+}
+
+// scheduleLoss schedules passed position, all moves of which staying in the table lead to the wins of the opponent,
+// to be resolved as a loss in not less than passed count of plies, if the moves leaving the table are not better.
+func (solver *Solver) scheduleLoss(state *solveState, index, plies int) {
+	if !state.hasExits[index] {
+		state.schedule(index, plies)
+		return
+	}
+
+	outcome, exitPlies := decodeValue(state.exits[index])
+
+	switch outcome {
+	case OutcomeLoss:
+		state.schedule(index, max(plies, exitPlies))
+	case OutcomeDraw:
+		state.resolved[index] = true
+	case OutcomeWin, OutcomeNil:
+		// Wins are scheduled on the initialization.
+	}
+}
+
+// newLegalPosition creates the position with passed index. False is returned if the position is illegal: pieces
+// share squares, same pieces are not in the ascending squares order, pawns stand on the last ranks or the king of the
+// inactive color is in check.
+func (solver *Solver) newLegalPosition(table *Table, index int) (*position.Position, bool, error) {
+	squares, activeColor := table.decodeIndex(index, nil)
+
+	var occupied uint64
+
+	for i, sq := range squares {
+		if occupied&(1<<sq) != 0 {
+			return nil, false, nil
+		}
+
+		occupied |= 1 << sq
+
+		if i > 0 && table.pieces[i-1] == table.pieces[i] && squares[i-1] > sq {
+			return nil, false, nil
+		}
+
+		if (table.pieces[i] == piece.PieceWhitePawn || table.pieces[i] == piece.PieceBlackPawn) &&
+			(sq < len(square.Files) || sq >= tableSquaresCount-len(square.Files)) {
+			return nil, false, nil
+		}
+	}
+
+	bitboards := make(map[piece.Piece]bitboard.Bitboard, len(squares))
+
+	for i, sq := range squares {
+		bb, err := bitboards[table.pieces[i]].SetSquares(square.SquareA1 + square.Square(sq))
+		if err != nil {
+			return nil, false, fmt.Errorf("SetSquares(%d): %w", sq, err)
+		}
+
+		bitboards[table.pieces[i]] = bb
+	}
+
+	pos := position.NewPosition(board.NewBoard(bitboards), activeColor, nil, square.SquareNil, 0, 1)
+
+	inactiveColor, err := activeColor.Opposite()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s.Opposite(): %w", activeColor, err)
+	}
+
+	checked, err := solver.engine.CheckChecked(pos, inactiveColor)
+	if err != nil {
+		return nil, false, fmt.Errorf("CheckChecked(%s): %w", inactiveColor, err)
+	}
+
+	return pos, !checked, nil
+}
+
+// probeExit returns the value of passed move, which leaves the table by the capture or the promotion, from the point
+// of view of the active color of passed position.
+func (solver *Solver) probeExit(pos *position.Position, m move.Move) (byte, error) {
+	next, err := pos.DeepCopy()
+	if err != nil {
+		return tableDraw, fmt.Errorf("DeepCopy(): %w", err)
+	}
+
+	if err := next.MoveRaw(m); err != nil {
+		return tableDraw, fmt.Errorf("MoveRaw(%+v): %w", m, err)
+	}
+
+	key, err := syzygy.CalcMaterialKey(next)
+	if err != nil {
+		return tableDraw, fmt.Errorf("CalcMaterialKey(): %w", err)
+	}
+
+	if key == solverKingsKey {
+		return tableDraw, nil
+	}
+
+	table, err := solver.Solve(key)
+	if err != nil {
+		return tableDraw, fmt.Errorf("Solve(%q): %w", key, err)
+	}
+
+	outcome, plies, err := table.Probe(next)
+	if err != nil {
+		return tableDraw, fmt.Errorf("Probe(): %w", err)
+	}
+
+	// Win of the opponent is a loss with one more ply and vice versa.
+	switch outcome {
+	case OutcomeWin:
+		return encodeValue(OutcomeLoss, plies+1)
+	case OutcomeLoss:
+		return encodeValue(OutcomeWin, plies+1)
+	case OutcomeDraw:
+		return tableDraw, nil
+	default:
+		return tableDraw, fmt.Errorf("unknown outcome %s", outcome)
+	}
+}
+
+// calcPredecessors calculates the indexes of the positions, from which the inactive color of the position with passed
+// index could move to it without a capture and a promotion. Predecessors slice is reused.
+func (table *Table) calcPredecessors(index int, predecessors []int) []int {
+	squares, activeColor := table.decodeIndex(index, make([]int, 0, len(table.pieces)))
+	predecessors = predecessors[:0]
+
+	var occupied uint64
+	for _, sq := range squares {
+		occupied |= 1 << sq
+	}
+
+	moverColor := piece.ColorWhite
+	if activeColor == piece.ColorWhite {
+		moverColor = piece.ColorBlack
+	}
+
+	previous := make([]int, len(squares))
+
+	for i, p := range table.pieces {
+		color, err := p.Color()
+		if err != nil || color != moverColor {
+			continue
+		}
+
+		for _, origin := range calcUnmoveOrigins(p, squares[i], occupied) {
+			copy(previous, squares)
+			previous[i] = origin
+
+			// Keep the same pieces in the ascending squares order.
+			for j := i; j > 0 && table.pieces[j-1] == p && previous[j-1] > previous[j]; j-- {
+				previous[j-1], previous[j] = previous[j], previous[j-1]
+			}
+
+			for j := i; j+1 < len(previous) && table.pieces[j+1] == p && previous[j] > previous[j+1]; j++ {
+				previous[j], previous[j+1] = previous[j+1], previous[j]
+			}
+
+			predecessors = append(predecessors, table.calcIndex(previous, moverColor))
+		}
+	}
+
+	return predecessors
+}
+
+// calcUnmoveOrigins calculates the squares, from which passed piece could move to passed destination without
+// a capture. Occupied is the mask of the occupied squares, where A1 is the lowest bit.
+func calcUnmoveOrigins(p piece.Piece, dest int, occupied uint64) []int {
+	var origins []int
+
+	rank, file := dest/len(square.Files), dest%len(square.Files)
+
+	addOrigin := func(originRank, originFile int) bool {
+		if originRank < 0 || originRank >= len(square.Ranks) || originFile < 0 || originFile >= len(square.Files) {
+			return false
+		}
+
+		origin := originRank*len(square.Files) + originFile
+		if occupied&(1<<origin) != 0 {
+			return false
+		}
+
+		origins = append(origins, origin)
+
+		return true
+	}
+
+	role, _ := p.Role()
+
+	switch role {
+	case piece.RoleKing:
+		for _, step := range solverKingSteps {
+			addOrigin(rank+step[0], file+step[1])
+		}
+	case piece.RoleKnight:
+		for _, step := range solverKnightSteps {
+			addOrigin(rank+step[0], file+step[1])
+		}
+	case piece.RoleBishop, piece.RoleRook, piece.RoleQueen:
+		var directions [][2]int
+		if role != piece.RoleRook {
+			directions = append(directions, solverBishopDirections[:]...)
+		}
+
+		if role != piece.RoleBishop {
+			directions = append(directions, solverRookDirections[:]...)
+		}
+
+		// Sliding pieces came from any empty square in the direction until the first occupied one.
+		for _, direction := range directions {
+			for distance := 1; ; distance++ {
+				if !addOrigin(rank+distance*direction[0], file+distance*direction[1]) {
+					break
+				}
+			}
+		}
+	case piece.RolePawn:
+		// Pawns move forward, so they came from the previous rank or, by the long move, from the start rank.
+		//nolint:mnd // Destination ranks of the long moves are the fourth and the fifth.
+		forward, longMoveRank := 1, 3
+		if p == piece.PieceBlackPawn {
+			forward, longMoveRank = -1, 4
+		}
+
+		// Pawns do not stand on the first rank of their color.
+		if rank-forward == 0 || rank-forward == len(square.Ranks)-1 {
+			break
+		}
+
+		if addOrigin(rank-forward, file) && rank == longMoveRank {
+			addOrigin(rank-2*forward, file)
+		}
+	case piece.RoleNil:
+		// Table pieces always have roles.
+	}
+
+	return origins
+}
+
+// calcValueScore calculates the score of passed table value to compare values: shorter wins are better than longer
+// wins, wins are better than draws and draws are better than losses, which are better if longer.
+func calcValueScore(value byte) int {
+	outcome, plies := decodeValue(value)
+
+	switch outcome {
+	case OutcomeWin:
+		return 2*tableMaxPlies - plies
+	case OutcomeLoss:
+		return plies - 2*tableMaxPlies
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,54 @@
+package retro
+
+import (
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+func TestSolverSolve(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("solving takes a lot of time")
+	}
+
+	table, err := NewSolver(game.Engine{}).Solve("KQvK")
+	if err != nil {
+		t.Fatalf("Solve(): %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		fen     string
+		outcome Outcome
+		plies   int
+	}{
+		{"mate in one", "7k/8/6K1/8/8/8/8/Q7 w - - 0 1", OutcomeWin, 1},
+		{"checkmated", "Q6k/8/6K1/8/8/8/8/8 b - - 0 1", OutcomeLoss, 0},
+		{"stalemate", "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", OutcomeDraw, 0},
+		{"queen capture", "8/8/8/8/8/8/6Qk/4K3 b - - 0 1", OutcomeDraw, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			outcome, plies, err := table.Probe(pos)
+			if err != nil {
+				t.Fatalf("Probe(%q): %v", test.fen, err)
+			}
+
+			if outcome != test.outcome || plies != test.plies {
+				t.Fatalf("Probe(%q) expected (%s, %d) but got (%s, %d)", test.fen, test.outcome, test.plies, outcome,
+					plies)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package retro
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+	"github.com/rylenko/limbo/pkg/chess/syzygy"
+)
+
+const (
+	// Maximum count of pieces in the supported tables including kings.
+	tableMaxPieces = 4
+
+	// Count of the squares on the board.
+	tableSquaresCount = 64
+
+	// Values are encoded in one byte: zero is a draw, odd values until tableLossFlag are wins in plies and values
+	// with tableLossFlag are losses in plies.
+	tableDraw     = 0
+	tableLossFlag = 0x80
+	tableMaxPlies = 0x7F
+)
+
+var (
+	// Magic bytes at the start of the table files.
+	tableMagic = []byte("LIMBODTM")
+
+	// Pieces of the material key letters.
+	tableWhitePieces = map[rune]piece.Piece{
+		'K': piece.PieceWhiteKing,
+		'Q': piece.PieceWhiteQueen,
+		'R': piece.PieceWhiteRook,
+		'B': piece.PieceWhiteBishop,
+		'N': piece.PieceWhiteKnight,
+		'P': piece.PieceWhitePawn,
+	}
+	tableBlackPieces = map[rune]piece.Piece{
+		'K': piece.PieceBlackKing,
+		'Q': piece.PieceBlackQueen,
+		'R': piece.PieceBlackRook,
+		'B': piece.PieceBlackBishop,
+		'N': piece.PieceBlackKnight,
+		'P': piece.PieceBlackPawn,
+	}
+)
+
+// Outcome represents the result of the position with the optimal play from the point of view of the active color.
+type Outcome uint8
+
+const (
+	OutcomeNil Outcome = iota
+	OutcomeWin
+	OutcomeDraw
+	OutcomeLoss
+)
+
+// String returns string representation of current outcome.
+func (outcome Outcome) String() string {
+	switch outcome {
+	case OutcomeNil:
+		return "OutcomeNil"
+	case OutcomeWin:
+		return "OutcomeWin"
+	case OutcomeDraw:
+		return "OutcomeDraw"
+	case OutcomeLoss:
+		return "OutcomeLoss"
+	default:
+		return fmt.Sprintf("<unknown Outcome=%d>", outcome)
+	}
+}
+
+// Table contains the distance to mate of every position with the material of the table.
+//
+// Positions are indexed by the squares of the pieces in the material key order and by the active color, so the table
+// takes 2*64^n bytes, where n is the count of the pieces. Castling and en passant are not taken into account.
+type Table struct {
+	key    string
+	pieces []piece.Piece
+	values []byte
+}
+
+// newTable creates a new table with passed material key and no values.
+//
+// Key argument examples: "KQvK", "KPvK", "KRvKN".
+func newTable(key string) (*Table, error) {
+	pieces, err := parseMaterialKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("parseMaterialKey(%q): %w", key, err)
+	}
+
+	return &Table{key: key, pieces: pieces}, nil
+}
+
+// ReadTable reads the table, which was written using Table.WriteTo.
+func ReadTable(reader io.Reader) (*Table, error) {
+	header := make([]byte, len(tableMagic)+1)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("ReadFull(header): %w", err)
+	}
+
+	if !bytes.Equal(header[:len(tableMagic)], tableMagic) {
+		return nil, errors.New("invalid magic")
+	}
+
+	key := make([]byte, header[len(tableMagic)])
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, fmt.Errorf("ReadFull(key): %w", err)
+	}
+
+	table, err := newTable(string(key))
+	if err != nil {
+		return nil, fmt.Errorf("newTable(%q): %w", key, err)
+	}
+
+	table.values = make([]byte, table.calcSize())
+	if _, err := io.ReadFull(reader, table.values); err != nil {
+		return nil, fmt.Errorf("ReadFull(values): %w", err)
+	}
+
+	return table, nil
+}
+
+// Key returns the material key of the table.
+//
+// Return examples: "KQvK", "KPvK", "KRvKN".
+func (table *Table) Key() string {
+	return table.key
+}
+
+// Probe returns the outcome of passed position and the count of plies to mate. The count is zero in draws.
+//
+// Returns an error if the material of the position differs from the material of the table.
+func (table *Table) Probe(pos *position.Position) (Outcome, int, error) {
+	if pos == nil {
+		return OutcomeNil, 0, errors.New("position is nil")
+	}
+
+	if len(pos.CastlingRights()) > 0 {
+		return OutcomeNil, 0, errors.New("castling rights are not supported")
+	}
+
+	key, err := syzygy.CalcMaterialKey(pos)
+	if err != nil {
+		return OutcomeNil, 0, fmt.Errorf("CalcMaterialKey(): %w", err)
+	}
+
+	if key != table.key {
+		return OutcomeNil, 0, fmt.Errorf("material %s differs from the table %s", key, table.key)
+	}
+
+	index, err := table.calcPositionIndex(pos)
+	if err != nil {
+		return OutcomeNil, 0, fmt.Errorf("calcPositionIndex(): %w", err)
+	}
+
+	outcome, plies := decodeValue(table.values[index])
+
+	return outcome, plies, nil
+}
+
+// WriteTo writes the table to passed writer: magic bytes, material key length and material key followed by the
+// values.
+func (table *Table) WriteTo(writer io.Writer) (int64, error) {
+	header := append(append([]byte(nil), tableMagic...), byte(len(table.key)))
+	header = append(header, table.key...)
+
+	var written int64
+
+	for _, data := range [...][]byte{header, table.values} {
+		count, err := writer.Write(data)
+		written += int64(count)
+
+		if err != nil {
+			return written, fmt.Errorf("Write(): %w", err)
+		}
+	}
+
+	return written, nil
+}
+
+// calcIndex calculates the index of passed piece squares, where A1 is zero, and passed active color.
+func (table *Table) calcIndex(squares []int, activeColor piece.Color) int {
+	index := 0
+
+	for i := len(squares) - 1; i >= 0; i-- {
+		index = index*tableSquaresCount + squares[i]
+	}
+
+	index *= 2
+	if activeColor == piece.ColorBlack {
+		index++
+	}
+
+	return index
+}
+
+// calcPositionIndex calculates the index of passed position, which has the material of the table.
+func (table *Table) calcPositionIndex(pos *position.Position) (int, error) {
+	squares := make([]int, 0, len(table.pieces))
+
+	// Same pieces are adjacent in the material key order, so their squares are ascending as required.
+	for index, p := range table.pieces {
+		if index > 0 && table.pieces[index-1] == p {
+			continue
+		}
+
+		for _, sq := range pos.Board().GetPieceBitboard(p).GetSquares() {
+			squares = append(squares, int(sq-square.SquareA1))
+		}
+	}
+
+	if len(squares) != len(table.pieces) {
+		return 0, fmt.Errorf("expected %d pieces but got %d", len(table.pieces), len(squares))
+	}
+
+	return table.calcIndex(squares, pos.ActiveColor()), nil
+}
+
+// calcSize returns the count of the positions in the table.
+func (table *Table) calcSize() int {
+	size := 2
+
+	for range table.pieces {
+		size *= tableSquaresCount
+	}
+
+	return size
+}
+
+// decodeIndex decodes passed index to the piece squares and the active color. Squares slice is reused.
+func (table *Table) decodeIndex(index int, squares []int) ([]int, piece.Color) {
+	activeColor := piece.ColorWhite
+	if index%2 == 1 {
+		activeColor = piece.ColorBlack
+	}
+
+	index /= 2
+	squares = squares[:0]
+
+	for range table.pieces {
+		squares = append(squares, index%tableSquaresCount)
+		index /= tableSquaresCount
+	}
+
+	return squares, activeColor
+}
+
+// decodeValue decodes passed table value to the outcome and the count of plies to mate.
+func decodeValue(value byte) (Outcome, int) {
+	switch {
+	case value == tableDraw:
+		return OutcomeDraw, 0
+	case value&tableLossFlag != 0:
+		return OutcomeLoss, int(value &^ tableLossFlag)
+	default:
+		return OutcomeWin, int(value)
+	}
+}
+
+// encodeValue encodes passed outcome and count of plies to mate to the table value.
+func encodeValue(outcome Outcome, plies int) (byte, error) {
+	if plies < 0 || plies > tableMaxPlies {
+		return tableDraw, fmt.Errorf("%d plies are out of range", plies)
+	}
+
+	switch outcome {
+	case OutcomeWin:
+		return byte(plies), nil
+	case OutcomeLoss:
+		return byte(plies) | tableLossFlag, nil
+	case OutcomeDraw:
+		return tableDraw, nil
+	default:
+		return tableDraw, fmt.Errorf("unknown outcome %s", outcome)
+	}
+}
+
+// parseMaterialKey parses passed material key to the pieces in the key order.
+//
+// Key argument examples: "KQvK", "KPvK", "KRvKN".
+func parseMaterialKey(key string) ([]piece.Piece, error) {
+	white, black, ok := strings.Cut(key, "v")
+	if !ok {
+		return nil, errors.New("no color separator")
+	}
+
+	if !strings.HasPrefix(white, "K") || !strings.HasPrefix(black, "K") ||
+		strings.Count(white, "K") != 1 || strings.Count(black, "K") != 1 {
+		return nil, errors.New("each color must have one king at the start")
+	}
+
+	if len(white)+len(black) > tableMaxPieces {
+		return nil, fmt.Errorf("%d pieces are not supported", len(white)+len(black))
+	}
+
+	pieces := make([]piece.Piece, 0, len(white)+len(black))
+
+	for _, side := range [...]struct {
+		letters string
+		pieces  map[rune]piece.Piece
+	}{{white, tableWhitePieces}, {black, tableBlackPieces}} {
+		for _, letter := range side.letters {
+			p, ok := side.pieces[letter]
+			if !ok {
+				return nil, fmt.Errorf("unknown piece letter %q", letter)
+			}
+
+			pieces = append(pieces, p)
+		}
+	}
+
+	// Check the order of the letters by recalculating the key, which is always in the canonical order.
+	if canonical := calcPiecesKey(pieces); canonical != key {
+		return nil, fmt.Errorf("pieces must be in the order %s", canonical)
+	}
+
+	return pieces, nil
+}
+
+// calcPiecesKey calculates the material key of passed pieces with the pieces of each color in the order from the king
+// to the pawn.
+func calcPiecesKey(pieces []piece.Piece) string {
+	var white, black strings.Builder
+
+	for _, letter := range "KQRBNP" {
+		for _, p := range pieces {
+			if p == tableWhitePieces[letter] {
+				white.WriteRune(letter)
+			} else if p == tableBlackPieces[letter] {
+				black.WriteRune(letter)
+			}
+		}
+	}
+
+	return white.String() + "v" + black.String()
+}
@@ -0,0 +1,174 @@
+package retro
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+func TestParseMaterialKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key       string
+		pieces    []piece.Piece
+		errString string
+	}{
+		{"KQvK", []piece.Piece{piece.PieceWhiteKing, piece.PieceWhiteQueen, piece.PieceBlackKing}, ""},
+		{"KvKP", []piece.Piece{piece.PieceWhiteKing, piece.PieceBlackKing, piece.PieceBlackPawn}, ""},
+		{"KQK", nil, "no color separator"},
+		{"QKvK", nil, "each color must have one king at the start"},
+		{"KRRvKR", nil, "5 pieces are not supported"},
+		{"KXvK", nil, "unknown piece letter 'X'"},
+		{"KPRvK", nil, "pieces must be in the order KRPvK"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			t.Parallel()
+
+			pieces, err := parseMaterialKey(test.key)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("parseMaterialKey(%q) expected error %q but got %v", test.key, test.errString, err)
+			}
+
+			if !slices.Equal(pieces, test.pieces) {
+				t.Fatalf("parseMaterialKey(%q) expected %v but got %v", test.key, test.pieces, pieces)
+			}
+		})
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		outcome Outcome
+		plies   int
+		value   byte
+	}{
+		{OutcomeDraw, 0, 0x00},
+		{OutcomeWin, 1, 0x01},
+		{OutcomeWin, 127, 0x7F},
+		{OutcomeLoss, 0, 0x80},
+		{OutcomeLoss, 42, 0xAA},
+	}
+
+	for _, test := range tests {
+		value, err := encodeValue(test.outcome, test.plies)
+		if err != nil {
+			t.Fatalf("encodeValue(%s, %d): %v", test.outcome, test.plies, err)
+		}
+
+		if value != test.value {
+			t.Fatalf("encodeValue(%s, %d) expected 0x%X but got 0x%X", test.outcome, test.plies, test.value, value)
+		}
+
+		if outcome, plies := decodeValue(value); outcome != test.outcome || plies != test.plies {
+			t.Fatalf("decodeValue(0x%X) expected (%s, %d) but got (%s, %d)", value, test.outcome, test.plies, outcome,
+				plies)
+		}
+	}
+
+	if _, err := encodeValue(OutcomeWin, 128); err == nil {
+		t.Fatal("encodeValue(128) expected error")
+	}
+}
+
+func TestTableWriteToAndReadTable(t *testing.T) {
+	t.Parallel()
+
+	table, err := newTable("KQvK")
+	if err != nil {
+		t.Fatalf("newTable(): %v", err)
+	}
+
+	table.values = make([]byte, table.calcSize())
+	table.values[1], table.values[len(table.values)-1] = 0x01, 0x86
+
+	var buffer bytes.Buffer
+
+	written, err := table.WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("WriteTo(): %v", err)
+	}
+
+	if written != int64(buffer.Len()) {
+		t.Fatalf("WriteTo() expected %d written bytes but got %d", buffer.Len(), written)
+	}
+
+	read, err := ReadTable(&buffer)
+	if err != nil {
+		t.Fatalf("ReadTable(): %v", err)
+	}
+
+	if read.Key() != table.Key() || !bytes.Equal(read.values, table.values) {
+		t.Fatalf("ReadTable() expected table %s but got %s", table.Key(), read.Key())
+	}
+
+	if _, err := ReadTable(bytes.NewReader([]byte("LIMBOBK1"))); err == nil {
+		t.Fatal("ReadTable() expected error on invalid magic")
+	}
+}
+
+func TestCalcUnmoveOrigins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		piece    piece.Piece
+		dest     int
+		occupied uint64
+		origins  []int
+	}{
+		// King on A1.
+		{"king corner", piece.PieceWhiteKing, 0, 1, []int{1, 8, 9}},
+		// Rook on A1 and a piece on A3.
+		{"rook blocked", piece.PieceBlackRook, 0, 1 | 1<<16, []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		// White pawn on E4 came from E3 or E2.
+		{"white pawn long move", piece.PieceWhitePawn, 28, 1 << 28, []int{20, 12}},
+		// White pawn on E3 came from E2.
+		{"white pawn", piece.PieceWhitePawn, 20, 1 << 20, []int{12}},
+		// White pawn on E2 could not move.
+		{"white pawn start", piece.PieceWhitePawn, 12, 1 << 12, nil},
+		// Black pawn on D5 with a piece on D6.
+		{"black pawn blocked", piece.PieceBlackPawn, 35, 1<<35 | 1<<43, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			origins := calcUnmoveOrigins(test.piece, test.dest, test.occupied)
+			slices.Sort(origins)
+
+			want := slices.Sorted(slices.Values(test.origins))
+			if !slices.Equal(origins, want) {
+				t.Fatalf("calcUnmoveOrigins() expected %v but got %v", want, origins)
+			}
+		})
+	}
+}
+
+func TestOutcomeString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		outcome Outcome
+		want    string
+	}{
+		{OutcomeNil, "OutcomeNil"},
+		{OutcomeWin, "OutcomeWin"},
+		{OutcomeDraw, "OutcomeDraw"},
+		{OutcomeLoss, "OutcomeLoss"},
+		{Outcome(4), "<unknown Outcome=4>"},
+	}
+
+	for _, test := range tests {
+		if got := test.outcome.String(); got != test.want {
+			t.Fatalf("%d.String() expected %q but got %q", test.outcome, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Material values of the roles in centipawns.
+//
+//nolint:mnd // Magic numbers represents classical material values.
+var evalRoleValues = map[piece.Role]int{
+	piece.RoleKing:   0,
+	piece.RoleQueen:  900,
+	piece.RoleRook:   500,
+	piece.RoleBishop: 330,
+	piece.RoleKnight: 320,
+	piece.RolePawn:   100,
+}
+
+// evalMaterial evaluates passed position in centipawns from the point of view of the active color.
+//
+// TODO: evaluate piece placement.
+func evalMaterial(pos *position.Position) (int, error) {
+	activeColor := pos.ActiveColor()
+
+	var score int
+
+	for role, value := range evalRoleValues {
+		for _, color := range [...]piece.Color{piece.ColorWhite, piece.ColorBlack} {
+			p, err := piece.NewPiece(color, role)
+			if err != nil {
+				return 0, fmt.Errorf("NewPiece(%s, %s): %w", color, role, err)
+			}
+
+			count := len(pos.Board().GetPieceBitboard(p).GetSquares())
+
+			if color == activeColor {
+				score += count * value
+			} else {
+				score -= count * value
+			}
+		}
+	}
+
+	return score, nil
+}
@@ -0,0 +1,56 @@
+package search
+
+import "time"
+
+const (
+	// Expected count of moves until the end of the game if the GUI did not pass "movestogo".
+	limitsDefaultMovesToGo = 30
+
+	// Part of the time left to keep as a reserve to not to lose on time because of the lags.
+	limitsTimeReserveDivisor = 20
+)
+
+// Limits represents the restrictions of a single search.
+//
+// Zero value means no limits, so the search will continue until the maximum depth of the Searcher.
+type Limits struct {
+	Depth     uint8
+	Nodes     uint64
+	MoveTime  time.Duration
+	WhiteTime time.Duration
+	BlackTime time.Duration
+	WhiteInc  time.Duration
+	BlackInc  time.Duration
+	MovesToGo uint16
+	Infinite  bool
+}
+
+// calcTimeBudget calculates the time to spend on the move using passed clock of the active color.
+//
+// Returns zero if the time is not limited.
+func (limits Limits) calcTimeBudget(timeLeft, inc time.Duration) time.Duration {
+	if limits.Infinite {
+		return 0
+	}
+
+	if limits.MoveTime > 0 {
+		return limits.MoveTime
+	}
+
+	if timeLeft <= 0 {
+		return 0
+	}
+
+	movesToGo := time.Duration(limits.MovesToGo)
+	if movesToGo == 0 {
+		movesToGo = limitsDefaultMovesToGo
+	}
+
+	budget := timeLeft/movesToGo + inc/2 //nolint:mnd // Spend half of the increment.
+
+	if maxBudget := timeLeft - timeLeft/limitsTimeReserveDivisor; budget > maxBudget {
+		budget = maxBudget
+	}
+
+	return budget
+}
@@ -0,0 +1,37 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitsCalcTimeBudget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		limits   Limits
+		timeLeft time.Duration
+		inc      time.Duration
+		budget   time.Duration
+	}{
+		{"no limits", Limits{}, 0, 0, 0},
+		{"infinite", Limits{Infinite: true, MoveTime: time.Second}, time.Minute, 0, 0},
+		{"move time", Limits{MoveTime: time.Second}, time.Minute, 0, time.Second},
+		{"default moves to go", Limits{}, 30 * time.Second, 0, time.Second},
+		{"moves to go", Limits{MovesToGo: 10}, 30 * time.Second, 0, 3 * time.Second},
+		{"increment", Limits{}, 30 * time.Second, 2 * time.Second, 2 * time.Second},
+		{"reserve", Limits{MovesToGo: 1}, 20 * time.Second, 0, 19 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			budget := test.limits.calcTimeBudget(test.timeLeft, test.inc)
+			if budget != test.budget {
+				t.Fatalf("calcTimeBudget(%s, %s) expected %s but got %s", test.timeLeft, test.inc, test.budget, budget)
+			}
+		})
+	}
+}
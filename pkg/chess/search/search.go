@@ -0,0 +1,270 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+const (
+	// SearcherMaxDepth is the maximum depth the Searcher is able to reach.
+	SearcherMaxDepth uint8 = 64
+
+	// Score of the mate in zero plies. Mate in N plies has the score scoreMate-N.
+	scoreMate = 100_000
+
+	// Score that is greater than any possible score.
+	scoreInfinity = scoreMate + 1
+)
+
+// errSearchStopped is returned by the internal search functions when the search is stopped because of the limits.
+var errSearchStopped = errors.New("search stopped")
+
+// Info represents the result of the completed search iteration.
+type Info struct {
+	Depth uint8
+	// Score in centipawns from the point of view of the active color. Makes no sense if MateIn is not zero.
+	Score int
+	// Moves count until the mate. Positive if the active color mates, negative if the active color gets mated.
+	MateIn int
+	Nodes  uint64
+	Time   time.Duration
+	PV     []move.Move
+}
+
+// Searcher searches for the best move in the position using the Engine move generation.
+//
+// The search is the iterative deepening alpha-beta negamax with the material evaluation.
+//
+// TODO: transposition table.
+// TODO: quiescence search.
+type Searcher struct {
+	engine   game.Engine
+	maxDepth uint8
+}
+
+// NewSearcher creates a new Searcher with passed parameters.
+//
+// Passed max depth is clamped to the SearcherMaxDepth.
+func NewSearcher(engine game.Engine, maxDepth uint8) *Searcher {
+	return &Searcher{
+		engine:   engine,
+		maxDepth: min(maxDepth, SearcherMaxDepth),
+	}
+}
+
+// MaxDepth returns maximum search depth of the current searcher.
+func (searcher *Searcher) MaxDepth() uint8 {
+	return searcher.maxDepth
+}
+
+// SetMaxDepth sets maximum search depth of the current searcher.
+//
+// Passed max depth is clamped to the SearcherMaxDepth.
+func (searcher *Searcher) SetMaxDepth(maxDepth uint8) {
+	searcher.maxDepth = min(maxDepth, SearcherMaxDepth)
+}
+
+// Search searches for the best move in passed position until passed limits are reached or the context is done.
+//
+// The passed callback, if not nil, is called after each completed iteration. The best move of the last completed
+// iteration is returned. If no iteration was completed, the first possible move is returned.
+func (searcher *Searcher) Search(
+	ctx context.Context,
+	pos *position.Position,
+	limits Limits,
+	onInfo func(Info),
+) (move.Move, error) {
+	if pos == nil {
+		return move.Move{}, errors.New("position is nil")
+	}
+
+	moves, err := searcher.engine.CalcMoves(pos)
+	if err != nil {
+		return move.Move{}, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		return move.Move{}, errors.New("no possible moves")
+	}
+
+	timeLeft, inc := limits.WhiteTime, limits.WhiteInc
+	if pos.ActiveColor() == piece.ColorBlack {
+		timeLeft, inc = limits.BlackTime, limits.BlackInc
+	}
+
+	if budget := limits.calcTimeBudget(timeLeft, inc); budget > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	maxDepth := searcher.maxDepth
+	if limits.Depth > 0 {
+		maxDepth = min(maxDepth, limits.Depth)
+	}
+
+	state := &searchState{ctx: ctx, nodesLimit: limits.Nodes, start: time.Now()}
+	bestMove := moves[0]
+
+	for depth := uint8(1); depth <= maxDepth; depth++ {
+		score, pv, err := searcher.negamax(state, pos, depth, 0, -scoreInfinity, scoreInfinity)
+		if errors.Is(err, errSearchStopped) {
+			break
+		}
+
+		if err != nil {
+			return move.Move{}, fmt.Errorf("negamax(%d): %w", depth, err)
+		}
+
+		bestMove = pv[0]
+
+		if onInfo != nil {
+			onInfo(state.newInfo(depth, score, pv))
+		}
+
+		// There is no reason to search deeper if the forced mate is found.
+		if score >= scoreMate-int(depth) || score <= -scoreMate+int(depth) {
+			break
+		}
+	}
+
+	return bestMove, nil
+}
+
+// negamax searches for the best score of the active color in passed position and returns it with principal variation.
+//
+// Returns errSearchStopped if the search is stopped because of the limits.
+func (searcher *Searcher) negamax(
+	state *searchState,
+	pos *position.Position,
+	depth uint8,
+	ply int,
+	alpha int,
+	beta int,
+) (int, []move.Move, error) {
+	if err := state.visitNode(); err != nil {
+		return 0, nil, err
+	}
+
+	moves, err := searcher.engine.CalcMoves(pos)
+	if err != nil {
+		return 0, nil, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	if len(moves) == 0 {
+		checked, err := searcher.engine.CheckChecked(pos, pos.ActiveColor())
+		if err != nil {
+			return 0, nil, fmt.Errorf("CheckChecked(%s): %w", pos.ActiveColor(), err)
+		}
+
+		if checked {
+			return -scoreMate + ply, nil, nil
+		}
+
+		return 0, nil, nil
+	}
+
+	if depth == 0 {
+		score, err := evalMaterial(pos)
+		if err != nil {
+			return 0, nil, fmt.Errorf("evalMaterial(): %w", err)
+		}
+
+		return score, nil, nil
+	}
+
+	// Captures first, so the alpha-beta cuts more.
+	slices.SortStableFunc(moves, func(a, b move.Move) int {
+		return compareCaptures(b) - compareCaptures(a)
+	})
+
+	var bestPV []move.Move
+
+	for _, m := range moves {
+		child, err := pos.DeepCopy()
+		if err != nil {
+			return 0, nil, fmt.Errorf("DeepCopy(): %w", err)
+		}
+
+		if err := child.MoveRaw(m); err != nil {
+			return 0, nil, fmt.Errorf("MoveRaw(%+v): %w", m, err)
+		}
+
+		childScore, childPV, err := searcher.negamax(state, child, depth-1, ply+1, -beta, -alpha)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if score := -childScore; score > alpha || bestPV == nil {
+			alpha = max(alpha, score)
+			bestPV = append([]move.Move{m}, childPV...)
+		}
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return alpha, bestPV, nil
+}
+
+// compareCaptures returns 1 if passed move is capture and 0 otherwise.
+func compareCaptures(m move.Move) int {
+	if m.Tags().Contains(move.MoveTagCapture) {
+		return 1
+	}
+
+	return 0
+}
+
+// searchState contains the counters and limits of the single search.
+type searchState struct {
+	ctx        context.Context //nolint:containedctx // The state lives only during single search.
+	nodesLimit uint64
+	nodes      uint64
+	start      time.Time
+}
+
+// newInfo creates a new Info using the current state and passed iteration results.
+func (state *searchState) newInfo(depth uint8, score int, pv []move.Move) Info {
+	info := Info{
+		Depth: depth,
+		Score: score,
+		Nodes: state.nodes,
+		Time:  time.Since(state.start),
+		PV:    pv,
+	}
+
+	switch {
+	case score >= scoreMate-int(SearcherMaxDepth):
+		info.MateIn = (scoreMate - score + 1) / 2 //nolint:mnd // Plies to moves.
+	case score <= -scoreMate+int(SearcherMaxDepth):
+		info.MateIn = -(scoreMate + score) / 2 //nolint:mnd // Plies to moves.
+	}
+
+	return info
+}
+
+// visitNode counts the visited node and checks that the search limits are not reached yet.
+func (state *searchState) visitNode() error {
+	if state.ctx.Err() != nil {
+		return errSearchStopped
+	}
+
+	if state.nodesLimit > 0 && state.nodes >= state.nodesLimit {
+		return errSearchStopped
+	}
+
+	state.nodes++
+
+	return nil
+}
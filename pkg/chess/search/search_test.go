@@ -0,0 +1,56 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+func TestSearcherSearch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		fen    string
+		limits Limits
+		uci    string
+		mateIn int
+	}{
+		{"back rank mate", "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", Limits{Depth: 2}, "a1a8", 1},
+		{"free queen", "4k3/8/8/3q4/8/8/3R4/4K3 w - - 0 1", Limits{Depth: 1}, "d2d5", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			var lastInfo Info
+
+			move, err := NewSearcher(game.Engine{}, SearcherMaxDepth).Search(
+				context.Background(), pos, test.limits, func(info Info) { lastInfo = info })
+			if err != nil {
+				t.Fatalf("Search(%q): %v", test.fen, err)
+			}
+
+			uci, err := move.UCI()
+			if err != nil {
+				t.Fatalf("UCI(%+v): %v", move, err)
+			}
+
+			if uci != test.uci {
+				t.Fatalf("Search(%q) expected %q but got %q", test.fen, test.uci, uci)
+			}
+
+			if lastInfo.MateIn != test.mateIn {
+				t.Fatalf("Search(%q) expected mate in %d but got %d", test.fen, test.mateIn, lastInfo.MateIn)
+			}
+		})
+	}
+}
@@ -191,6 +191,23 @@ func NewSquareFromFEN(fen string) (Square, error) {
 	return square, nil
 }
 
+// FEN returns FEN representation of the current square.
+//
+// Return examples: "a1", "h8".
+func (square Square) FEN() (string, error) {
+	rank, err := square.Rank()
+	if err != nil {
+		return "", fmt.Errorf("Rank(): %w", err)
+	}
+
+	file, err := square.File()
+	if err != nil {
+		return "", fmt.Errorf("File(): %w", err)
+	}
+
+	return string([]byte{'a' + (uint8(file) - 1), '1' + (uint8(rank) - 1)}), nil
+}
+
 // File returns file of the current square.
 func (square Square) File() (File, error) {
 	if square == SquareNil || square > SquareH8 {
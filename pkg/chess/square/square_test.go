@@ -238,3 +238,35 @@ func TestSquareString(t *testing.T) {
 		})
 	}
 }
+
+func TestSquareFEN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		square    Square
+		fen       string
+		errString string
+	}{
+		{SquareA1, "a1", ""},
+		{SquareE2, "e2", ""},
+		{SquareD5, "d5", ""},
+		{SquareH8, "h8", ""},
+		{SquareNil, "", "Rank(): unknown square"},
+		{Square(123), "", "Rank(): unknown square"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.square.String(), func(t *testing.T) {
+			t.Parallel()
+
+			fen, err := test.square.FEN()
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("%s.FEN() expected error %q but got %q", test.square, test.errString, err)
+			}
+
+			if fen != test.fen {
+				t.Fatalf("%s.FEN() expected %q but got %q", test.square, test.fen, fen)
+			}
+		})
+	}
+}
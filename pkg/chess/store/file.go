@@ -0,0 +1,343 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+const (
+	// Names of the files in the store directory.
+	fileLogName   = "games.log"
+	fileIndexName = "games.idx"
+
+	// Permissions of the created directory and files.
+	fileDirPerm  = 0o755
+	fileFilePerm = 0o644
+)
+
+// fileRecord is the line of the log file.
+type fileRecord struct {
+	ID      string `json:"id"`
+	Variant string `json:"variant"`
+	White   string `json:"white"`
+	Black   string `json:"black"`
+	FEN     string `json:"fen"`
+	// Moves in UCI separated by spaces.
+	Moves       string           `json:"moves"`
+	Result      game.Result      `json:"result"`
+	Termination game.Termination `json:"termination"`
+}
+
+// fileIndexEntry is the line of the index file, which points to the last saved record with the ID in the log file.
+type fileIndexEntry struct {
+	ID      string `json:"id"`
+	Offset  int64  `json:"offset"`
+	Length  int64  `json:"length"`
+	White   string `json:"white"`
+	Black   string `json:"black"`
+	Ongoing bool   `json:"ongoing"`
+}
+
+// FileStore is the GameStore, which appends the records to the log file and their locations to the index file. The
+// last saved record with the ID replaces the previous ones. It is safe for concurrent use.
+//
+// The index is rebuilt from the tail of the log if it is behind the log, for example, after the crash. The incomplete
+// last lines of the files are truncated.
+type FileStore struct {
+	mu      sync.Mutex
+	log     *os.File
+	logSize int64
+	index   *os.File
+	entries map[string]fileIndexEntry
+	// IDs of the records in the order of the first save.
+	ids []string
+}
+
+// OpenFileStore opens the store in passed directory or creates a new one.
+func OpenFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, fileDirPerm); err != nil {
+		return nil, fmt.Errorf("MkdirAll(%q): %w", dir, err)
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, fileLogName), os.O_RDWR|os.O_CREATE|os.O_APPEND, fileFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("OpenFile(%s): %w", fileLogName, err)
+	}
+
+	index, err := os.OpenFile(filepath.Join(dir, fileIndexName), os.O_RDWR|os.O_CREATE|os.O_APPEND, fileFilePerm)
+	if err != nil {
+		log.Close() //nolint:errcheck // The open error is more important.
+		return nil, fmt.Errorf("OpenFile(%s): %w", fileIndexName, err)
+	}
+
+	store := &FileStore{log: log, index: index, entries: make(map[string]fileIndexEntry)}
+
+	if err := store.load(); err != nil {
+		store.Close() //nolint:errcheck // The load error is more important.
+		return nil, fmt.Errorf("load(): %w", err)
+	}
+
+	return store, nil
+}
+
+// Save appends the record to the log and its location to the index.
+func (store *FileStore) Save(record Record) error {
+	if record.ID == "" {
+		return errors.New("no ID")
+	}
+
+	data, err := json.Marshal(fileRecord{
+		ID:          record.ID,
+		Variant:     record.Variant,
+		White:       record.White,
+		Black:       record.Black,
+		FEN:         record.FEN,
+		Moves:       strings.Join(record.Moves, " "),
+		Result:      record.Result,
+		Termination: record.Termination,
+	})
+	if err != nil {
+		return fmt.Errorf("Marshal(%s): %w", record.ID, err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, err := store.log.Write(append(data, '\n')); err != nil {
+		// Remove the part of the record, so the next record is written at the known offset.
+		return errors.Join(fmt.Errorf("Write(log): %w", err), store.log.Truncate(store.logSize))
+	}
+
+	entry := fileIndexEntry{
+		ID:      record.ID,
+		Offset:  store.logSize,
+		Length:  int64(len(data)),
+		White:   record.White,
+		Black:   record.Black,
+		Ongoing: record.Result == game.ResultNil,
+	}
+
+	store.logSize += entry.Length + 1
+
+	if err := store.appendEntryLocked(entry); err != nil {
+		return fmt.Errorf("appendEntryLocked(%s): %w", entry.ID, err)
+	}
+
+	return nil
+}
+
+// Load reads the last saved record with passed ID or returns ErrNotFound.
+func (store *FileStore) Load(id string) (Record, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	entry, ok := store.entries[id]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+
+	record, err := store.readRecordLocked(entry)
+	if err != nil {
+		return Record{}, fmt.Errorf("readRecordLocked(): %w", err)
+	}
+
+	return record, nil
+}
+
+// ListByPlayer returns the records of the games of passed player in the order of the first save.
+func (store *FileStore) ListByPlayer(player string) ([]Record, error) {
+	return store.list(func(entry fileIndexEntry) bool {
+		return entry.White == player || entry.Black == player
+	})
+}
+
+// ListOngoing returns the records of the games in progress in the order of the first save.
+func (store *FileStore) ListOngoing() ([]Record, error) {
+	return store.list(func(entry fileIndexEntry) bool {
+		return entry.Ongoing
+	})
+}
+
+// Close closes the files of the store.
+func (store *FileStore) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return errors.Join(store.log.Close(), store.index.Close())
+}
+
+// list reads the records, whose index entries match passed filter, in the order of the first save.
+func (store *FileStore) list(filter func(fileIndexEntry) bool) ([]Record, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var records []Record
+
+	for _, id := range store.ids {
+		entry := store.entries[id]
+		if !filter(entry) {
+			continue
+		}
+
+		record, err := store.readRecordLocked(entry)
+		if err != nil {
+			return nil, fmt.Errorf("readRecordLocked(%s): %w", id, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// load reads the index and then indexes the records of the log, which are not in the index yet.
+func (store *FileStore) load() error {
+	indexEnd, err := readFileLines(store.index, 0, func(_ int64, line []byte) error {
+		var entry fileIndexEntry
+
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("Unmarshal(entry): %w", err)
+		}
+
+		store.addEntry(entry)
+		store.logSize = max(store.logSize, entry.Offset+entry.Length+1)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("readFileLines(index): %w", err)
+	}
+
+	if err := store.index.Truncate(indexEnd); err != nil {
+		return fmt.Errorf("Truncate(index): %w", err)
+	}
+
+	logEnd, err := readFileLines(store.log, store.logSize, func(offset int64, line []byte) error {
+		var record fileRecord
+
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("Unmarshal(record): %w", err)
+		}
+
+		entry := fileIndexEntry{
+			ID:      record.ID,
+			Offset:  offset,
+			Length:  int64(len(line)),
+			White:   record.White,
+			Black:   record.Black,
+			Ongoing: record.Result == game.ResultNil,
+		}
+
+		if err := store.appendEntryLocked(entry); err != nil {
+			return fmt.Errorf("appendEntryLocked(%s): %w", entry.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("readFileLines(log): %w", err)
+	}
+
+	if err := store.log.Truncate(logEnd); err != nil {
+		return fmt.Errorf("Truncate(log): %w", err)
+	}
+
+	store.logSize = logEnd
+
+	return nil
+}
+
+// appendEntryLocked appends passed entry to the index file and adds it to the entries.
+//
+// The store must be locked.
+func (store *FileStore) appendEntryLocked(entry fileIndexEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("Marshal(): %w", err)
+	}
+
+	if _, err := store.index.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("Write(index): %w", err)
+	}
+
+	store.addEntry(entry)
+
+	return nil
+}
+
+// addEntry adds passed entry to the entries. The entry replaces the previous entry with the same ID.
+func (store *FileStore) addEntry(entry fileIndexEntry) {
+	if _, ok := store.entries[entry.ID]; !ok {
+		store.ids = append(store.ids, entry.ID)
+	}
+
+	store.entries[entry.ID] = entry
+}
+
+// readRecordLocked reads the record, which passed entry points to.
+//
+// The store must be locked.
+func (store *FileStore) readRecordLocked(entry fileIndexEntry) (Record, error) {
+	data := make([]byte, entry.Length)
+
+	if _, err := store.log.ReadAt(data, entry.Offset); err != nil {
+		return Record{}, fmt.Errorf("ReadAt(%d): %w", entry.Offset, err)
+	}
+
+	var record fileRecord
+
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, fmt.Errorf("Unmarshal(): %w", err)
+	}
+
+	var moves []string
+	if record.Moves != "" {
+		moves = strings.Split(record.Moves, " ")
+	}
+
+	return Record{
+		ID:          record.ID,
+		Variant:     record.Variant,
+		White:       record.White,
+		Black:       record.Black,
+		FEN:         record.FEN,
+		Moves:       moves,
+		Result:      record.Result,
+		Termination: record.Termination,
+	}, nil
+}
+
+// readFileLines calls passed function with the offset and the content of each complete line of the file starting
+// from passed offset.
+//
+// Returns the end of the last complete line.
+func readFileLines(file *os.File, offset int64, handle func(offset int64, line []byte) error) (int64, error) {
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, 1<<62)) //nolint:mnd // Until the end of the file.
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("ReadBytes(): %w", err)
+		}
+
+		if err := handle(offset, bytes.TrimSuffix(line, []byte{'\n'})); err != nil {
+			return 0, fmt.Errorf("line at %d: %w", offset, err)
+		}
+
+		offset += int64(len(line))
+	}
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("OpenFileStore(): %v", err)
+	}
+
+	testGameStore(t, store)
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	reopened, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("OpenFileStore() again: %v", err)
+	}
+	defer reopened.Close() //nolint:errcheck // The test is over.
+
+	ongoing, err := reopened.ListOngoing()
+	if err != nil || len(ongoing) != 1 || ongoing[0].ID != "3" {
+		t.Fatalf("ListOngoing() expected game 3 after the reopen but got %+v, %v", ongoing, err)
+	}
+}
+
+func TestFileStoreRecovery(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("OpenFileStore(): %v", err)
+	}
+
+	records := []Record{
+		{ID: "1", Variant: "Standard", White: "alice", Black: "bob", Moves: []string{"e2e4"}},
+		{ID: "2", Variant: "Standard", White: "carol", Black: "dave", Moves: []string{"d2d4", "d7d5"}},
+	}
+
+	for _, record := range records {
+		if err := store.Save(record); err != nil {
+			t.Fatalf("Save(%s): %v", record.ID, err)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	// Lose the index entry of the second record and write the part of the third record as after the crash.
+	indexPath := filepath.Join(dir, fileIndexName)
+
+	index, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("ReadFile(index): %v", err)
+	}
+
+	if err := os.WriteFile(indexPath, index[:bytes.IndexByte(index, '\n')+3], 0o600); err != nil {
+		t.Fatalf("WriteFile(index): %v", err)
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, fileLogName), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile(log): %v", err)
+	}
+
+	if _, err := log.WriteString(`{"id":"3","vari`); err != nil {
+		t.Fatalf("WriteString(log): %v", err)
+	}
+
+	log.Close() //nolint:errcheck // Nothing to lose on the test file close error.
+
+	recovered, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("OpenFileStore() after the crash: %v", err)
+	}
+	defer recovered.Close() //nolint:errcheck // The test is over.
+
+	ongoing, err := recovered.ListOngoing()
+	if err != nil {
+		t.Fatalf("ListOngoing(): %v", err)
+	}
+
+	if !reflect.DeepEqual(ongoing, records) {
+		t.Fatalf("ListOngoing() expected %+v but got %+v", records, ongoing)
+	}
+
+	third := Record{ID: "3", Variant: "Standard", White: "erin", Black: "frank"}
+	if err := recovered.Save(third); err != nil {
+		t.Fatalf("Save(3): %v", err)
+	}
+
+	if record, err := recovered.Load("3"); err != nil || !reflect.DeepEqual(record, third) {
+		t.Fatalf("Load(3) expected %+v but got %+v, %v", third, record, err)
+	}
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+// MemoryStore is the GameStore, which keeps the records in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+	// IDs of the records in the order of the first save.
+	ids []string
+}
+
+// NewMemoryStore creates a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Save inserts the record or replaces the record with the same ID.
+func (store *MemoryStore) Save(record Record) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.records[record.ID]; !ok {
+		store.ids = append(store.ids, record.ID)
+	}
+
+	record.Moves = slices.Clone(record.Moves)
+	store.records[record.ID] = record
+
+	return nil
+}
+
+// Load returns the record with passed ID or ErrNotFound.
+func (store *MemoryStore) Load(id string) (Record, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	record, ok := store.records[id]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+
+	record.Moves = slices.Clone(record.Moves)
+
+	return record, nil
+}
+
+// ListByPlayer returns the records of the games of passed player in the order of the first save.
+func (store *MemoryStore) ListByPlayer(player string) ([]Record, error) {
+	return store.list(func(record Record) bool {
+		return record.checkPlayer(player)
+	}), nil
+}
+
+// ListOngoing returns the records of the games in progress in the order of the first save.
+func (store *MemoryStore) ListOngoing() ([]Record, error) {
+	return store.list(func(record Record) bool {
+		return record.Result == game.ResultNil
+	}), nil
+}
+
+// list returns the records, which match passed filter, in the order of the first save.
+func (store *MemoryStore) list(filter func(Record) bool) []Record {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var records []Record
+
+	for _, id := range store.ids {
+		if record := store.records[id]; filter(record) {
+			record.Moves = slices.Clone(record.Moves)
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
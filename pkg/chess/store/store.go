@@ -0,0 +1,146 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// ErrNotFound is returned if there is no game with passed ID in the store.
+var ErrNotFound = errors.New("game not found")
+
+// GameStore keeps the games.
+type GameStore interface {
+	// Save inserts the record or replaces the record with the same ID.
+	Save(record Record) error
+	// Load returns the record with passed ID or ErrNotFound.
+	Load(id string) (Record, error)
+	// ListByPlayer returns the records of the games of passed player in the order of the first save.
+	ListByPlayer(player string) ([]Record, error)
+	// ListOngoing returns the records of the games in progress in the order of the first save.
+	ListOngoing() ([]Record, error)
+}
+
+// Record is the stored game. The positions are not stored and are rebuilt by the replay of the moves.
+type Record struct {
+	ID      string
+	Variant string
+	White   string
+	Black   string
+	// FEN of the start position.
+	FEN string
+	// Moves in UCI.
+	Moves       []string
+	Result      game.Result
+	Termination game.Termination
+}
+
+// NewRecordFromGame creates the record of passed game.
+func NewRecordFromGame(id, white, black string, g *game.Game) (Record, error) {
+	positions := g.Positions()
+	if len(positions) == 0 {
+		return Record{}, errors.New("no positions")
+	}
+
+	fen, err := positions[0].FEN()
+	if err != nil {
+		return Record{}, fmt.Errorf("FEN(): %w", err)
+	}
+
+	moves := g.Moves()
+	ucis := make([]string, 0, len(moves))
+
+	for _, m := range moves {
+		uci, err := m.UCI()
+		if err != nil {
+			return Record{}, fmt.Errorf("UCI(%+v): %w", m, err)
+		}
+
+		ucis = append(ucis, uci)
+	}
+
+	result, termination, err := g.Result()
+	if err != nil {
+		return Record{}, fmt.Errorf("Result(): %w", err)
+	}
+
+	return Record{
+		ID:          id,
+		Variant:     g.Variant().Name(),
+		White:       white,
+		Black:       black,
+		FEN:         fen,
+		Moves:       ucis,
+		Result:      result,
+		Termination: termination,
+	}, nil
+}
+
+// NewGame rebuilds the game by the replay of the moves from the start position. The result, which is not calculated
+// from the positions, for example, the timeout, is restored from the record.
+func (record Record) NewGame(engine game.Engine) (*game.Game, error) {
+	variant, err := game.NewVariantFromName(engine, record.Variant)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantFromName(%q): %w", record.Variant, err)
+	}
+
+	pos, err := position.NewPositionFromFEN(record.FEN)
+	if err != nil {
+		return nil, fmt.Errorf("NewPositionFromFEN(%q): %w", record.FEN, err)
+	}
+
+	g := game.NewGame(variant, []*position.Position{pos}, nil)
+
+	for index, uci := range record.Moves {
+		m, err := calcMoveFromUCI(variant, g.Position(), uci)
+		if err != nil {
+			return nil, fmt.Errorf("move #%d, calcMoveFromUCI(%q): %w", index, uci, err)
+		}
+
+		if err := g.Move(m); err != nil {
+			return nil, fmt.Errorf("move #%d, Move(%+v): %w", index, m, err)
+		}
+	}
+
+	result, _, err := g.Result()
+	if err != nil {
+		return nil, fmt.Errorf("Result(): %w", err)
+	}
+
+	if result == game.ResultNil && record.Result != game.ResultNil {
+		if err := g.End(record.Result, record.Termination); err != nil {
+			return nil, fmt.Errorf("End(%s, %s): %w", record.Result, record.Termination, err)
+		}
+	}
+
+	return g, nil
+}
+
+// checkPlayer checks that passed player plays in the game of the record.
+func (record Record) checkPlayer(player string) bool {
+	return record.White == player || record.Black == player
+}
+
+// calcMoveFromUCI finds the possible move of the variant in passed position by its UCI.
+func calcMoveFromUCI(variant game.Variant, pos *position.Position, uci string) (move.Move, error) {
+	moves, err := variant.CalcMoves(pos)
+	if err != nil {
+		return move.Move{}, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	for _, m := range moves {
+		moveUCI, err := m.UCI()
+		if err != nil {
+			return move.Move{}, fmt.Errorf("UCI(%+v): %w", m, err)
+		}
+
+		if moveUCI == uci {
+			return m, nil
+		}
+	}
+
+	return move.Move{}, errors.New("no possible move")
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+)
+
+func TestRecordNewGame(t *testing.T) {
+	t.Parallel()
+
+	engine := game.Engine{}
+
+	g, err := game.NewGameFromUCIs("", "e2e4", "e7e5", "g1f3")
+	if err != nil {
+		t.Fatalf("NewGameFromUCIs(): %v", err)
+	}
+
+	if err := g.Timeout(piece.ColorBlack); err != nil {
+		t.Fatalf("Timeout(): %v", err)
+	}
+
+	record, err := NewRecordFromGame("1", "alice", "bob", g)
+	if err != nil {
+		t.Fatalf("NewRecordFromGame(): %v", err)
+	}
+
+	expected := Record{
+		ID:          "1",
+		Variant:     "Standard",
+		White:       "alice",
+		Black:       "bob",
+		FEN:         "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		Moves:       []string{"e2e4", "e7e5", "g1f3"},
+		Result:      game.ResultWhiteWon,
+		Termination: game.TerminationTimeout,
+	}
+
+	if !reflect.DeepEqual(record, expected) {
+		t.Fatalf("NewRecordFromGame() expected %+v but got %+v", expected, record)
+	}
+
+	replayed, err := record.NewGame(engine)
+	if err != nil {
+		t.Fatalf("NewGame(): %v", err)
+	}
+
+	if !reflect.DeepEqual(replayed.Positions(), g.Positions()) || !reflect.DeepEqual(replayed.Moves(), g.Moves()) {
+		t.Fatal("NewGame() expected the same positions and moves as the original game")
+	}
+
+	result, termination, err := replayed.Result()
+	if err != nil || result != game.ResultWhiteWon || termination != game.TerminationTimeout {
+		t.Fatalf("Result() expected white won by timeout but got %s, %s, %v", result, termination, err)
+	}
+
+	record.Moves = append(record.Moves, "e1e3")
+	errString := `move #3, calcMoveFromUCI("e1e3"): no possible move`
+
+	if _, err := record.NewGame(engine); err == nil || err.Error() != errString {
+		t.Fatalf("NewGame() expected error %q but got %q", errString, err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	testGameStore(t, NewMemoryStore())
+}
+
+// testGameStore checks the behaviour of GameStore, which is common for all implementations.
+func testGameStore(t *testing.T, store GameStore) {
+	t.Helper()
+
+	records := []Record{
+		{ID: "1", Variant: "Standard", White: "alice", Black: "bob", Moves: []string{"e2e4"}},
+		{ID: "2", Variant: "Standard", White: "carol", Black: "alice", Result: game.ResultDraw},
+		{ID: "3", Variant: "Atomic", White: "bob", Black: "carol", Moves: []string{"d2d4", "d7d5"}},
+	}
+
+	for _, record := range records {
+		if err := store.Save(record); err != nil {
+			t.Fatalf("Save(%s): %v", record.ID, err)
+		}
+	}
+
+	records[0].Moves = append(records[0].Moves, "e7e5")
+	records[0].Result, records[0].Termination = game.ResultBlackWon, game.TerminationTimeout
+
+	if err := store.Save(records[0]); err != nil {
+		t.Fatalf("Save(%s) again: %v", records[0].ID, err)
+	}
+
+	record, err := store.Load("1")
+	if err != nil {
+		t.Fatalf("Load(1): %v", err)
+	}
+
+	if !reflect.DeepEqual(record, records[0]) {
+		t.Fatalf("Load(1) expected %+v but got %+v", records[0], record)
+	}
+
+	if _, err := store.Load("4"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load(4) expected ErrNotFound but got %v", err)
+	}
+
+	byPlayer, err := store.ListByPlayer("alice")
+	if err != nil {
+		t.Fatalf("ListByPlayer(alice): %v", err)
+	}
+
+	if !reflect.DeepEqual(byPlayer, records[:2]) {
+		t.Fatalf("ListByPlayer(alice) expected %+v but got %+v", records[:2], byPlayer)
+	}
+
+	ongoing, err := store.ListOngoing()
+	if err != nil {
+		t.Fatalf("ListOngoing(): %v", err)
+	}
+
+	if !reflect.DeepEqual(ongoing, records[2:]) {
+		t.Fatalf("ListOngoing() expected %+v but got %+v", records[2:], ongoing)
+	}
+}
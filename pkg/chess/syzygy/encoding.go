@@ -0,0 +1,222 @@
+package syzygy
+
+// Squares are numbered as in the tablebase files: A1 is 0, B1 is 1 and H8 is 63.
+
+const (
+	// Maximum count of pieces in the supported tables.
+	encodingMaxPieces = 5
+
+	// Count of the squares on the board.
+	encodingSquaresCount = 64
+
+	// Count of the legal placements of two kings, where the first one is in the A1-D1-D4 triangle.
+	encodingKingsPlacementsCount = 462
+
+	// Count of the placements of three unique pieces, where the first one is in the A1-D1-D4 triangle.
+	encodingUniquePiecesPlacementsCount = 31332
+)
+
+var (
+	// Binomial coefficients: encodingBinomial[k][n] is the count of ways to choose k elements from n.
+	encodingBinomial = newEncodingBinomial()
+
+	// Encodes the squares below the A1-H8 diagonal to 0..27.
+	encodingMapB1H1H7 = newEncodingMapB1H1H7()
+
+	// Encodes the squares of the A1-D1-D4 triangle to 0..9. Diagonal squares are encoded as the last ones.
+	encodingMapA1D1D4 = newEncodingMapA1D1D4()
+
+	// Encodes the legal placements of two kings, where the first one is in the A1-D1-D4 triangle, to 0..461.
+	encodingMapKK = newEncodingMapKK()
+
+	// Encodes the pawn squares A2-H7 to 0..47. The pawn with the greatest value is the leading one: the nearest to
+	// the edge and, among the pawns on the same file, the one with the lowest rank.
+	encodingMapPawns = newEncodingMapPawns()
+
+	// encodingLeadPawnsIndexes[count][square] is the index of the leading pawns group by the leading pawn square and
+	// encodingLeadPawnsSizes[count][file] is the count of the leading pawns group placements by the leading pawn file.
+	encodingLeadPawnsIndexes, encodingLeadPawnsSizes = newEncodingLeadPawns()
+)
+
+// calcOffA1H8 returns positive value if passed square is above the A1-H8 diagonal, negative if below and zero if on.
+func calcOffA1H8(square int) int {
+	return square>>3 - square&7
+}
+
+// calcEdgeDistance returns the distance of passed file from the nearest board edge.
+func calcEdgeDistance(file int) int {
+	return min(file, 7-file) //nolint:mnd // H file.
+}
+
+// checkKingsAdjacent checks that passed squares are equal or adjacent.
+func checkKingsAdjacent(first, second int) bool {
+	rankDistance := max(first>>3-second>>3, second>>3-first>>3)
+	fileDistance := max(first&7-second&7, second&7-first&7)
+
+	return rankDistance <= 1 && fileDistance <= 1
+}
+
+// flipDiagonal mirrors passed square along the A1-H8 diagonal.
+func flipDiagonal(square int) int {
+	return (square>>3 | square<<3) & 63 //nolint:mnd // Squares mask.
+}
+
+// newEncodingBinomial calculates binomial coefficients table using Pascal rule.
+func newEncodingBinomial() [encodingMaxPieces + 1][encodingSquaresCount]uint64 {
+	var binomial [encodingMaxPieces + 1][encodingSquaresCount]uint64
+
+	binomial[0][0] = 1
+
+	for n := 1; n < encodingSquaresCount; n++ {
+		for k := 0; k <= encodingMaxPieces && k <= n; k++ {
+			if k > 0 {
+				binomial[k][n] += binomial[k-1][n-1]
+			}
+
+			if k < n {
+				binomial[k][n] += binomial[k][n-1]
+			}
+		}
+	}
+
+	return binomial
+}
+
+// newEncodingLeadPawns calculates the indexes and the sizes of the leading pawns groups.
+func newEncodingLeadPawns() (
+	[encodingMaxPieces + 1][encodingSquaresCount]uint64, [encodingMaxPieces + 1][4]uint64,
+) {
+	var (
+		indexes [encodingMaxPieces + 1][encodingSquaresCount]uint64
+		sizes   [encodingMaxPieces + 1][4]uint64
+	)
+
+	for count := 1; count <= encodingMaxPieces; count++ {
+		for file := range 4 {
+			var index uint64
+
+			for rank := 1; rank <= 6; rank++ { //nolint:mnd // Pawns stand on the second to seventh ranks.
+				square := rank<<3 | file
+
+				indexes[count][square] = index
+				index += encodingBinomial[count-1][encodingMapPawns[square]]
+			}
+
+			sizes[count][file] = index
+		}
+	}
+
+	return indexes, sizes
+}
+
+// newEncodingMapA1D1D4 calculates the encoding of the A1-D1-D4 triangle squares.
+func newEncodingMapA1D1D4() [encodingSquaresCount]int {
+	var (
+		encoding [encodingSquaresCount]int
+		diagonal []int
+		code     int
+	)
+
+	for square := range 28 { //nolint:mnd // Squares until D4.
+		switch {
+		case square&7 > 3: //nolint:mnd // Files after D.
+			continue
+		case calcOffA1H8(square) < 0:
+			encoding[square] = code
+			code++
+		case calcOffA1H8(square) == 0:
+			diagonal = append(diagonal, square)
+		}
+	}
+
+	for _, square := range diagonal {
+		encoding[square] = code
+		code++
+	}
+
+	return encoding
+}
+
+// newEncodingMapB1H1H7 calculates the encoding of the squares below the A1-H8 diagonal.
+func newEncodingMapB1H1H7() [encodingSquaresCount]int {
+	var (
+		encoding [encodingSquaresCount]int
+		code     int
+	)
+
+	for square := range encodingSquaresCount {
+		if calcOffA1H8(square) < 0 {
+			encoding[square] = code
+			code++
+		}
+	}
+
+	return encoding
+}
+
+// newEncodingMapKK calculates the encoding of the legal two kings placements.
+//
+// If the first king is on the A1-D4 diagonal, the second one is not above the A1-H8 diagonal. Placements with both
+// kings on the diagonal are encoded as the last ones.
+func newEncodingMapKK() [10][encodingSquaresCount]int {
+	type placement struct {
+		index  int
+		square int
+	}
+
+	var (
+		encoding      [10][encodingSquaresCount]int
+		bothDiagonals []placement
+		code          int
+	)
+
+	for index := range 10 {
+		for first := range 28 { //nolint:mnd // Squares until D4.
+			// B1 is encoded as zero as well as the squares out of the triangle.
+			if encodingMapA1D1D4[first] != index || (index == 0 && first != 1) || first&7 > 3 {
+				continue
+			}
+
+			for second := range encodingSquaresCount {
+				switch {
+				case checkKingsAdjacent(first, second):
+					continue
+				case calcOffA1H8(first) == 0 && calcOffA1H8(second) > 0:
+					continue
+				case calcOffA1H8(first) == 0 && calcOffA1H8(second) == 0:
+					bothDiagonals = append(bothDiagonals, placement{index: index, square: second})
+				default:
+					encoding[index][second] = code
+					code++
+				}
+			}
+		}
+	}
+
+	for _, p := range bothDiagonals {
+		encoding[p.index][p.square] = code
+		code++
+	}
+
+	return encoding
+}
+
+// newEncodingMapPawns calculates the encoding of the pawn squares.
+func newEncodingMapPawns() [encodingSquaresCount]uint64 {
+	var encoding [encodingSquaresCount]uint64
+
+	// Available squares for other pawns when the leading pawn is on A2.
+	available := uint64(47) //nolint:mnd // 64 squares minus the first and the last ranks minus A2.
+
+	for file := range 4 {
+		for rank := 1; rank <= 6; rank++ { //nolint:mnd // Pawns stand on the second to seventh ranks.
+			square := rank<<3 | file
+
+			encoding[square] = available
+			encoding[square^7] = available - 1
+			available -= 2
+		}
+	}
+
+	return encoding
+}
@@ -0,0 +1,64 @@
+package syzygy
+
+import "testing"
+
+func TestEncodingBinomial(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		k    int
+		n    int
+		want uint64
+	}{
+		{0, 0, 1},
+		{1, 63, 63},
+		{2, 48, 1128},
+		{3, 62, 37820},
+		{5, 63, 7028847},
+	}
+
+	for _, test := range tests {
+		if got := encodingBinomial[test.k][test.n]; got != test.want {
+			t.Fatalf("encodingBinomial[%d][%d] expected %d but got %d", test.k, test.n, test.want, got)
+		}
+	}
+}
+
+func TestEncodingMapKK(t *testing.T) {
+	t.Parallel()
+
+	// Maximum code plus one must be the count of the kings placements.
+	maxCode := 0
+
+	for index := range encodingMapKK {
+		for _, code := range encodingMapKK[index] {
+			maxCode = max(maxCode, code)
+		}
+	}
+
+	if maxCode+1 != encodingKingsPlacementsCount {
+		t.Fatalf("expected %d placements but got %d", encodingKingsPlacementsCount, maxCode+1)
+	}
+}
+
+func TestEncodingMapPawns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		square int
+		want   uint64
+	}{
+		// A2, H2, A7, D7 and E7.
+		{8, 47},
+		{15, 46},
+		{48, 37},
+		{51, 1},
+		{52, 0},
+	}
+
+	for _, test := range tests {
+		if got := encodingMapPawns[test.square]; got != test.want {
+			t.Fatalf("encodingMapPawns[%d] expected %d but got %d", test.square, test.want, got)
+		}
+	}
+}
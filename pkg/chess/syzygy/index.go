@@ -0,0 +1,311 @@
+package syzygy
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Piece codes and squares of the position in the tablebase encoding.
+type tablePieces struct {
+	codes   []uint8
+	squares []int
+}
+
+// probeState represents the additional result of the table probe.
+type probeState uint8
+
+const (
+	probeStateOK probeState = iota
+	// The best move zeroes the DTZ, so the stored DTZ value is "don't care" and must not be used.
+	probeStateZeroingBestMove
+	// The DTZ table stores the values for the other side to move.
+	probeStateChangeSTM
+)
+
+// probe probes the value of passed position pieces with passed side to move.
+//
+// WDL argument is used only to map DTZ values. Returned WDL values are shifted by two: zero is a loss and four is a
+// win.
+func (tbl *table) probe(pieces tablePieces, materialKey string, blackToMove bool, wdl WDL) (int, probeState, error) {
+	squares := make([]int, 0, tbl.piecesCount)
+	codes := make([]uint8, 0, tbl.piecesCount)
+
+	// Tables store white as the stronger side and symmetric tables store only white to move, so flip the colors and
+	// the squares if needed.
+	flip := (tbl.key == tbl.swappedKey && blackToMove) || materialKey != tbl.key
+
+	var (
+		flipColor   uint8
+		flipSquares int
+		stm         int
+	)
+
+	if flip {
+		flipColor, flipSquares = tablePieceCodeBlack, 56 //nolint:mnd // Rank flip mask.
+	}
+
+	if flip != blackToMove {
+		stm = 1
+	}
+
+	var (
+		leadPawnsCount int
+		leadFile       int
+		leadPawnCode   uint8
+	)
+
+	// The leading pawns are the pawns of the color of the first piece of the tables with pawns.
+	if tbl.hasPawns {
+		leadPawnCode = tbl.get(0, 0).pieces[0] ^ flipColor
+
+		for index, code := range pieces.codes {
+			if code == leadPawnCode {
+				squares = append(squares, pieces.squares[index]^flipSquares)
+			}
+		}
+
+		leadPawnsCount = len(squares)
+
+		if leadPawnsCount == 0 {
+			return 0, probeStateOK, errors.New("no leading pawns")
+		}
+
+		// The leading pawn is the one with the maximum encoding value.
+		maxIndex := 0
+
+		for index, square := range squares {
+			if encodingMapPawns[square] > encodingMapPawns[squares[maxIndex]] {
+				maxIndex = index
+			}
+		}
+
+		squares[0], squares[maxIndex] = squares[maxIndex], squares[0]
+		leadFile = calcEdgeDistance(squares[0] & 7)
+
+		for range leadPawnsCount {
+			codes = append(codes, leadPawnCode^flipColor)
+		}
+	}
+
+	// DTZ tables store only one side to move.
+	if tbl.kind == tableKindDTZ {
+		storedSTM := int(tbl.get(0, leadFile).flags & tablePairsFlagSTM)
+		if storedSTM != stm && (tbl.key != tbl.swappedKey || tbl.hasPawns) {
+			return 0, probeStateChangeSTM, nil
+		}
+	}
+
+	for index, code := range pieces.codes {
+		if tbl.hasPawns && code == leadPawnCode {
+			continue
+		}
+
+		squares = append(squares, pieces.squares[index]^flipSquares)
+		codes = append(codes, code^flipColor)
+	}
+
+	if len(squares) != tbl.piecesCount {
+		return 0, probeStateOK, fmt.Errorf("expected %d pieces but got %d", tbl.piecesCount, len(squares))
+	}
+
+	pairs := tbl.get(stm, leadFile)
+
+	// Reorder the pieces to the same sequence as in the table.
+	for i := leadPawnsCount; i < len(codes)-1; i++ {
+		for j := i + 1; j < len(codes); j++ {
+			if pairs.pieces[i] == codes[j] {
+				codes[i], codes[j] = codes[j], codes[i]
+				squares[i], squares[j] = squares[j], squares[i]
+
+				break
+			}
+		}
+	}
+
+	index := tbl.calcIndex(pairs, squares, leadPawnsCount)
+
+	value, err := tbl.decompressPairs(pairs, index)
+	if err != nil {
+		return 0, probeStateOK, fmt.Errorf("decompressPairs(%d): %w", index, err)
+	}
+
+	if tbl.kind == tableKindWDL {
+		return value, probeStateOK, nil
+	}
+
+	dtz, err := tbl.mapDTZ(leadFile, value, wdl)
+	if err != nil {
+		return 0, probeStateOK, fmt.Errorf("mapDTZ(%d, %d, %s): %w", leadFile, value, wdl, err)
+	}
+
+	return dtz, probeStateOK, nil
+}
+
+// calcIndex calculates the index of passed squares ordered as the table pieces.
+//
+// Note that passed squares are modified.
+func (tbl *table) calcIndex(pairs *pairsData, squares []int, leadPawnsCount int) uint64 {
+	// Mirror the squares so that the leading piece is on the files A-D.
+	if squares[0]&7 > 3 { //nolint:mnd // D file.
+		for index := range squares {
+			squares[index] ^= 7
+		}
+	}
+
+	var index uint64
+
+	if tbl.hasPawns {
+		index = encodingLeadPawnsIndexes[leadPawnsCount][squares[0]]
+
+		slices.SortStableFunc(squares[1:leadPawnsCount], func(left, right int) int {
+			return cmp.Compare(encodingMapPawns[left], encodingMapPawns[right])
+		})
+
+		for i := 1; i < leadPawnsCount; i++ {
+			index += encodingBinomial[i][encodingMapPawns[squares[i]]]
+		}
+	} else {
+		index = tbl.calcLeadingPiecesIndex(pairs, squares)
+	}
+
+	index *= pairs.groupIndexes[0]
+
+	// Encode the remaining pawns and then the pieces by ascending squares.
+	remainingPawns := tbl.hasPawns && tbl.pawnsCounts[1] > 0
+	groupStart := pairs.groupLens[0]
+
+	for group := 1; pairs.groupLens[group] != 0; group++ {
+		groupSquares := squares[groupStart : groupStart+pairs.groupLens[group]]
+		slices.Sort(groupSquares)
+
+		var groupIndex uint64
+
+		for i, square := range groupSquares {
+			// Squares of the previous groups are not available, so shift the square down.
+			adjust := 0
+
+			for _, previous := range squares[:groupStart] {
+				if square > previous {
+					adjust++
+				}
+			}
+
+			if remainingPawns {
+				adjust += 8 //nolint:mnd // Pawns are not on the first rank.
+			}
+
+			groupIndex += encodingBinomial[i+1][square-adjust]
+		}
+
+		remainingPawns = false
+		index += groupIndex * pairs.groupIndexes[group]
+		groupStart += pairs.groupLens[group]
+	}
+
+	return index
+}
+
+// calcLeadingPiecesIndex calculates the index of the leading group of the table without pawns.
+//
+// Note that passed squares are modified.
+func (tbl *table) calcLeadingPiecesIndex(pairs *pairsData, squares []int) uint64 {
+	// Mirror the squares so that the leading piece is on the ranks 1-4.
+	if squares[0]>>3 > 3 { //nolint:mnd // Fourth rank.
+		for index := range squares {
+			squares[index] ^= 56
+		}
+	}
+
+	// Mirror the squares along the diagonal so that the first piece of the leading group, which is not on the
+	// diagonal, is below it.
+	for i := range pairs.groupLens[0] {
+		if calcOffA1H8(squares[i]) == 0 {
+			continue
+		}
+
+		if calcOffA1H8(squares[i]) > 0 {
+			for j := i; j < len(squares); j++ {
+				squares[j] = flipDiagonal(squares[j])
+			}
+		}
+
+		break
+	}
+
+	if !tbl.hasUniquePieces {
+		return uint64(encodingMapKK[encodingMapA1D1D4[squares[0]]][squares[1]]) //nolint:gosec // Non-negative.
+	}
+
+	adjust1 := boolToInt(squares[1] > squares[0])
+	adjust2 := boolToInt(squares[2] > squares[0]) + boolToInt(squares[2] > squares[1])
+
+	var index int
+
+	//nolint:mnd // Counts of the placements of each piece.
+	switch {
+	case calcOffA1H8(squares[0]) != 0:
+		// The first piece is below the diagonal: 6 triangle squares, 63 squares for the second piece and 62 for the
+		// third one.
+		index = (encodingMapA1D1D4[squares[0]]*63+squares[1]-adjust1)*62 + squares[2] - adjust2
+	case calcOffA1H8(squares[1]) != 0:
+		// The first piece is on the diagonal and the second one is below.
+		index = (6*63+(squares[0]>>3)*28+encodingMapB1H1H7[squares[1]])*62 + squares[2] - adjust2
+	case calcOffA1H8(squares[2]) != 0:
+		// The first two pieces are on the diagonal and the third one is below.
+		index = 6*63*62 + 4*28*62 + (squares[0]>>3)*7*28 + (squares[1]>>3-adjust1)*28 + encodingMapB1H1H7[squares[2]]
+	default:
+		// All pieces are on the diagonal.
+		index = 6*63*62 + 4*28*62 + 4*7*28 + (squares[0]>>3)*7*6 + (squares[1]>>3-adjust1)*6 + squares[2]>>3 - adjust2
+	}
+
+	return uint64(index) //nolint:gosec // Non-negative.
+}
+
+// mapDTZ maps passed decompressed DTZ value of the position with passed WDL to the count of plies.
+func (tbl *table) mapDTZ(file, value int, wdl WDL) (int, error) {
+	pairs := tbl.get(0, file)
+
+	// Maps indexes by WDL: losses, blessed losses, draws, cursed wins and wins.
+	mapIndexes := [...]int{1, 3, 0, 2, 0}
+
+	if pairs.flags&tablePairsFlagMapped != 0 {
+		mapIndex := pairs.mapIndexes[mapIndexes[wdl-WDLLoss]] + value
+
+		if pairs.flags&tablePairsFlagWide != 0 {
+			offset := tbl.mapOffset + 2*mapIndex
+			if offset+2 > len(tbl.data) {
+				return 0, errTableEnd
+			}
+
+			value = int(binary.LittleEndian.Uint16(tbl.data[offset:]))
+		} else {
+			offset := tbl.mapOffset + mapIndex
+			if offset >= len(tbl.data) {
+				return 0, errTableEnd
+			}
+
+			value = int(tbl.data[offset])
+		}
+	}
+
+	// Values are stored in moves or in plies, convert to plies.
+	if (wdl == WDLWin && pairs.flags&tablePairsFlagWinPlies == 0) ||
+		(wdl == WDLLoss && pairs.flags&tablePairsFlagLossPlies == 0) ||
+		wdl == WDLCursedWin || wdl == WDLBlessedLoss {
+		value *= 2
+	}
+
+	return value + 1, nil
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
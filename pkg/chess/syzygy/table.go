@@ -0,0 +1,638 @@
+package syzygy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// Table header flags.
+	tableHeaderFlagSplit    = 1
+	tableHeaderFlagHasPawns = 2
+
+	// Pairs data flags.
+	tablePairsFlagSTM         = 1
+	tablePairsFlagMapped      = 2
+	tablePairsFlagWinPlies    = 4
+	tablePairsFlagLossPlies   = 8
+	tablePairsFlagWide        = 16
+	tablePairsFlagSingleValue = 128
+
+	// Tablebase piece codes. Black pieces have the color bit set.
+	tablePieceCodePawn  = 1
+	tablePieceCodeKing  = 6
+	tablePieceCodeBlack = 8
+
+	// Size of the sparse index entry: 32-bit block and 16-bit offset.
+	tableSparseEntrySize = 6
+
+	// Size of the binary tree node: 12-bit left and 12-bit right symbols.
+	tableBinaryTreeNodeSize = 3
+
+	// Right symbol of the binary tree leaf.
+	tableBinaryTreeLeaf = 0xFFF
+)
+
+var (
+	// Magic bytes at the start of the table files.
+	tableWDLMagic = []byte{0x71, 0xE8, 0x23, 0x5D}
+	tableDTZMagic = []byte{0xD7, 0x66, 0x0C, 0xA5}
+
+	errTableEnd = errors.New("unexpected end of table")
+)
+
+// tableKind represents the kind of the table file.
+type tableKind uint8
+
+const (
+	tableKindWDL tableKind = iota
+	tableKindDTZ
+)
+
+// pairsData contains the compressed values of one side and one leading pawn file of the table.
+type pairsData struct {
+	flags uint8
+
+	blockSize   uint64
+	span        uint64
+	blocksCount uint32
+	maxSymLen   int
+	// Minimum symbol length or the value if the table stores single value.
+	minSymLen int
+
+	lowestSymOffset    int
+	binaryTreeOffset   int
+	blockLengthsOffset int
+	blockLengthsSize   int
+	sparseIndexOffset  int
+	sparseIndexSize    uint64
+	dataOffset         int
+
+	// base64[length - minSymLen] is the lowest symbol of the length right-padded to 64 bits.
+	base64 []uint64
+	// Count of values minus one represented by the symbol.
+	symLens []int
+
+	// Piece codes in the order of the encoding.
+	pieces [encodingMaxPieces]uint8
+	// Start indexes and lengths of the pieces groups. Lengths are zero-terminated.
+	groupIndexes [encodingMaxPieces + 1]uint64
+	groupLens    [encodingMaxPieces + 1]int
+
+	// Offsets of the DTZ values maps for wins, losses, cursed wins and blessed losses.
+	mapIndexes [4]int
+}
+
+// table represents the parsed WDL or DTZ table file.
+//
+// The whole file is kept in the memory and the values are decompressed on demand.
+type table struct {
+	kind tableKind
+	data []byte
+
+	// Material keys of the table with white as the stronger side and with swapped colors, for example, "KRvK" and
+	// "KvKR". Keys are equal for the symmetric tables.
+	key        string
+	swappedKey string
+
+	piecesCount     int
+	hasPawns        bool
+	hasUniquePieces bool
+	// Pawn counts of the leading color and the other one.
+	pawnsCounts [2]int
+
+	// Pairs data by side to move and by leading pawn file.
+	items     [2][4]pairsData
+	mapOffset int
+}
+
+// newTable parses passed data of the table with passed material key.
+//
+// Key argument example: "KRPvKR".
+func newTable(kind tableKind, key string, data []byte) (*table, error) {
+	white, black, ok := strings.Cut(key, "v")
+	if !ok {
+		return nil, fmt.Errorf("no color separator in key %q", key)
+	}
+
+	tbl := &table{
+		kind:        kind,
+		data:        data,
+		key:         key,
+		swappedKey:  black + "v" + white,
+		piecesCount: len(white) + len(black),
+	}
+
+	if tbl.piecesCount > encodingMaxPieces {
+		return nil, fmt.Errorf("%d pieces are not supported", tbl.piecesCount)
+	}
+
+	whitePawnsCount, blackPawnsCount := strings.Count(white, "P"), strings.Count(black, "P")
+	tbl.hasPawns = whitePawnsCount+blackPawnsCount > 0
+
+	for _, side := range [...]string{white, black} {
+		for _, role := range "QRBNP" {
+			if strings.Count(side, string(role)) == 1 {
+				tbl.hasUniquePieces = true
+			}
+		}
+	}
+
+	// The leading color is the side with less pawns, because it leads to better compression.
+	tbl.pawnsCounts = [2]int{whitePawnsCount, blackPawnsCount}
+	if blackPawnsCount > 0 && (whitePawnsCount == 0 || blackPawnsCount < whitePawnsCount) {
+		tbl.pawnsCounts = [2]int{blackPawnsCount, whitePawnsCount}
+	}
+
+	if err := tbl.parse(); err != nil {
+		return nil, fmt.Errorf("parse(): %w", err)
+	}
+
+	return tbl, nil
+}
+
+// get returns the pairs data of passed side to move and leading pawn file.
+func (tbl *table) get(stm, file int) *pairsData {
+	if tbl.kind == tableKindDTZ {
+		stm = 0
+	}
+
+	if !tbl.hasPawns {
+		file = 0
+	}
+
+	return &tbl.items[stm][file]
+}
+
+// calcSidesCount returns the count of the sides stored in the table.
+func (tbl *table) calcSidesCount() int {
+	if tbl.kind == tableKindWDL && tbl.key != tbl.swappedKey {
+		return 2 //nolint:mnd // Both sides to move.
+	}
+
+	return 1
+}
+
+// calcMaxFile returns the count of the leading pawn files minus one.
+func (tbl *table) calcMaxFile() int {
+	if tbl.hasPawns {
+		return 3 //nolint:mnd // D file.
+	}
+
+	return 0
+}
+
+// parse parses the table header and sets up all pairs data.
+func (tbl *table) parse() error {
+	magic := tableWDLMagic
+	if tbl.kind == tableKindDTZ {
+		magic = tableDTZMagic
+	}
+
+	if len(tbl.data) < len(magic)+1 || !slices.Equal(tbl.data[:len(magic)], magic) {
+		return errors.New("invalid magic")
+	}
+
+	offset := len(magic)
+
+	headerFlags := tbl.data[offset]
+	if (headerFlags&tableHeaderFlagHasPawns != 0) != tbl.hasPawns {
+		return errors.New("pawns flag does not match the key")
+	}
+
+	if tbl.kind == tableKindWDL && (headerFlags&tableHeaderFlagSplit != 0) != (tbl.key != tbl.swappedKey) {
+		return errors.New("split flag does not match the key")
+	}
+
+	offset++
+
+	sidesCount, maxFile := tbl.calcSidesCount(), tbl.calcMaxFile()
+	bothPawns := tbl.hasPawns && tbl.pawnsCounts[1] > 0
+
+	for file := 0; file <= maxFile; file++ {
+		if offset+1+tbl.piecesCount >= len(tbl.data) {
+			return errTableEnd
+		}
+
+		orders := [2][2]int{{int(tbl.data[offset] & 0xF), 0xF}, {int(tbl.data[offset] >> 4), 0xF}}
+
+		if bothPawns {
+			orders[0][1], orders[1][1] = int(tbl.data[offset+1]&0xF), int(tbl.data[offset+1]>>4)
+			offset++
+		}
+
+		offset++
+
+		for index := range tbl.piecesCount {
+			for side := range sidesCount {
+				code := tbl.data[offset] & 0xF
+				if side == 1 {
+					code = tbl.data[offset] >> 4
+				}
+
+				tbl.get(side, file).pieces[index] = code
+			}
+
+			offset++
+		}
+
+		for side := range sidesCount {
+			tbl.setGroups(tbl.get(side, file), orders[side], file)
+		}
+	}
+
+	offset += offset & 1
+
+	for file := 0; file <= maxFile; file++ {
+		for side := range sidesCount {
+			next, err := tbl.setSizes(tbl.get(side, file), offset)
+			if err != nil {
+				return fmt.Errorf("file %d, side %d, setSizes(%d): %w", file, side, offset, err)
+			}
+
+			offset = next
+		}
+	}
+
+	if tbl.kind == tableKindDTZ {
+		next, err := tbl.setDTZMap(offset, maxFile)
+		if err != nil {
+			return fmt.Errorf("setDTZMap(%d): %w", offset, err)
+		}
+
+		offset = next
+	}
+
+	for file := 0; file <= maxFile; file++ {
+		for side := range sidesCount {
+			pairs := tbl.get(side, file)
+			pairs.sparseIndexOffset = offset
+			offset += int(pairs.sparseIndexSize) * tableSparseEntrySize //nolint:gosec // Checked below.
+		}
+	}
+
+	for file := 0; file <= maxFile; file++ {
+		for side := range sidesCount {
+			pairs := tbl.get(side, file)
+			pairs.blockLengthsOffset = offset
+			offset += pairs.blockLengthsSize * 2 //nolint:mnd // 16-bit lengths.
+		}
+	}
+
+	for file := 0; file <= maxFile; file++ {
+		for side := range sidesCount {
+			// Single value pairs have no data.
+			pairs := tbl.get(side, file)
+			if pairs.flags&tablePairsFlagSingleValue != 0 {
+				continue
+			}
+
+			offset = (offset + 0x3F) &^ 0x3F
+			pairs.dataOffset = offset
+			offset += int(uint64(pairs.blocksCount) * pairs.blockSize) //nolint:gosec // Checked below.
+		}
+	}
+
+	if offset > len(tbl.data) {
+		return errTableEnd
+	}
+
+	return nil
+}
+
+// setDTZMap sets up the maps of the DTZ values starting from passed offset and returns the offset after them.
+func (tbl *table) setDTZMap(offset, maxFile int) (int, error) {
+	tbl.mapOffset = offset
+
+	for file := 0; file <= maxFile; file++ {
+		pairs := tbl.get(0, file)
+		if pairs.flags&tablePairsFlagMapped == 0 {
+			continue
+		}
+
+		if pairs.flags&tablePairsFlagWide != 0 {
+			offset += offset & 1
+
+			for index := range pairs.mapIndexes {
+				if offset+2 > len(tbl.data) {
+					return 0, errTableEnd
+				}
+
+				// Indexes of the 16-bit values after the length.
+				pairs.mapIndexes[index] = (offset-tbl.mapOffset)/2 + 1
+				offset += 2*int(binary.LittleEndian.Uint16(tbl.data[offset:])) + 2 //nolint:mnd // 16-bit values.
+			}
+
+			continue
+		}
+
+		for index := range pairs.mapIndexes {
+			if offset >= len(tbl.data) {
+				return 0, errTableEnd
+			}
+
+			// Indexes of the 8-bit values after the length.
+			pairs.mapIndexes[index] = offset - tbl.mapOffset + 1
+			offset += int(tbl.data[offset]) + 1
+		}
+	}
+
+	return offset + offset&1, nil
+}
+
+// setGroups groups together the pieces, which are encoded together, and calculates the start indexes of the
+// groups in passed order.
+//
+// Pieces of the same type and color form a group. The leading group is formed by the leading pawns or, if there are
+// no pawns, by three first pieces if there are unique pieces and by two kings otherwise. For example, KRvKN is grouped
+// as KRK + N, KNNvK as KK + NN and KPPvKP as P + PP + K + K.
+func (tbl *table) setGroups(pairs *pairsData, order [2]int, file int) {
+	firstLen := 2
+	if tbl.hasPawns {
+		firstLen = 0
+	} else if tbl.hasUniquePieces {
+		firstLen = 3
+	}
+
+	groupsCount := 0
+	pairs.groupLens[0] = 1
+
+	for index := 1; index < tbl.piecesCount; index++ {
+		firstLen--
+
+		if firstLen > 0 || pairs.pieces[index] == pairs.pieces[index-1] {
+			pairs.groupLens[groupsCount]++
+		} else {
+			groupsCount++
+			pairs.groupLens[groupsCount] = 1
+		}
+	}
+
+	groupsCount++
+	pairs.groupLens[groupsCount] = 0
+
+	bothPawns := tbl.hasPawns && tbl.pawnsCounts[1] > 0
+
+	next := 1
+	freeSquares := encodingSquaresCount - pairs.groupLens[0]
+
+	if bothPawns {
+		next = 2
+		freeSquares -= pairs.groupLens[1]
+	}
+
+	index := uint64(1)
+
+	for k := 0; next < groupsCount || k == order[0] || k == order[1]; k++ {
+		switch {
+		case k == order[0]:
+			pairs.groupIndexes[0] = index
+
+			switch {
+			case tbl.hasPawns:
+				index *= encodingLeadPawnsSizes[pairs.groupLens[0]][file]
+			case tbl.hasUniquePieces:
+				index *= encodingUniquePiecesPlacementsCount
+			default:
+				index *= encodingKingsPlacementsCount
+			}
+		case k == order[1]:
+			pairs.groupIndexes[1] = index
+			index *= encodingBinomial[pairs.groupLens[1]][48-pairs.groupLens[0]]
+		default:
+			pairs.groupIndexes[next] = index
+			index *= encodingBinomial[pairs.groupLens[next]][freeSquares]
+			freeSquares -= pairs.groupLens[next]
+			next++
+		}
+	}
+
+	pairs.groupIndexes[groupsCount] = index
+}
+
+// setSizes sets up the sizes and the Huffman code of passed pairs data starting from passed offset and returns the
+// offset after them.
+func (tbl *table) setSizes(pairs *pairsData, offset int) (int, error) {
+	if offset+2 > len(tbl.data) {
+		return 0, errTableEnd
+	}
+
+	pairs.flags = tbl.data[offset]
+	offset++
+
+	if pairs.flags&tablePairsFlagSingleValue != 0 {
+		pairs.minSymLen = int(tbl.data[offset])
+		return offset + 1, nil
+	}
+
+	const headerSize = 10
+
+	if offset+headerSize > len(tbl.data) {
+		return 0, errTableEnd
+	}
+
+	tableSize := pairs.groupIndexes[slices.Index(pairs.groupLens[:], 0)]
+
+	pairs.blockSize = 1 << tbl.data[offset]
+	pairs.span = 1 << tbl.data[offset+1]
+	pairs.sparseIndexSize = (tableSize + pairs.span - 1) / pairs.span
+	padding := int(tbl.data[offset+2])
+	pairs.blocksCount = binary.LittleEndian.Uint32(tbl.data[offset+3:])
+	pairs.blockLengthsSize = int(pairs.blocksCount) + padding
+	pairs.maxSymLen = int(tbl.data[offset+7])
+	pairs.minSymLen = int(tbl.data[offset+8])
+	offset += headerSize - 1
+
+	if pairs.maxSymLen < pairs.minSymLen || pairs.maxSymLen > 64 { //nolint:mnd // Symbols are read from 64 bits.
+		return 0, fmt.Errorf("invalid symbol lengths %d..%d", pairs.minSymLen, pairs.maxSymLen)
+	}
+
+	pairs.lowestSymOffset = offset
+	pairs.base64 = make([]uint64, pairs.maxSymLen-pairs.minSymLen+1)
+	offset += 2 * len(pairs.base64) //nolint:mnd // 16-bit symbols.
+
+	if offset+2 > len(tbl.data) {
+		return 0, errTableEnd
+	}
+
+	// Canonical Huffman code: longer symbols have lower values, so base64 is non-increasing after the padding.
+	for index := len(pairs.base64) - 2; index >= 0; index-- {
+		pairs.base64[index] = (pairs.base64[index+1] + uint64(tbl.readLowestSym(pairs, index)) -
+			uint64(tbl.readLowestSym(pairs, index+1))) / 2 //nolint:mnd // One bit shorter.
+	}
+
+	for index := range pairs.base64 {
+		pairs.base64[index] <<= 64 - index - pairs.minSymLen
+	}
+
+	pairs.symLens = make([]int, binary.LittleEndian.Uint16(tbl.data[offset:]))
+	offset += 2
+	pairs.binaryTreeOffset = offset
+
+	offset += len(pairs.symLens)*tableBinaryTreeNodeSize + len(pairs.symLens)&1
+	if offset > len(tbl.data) {
+		return 0, errTableEnd
+	}
+
+	// Symbols are expanded recursively to the pairs of symbols, so calculate the counts of the values in the leafs.
+	visited := make([]bool, len(pairs.symLens))
+
+	for sym := range pairs.symLens {
+		if !visited[sym] {
+			symLen, err := tbl.calcSymLen(pairs, sym, visited)
+			if err != nil {
+				return 0, fmt.Errorf("calcSymLen(%d): %w", sym, err)
+			}
+
+			pairs.symLens[sym] = symLen
+		}
+	}
+
+	return offset, nil
+}
+
+// calcSymLen calculates the count of values minus one represented by passed symbol.
+func (tbl *table) calcSymLen(pairs *pairsData, sym int, visited []bool) (int, error) {
+	visited[sym] = true
+
+	left, right := tbl.readBinaryTreeNode(pairs, sym)
+	if right == tableBinaryTreeLeaf {
+		return 0, nil
+	}
+
+	if left >= len(pairs.symLens) || right >= len(pairs.symLens) {
+		return 0, fmt.Errorf("symbol %d children %d and %d are out of range", sym, left, right)
+	}
+
+	for _, child := range [...]int{left, right} {
+		if visited[child] {
+			continue
+		}
+
+		childLen, err := tbl.calcSymLen(pairs, child, visited)
+		if err != nil {
+			return 0, fmt.Errorf("calcSymLen(%d): %w", child, err)
+		}
+
+		pairs.symLens[child] = childLen
+	}
+
+	return pairs.symLens[left] + pairs.symLens[right] + 1, nil
+}
+
+// decompressPairs decompresses the value with passed index.
+func (tbl *table) decompressPairs(pairs *pairsData, index uint64) (int, error) {
+	if pairs.flags&tablePairsFlagSingleValue != 0 {
+		return pairs.minSymLen, nil
+	}
+
+	// Sparse index entry k points to the block and the offset of the value with index k * span + span / 2.
+	k := index / pairs.span
+	if k >= pairs.sparseIndexSize {
+		return 0, fmt.Errorf("index %d is out of range", index)
+	}
+
+	entryOffset := pairs.sparseIndexOffset + int(k)*tableSparseEntrySize //nolint:gosec // Checked above.
+	block := int(binary.LittleEndian.Uint32(tbl.data[entryOffset:]))
+	offset := int(binary.LittleEndian.Uint16(tbl.data[entryOffset+4:]))
+	offset += int(index%pairs.span) - int(pairs.span/2) //nolint:gosec,mnd // Span is small. Half of the span.
+
+	for offset < 0 {
+		block--
+
+		if block < 0 {
+			return 0, errTableEnd
+		}
+
+		offset += tbl.readBlockLength(pairs, block) + 1
+	}
+
+	for offset > tbl.readBlockLength(pairs, block) {
+		offset -= tbl.readBlockLength(pairs, block) + 1
+		block++
+
+		if block >= pairs.blockLengthsSize {
+			return 0, errTableEnd
+		}
+	}
+
+	// Read the canonical Huffman symbols from the start of the block until the one containing the offset.
+	dataOffset := pairs.dataOffset + int(uint64(block)*pairs.blockSize) //nolint:gosec // Checked in parse.
+	if dataOffset+int(pairs.blockSize) > len(tbl.data) {                //nolint:gosec // Checked in parse.
+		return 0, errTableEnd
+	}
+
+	buffer := binary.BigEndian.Uint64(tbl.data[dataOffset:])
+	bufferSize := 64
+	dataOffset += 8
+
+	var sym int
+
+	for {
+		length := 0
+
+		for length+1 < len(pairs.base64) && buffer < pairs.base64[length] {
+			length++
+		}
+
+		sym = int((buffer-pairs.base64[length])>>(64-length-pairs.minSymLen)) + int(tbl.readLowestSym(pairs, length))
+		if sym >= len(pairs.symLens) {
+			return 0, fmt.Errorf("symbol %d is out of range", sym)
+		}
+
+		if offset < pairs.symLens[sym]+1 {
+			break
+		}
+
+		offset -= pairs.symLens[sym] + 1
+		length += pairs.minSymLen
+		buffer <<= length
+		bufferSize -= length
+
+		if bufferSize <= 32 { //nolint:mnd // Refill the half of the buffer.
+			if dataOffset+4 > len(tbl.data) {
+				return 0, errTableEnd
+			}
+
+			bufferSize += 32
+			buffer |= uint64(binary.BigEndian.Uint32(tbl.data[dataOffset:])) << (64 - bufferSize)
+			dataOffset += 4
+		}
+	}
+
+	// Expand the symbol to the pair of symbols until the leaf containing the offset.
+	for pairs.symLens[sym] > 0 {
+		left, right := tbl.readBinaryTreeNode(pairs, sym)
+
+		if offset < pairs.symLens[left]+1 {
+			sym = left
+		} else {
+			offset -= pairs.symLens[left] + 1
+			sym = right
+		}
+	}
+
+	left, _ := tbl.readBinaryTreeNode(pairs, sym)
+
+	return left, nil
+}
+
+// readBinaryTreeNode reads the left and the right symbols, which expand passed symbol.
+func (tbl *table) readBinaryTreeNode(pairs *pairsData, sym int) (int, int) {
+	node := tbl.data[pairs.binaryTreeOffset+sym*tableBinaryTreeNodeSize:]
+
+	return int(node[1]&0xF)<<8 | int(node[0]), int(node[2])<<4 | int(node[1]>>4)
+}
+
+// readBlockLength reads the count of values minus one in the block with passed index.
+func (tbl *table) readBlockLength(pairs *pairsData, block int) int {
+	return int(binary.LittleEndian.Uint16(tbl.data[pairs.blockLengthsOffset+2*block:]))
+}
+
+// readLowestSym reads the lowest symbol of length minSymLen plus passed index.
+func (tbl *table) readLowestSym(pairs *pairsData, index int) uint16 {
+	return binary.LittleEndian.Uint16(tbl.data[pairs.lowestSymOffset+2*index:])
+}
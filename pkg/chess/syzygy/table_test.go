@@ -0,0 +1,172 @@
+package syzygy
+
+import (
+	"testing"
+)
+
+// newTestSingleValueTableData creates the data of the table without pawns, which stores single value for each side to
+// move. Pieces are the piece codes in the order of the encoding.
+func newTestSingleValueTableData(kind tableKind, pieces []uint8, values []uint8) []byte {
+	data := append([]byte(nil), tableWDLMagic...)
+	if kind == tableKindDTZ {
+		data = append([]byte(nil), tableDTZMagic...)
+	}
+
+	var headerFlags uint8
+	if len(values) > 1 {
+		headerFlags = tableHeaderFlagSplit
+	}
+
+	// Header flags and the orders of the leading groups.
+	data = append(data, headerFlags, 0)
+
+	for _, code := range pieces {
+		data = append(data, code|code<<4)
+	}
+
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+
+	for _, value := range values {
+		data = append(data, tablePairsFlagSingleValue, value)
+	}
+
+	return data
+}
+
+func TestNewTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		kind      tableKind
+		key       string
+		data      []byte
+		errString string
+	}{
+		{
+			"KRvK WDL",
+			tableKindWDL,
+			"KRvK",
+			newTestSingleValueTableData(tableKindWDL, []uint8{6, 4, 14}, []uint8{4, 0}),
+			"",
+		},
+		{
+			"KRvK DTZ",
+			tableKindDTZ,
+			"KRvK",
+			newTestSingleValueTableData(tableKindDTZ, []uint8{6, 4, 14}, []uint8{5}),
+			"",
+		},
+		{
+			"invalid magic",
+			tableKindDTZ,
+			"KRvK",
+			newTestSingleValueTableData(tableKindWDL, []uint8{6, 4, 14}, []uint8{4, 0}),
+			"parse(): invalid magic",
+		},
+		{
+			"split flag",
+			tableKindWDL,
+			"KRvK",
+			newTestSingleValueTableData(tableKindWDL, []uint8{6, 4, 14}, []uint8{4}),
+			"parse(): split flag does not match the key",
+		},
+		{
+			"pawns flag",
+			tableKindWDL,
+			"KPvK",
+			newTestSingleValueTableData(tableKindWDL, []uint8{6, 1, 14}, []uint8{4, 0}),
+			"parse(): pawns flag does not match the key",
+		},
+		{
+			"truncated",
+			tableKindWDL,
+			"KRvK",
+			newTestSingleValueTableData(tableKindWDL, []uint8{6, 4, 14}, []uint8{4, 0})[:11],
+			"parse(): file 0, side 0, setSizes(10): unexpected end of table",
+		},
+		{
+			"no separator",
+			tableKindWDL,
+			"KRK",
+			nil,
+			`no color separator in key "KRK"`,
+		},
+		{
+			"too many pieces",
+			tableKindWDL,
+			"KQRvKQR",
+			nil,
+			"6 pieces are not supported",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newTable(test.kind, test.key, test.data)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("newTable(%q) expected error %q but got %v", test.key, test.errString, err)
+			}
+		})
+	}
+}
+
+func TestTableProbe(t *testing.T) {
+	t.Parallel()
+
+	wdlTable, err := newTable(
+		tableKindWDL, "KRvK", newTestSingleValueTableData(tableKindWDL, []uint8{6, 4, 14}, []uint8{4, 0}))
+	if err != nil {
+		t.Fatalf("newTable(WDL): %v", err)
+	}
+
+	dtzTable, err := newTable(
+		tableKindDTZ, "KRvK", newTestSingleValueTableData(tableKindDTZ, []uint8{6, 4, 14}, []uint8{5}))
+	if err != nil {
+		t.Fatalf("newTable(DTZ): %v", err)
+	}
+
+	// White king on A1, white rook on E4 and black king on H8.
+	whiteRook := tablePieces{codes: []uint8{6, 4, 14}, squares: []int{0, 28, 63}}
+	// White king on A1, black rook on E4 and black king on H8.
+	blackRook := tablePieces{codes: []uint8{6, 12, 14}, squares: []int{0, 28, 63}}
+
+	tests := []struct {
+		name        string
+		tbl         *table
+		pieces      tablePieces
+		key         string
+		blackToMove bool
+		wdl         WDL
+		value       int
+		state       probeState
+	}{
+		{"WDL white to move", wdlTable, whiteRook, "KRvK", false, WDLDraw, 4, probeStateOK},
+		{"WDL black to move", wdlTable, whiteRook, "KRvK", true, WDLDraw, 0, probeStateOK},
+		{"WDL swapped white to move", wdlTable, blackRook, "KvKR", false, WDLDraw, 0, probeStateOK},
+		{"WDL swapped black to move", wdlTable, blackRook, "KvKR", true, WDLDraw, 4, probeStateOK},
+		{"DTZ win in plies", dtzTable, whiteRook, "KRvK", false, WDLWin, 11, probeStateOK},
+		{"DTZ cursed win", dtzTable, whiteRook, "KRvK", false, WDLCursedWin, 11, probeStateOK},
+		{"DTZ other side to move", dtzTable, whiteRook, "KRvK", true, WDLLoss, 0, probeStateChangeSTM},
+		{"DTZ swapped", dtzTable, blackRook, "KvKR", true, WDLWin, 11, probeStateOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, state, err := test.tbl.probe(test.pieces, test.key, test.blackToMove, test.wdl)
+			if err != nil {
+				t.Fatalf("probe(): %v", err)
+			}
+
+			if value != test.value || state != test.state {
+				t.Fatalf("probe() expected (%d, %d) but got (%d, %d)", test.value, test.state, value, state)
+			}
+		})
+	}
+}
@@ -0,0 +1,574 @@
+package syzygy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/square"
+)
+
+const (
+	// Extensions of the WDL and DTZ table files.
+	tablebaseWDLExtension = ".rtbw"
+	tablebaseDTZExtension = ".rtbz"
+
+	// DTZ of the move before zeroing in the cursed wins and the blessed losses: the fifty moves rule plus one ply.
+	tablebaseCursedDTZ = 101
+
+	// Value greater than any DTZ.
+	tablebaseInfinityDTZ = 0xFFFF
+)
+
+var (
+	// Tablebase piece codes of the pieces.
+	tablebasePieceCodes = map[piece.Piece]uint8{
+		piece.PieceWhitePawn:   1,
+		piece.PieceWhiteKnight: 2,
+		piece.PieceWhiteBishop: 3,
+		piece.PieceWhiteRook:   4,
+		piece.PieceWhiteQueen:  5,
+		piece.PieceWhiteKing:   6,
+		piece.PieceBlackPawn:   9,
+		piece.PieceBlackKnight: 10,
+		piece.PieceBlackBishop: 11,
+		piece.PieceBlackRook:   12,
+		piece.PieceBlackQueen:  13,
+		piece.PieceBlackKing:   14,
+	}
+
+	// Pieces in the order of the material key letters.
+	tablebaseWhitePieces = [...]piece.Piece{
+		piece.PieceWhiteKing, piece.PieceWhiteQueen, piece.PieceWhiteRook,
+		piece.PieceWhiteBishop, piece.PieceWhiteKnight, piece.PieceWhitePawn,
+	}
+	tablebaseBlackPieces = [...]piece.Piece{
+		piece.PieceBlackKing, piece.PieceBlackQueen, piece.PieceBlackRook,
+		piece.PieceBlackBishop, piece.PieceBlackKnight, piece.PieceBlackPawn,
+	}
+
+	// Material key letters of the pieces.
+	tablebasePieceLetters = map[piece.Piece]string{
+		piece.PieceWhiteKing:   "K",
+		piece.PieceWhiteQueen:  "Q",
+		piece.PieceWhiteRook:   "R",
+		piece.PieceWhiteBishop: "B",
+		piece.PieceWhiteKnight: "N",
+		piece.PieceWhitePawn:   "P",
+		piece.PieceBlackKing:   "K",
+		piece.PieceBlackQueen:  "Q",
+		piece.PieceBlackRook:   "R",
+		piece.PieceBlackBishop: "B",
+		piece.PieceBlackKnight: "N",
+		piece.PieceBlackPawn:   "P",
+	}
+)
+
+// WDL represents the win-draw-loss value of the position from the point of view of the active color.
+//
+// Cursed wins and blessed losses are wins and losses, which are draws because of the fifty moves rule.
+type WDL int8
+
+const (
+	WDLLoss WDL = iota - 2
+	WDLBlessedLoss
+	WDLDraw
+	WDLCursedWin
+	WDLWin
+)
+
+// String returns string representation of current WDL.
+func (wdl WDL) String() string {
+	switch wdl {
+	case WDLLoss:
+		return "WDLLoss"
+	case WDLBlessedLoss:
+		return "WDLBlessedLoss"
+	case WDLDraw:
+		return "WDLDraw"
+	case WDLCursedWin:
+		return "WDLCursedWin"
+	case WDLWin:
+		return "WDLWin"
+	default:
+		return fmt.Sprintf("<unknown WDL=%d>", wdl)
+	}
+}
+
+// DTZ represents the distance to zeroing the half move clock in plies with the optimal play: by the capture or by
+// the pawn move. Positive if the active color wins, negative if it loses and zero in the draws.
+//
+// Values in the cursed wins and the blessed losses are greater than 100 by absolute value.
+type DTZ int
+
+// Tablebase probes Syzygy WDL and DTZ tables located in the directory.
+//
+// Tables are read on the first use and kept in the memory. Only tables up to 5 pieces are supported.
+type Tablebase struct {
+	engine game.Engine
+	dir    string
+
+	tables      map[string]*table
+	tablesMutex sync.Mutex
+}
+
+// NewTablebase creates a new Tablebase, which reads the tables from passed directory.
+func NewTablebase(engine game.Engine, dir string) *Tablebase {
+	return &Tablebase{
+		engine: engine,
+		dir:    dir,
+		tables: make(map[string]*table),
+	}
+}
+
+// Probe probes WDL and DTZ of passed position.
+//
+// Returns error wrapping fs.ErrNotExist if a needed table file is missing.
+func (tablebase *Tablebase) Probe(pos *position.Position) (WDL, DTZ, error) {
+	wdl, err := tablebase.ProbeWDL(pos)
+	if err != nil {
+		return WDLDraw, 0, fmt.Errorf("ProbeWDL(): %w", err)
+	}
+
+	dtz, err := tablebase.ProbeDTZ(pos)
+	if err != nil {
+		return WDLDraw, 0, fmt.Errorf("ProbeDTZ(): %w", err)
+	}
+
+	return wdl, dtz, nil
+}
+
+// ProbeDTZ probes DTZ of passed position.
+func (tablebase *Tablebase) ProbeDTZ(pos *position.Position) (DTZ, error) {
+	if err := checkProbable(pos); err != nil {
+		return 0, fmt.Errorf("checkProbable(): %w", err)
+	}
+
+	dtz, err := tablebase.probeDTZ(pos)
+	if err != nil {
+		return 0, fmt.Errorf("probeDTZ(): %w", err)
+	}
+
+	return DTZ(dtz), nil
+}
+
+// ProbeWDL probes WDL of passed position.
+func (tablebase *Tablebase) ProbeWDL(pos *position.Position) (WDL, error) {
+	if err := checkProbable(pos); err != nil {
+		return WDLDraw, fmt.Errorf("checkProbable(): %w", err)
+	}
+
+	wdl, _, err := tablebase.search(pos, false)
+	if err != nil {
+		return WDLDraw, fmt.Errorf("search(): %w", err)
+	}
+
+	return wdl, nil
+}
+
+// getTable returns the table of passed kind for passed material key or the key with swapped colors.
+func (tablebase *Tablebase) getTable(kind tableKind, key string) (*table, error) {
+	extension := tablebaseWDLExtension
+	if kind == tableKindDTZ {
+		extension = tablebaseDTZExtension
+	}
+
+	white, black, _ := strings.Cut(key, "v")
+
+	tablebase.tablesMutex.Lock()
+	defer tablebase.tablesMutex.Unlock()
+
+	for _, tableKey := range [...]string{key, black + "v" + white} {
+		if tbl, ok := tablebase.tables[tableKey+extension]; ok {
+			return tbl, nil
+		}
+	}
+
+	var readErr error
+
+	for _, tableKey := range [...]string{key, black + "v" + white} {
+		path := filepath.Join(tablebase.dir, tableKey+extension)
+
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			readErr = err
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("ReadFile(%q): %w", path, err)
+		}
+
+		tbl, err := newTable(kind, tableKey, data)
+		if err != nil {
+			return nil, fmt.Errorf("newTable(%q): %w", path, err)
+		}
+
+		tablebase.tables[tableKey+extension] = tbl
+
+		return tbl, nil
+	}
+
+	return nil, fmt.Errorf("no table for %s: %w", key, readErr)
+}
+
+// probeDTZ probes DTZ of passed position in plies.
+func (tablebase *Tablebase) probeDTZ(pos *position.Position) (int, error) {
+	wdl, state, err := tablebase.search(pos, true)
+	if err != nil {
+		return 0, fmt.Errorf("search(): %w", err)
+	}
+
+	// DTZ tables do not store draws.
+	if wdl == WDLDraw {
+		return 0, nil
+	}
+
+	// DTZ tables store "don't care" values in this case.
+	if state == probeStateZeroingBestMove {
+		return calcDTZBeforeZeroing(wdl), nil
+	}
+
+	dtz, state, err := tablebase.probeTable(tableKindDTZ, pos, wdl)
+	if err != nil {
+		return 0, fmt.Errorf("probeTable(DTZ, %s): %w", wdl, err)
+	}
+
+	if state != probeStateChangeSTM {
+		if wdl == WDLCursedWin || wdl == WDLBlessedLoss {
+			dtz += 100 //nolint:mnd // Fifty moves rule.
+		}
+
+		return dtz * calcSign(int(wdl)), nil
+	}
+
+	// DTZ table stores the values for the other side to move, so find the best move by one ply search.
+	moves, err := tablebase.engine.CalcMoves(pos)
+	if err != nil {
+		return 0, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	minDTZ := tablebaseInfinityDTZ
+
+	for _, m := range moves {
+		zeroing, err := tablebase.checkZeroing(pos, m)
+		if err != nil {
+			return 0, fmt.Errorf("checkZeroing(%+v): %w", m, err)
+		}
+
+		next, err := newPositionAfterMove(pos, m)
+		if err != nil {
+			return 0, fmt.Errorf("newPositionAfterMove(%+v): %w", m, err)
+		}
+
+		var moveDTZ int
+
+		// For zeroing moves DTZ is calculated before the move, but WDL after the move is needed for the sign.
+		if zeroing {
+			nextWDL, _, err := tablebase.search(next, false)
+			if err != nil {
+				return 0, fmt.Errorf("search(%+v): %w", m, err)
+			}
+
+			moveDTZ = -calcDTZBeforeZeroing(nextWDL)
+		} else {
+			nextDTZ, err := tablebase.probeDTZ(next)
+			if err != nil {
+				return 0, fmt.Errorf("probeDTZ(%+v): %w", m, err)
+			}
+
+			moveDTZ = -nextDTZ
+		}
+
+		// The move mates.
+		if moveDTZ == 1 {
+			mates, err := tablebase.checkCheckmate(next)
+			if err != nil {
+				return 0, fmt.Errorf("checkCheckmate(%+v): %w", m, err)
+			}
+
+			if mates {
+				minDTZ = 1
+			}
+		}
+
+		if !zeroing {
+			moveDTZ += calcSign(moveDTZ)
+		}
+
+		if moveDTZ < minDTZ && calcSign(moveDTZ) == calcSign(int(wdl)) {
+			minDTZ = moveDTZ
+		}
+	}
+
+	// There are no legal moves, so the active color is mated.
+	if minDTZ == tablebaseInfinityDTZ {
+		return -1, nil
+	}
+
+	return minDTZ, nil
+}
+
+// probeTable probes the table of passed kind. WDL is used only to map DTZ values.
+func (tablebase *Tablebase) probeTable(kind tableKind, pos *position.Position, wdl WDL) (int, probeState, error) {
+	pieces, whiteKey, blackKey, err := calcTablePieces(pos)
+	if err != nil {
+		return 0, probeStateOK, fmt.Errorf("calcTablePieces(): %w", err)
+	}
+
+	// Two kings are always draw.
+	if len(pieces.codes) == 2 { //nolint:mnd // Two kings.
+		return 0, probeStateOK, nil
+	}
+
+	key := whiteKey + "v" + blackKey
+
+	tbl, err := tablebase.getTable(kind, key)
+	if err != nil {
+		return 0, probeStateOK, fmt.Errorf("getTable(%s): %w", key, err)
+	}
+
+	value, state, err := tbl.probe(pieces, key, pos.ActiveColor() == piece.ColorBlack, wdl)
+	if err != nil {
+		return 0, probeStateOK, fmt.Errorf("probe(%s): %w", key, err)
+	}
+
+	if kind == tableKindWDL {
+		// WDL values are stored shifted by two.
+		return value + int(WDLLoss), state, nil
+	}
+
+	return value, state, nil
+}
+
+// search probes WDL of passed position taking into account the captures and, if needed, the pawn moves.
+//
+// Tables store "don't care" values in the positions, where the best move is the capture, and do not store the
+// positions with possible en passant captures, so the captures are searched before the table probe.
+func (tablebase *Tablebase) search(pos *position.Position, checkZeroingMoves bool) (WDL, probeState, error) {
+	moves, err := tablebase.engine.CalcMoves(pos)
+	if err != nil {
+		return WDLDraw, probeStateOK, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	bestWDL := WDLLoss
+	movesCount := 0
+
+	for _, m := range moves {
+		capture := m.Tags().Contains(move.MoveTagCapture) || m.Tags().Contains(move.MoveTagEnPassantCapture)
+
+		if !capture && checkZeroingMoves {
+			capture, err = tablebase.checkZeroing(pos, m)
+			if err != nil {
+				return WDLDraw, probeStateOK, fmt.Errorf("checkZeroing(%+v): %w", m, err)
+			}
+		}
+
+		if !capture {
+			continue
+		}
+
+		movesCount++
+
+		next, err := newPositionAfterMove(pos, m)
+		if err != nil {
+			return WDLDraw, probeStateOK, fmt.Errorf("newPositionAfterMove(%+v): %w", m, err)
+		}
+
+		nextWDL, _, err := tablebase.search(next, false)
+		if err != nil {
+			return WDLDraw, probeStateOK, fmt.Errorf("search(%+v): %w", m, err)
+		}
+
+		if -nextWDL > bestWDL {
+			bestWDL = -nextWDL
+
+			if bestWDL >= WDLWin {
+				return bestWDL, probeStateZeroingBestMove, nil
+			}
+		}
+	}
+
+	// If all moves are searched, the table value is not needed and may be wrong.
+	noMoreMoves := movesCount > 0 && movesCount == len(moves)
+
+	wdl := bestWDL
+
+	if !noMoreMoves {
+		value, _, err := tablebase.probeTable(tableKindWDL, pos, WDLDraw)
+		if err != nil {
+			return WDLDraw, probeStateOK, fmt.Errorf("probeTable(WDL): %w", err)
+		}
+
+		wdl = WDL(value) //nolint:gosec // WDL values are small.
+	}
+
+	if bestWDL >= wdl {
+		if bestWDL > WDLDraw || noMoreMoves {
+			return bestWDL, probeStateZeroingBestMove, nil
+		}
+
+		return bestWDL, probeStateOK, nil
+	}
+
+	return wdl, probeStateOK, nil
+}
+
+// checkCheckmate checks that the active color is mated in passed position.
+func (tablebase *Tablebase) checkCheckmate(pos *position.Position) (bool, error) {
+	checked, err := tablebase.engine.CheckChecked(pos, pos.ActiveColor())
+	if err != nil {
+		return false, fmt.Errorf("CheckChecked(%s): %w", pos.ActiveColor(), err)
+	}
+
+	if !checked {
+		return false, nil
+	}
+
+	moves, err := tablebase.engine.CalcMoves(pos)
+	if err != nil {
+		return false, fmt.Errorf("CalcMoves(): %w", err)
+	}
+
+	return len(moves) == 0, nil
+}
+
+// checkZeroing checks that passed move resets the half move clock: it is the capture or the pawn move.
+func (tablebase *Tablebase) checkZeroing(pos *position.Position, m move.Move) (bool, error) {
+	if m.Tags().Contains(move.MoveTagCapture) || m.Tags().Contains(move.MoveTagEnPassantCapture) {
+		return true, nil
+	}
+
+	originPiece, err := pos.Board().GetPieceFromSquare(m.Origin())
+	if err != nil {
+		return false, fmt.Errorf("GetPieceFromSquare(%s): %w", m.Origin(), err)
+	}
+
+	return originPiece == piece.PieceWhitePawn || originPiece == piece.PieceBlackPawn, nil
+}
+
+// CalcMaterialKey calculates the material key of passed position, which is the name of the table file without the
+// extension if white is the stronger side.
+//
+// Return examples: "KQvK", "KRPvKR", "KvKBN".
+func CalcMaterialKey(pos *position.Position) (string, error) {
+	_, whiteKey, blackKey, err := calcTablePieces(pos)
+	if err != nil {
+		return "", fmt.Errorf("calcTablePieces(): %w", err)
+	}
+
+	return whiteKey + "v" + blackKey, nil
+}
+
+// calcDTZBeforeZeroing returns DTZ of the position, where the zeroing move leads to passed WDL.
+func calcDTZBeforeZeroing(wdl WDL) int {
+	switch wdl {
+	case WDLWin:
+		return 1
+	case WDLCursedWin:
+		return tablebaseCursedDTZ
+	case WDLBlessedLoss:
+		return -tablebaseCursedDTZ
+	case WDLLoss:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// calcSign returns 1 for positive values, -1 for negative values and 0 for zero.
+func calcSign(value int) int {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// calcTablePieces calculates the tablebase piece codes and squares of passed position and the material keys of both
+// colors.
+func calcTablePieces(pos *position.Position) (tablePieces, string, string, error) {
+	var (
+		pieces tablePieces
+		keys   [2]strings.Builder
+	)
+
+	for colorIndex, colorPieces := range [...][6]piece.Piece{tablebaseWhitePieces, tablebaseBlackPieces} {
+		for _, p := range colorPieces {
+			for _, sq := range pos.Board().GetPieceBitboard(p).GetSquares() {
+				index, err := calcTableSquare(sq)
+				if err != nil {
+					return tablePieces{}, "", "", fmt.Errorf("calcTableSquare(%s): %w", sq, err)
+				}
+
+				pieces.codes = append(pieces.codes, tablebasePieceCodes[p])
+				pieces.squares = append(pieces.squares, index)
+				keys[colorIndex].WriteString(tablebasePieceLetters[p])
+			}
+		}
+	}
+
+	return pieces, keys[0].String(), keys[1].String(), nil
+}
+
+// calcTableSquare calculates the tablebase index of passed square: 8*rank+file, where A1 is zero.
+func calcTableSquare(sq square.Square) (int, error) {
+	rank, err := sq.Rank()
+	if err != nil {
+		return 0, fmt.Errorf("Rank(): %w", err)
+	}
+
+	file, err := sq.File()
+	if err != nil {
+		return 0, fmt.Errorf("File(): %w", err)
+	}
+
+	return len(square.Files)*(int(rank)-1) + int(file) - 1, nil
+}
+
+// checkProbable checks that passed position may be in the tables: it has no castling rights and no more pieces than
+// the tables support.
+func checkProbable(pos *position.Position) error {
+	if pos == nil {
+		return errors.New("position is nil")
+	}
+
+	if len(pos.CastlingRights()) > 0 {
+		return errors.New("castling rights are not supported")
+	}
+
+	occupied, err := pos.Board().GetOccupiedBitboard()
+	if err != nil {
+		return fmt.Errorf("GetOccupiedBitboard(): %w", err)
+	}
+
+	if count := len(occupied.GetSquares()); count > encodingMaxPieces {
+		return fmt.Errorf("%d pieces are not supported", count)
+	}
+
+	return nil
+}
+
+// newPositionAfterMove creates a copy of passed position with passed move made.
+func newPositionAfterMove(pos *position.Position, m move.Move) (*position.Position, error) {
+	next, err := pos.DeepCopy()
+	if err != nil {
+		return nil, fmt.Errorf("DeepCopy(): %w", err)
+	}
+
+	if err := next.MoveRaw(m); err != nil {
+		return nil, fmt.Errorf("MoveRaw(%+v): %w", m, err)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,201 @@
+package syzygy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// newTestTablebase creates a new tablebase with synthetic KRvK tables, where white always wins in five moves.
+func newTestTablebase(t *testing.T) *Tablebase {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string][]byte{
+		"KRvK" + tablebaseWDLExtension: newTestSingleValueTableData(tableKindWDL, []uint8{6, 4, 14}, []uint8{4, 0}),
+		"KRvK" + tablebaseDTZExtension: newTestSingleValueTableData(tableKindDTZ, []uint8{6, 4, 14}, []uint8{5}),
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("WriteFile(%q): %v", name, err)
+		}
+	}
+
+	return NewTablebase(game.Engine{}, dir)
+}
+
+func TestTablebaseProbe(t *testing.T) {
+	t.Parallel()
+
+	tablebase := newTestTablebase(t)
+
+	tests := []struct {
+		name string
+		fen  string
+		wdl  WDL
+		dtz  DTZ
+	}{
+		{"two kings", "7k/8/8/8/8/8/8/K7 w - - 0 1", WDLDraw, 0},
+		{"white rook", "7k/8/8/8/4R3/8/8/K7 w - - 0 1", WDLWin, 11},
+		{"black rook", "7k/8/8/8/4r3/8/8/K7 b - - 0 1", WDLWin, 11},
+		{"rook capture", "7k/8/8/8/8/8/8/Kr6 w - - 0 1", WDLDraw, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			wdl, dtz, err := tablebase.Probe(pos)
+			if err != nil {
+				t.Fatalf("Probe(%q): %v", test.fen, err)
+			}
+
+			if wdl != test.wdl || dtz != test.dtz {
+				t.Fatalf("Probe(%q) expected (%s, %d) but got (%s, %d)", test.fen, test.wdl, test.dtz, wdl, dtz)
+			}
+		})
+	}
+}
+
+func TestTablebaseProbeTestdata(t *testing.T) {
+	t.Parallel()
+
+	tablebase := NewTablebase(game.Engine{}, "testdata")
+
+	tests := []struct {
+		name string
+		fen  string
+		wdl  WDL
+		dtz  DTZ
+	}{
+		{"mate in one", "7k/5K2/8/8/8/8/8/6R1 w - - 0 1", WDLWin, 1},
+		{"mated in one", "7k/5K2/8/8/8/8/8/6R1 b - - 0 1", WDLLoss, -2},
+		{"black rook mate in one", "6r1/8/8/8/8/8/5k2/7K b - - 0 1", WDLWin, 1},
+		{"stalemate", "k7/8/1K6/8/8/8/8/1R6 b - - 0 1", WDLDraw, 0},
+		{"rook capture", "7k/6R1/8/8/8/8/8/K7 b - - 0 1", WDLDraw, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			wdl, dtz, err := tablebase.Probe(pos)
+			if err != nil {
+				t.Fatalf("Probe(%q): %v", test.fen, err)
+			}
+
+			if wdl != test.wdl || dtz != test.dtz {
+				t.Fatalf("Probe(%q) expected (%s, %d) but got (%s, %d)", test.fen, test.wdl, test.dtz, wdl, dtz)
+			}
+		})
+	}
+}
+
+func TestTablebaseProbeErrors(t *testing.T) {
+	t.Parallel()
+
+	tablebase := newTestTablebase(t)
+
+	tests := []struct {
+		name     string
+		fen      string
+		notExist bool
+	}{
+		{"missing table", "7k/8/8/8/4Q3/8/8/K7 w - - 0 1", true},
+		{"castling rights", "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1", false},
+		{"too many pieces", "4k3/8/8/8/8/8/PPPP4/4K3 w - - 0 1", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			_, err = tablebase.ProbeWDL(pos)
+			if err == nil {
+				t.Fatalf("ProbeWDL(%q) expected error", test.fen)
+			}
+
+			if errors.Is(err, fs.ErrNotExist) != test.notExist {
+				t.Fatalf("ProbeWDL(%q) got unexpected error %v", test.fen, err)
+			}
+		})
+	}
+}
+
+func TestCalcMaterialKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fen  string
+		want string
+	}{
+		{"7k/8/8/8/8/8/8/K7 w - - 0 1", "KvK"},
+		{"7k/8/8/8/4r3/8/8/K7 b - - 0 1", "KvKR"},
+		{"3rk3/8/8/8/8/8/3P4/1R2K3 w - - 0 1", "KRPvKR"},
+		{"6nk/8/8/8/8/8/8/KQ3B2 w - - 0 1", "KQBvKN"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fen, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := position.NewPositionFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("NewPositionFromFEN(%q): %v", test.fen, err)
+			}
+
+			got, err := CalcMaterialKey(pos)
+			if err != nil {
+				t.Fatalf("CalcMaterialKey(%q): %v", test.fen, err)
+			}
+
+			if got != test.want {
+				t.Fatalf("CalcMaterialKey(%q) expected %q but got %q", test.fen, test.want, got)
+			}
+		})
+	}
+}
+
+func TestWDLString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wdl  WDL
+		want string
+	}{
+		{WDLLoss, "WDLLoss"},
+		{WDLBlessedLoss, "WDLBlessedLoss"},
+		{WDLDraw, "WDLDraw"},
+		{WDLCursedWin, "WDLCursedWin"},
+		{WDLWin, "WDLWin"},
+		{WDL(3), "<unknown WDL=3>"},
+	}
+
+	for _, test := range tests {
+		if got := test.wdl.String(); got != test.want {
+			t.Fatalf("%d.String() expected %q but got %q", test.wdl, test.want, got)
+		}
+	}
+}
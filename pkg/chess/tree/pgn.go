@@ -0,0 +1,417 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+	"github.com/rylenko/limbo/pkg/chess/piece"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+const (
+	// Symbols, which terminate the move text symbol.
+	pgnSymbolTerminators = "{}();"
+
+	// Maximum length of the move text line.
+	pgnLineLength = 80
+)
+
+// Move suffix annotations from the longest ones and their numeric annotation glyphs.
+var pgnSuffixAnnotations = [...]struct {
+	suffix string
+	nag    uint8
+}{
+	{"!!", 3}, {"??", 4}, {"!?", 5}, {"?!", 6}, {"!", 1}, {"?", 2},
+}
+
+// pgnFrame is the line of the moves, which is being parsed: the main line or the variation.
+type pgnFrame struct {
+	// Node, after which the next move of the line is played.
+	last *Node
+	// Whether the line is the variation, not the main line.
+	variation bool
+	// Whether the line has moves.
+	started bool
+	// Comment before the first move of the variation, which is added to the comment of the move.
+	pendingComment string
+}
+
+// NewTreeFromPGN creates the tree from passed PGN text of the single game with the variations, comments and numeric
+// annotation glyphs. The variant and the start position are taken from "Variant" and "FEN" tags.
+func NewTreeFromPGN(engine game.Engine, text string) (*Tree, error) {
+	pgnGame, err := pgn.NewReader(strings.NewReader(text)).Read()
+	if errors.Is(err, io.EOF) {
+		pgnGame = &pgn.Game{}
+	} else if err != nil {
+		return nil, fmt.Errorf("Read(): %w", err)
+	}
+
+	g, err := (&pgn.Game{Tags: pgnGame.Tags}).NewVariantGame(engine)
+	if err != nil {
+		return nil, fmt.Errorf("NewVariantGame(): %w", err)
+	}
+
+	tree, err := NewTree(g.Variant(), g.Position())
+	if err != nil {
+		return nil, fmt.Errorf("NewTree(): %w", err)
+	}
+
+	for _, tag := range pgnGame.Tags {
+		switch tag.Name {
+		case "Result", "Variant", "SetUp", "FEN":
+		default:
+			tree.Tags = append(tree.Tags, tag)
+		}
+	}
+
+	if err := tree.parseMoveText(cutPGNMoveText(text)); err != nil {
+		return nil, fmt.Errorf("parseMoveText(): %w", err)
+	}
+
+	return tree, nil
+}
+
+// PGN returns PGN text of the tree: the tags and the move text with the variations in parentheses, comments and
+// numeric annotation glyphs.
+func (tree *Tree) PGN() (string, error) {
+	result, err := tree.Result.PGN()
+	if err != nil {
+		return "", fmt.Errorf("%s.PGN(): %w", tree.Result, err)
+	}
+
+	start := game.NewGame(tree.variant, []*position.Position{tree.root.position}, nil)
+
+	pgnGame, err := pgn.NewGameFromGame(start, tree.Tags)
+	if err != nil {
+		return "", fmt.Errorf("NewGameFromGame(): %w", err)
+	}
+
+	var builder strings.Builder
+
+	for _, tag := range pgnGame.Tags {
+		if tag.Name == "Result" {
+			tag.Value = result
+		}
+
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(tag.Value)
+		fmt.Fprintf(&builder, "[%s \"%s\"]\n", tag.Name, value)
+	}
+
+	builder.WriteByte('\n')
+
+	var symbols []string
+
+	if tree.root.Comment != "" {
+		symbols = append(symbols, "{"+tree.root.Comment+"}")
+	}
+
+	symbols = appendLineSymbols(symbols, tree.root, true)
+
+	writeSymbols(&builder, append(symbols, result))
+
+	return builder.String(), nil
+}
+
+// parseMoveText parses the move text with the variations and adds the moves to the tree.
+func (tree *Tree) parseMoveText(text string) error {
+	frames := []*pgnFrame{{last: tree.root}}
+	reader := strings.NewReader(text)
+
+	for {
+		r, _, err := reader.ReadRune()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		frame := frames[len(frames)-1]
+
+		switch {
+		case unicode.IsSpace(r):
+		case r == '{':
+			comment, ok := readUntil(reader, '}')
+			if !ok {
+				return errors.New("comment is not closed")
+			}
+
+			frame.addComment(strings.TrimSpace(comment))
+		case r == ';':
+			comment, _ := readUntil(reader, '\n')
+			frame.addComment(strings.TrimSpace(comment))
+		case r == '(':
+			if !frame.started {
+				return errors.New("variation without move")
+			}
+
+			frames = append(frames, &pgnFrame{last: frame.last.parent, variation: true})
+		case r == ')':
+			if len(frames) == 1 {
+				return errors.New("unexpected )")
+			}
+
+			frames = frames[:len(frames)-1]
+		default:
+			if err := reader.UnreadRune(); err != nil {
+				return fmt.Errorf("UnreadRune(): %w", err)
+			}
+
+			symbol := readSymbol(reader)
+
+			end, err := tree.parseSymbol(frame, symbol)
+			if err != nil {
+				return fmt.Errorf("parseSymbol(%q): %w", symbol, err)
+			}
+
+			if end && len(frames) > 1 {
+				return errors.New("variation is not closed")
+			}
+
+			if end {
+				return nil
+			}
+		}
+	}
+
+	if len(frames) > 1 {
+		return errors.New("variation is not closed")
+	}
+
+	return nil
+}
+
+// parseSymbol parses the move with the move number and the suffix annotation, the numeric annotation glyph or the
+// game termination marker.
+//
+// Returns true if the symbol is the game termination marker.
+func (tree *Tree) parseSymbol(frame *pgnFrame, symbol string) (bool, error) {
+	if result, err := game.NewResultFromPGN(symbol); err == nil {
+		tree.Result = result
+		return true, nil
+	}
+
+	if nag, ok := strings.CutPrefix(symbol, "$"); ok {
+		value, err := strconv.ParseUint(nag, 10, 8)
+		if err != nil {
+			return false, fmt.Errorf("ParseUint(%q): %w", nag, err)
+		}
+
+		if !frame.started {
+			return false, errors.New("NAG without move")
+		}
+
+		frame.last.NAGs = append(frame.last.NAGs, uint8(value))
+
+		return false, nil
+	}
+
+	san := trimMoveNumber(symbol)
+	if san == "" {
+		return false, nil
+	}
+
+	san, nag := cutSuffixAnnotation(san)
+
+	node, err := tree.AddMoveSAN(frame.last, san)
+	if err != nil {
+		return false, fmt.Errorf("AddMoveSAN(%q): %w", san, err)
+	}
+
+	if nag != 0 {
+		node.NAGs = append(node.NAGs, nag)
+	}
+
+	if frame.pendingComment != "" {
+		node.Comment = strings.TrimSpace(frame.pendingComment + " " + node.Comment)
+		frame.pendingComment = ""
+	}
+
+	frame.last, frame.started = node, true
+
+	return false, nil
+}
+
+// addComment adds passed comment to the last move of the line. The comment before the first move is added to the root
+// in the main line and to the first move in the variation.
+func (frame *pgnFrame) addComment(comment string) {
+	target := &frame.last.Comment
+	if frame.variation && !frame.started {
+		target = &frame.pendingComment
+	}
+
+	*target = strings.TrimSpace(*target + " " + comment)
+}
+
+// appendLineSymbols appends the symbols of the continuations of passed node: the main move, the variations in
+// parentheses and then the rest of the main line.
+//
+// The move number of the black move is forced at the start of the line and after the variations and comments.
+func appendLineSymbols(symbols []string, node *Node, forceNumber bool) []string {
+	for len(node.children) > 0 {
+		main := node.children[0]
+		symbols = appendMoveSymbols(symbols, main, forceNumber)
+
+		for _, variation := range node.children[1:] {
+			symbols = append(symbols, "(")
+			symbols = appendMoveSymbols(symbols, variation, true)
+			symbols = appendLineSymbols(symbols, variation, variation.Comment != "")
+			symbols = append(symbols, ")")
+		}
+
+		forceNumber = len(node.children) > 1 || main.Comment != ""
+		node = main
+	}
+
+	return symbols
+}
+
+// appendMoveSymbols appends the move number, the move, the numeric annotation glyphs and the comment of passed node.
+func appendMoveSymbols(symbols []string, node *Node, forceNumber bool) []string {
+	pos := node.parent.position
+	number := strconv.Itoa(int(pos.FullMoveNumber()))
+
+	switch {
+	case pos.ActiveColor() == piece.ColorWhite:
+		symbols = append(symbols, number+".")
+	case forceNumber:
+		symbols = append(symbols, number+"...")
+	}
+
+	symbols = append(symbols, node.san)
+
+	for _, nag := range node.NAGs {
+		symbols = append(symbols, "$"+strconv.Itoa(int(nag)))
+	}
+
+	if node.Comment != "" {
+		symbols = append(symbols, "{"+node.Comment+"}")
+	}
+
+	return symbols
+}
+
+// writeSymbols writes passed move text symbols separated by spaces and lines of limited length followed by the line
+// break. The parentheses are attached to the adjacent symbols.
+func writeSymbols(builder *strings.Builder, symbols []string) {
+	var (
+		words []string
+		open  bool
+	)
+
+	for _, symbol := range symbols {
+		switch {
+		case symbol == "(":
+			open = true
+		case symbol == ")":
+			words[len(words)-1] += ")"
+		case open:
+			words = append(words, "("+symbol)
+			open = false
+		default:
+			words = append(words, symbol)
+		}
+	}
+
+	var lineLength int
+
+	for _, word := range words {
+		switch {
+		case lineLength == 0:
+		case lineLength+1+len(word) > pgnLineLength:
+			builder.WriteByte('\n')
+
+			lineLength = 0
+		default:
+			builder.WriteByte(' ')
+
+			lineLength++
+		}
+
+		builder.WriteString(word)
+
+		lineLength += len(word)
+	}
+
+	builder.WriteByte('\n')
+}
+
+// cutPGNMoveText returns the move text of passed PGN text without the tags and the escaped lines before it.
+func cutPGNMoveText(text string) string {
+	for text != "" {
+		line, rest, _ := strings.Cut(text, "\n")
+		if trimmed := strings.TrimSpace(line); trimmed != "" && trimmed[0] != '[' && trimmed[0] != '%' {
+			return text
+		}
+
+		text = rest
+	}
+
+	return ""
+}
+
+// cutSuffixAnnotation removes the suffix annotation from passed move and returns its numeric annotation glyph or zero
+// if there is no annotation.
+func cutSuffixAnnotation(san string) (string, uint8) {
+	for _, annotation := range pgnSuffixAnnotations {
+		if trimmed, ok := strings.CutSuffix(san, annotation.suffix); ok {
+			return trimmed, annotation.nag
+		}
+	}
+
+	return san, 0
+}
+
+// readSymbol reads the move text symbol until the space or the symbol terminator.
+func readSymbol(reader *strings.Reader) string {
+	var builder strings.Builder
+
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return builder.String()
+		}
+
+		if unicode.IsSpace(r) || strings.ContainsRune(pgnSymbolTerminators, r) {
+			reader.UnreadRune() //nolint:errcheck // The rune was just read.
+			return builder.String()
+		}
+
+		builder.WriteRune(r)
+	}
+}
+
+// readUntil reads the text until passed terminator, which is skipped.
+//
+// Returns false if the text ended before the terminator.
+func readUntil(reader *strings.Reader, terminator rune) (string, bool) {
+	var builder strings.Builder
+
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return builder.String(), false
+		}
+
+		if r == terminator {
+			return builder.String(), true
+		}
+
+		builder.WriteRune(r)
+	}
+}
+
+// trimMoveNumber removes the move number indication from passed symbol.
+//
+// Return examples: "e4" for "1.e4", "" for "12...", "Nf3" for "Nf3".
+func trimMoveNumber(symbol string) string {
+	trimmed := strings.TrimLeft(symbol, "0123456789")
+	if trimmed == symbol || !strings.HasPrefix(trimmed, ".") {
+		return symbol
+	}
+
+	return strings.TrimLeft(trimmed, ".")
+}
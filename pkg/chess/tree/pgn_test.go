@@ -0,0 +1,115 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestNewTreeFromPGN(t *testing.T) {
+	t.Parallel()
+
+	const text = `[Event "Analysis"]
+[White "alice"]
+[Result "1-0"]
+
+{Start} 1. e4 e5 {Open game} 2. Nf3 (2. f4 exf4 (2... d5) 3. Nf3) 2... Nc6 $1 3.Bb5 a6!? 1-0
+`
+
+	const expected = `[Event "Analysis"]
+[White "alice"]
+[Result "1-0"]
+
+{Start} 1. e4 e5 {Open game} 2. Nf3 (2. f4 exf4 (2... d5) 3. Nf3) 2... Nc6 $1 3.
+Bb5 a6 $5 1-0
+`
+
+	tree, err := NewTreeFromPGN(game.Engine{}, text)
+	if err != nil {
+		t.Fatalf("NewTreeFromPGN(): %v", err)
+	}
+
+	mainLine := tree.MainLine()
+	if len(mainLine) != 6 || mainLine[5].SAN() != "a6" || mainLine[5].NAGs[0] != 5 || tree.Result != game.ResultWhiteWon {
+		t.Fatalf("NewTreeFromPGN() expected main line to a6!? with white win but got %d moves", len(mainLine))
+	}
+
+	variations := mainLine[1].Children()
+	if len(variations) != 2 || variations[1].SAN() != "f4" || len(variations[1].Children()) != 2 {
+		t.Fatalf("NewTreeFromPGN() expected 2. f4 variation with 2... d5 subvariation")
+	}
+
+	pgnText, err := tree.PGN()
+	if err != nil {
+		t.Fatalf("PGN(): %v", err)
+	}
+
+	if pgnText != expected {
+		t.Fatalf("PGN() expected %q but got %q", expected, pgnText)
+	}
+
+	reparsed, err := NewTreeFromPGN(game.Engine{}, pgnText)
+	if err != nil {
+		t.Fatalf("NewTreeFromPGN(PGN()): %v", err)
+	}
+
+	if reparsedText, err := reparsed.PGN(); err != nil || reparsedText != expected {
+		t.Fatalf("PGN() of the reparsed tree expected %q but got %q, %v", expected, reparsedText, err)
+	}
+}
+
+func TestNewTreeFromPGNStartPosition(t *testing.T) {
+	t.Parallel()
+
+	const text = `[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 30"]
+
+30... Kd7 (30... Kf7 {Other king}) 31. e4 *`
+
+	const expected = `[Result "*"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 30"]
+
+30... Kd7 (30... Kf7 {Other king}) 31. e4 *
+`
+
+	tree, err := NewTreeFromPGN(game.Engine{}, text)
+	if err != nil {
+		t.Fatalf("NewTreeFromPGN(): %v", err)
+	}
+
+	pgnText, err := tree.PGN()
+	if err != nil {
+		t.Fatalf("PGN(): %v", err)
+	}
+
+	if pgnText != expected {
+		t.Fatalf("PGN() expected %q but got %q", expected, pgnText)
+	}
+}
+
+func TestNewTreeFromPGNErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text      string
+		errString string
+	}{
+		{"1. e4 (1. d4", "parseMoveText(): variation is not closed"},
+		{"1. e4 e5) *", "parseMoveText(): unexpected )"},
+		{"(1. e4) *", "parseMoveText(): variation without move"},
+		{"$1 1. e4 *", `parseMoveText(): parseSymbol("$1"): NAG without move`},
+		{"1. e4 {comment", "parseMoveText(): comment is not closed"},
+		{"1. e4 (1. d4 d5 *", "parseMoveText(): variation is not closed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewTreeFromPGN(game.Engine{}, test.text)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("NewTreeFromPGN() expected error %q but got %q", test.errString, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package tree implements the tree of the moves with variations, comments and numeric annotation glyphs for the
+// analysis.
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rylenko/limbo/pkg/chess/book"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/move"
+	"github.com/rylenko/limbo/pkg/chess/pgn"
+	"github.com/rylenko/limbo/pkg/chess/position"
+)
+
+// Node is the move in the tree with the position after it. The root node has no move and contains the start position.
+type Node struct {
+	parent   *Node
+	move     move.Move
+	san      string
+	position *position.Position
+	// Continuations of the node. The first one is the main line, the others are the variations.
+	children []*Node
+
+	// Comment after the move or before the first move for the root.
+	Comment string
+	// Numeric annotation glyphs of the move, for example, 1 for "!" and 4 for "??".
+	NAGs []uint8
+}
+
+// Parent returns the node before the move or nil for the root.
+func (node *Node) Parent() *Node {
+	return node.parent
+}
+
+// Move returns the move of the node. The root has no move.
+func (node *Node) Move() move.Move {
+	return node.move
+}
+
+// SAN returns standard algebraic notation of the move of the node or the empty string for the root.
+func (node *Node) SAN() string {
+	return node.san
+}
+
+// Position returns the position after the move of the node.
+//
+// Please note that the position is shared with the transpositions and not copied, so do not modify it.
+func (node *Node) Position() *position.Position {
+	return node.position
+}
+
+// Children returns the continuations of the node: the main line and then the variations.
+func (node *Node) Children() []*Node {
+	return slices.Clone(node.children)
+}
+
+// MainChild returns the main continuation of the node or nil if there are no continuations.
+func (node *Node) MainChild() *Node {
+	if len(node.children) == 0 {
+		return nil
+	}
+
+	return node.children[0]
+}
+
+// Tree is the tree of the moves from the start position of the variant.
+//
+// Equal positions reached by the different move orders are stored once: they are looked up by Polyglot key and
+// compared by FEN.
+type Tree struct {
+	variant game.Variant
+	root    *Node
+	// Positions of the tree by their Polyglot keys.
+	positions map[uint64][]*position.Position
+
+	// Tags of the game, for example, "White" or "Event". "Result", "Variant", "SetUp" and "FEN" tags are written from
+	// the tree.
+	Tags []pgn.Tag
+	// Result from the game termination marker.
+	Result game.Result
+}
+
+// NewTree creates a new Tree of passed variant without moves from passed start position.
+func NewTree(variant game.Variant, start *position.Position) (*Tree, error) {
+	if variant == nil {
+		return nil, errors.New("variant is nil")
+	}
+
+	tree := &Tree{variant: variant, positions: make(map[uint64][]*position.Position)}
+
+	start, err := tree.internPosition(start)
+	if err != nil {
+		return nil, fmt.Errorf("internPosition(): %w", err)
+	}
+
+	tree.root = &Node{position: start}
+
+	return tree, nil
+}
+
+// Root returns the root node with the start position.
+func (tree *Tree) Root() *Node {
+	return tree.root
+}
+
+// Variant returns the variant of the tree.
+func (tree *Tree) Variant() game.Variant {
+	return tree.variant
+}
+
+// AddMove adds passed possible move in the position of passed node and returns the node of the move. The move becomes
+// the last variation of the node or the main line if the node has no continuations.
+//
+// The existing node is returned if the move is already in the tree.
+func (tree *Tree) AddMove(node *Node, m move.Move) (*Node, error) {
+	for _, child := range node.children {
+		if child.move == m {
+			return child, nil
+		}
+	}
+
+	san, err := tree.variant.CalcMoveSAN(node.position, m)
+	if err != nil {
+		return nil, fmt.Errorf("CalcMoveSAN(%+v): %w", m, err)
+	}
+
+	g := game.NewGame(tree.variant, []*position.Position{node.position}, nil)
+
+	if err := g.Move(m); err != nil {
+		return nil, fmt.Errorf("Move(%+v): %w", m, err)
+	}
+
+	pos, err := tree.internPosition(g.Position())
+	if err != nil {
+		return nil, fmt.Errorf("internPosition(): %w", err)
+	}
+
+	child := &Node{parent: node, move: m, san: san, position: pos}
+	node.children = append(node.children, child)
+
+	return child, nil
+}
+
+// AddMoveSAN adds the move with passed standard algebraic notation in the position of passed node like AddMove.
+func (tree *Tree) AddMoveSAN(node *Node, san string) (*Node, error) {
+	m, err := tree.variant.CalcMoveFromSAN(node.position, san)
+	if err != nil {
+		return nil, fmt.Errorf("CalcMoveFromSAN(%q): %w", san, err)
+	}
+
+	child, err := tree.AddMove(node, m)
+	if err != nil {
+		return nil, fmt.Errorf("AddMove(%+v): %w", m, err)
+	}
+
+	return child, nil
+}
+
+// Promote makes passed node the main continuation of its parent. The previous main continuation becomes the first
+// variation.
+func (tree *Tree) Promote(node *Node) error {
+	index, err := calcChildIndex(node)
+	if err != nil {
+		return fmt.Errorf("calcChildIndex(): %w", err)
+	}
+
+	siblings := node.parent.children
+	copy(siblings[1:index+1], siblings[:index])
+	siblings[0] = node
+
+	return nil
+}
+
+// Delete removes passed node with all its continuations from the tree.
+func (tree *Tree) Delete(node *Node) error {
+	index, err := calcChildIndex(node)
+	if err != nil {
+		return fmt.Errorf("calcChildIndex(): %w", err)
+	}
+
+	node.parent.children = slices.Delete(node.parent.children, index, index+1)
+	node.parent = nil
+
+	return nil
+}
+
+// MainLine returns the nodes of the main line without the root.
+func (tree *Tree) MainLine() []*Node {
+	var nodes []*Node
+
+	for node := tree.root.MainChild(); node != nil; node = node.MainChild() {
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// internPosition returns the equal position of the tree or adds passed position to the tree and returns it.
+func (tree *Tree) internPosition(pos *position.Position) (*position.Position, error) {
+	key, err := book.CalcPolyglotKey(pos)
+	if err != nil {
+		return nil, fmt.Errorf("CalcPolyglotKey(): %w", err)
+	}
+
+	fen, err := pos.FEN()
+	if err != nil {
+		return nil, fmt.Errorf("FEN(): %w", err)
+	}
+
+	for _, candidate := range tree.positions[key] {
+		candidateFEN, err := candidate.FEN()
+		if err != nil {
+			return nil, fmt.Errorf("candidate FEN(): %w", err)
+		}
+
+		if candidateFEN == fen {
+			return candidate, nil
+		}
+	}
+
+	tree.positions[key] = append(tree.positions[key], pos)
+
+	return pos, nil
+}
+
+// calcChildIndex returns the index of passed node in the continuations of its parent.
+func calcChildIndex(node *Node) (int, error) {
+	if node.parent == nil {
+		return 0, errors.New("node is root or deleted")
+	}
+
+	index := slices.Index(node.parent.children, node)
+	if index < 0 {
+		return 0, errors.New("node is not a child of its parent")
+	}
+
+	return index, nil
+}
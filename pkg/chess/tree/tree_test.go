@@ -0,0 +1,77 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+)
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	g, err := game.NewGameStart()
+	if err != nil {
+		t.Fatalf("NewGameStart(): %v", err)
+	}
+
+	tree, err := NewTree(g.Variant(), g.Position())
+	if err != nil {
+		t.Fatalf("NewTree(): %v", err)
+	}
+
+	addLine := func(sans ...string) []*Node {
+		nodes := make([]*Node, 0, len(sans))
+		node := tree.Root()
+
+		for _, san := range sans {
+			if node, err = tree.AddMoveSAN(node, san); err != nil {
+				t.Fatalf("AddMoveSAN(%q): %v", san, err)
+			}
+
+			nodes = append(nodes, node)
+		}
+
+		return nodes
+	}
+
+	main := addLine("Nf3", "Nf6", "Nc3")
+	variation := addLine("Nc3", "Nf6", "Nf3")
+
+	if again := addLine("Nf3"); again[0] != main[0] {
+		t.Fatal("AddMoveSAN() expected the existing node for the same move")
+	}
+
+	if main[2].Position() != variation[2].Position() {
+		t.Fatal("AddMoveSAN() expected the shared position of the transposition")
+	}
+
+	if tree.Root().MainChild() != main[0] || len(tree.Root().Children()) != 2 {
+		t.Fatal("AddMoveSAN() expected the first move as the main line")
+	}
+
+	if err := tree.Promote(variation[0]); err != nil {
+		t.Fatalf("Promote(): %v", err)
+	}
+
+	if children := tree.Root().Children(); children[0] != variation[0] || children[1] != main[0] {
+		t.Fatal("Promote() expected the variation as the main line and the main line as the variation")
+	}
+
+	if mainLine := tree.MainLine(); len(mainLine) != 3 || mainLine[2] != variation[2] {
+		t.Fatalf("MainLine() expected the promoted line but got %d nodes", len(mainLine))
+	}
+
+	if err := tree.Delete(variation[1]); err != nil {
+		t.Fatalf("Delete(): %v", err)
+	}
+
+	if mainLine := tree.MainLine(); len(mainLine) != 1 || mainLine[0] != variation[0] {
+		t.Fatalf("Delete() expected the main line to end at the parent but got %d nodes", len(mainLine))
+	}
+
+	for _, node := range [...]*Node{tree.Root(), variation[1]} {
+		if err := tree.Promote(node); err == nil || err.Error() != "calcChildIndex(): node is root or deleted" {
+			t.Fatalf("Promote() expected error for the root or deleted node but got %v", err)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package uci
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rylenko/limbo/pkg/chess/book"
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/position"
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+const (
+	handlerEngineName   = "Limbo"
+	handlerEngineAuthor = "rylenko"
+
+	// Name of the option, which sets maximum depth of the searcher.
+	handlerOptionMaxDepth = "MaxDepth"
+
+	// Name of the option, which sets path to the Polyglot opening book. Empty path disables the book.
+	handlerOptionBookFile = "BookFile"
+
+	// Count of FEN parts in "position fen" command.
+	handlerPositionFENPartsCount = 6
+
+	// Move to send if there is no best move, for example, in checkmate.
+	handlerNullMove = "0000"
+)
+
+// Handler speaks UCI protocol with the GUI, drives the engine and the searcher.
+//
+// See https://backscattering.de/chess/uci/ for the protocol description.
+type Handler struct {
+	engine   game.Engine
+	searcher *search.Searcher
+
+	writer      io.Writer
+	writerMutex sync.Mutex
+
+	position *position.Position
+
+	book     *book.PolyglotBook
+	bookFile *os.File
+	random   *rand.Rand
+
+	searchCancel context.CancelFunc
+	searchDone   chan struct{}
+}
+
+// NewHandler creates a new Handler with passed parameters, which writes responses to passed writer.
+func NewHandler(engine game.Engine, searcher *search.Searcher, writer io.Writer) *Handler {
+	return &Handler{
+		engine:   engine,
+		searcher: searcher,
+		writer:   writer,
+		random:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), //nolint:gosec // Book variety only.
+	}
+}
+
+// Run reads commands line by line from passed reader and handles them until "quit" command or the end of the input.
+//
+// Invalid commands do not stop the handler, instead the error is sent to the GUI as "info string".
+func (handler *Handler) Run(reader io.Reader) error {
+	defer handler.stopSearch()
+	defer handler.closeBook()
+
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		quit, err := handler.HandleCommand(scanner.Text())
+		if err != nil {
+			handler.writeLine("info string " + err.Error())
+		}
+
+		if quit {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Scan(): %w", err)
+	}
+
+	return nil
+}
+
+// HandleCommand handles single command line. Returns true if the command is "quit".
+//
+// Line argument examples: "uci", "position startpos moves e2e4", "go wtime 1000 btime 1000".
+func (handler *Handler) HandleCommand(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case "uci":
+		handler.handleUCI()
+	case "isready":
+		handler.writeLine("readyok")
+	case "ucinewgame":
+		handler.stopSearch()
+		handler.position = nil
+	case "position":
+		handler.stopSearch()
+
+		if err := handler.handlePosition(args); err != nil {
+			return false, fmt.Errorf("handlePosition(%v): %w", args, err)
+		}
+	case "go":
+		if err := handler.handleGo(args); err != nil {
+			return false, fmt.Errorf("handleGo(%v): %w", args, err)
+		}
+	case "stop":
+		handler.stopSearch()
+	case "setoption":
+		handler.stopSearch()
+
+		if err := handler.handleSetOption(args); err != nil {
+			return false, fmt.Errorf("handleSetOption(%v): %w", args, err)
+		}
+	case "quit":
+		handler.stopSearch()
+		return true, nil
+	case "debug", "ponderhit", "register":
+	default:
+		return false, fmt.Errorf("unknown command %q", name)
+	}
+
+	return false, nil
+}
+
+// handleGo starts the search in the current position in the background.
+//
+// The best move is sent to the GUI when the search is done. Note that the infinite search waits for "stop" command.
+func (handler *Handler) handleGo(args []string) error {
+	limits, err := newLimitsFromGoArgs(args)
+	if err != nil {
+		return fmt.Errorf("newLimitsFromGoArgs(%v): %w", args, err)
+	}
+
+	handler.stopSearch()
+
+	if handler.position == nil {
+		handler.position, err = position.NewPositionStart()
+		if err != nil {
+			return fmt.Errorf("NewPositionStart(): %w", err)
+		}
+	}
+
+	if handler.book != nil && !limits.Infinite {
+		bookMove, ok, err := handler.book.PickMove(handler.position, handler.random)
+		if err != nil {
+			return fmt.Errorf("PickMove(): %w", err)
+		}
+
+		if ok {
+			bookMoveUCI, err := bookMove.UCI()
+			if err != nil {
+				return fmt.Errorf("UCI(%+v): %w", bookMove, err)
+			}
+
+			handler.writeLine("bestmove " + bookMoveUCI)
+
+			return nil
+		}
+	}
+
+	pos, err := handler.position.DeepCopy()
+	if err != nil {
+		return fmt.Errorf("DeepCopy(): %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	handler.searchCancel = cancel
+	handler.searchDone = done
+
+	go func() {
+		defer close(done)
+
+		bestMove, err := handler.searcher.Search(ctx, pos, limits, handler.writeInfo)
+
+		if limits.Infinite {
+			<-ctx.Done()
+		}
+
+		if err != nil {
+			handler.writeLine("info string Search(): " + err.Error())
+			handler.writeLine("bestmove " + handlerNullMove)
+
+			return
+		}
+
+		bestMoveUCI, err := bestMove.UCI()
+		if err != nil {
+			handler.writeLine("info string UCI(): " + err.Error())
+			handler.writeLine("bestmove " + handlerNullMove)
+
+			return
+		}
+
+		handler.writeLine("bestmove " + bestMoveUCI)
+	}()
+
+	return nil
+}
+
+// handlePosition sets up the position described by passed arguments.
+//
+// Args argument examples: ["startpos"], ["startpos", "moves", "e2e4"], ["fen", <6 FEN parts>, "moves", "e2e4"].
+func (handler *Handler) handlePosition(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no position")
+	}
+
+	var (
+		pos *position.Position
+		err error
+	)
+
+	switch args[0] {
+	case "startpos":
+		pos, err = position.NewPositionStart()
+		if err != nil {
+			return fmt.Errorf("NewPositionStart(): %w", err)
+		}
+
+		args = args[1:]
+	case "fen":
+		if len(args) < 1+handlerPositionFENPartsCount {
+			return fmt.Errorf("FEN parts required %d but got %d", handlerPositionFENPartsCount, len(args)-1)
+		}
+
+		fen := strings.Join(args[1:1+handlerPositionFENPartsCount], " ")
+
+		pos, err = position.NewPositionFromFEN(fen)
+		if err != nil {
+			return fmt.Errorf("NewPositionFromFEN(%q): %w", fen, err)
+		}
+
+		args = args[1+handlerPositionFENPartsCount:]
+	default:
+		return fmt.Errorf("unknown position %q", args[0])
+	}
+
+	if len(args) > 0 {
+		if args[0] != "moves" {
+			return fmt.Errorf("expected \"moves\" but got %q", args[0])
+		}
+
+		for _, uci := range args[1:] {
+			move, err := handler.engine.CalcMoveFromUCI(pos, uci)
+			if err != nil {
+				return fmt.Errorf("CalcMoveFromUCI(%q): %w", uci, err)
+			}
+
+			if err := pos.MoveRaw(move); err != nil {
+				return fmt.Errorf("MoveRaw(%+v): %w", move, err)
+			}
+		}
+	}
+
+	handler.position = pos
+
+	return nil
+}
+
+// handleSetOption sets the option described by passed arguments.
+//
+// Args argument example: ["name", "MaxDepth", "value", "8"].
+func (handler *Handler) handleSetOption(args []string) error {
+	if len(args) < 2 || args[0] != "name" { //nolint:mnd // Name keyword and the name itself.
+		return errors.New("no option name")
+	}
+
+	valueIndex := slices.Index(args, "value")
+	if valueIndex < 0 {
+		valueIndex = len(args)
+	}
+
+	name := strings.Join(args[1:valueIndex], " ")
+
+	var value string
+	if valueIndex < len(args) {
+		value = strings.Join(args[valueIndex+1:], " ")
+	}
+
+	switch name {
+	case handlerOptionMaxDepth:
+		maxDepth, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return fmt.Errorf("ParseUint(%s, 10, 8): %w", value, err)
+		}
+
+		if maxDepth == 0 {
+			return errors.New("max depth must be positive")
+		}
+
+		handler.searcher.SetMaxDepth(uint8(maxDepth))
+	case handlerOptionBookFile:
+		if err := handler.openBook(value); err != nil {
+			return fmt.Errorf("openBook(%q): %w", value, err)
+		}
+	default:
+		return fmt.Errorf("unknown option %q", name)
+	}
+
+	return nil
+}
+
+// handleUCI sends engine identity and supported options to the GUI.
+func (handler *Handler) handleUCI() {
+	handler.writeLine("id name " + handlerEngineName)
+	handler.writeLine("id author " + handlerEngineAuthor)
+	handler.writeLine(fmt.Sprintf(
+		"option name %s type spin default %d min 1 max %d",
+		handlerOptionMaxDepth,
+		handler.searcher.MaxDepth(),
+		search.SearcherMaxDepth,
+	))
+	handler.writeLine(fmt.Sprintf("option name %s type string default <empty>", handlerOptionBookFile))
+	handler.writeLine("uciok")
+}
+
+// closeBook closes the current opening book if exists.
+func (handler *Handler) closeBook() {
+	if handler.bookFile == nil {
+		return
+	}
+
+	// Nothing to lose on read-only file close error.
+	_ = handler.bookFile.Close()
+
+	handler.book = nil
+	handler.bookFile = nil
+}
+
+// openBook opens the Polyglot opening book located at passed path instead of the current one.
+//
+// Empty path and "<empty>" disable the book.
+func (handler *Handler) openBook(path string) error {
+	handler.closeBook()
+
+	if path == "" || path == "<empty>" {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Open(): %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(fmt.Errorf("Stat(): %w", err), file.Close())
+	}
+
+	polyglotBook, err := book.NewPolyglotBook(file, info.Size(), handler.engine)
+	if err != nil {
+		return errors.Join(fmt.Errorf("NewPolyglotBook(): %w", err), file.Close())
+	}
+
+	handler.book = polyglotBook
+	handler.bookFile = file
+
+	return nil
+}
+
+// stopSearch stops the current search if exists and waits until the best move is sent.
+func (handler *Handler) stopSearch() {
+	if handler.searchCancel == nil {
+		return
+	}
+
+	handler.searchCancel()
+	<-handler.searchDone
+
+	handler.searchCancel = nil
+	handler.searchDone = nil
+}
+
+// writeInfo sends search iteration info to the GUI.
+func (handler *Handler) writeInfo(info search.Info) {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "info depth %d", info.Depth)
+
+	if info.MateIn != 0 {
+		fmt.Fprintf(&builder, " score mate %d", info.MateIn)
+	} else {
+		fmt.Fprintf(&builder, " score cp %d", info.Score)
+	}
+
+	fmt.Fprintf(&builder, " nodes %d time %d", info.Nodes, info.Time.Milliseconds())
+
+	if info.Time > 0 {
+		fmt.Fprintf(&builder, " nps %d", uint64(float64(info.Nodes)/info.Time.Seconds()))
+	}
+
+	if len(info.PV) > 0 {
+		builder.WriteString(" pv")
+
+		for _, move := range info.PV {
+			uci, err := move.UCI()
+			if err != nil {
+				break
+			}
+
+			builder.WriteString(" " + uci)
+		}
+	}
+
+	handler.writeLine(builder.String())
+}
+
+// writeLine sends passed line to the GUI.
+//
+// The write is safe to call from the search goroutine. Write errors are ignored, because there is no one to report
+// them to.
+func (handler *Handler) writeLine(line string) {
+	handler.writerMutex.Lock()
+	defer handler.writerMutex.Unlock()
+
+	_, _ = io.WriteString(handler.writer, line+"\n")
+}
@@ -0,0 +1,62 @@
+package uci
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rylenko/limbo/pkg/chess/game"
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+func TestHandlerRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			"handshake",
+			"uci\nisready\nquit\n",
+			"id name Limbo\nid author rylenko\noption name MaxDepth type spin default 3 min 1 max 64\n" +
+				"option name BookFile type string default <empty>\nuciok\nreadyok\n",
+		},
+		{
+			"set option",
+			"setoption name MaxDepth value 2\nuci\n",
+			"id name Limbo\nid author rylenko\noption name MaxDepth type spin default 2 min 1 max 64\n" +
+				"option name BookFile type string default <empty>\nuciok\n",
+		},
+		{"unknown command", "hello\n", "info string unknown command \"hello\"\n"},
+		{
+			"unknown option",
+			"setoption name Hash value 16\n",
+			"info string handleSetOption([name Hash value 16]): unknown option \"Hash\"\n",
+		},
+		{
+			"impossible move",
+			"position startpos moves e2e5\n",
+			"info string handlePosition([startpos moves e2e5]): CalcMoveFromUCI(\"e2e5\"): no possible move \"e2e5\"\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			handler := NewHandler(game.Engine{}, search.NewSearcher(game.Engine{}, 3), &output)
+
+			if err := handler.Run(strings.NewReader(test.input)); err != nil {
+				t.Fatalf("Run(%q): %v", test.input, err)
+			}
+
+			if output.String() != test.output {
+				t.Fatalf("Run(%q) expected output %q but got %q", test.input, test.output, output.String())
+			}
+		})
+	}
+}
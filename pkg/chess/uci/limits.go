@@ -0,0 +1,82 @@
+package uci
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+// newLimitsFromGoArgs parses "go" command arguments to the search limits.
+//
+// Args argument example: ["wtime", "300000", "btime", "300000", "winc", "2000", "binc", "2000"].
+func newLimitsFromGoArgs(args []string) (search.Limits, error) {
+	var limits search.Limits
+
+	for index := 0; index < len(args); index++ {
+		name := args[index]
+
+		switch name {
+		case "infinite":
+			limits.Infinite = true
+			continue
+		case "ponder":
+			continue
+		}
+
+		if index+1 >= len(args) {
+			return search.Limits{}, fmt.Errorf("no value for %q", name)
+		}
+
+		index++
+		value := args[index]
+
+		var err error
+
+		switch name {
+		case "depth":
+			var depth uint64
+
+			depth, err = strconv.ParseUint(value, 10, 8)
+			limits.Depth = uint8(depth)
+		case "nodes":
+			limits.Nodes, err = strconv.ParseUint(value, 10, 64)
+		case "movestogo":
+			var movesToGo uint64
+
+			movesToGo, err = strconv.ParseUint(value, 10, 16)
+			limits.MovesToGo = uint16(movesToGo)
+		case "movetime":
+			limits.MoveTime, err = parseMilliseconds(value)
+		case "wtime":
+			limits.WhiteTime, err = parseMilliseconds(value)
+		case "btime":
+			limits.BlackTime, err = parseMilliseconds(value)
+		case "winc":
+			limits.WhiteInc, err = parseMilliseconds(value)
+		case "binc":
+			limits.BlackInc, err = parseMilliseconds(value)
+		default:
+			return search.Limits{}, fmt.Errorf("unknown argument %q", name)
+		}
+
+		if err != nil {
+			return search.Limits{}, fmt.Errorf("invalid %q value %q: %w", name, value, err)
+		}
+	}
+
+	return limits, nil
+}
+
+// parseMilliseconds parses milliseconds count to the duration.
+//
+// Note that GUIs can send negative time if the engine is late, so negative values are valid.
+func parseMilliseconds(value string) (time.Duration, error) {
+	milliseconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ParseInt(%s, 10, 64): %w", value, err)
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond, nil
+}
@@ -0,0 +1,66 @@
+package uci
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rylenko/limbo/pkg/chess/search"
+)
+
+func TestNewLimitsFromGoArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		args      []string
+		limits    search.Limits
+		errString string
+	}{
+		{"empty", nil, search.Limits{}, ""},
+		{"infinite", []string{"infinite"}, search.Limits{Infinite: true}, ""},
+		{"depth and nodes", []string{"depth", "5", "nodes", "1000"}, search.Limits{Depth: 5, Nodes: 1000}, ""},
+		{"move time", []string{"movetime", "1500"}, search.Limits{MoveTime: 1500 * time.Millisecond}, ""},
+		{
+			"clock",
+			[]string{"wtime", "60000", "btime", "-20", "winc", "1000", "binc", "0", "movestogo", "40"},
+			search.Limits{
+				WhiteTime: time.Minute,
+				BlackTime: -20 * time.Millisecond,
+				WhiteInc:  time.Second,
+				MovesToGo: 40,
+			},
+			"",
+		},
+		{"ponder", []string{"ponder", "depth", "3"}, search.Limits{Depth: 3}, ""},
+		{"no value", []string{"depth"}, search.Limits{}, "no value for \"depth\""},
+		{
+			"invalid depth",
+			[]string{"depth", "300"},
+			search.Limits{},
+			"invalid \"depth\" value \"300\": strconv.ParseUint: parsing \"300\": value out of range",
+		},
+		{
+			"invalid time",
+			[]string{"wtime", "x"},
+			search.Limits{},
+			"invalid \"wtime\" value \"x\": ParseInt(x, 10, 64): strconv.ParseInt: parsing \"x\": invalid syntax",
+		},
+		{"unknown", []string{"mate", "3"}, search.Limits{}, "unknown argument \"mate\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			limits, err := newLimitsFromGoArgs(test.args)
+			if (err == nil && test.errString != "") || (err != nil && err.Error() != test.errString) {
+				t.Fatalf("newLimitsFromGoArgs(%v) expected error %q but got %q", test.args, test.errString, err)
+			}
+
+			if !reflect.DeepEqual(limits, test.limits) {
+				t.Fatalf("newLimitsFromGoArgs(%v) expected %+v but got %+v", test.args, test.limits, limits)
+			}
+		})
+	}
+}